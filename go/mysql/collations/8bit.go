@@ -17,6 +17,8 @@ limitations under the License.
 package collations
 
 import (
+	"bytes"
+
 	"vitess.io/vitess/go/mysql/collations/internal/charset"
 )
 
@@ -61,8 +63,21 @@ func (c *Collation_8bit_bin) IsBinary() bool {
 	return true
 }
 
+func (c *Collation_8bit_bin) Capabilities() CollationCaps {
+	return CollationCaps{CaseSensitive: true, AccentSensitive: true, Binary: true, PadSpace: true, Levels: 1}
+}
+
 func (c *Collation_8bit_bin) Collate(left, right []byte, rightIsPrefix bool) int {
-	return collationBinary(left, right, rightIsPrefix)
+	if rightIsPrefix {
+		return collationBinary(left, right, rightIsPrefix)
+	}
+	cmpLen := minInt(len(left), len(right))
+	if diff := bytes.Compare(left[:cmpLen], right[:cmpLen]); diff != 0 {
+		return diff
+	}
+	return collatePadSpace(left[cmpLen:], right[cmpLen:], func(rest []byte) int {
+		return padSpaceBytes(rest, identityByte)
+	})
 }
 
 func (c *Collation_8bit_bin) WeightString(dst, src []byte, numCodepoints int) []byte {
@@ -86,6 +101,66 @@ func (c *Collation_8bit_bin) WeightStringLen(numBytes int) int {
 	return numBytes
 }
 
+func (c *Collation_8bit_bin) Hash(src []byte, numCodepoints int) uint64 {
+	limit, pad := hashLimit(src, numCodepoints)
+	if !pad {
+		// trailing spaces are not significant when comparing
+		src = bytes.TrimRight(src, " ")
+	}
+	copyCodepoints := minInt(len(src), limit)
+
+	h := newWeightHasher()
+	h.write(src[:copyCodepoints])
+	if pad {
+		h.pad(hashPadding8, limit-copyCodepoints)
+	}
+	return h.sum64()
+}
+
+func (c *Collation_8bit_bin) Like(pattern, input []byte, escape rune) bool {
+	return likeMatch(c, pattern, input, escape)
+}
+
+func (c *Collation_8bit_bin) Min(a, b []byte) []byte {
+	return collationMin(c, a, b)
+}
+
+func (c *Collation_8bit_bin) Max(a, b []byte) []byte {
+	return collationMax(c, a, b)
+}
+
+func (c *Collation_8bit_bin) TrimLeft(src, cutset []byte) []byte {
+	return collationTrimLeft(c, src, cutset)
+}
+
+func (c *Collation_8bit_bin) TrimRight(src, cutset []byte) []byte {
+	return collationTrimRight(c, src, cutset)
+}
+
+func (c *Collation_8bit_bin) Trim(src, cutset []byte) []byte {
+	return collationTrim(c, src, cutset)
+}
+
+func (c *Collation_8bit_bin) Index(haystack, needle []byte) int {
+	return collationIndex(c, haystack, needle)
+}
+
+func (c *Collation_8bit_bin) Upper(dst, src []byte) []byte {
+	return collationUpper(c, dst, src)
+}
+
+func (c *Collation_8bit_bin) Lower(dst, src []byte) []byte {
+	return collationLower(c, dst, src)
+}
+
+func (c *Collation_8bit_bin) Equal(left, right []byte) bool {
+	return c.Collate(left, right, false) == 0
+}
+
+func (c *Collation_8bit_bin) codepointWeight(r rune) (uint32, bool) {
+	return encodedWeight(c.charset, r)
+}
+
 type Collation_8bit_simple_ci struct {
 	id   ID
 	name string
@@ -111,6 +186,26 @@ func (c *Collation_8bit_simple_ci) IsBinary() bool {
 	return false
 }
 
+func (c *Collation_8bit_simple_ci) Capabilities() CollationCaps {
+	return CollationCaps{
+		CaseSensitive:   c.sort['a'] != c.sort['A'],
+		AccentSensitive: accentSensitive8bit(c.charset, c.sort),
+		PadSpace:        true,
+		Levels:          1,
+	}
+}
+
+// accentSensitive8bit returns whether the given sort order for an 8-bit charset tells
+// accented letters apart from unaccented ones. Charsets that cannot encode the accented
+// letter have nothing to be insensitive to, so they're always accent sensitive.
+func accentSensitive8bit(cs charset.Charset, sortOrder []byte) bool {
+	var buf [4]byte
+	if cs.EncodeRune(buf[:], 'é') != 1 {
+		return true
+	}
+	return sortOrder['e'] != sortOrder[buf[0]]
+}
+
 func (c *Collation_8bit_simple_ci) Collate(left, right []byte, rightIsPrefix bool) int {
 	sortOrder := c.sort
 	cmpLen := minInt(len(left), len(right))
@@ -123,8 +218,11 @@ func (c *Collation_8bit_simple_ci) Collate(left, right []byte, rightIsPrefix boo
 	}
 	if rightIsPrefix {
 		left = left[:cmpLen]
+		return len(left) - len(right)
 	}
-	return len(left) - len(right)
+	return collatePadSpace(left[cmpLen:], right[cmpLen:], func(rest []byte) int {
+		return padSpaceBytes(rest, func(b byte) byte { return sortOrder[b] })
+	})
 }
 
 func (c *Collation_8bit_simple_ci) WeightString(dst, src []byte, numCodepoints int) []byte {
@@ -151,6 +249,72 @@ func (c *Collation_8bit_simple_ci) WeightStringLen(numBytes int) int {
 	return numBytes
 }
 
+func (c *Collation_8bit_simple_ci) Hash(src []byte, numCodepoints int) uint64 {
+	sortOrder := c.sort[:256]
+	limit, pad := hashLimit(src, numCodepoints)
+	if !pad {
+		// trailing spaces, and anything that sorts like them, are not significant when comparing
+		for len(src) > 0 && sortOrder[src[len(src)-1]] == sortOrder[' '] {
+			src = src[:len(src)-1]
+		}
+	}
+	copyCodepoints := minInt(len(src), limit)
+
+	h := newWeightHasher()
+	for _, ch := range src[:copyCodepoints] {
+		h.writeByte(sortOrder[ch])
+	}
+	if pad {
+		h.pad(hashPadding8, limit-copyCodepoints)
+	}
+	return h.sum64()
+}
+
+func (c *Collation_8bit_simple_ci) Like(pattern, input []byte, escape rune) bool {
+	return likeMatch(c, pattern, input, escape)
+}
+
+func (c *Collation_8bit_simple_ci) Min(a, b []byte) []byte {
+	return collationMin(c, a, b)
+}
+
+func (c *Collation_8bit_simple_ci) Max(a, b []byte) []byte {
+	return collationMax(c, a, b)
+}
+
+func (c *Collation_8bit_simple_ci) TrimLeft(src, cutset []byte) []byte {
+	return collationTrimLeft(c, src, cutset)
+}
+
+func (c *Collation_8bit_simple_ci) TrimRight(src, cutset []byte) []byte {
+	return collationTrimRight(c, src, cutset)
+}
+
+func (c *Collation_8bit_simple_ci) Trim(src, cutset []byte) []byte {
+	return collationTrim(c, src, cutset)
+}
+
+func (c *Collation_8bit_simple_ci) Index(haystack, needle []byte) int {
+	return collationIndex(c, haystack, needle)
+}
+
+func (c *Collation_8bit_simple_ci) Upper(dst, src []byte) []byte {
+	return collationUpper(c, dst, src)
+}
+
+func (c *Collation_8bit_simple_ci) Lower(dst, src []byte) []byte {
+	return collationLower(c, dst, src)
+}
+
+func (c *Collation_8bit_simple_ci) Equal(left, right []byte) bool {
+	return c.Collate(left, right, false) == 0
+}
+
+func (c *Collation_8bit_simple_ci) codepointWeight(r rune) (uint32, bool) {
+	weight, ok := encodedWeight(c.charset, r)
+	return uint32(c.sort[weight>>24]), ok
+}
+
 func weightStringPadingSimple(padChar byte, dst []byte, numCodepoints int, padToMax bool) []byte {
 	if padToMax {
 		for len(dst) < cap(dst) {
@@ -185,6 +349,10 @@ func (c *Collation_binary) IsBinary() bool {
 	return true
 }
 
+func (c *Collation_binary) Capabilities() CollationCaps {
+	return CollationCaps{CaseSensitive: true, AccentSensitive: true, Binary: true, Levels: 1}
+}
+
 func (c *Collation_binary) Collate(left, right []byte, isPrefix bool) int {
 	return collationBinary(left, right, isPrefix)
 }
@@ -213,3 +381,52 @@ func (c *Collation_binary) WeightString(dst, src []byte, numCodepoints int) []by
 func (c *Collation_binary) WeightStringLen(numBytes int) int {
 	return numBytes
 }
+
+func (c *Collation_binary) Hash(src []byte, numCodepoints int) uint64 {
+	// binary weight strings are truncated, but never padded
+	limit, _ := hashLimit(src, numCodepoints)
+
+	h := newWeightHasher()
+	h.write(src[:minInt(len(src), limit)])
+	return h.sum64()
+}
+
+func (c *Collation_binary) Like(pattern, input []byte, escape rune) bool {
+	return likeMatch(c, pattern, input, escape)
+}
+
+func (c *Collation_binary) Min(a, b []byte) []byte {
+	return binaryMin(a, b)
+}
+
+func (c *Collation_binary) Max(a, b []byte) []byte {
+	return binaryMax(a, b)
+}
+
+func (c *Collation_binary) TrimLeft(src, cutset []byte) []byte {
+	return collationTrimLeft(c, src, cutset)
+}
+
+func (c *Collation_binary) TrimRight(src, cutset []byte) []byte {
+	return collationTrimRight(c, src, cutset)
+}
+
+func (c *Collation_binary) Trim(src, cutset []byte) []byte {
+	return collationTrim(c, src, cutset)
+}
+
+func (c *Collation_binary) Index(haystack, needle []byte) int {
+	return bytes.Index(haystack, needle)
+}
+
+func (c *Collation_binary) Upper(dst, src []byte) []byte {
+	return append(dst, src...)
+}
+
+func (c *Collation_binary) Lower(dst, src []byte) []byte {
+	return append(dst, src...)
+}
+
+func (c *Collation_binary) Equal(left, right []byte) bool {
+	return bytes.Equal(left, right)
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collations
+
+import (
+	"strings"
+	"unicode"
+
+	"vitess.io/vitess/go/mysql/collations/internal/charset"
+)
+
+// isTurkicCollation returns whether the collation with the given name is tailored for
+// Turkish or Azerbaijani, whose casing maps 'i' to the dotted 'İ' and 'I' to the dotless 'ı'
+func isTurkicCollation(name string) bool {
+	return strings.Contains(name, "_turkish_") ||
+		strings.HasPrefix(name, "utf8mb4_tr_") || strings.HasPrefix(name, "utf8mb4_az_")
+}
+
+// collationUpper implements Upper for any collation: see collationChangeCase
+func collationUpper(coll Collation, dst, src []byte) []byte {
+	if isTurkicCollation(coll.Name()) {
+		return collationChangeCase(coll.Charset(), dst, src, unicode.TurkishCase.ToUpper)
+	}
+	return collationChangeCase(coll.Charset(), dst, src, unicode.ToUpper)
+}
+
+// collationLower implements Lower for any collation: see collationChangeCase
+func collationLower(coll Collation, dst, src []byte) []byte {
+	if isTurkicCollation(coll.Name()) {
+		return collationChangeCase(coll.Charset(), dst, src, unicode.TurkishCase.ToLower)
+	}
+	return collationChangeCase(coll.Charset(), dst, src, unicode.ToLower)
+}
+
+// collationChangeCase appends `src` to `dst` with every codepoint replaced by the result
+// of `mapping`, using simple one-to-one case mappings like MySQL does, so "ß" is never
+// expanded into "SS". A codepoint whose mapping cannot be represented in the charset of
+// `src` is kept as-is, so that the casing tables for the charsets that are not Unicode
+// are derived from the codepoints they can represent. Invalid sequences are copied
+// without changes.
+func collationChangeCase(cs charset.Charset, dst, src []byte, mapping func(rune) rune) []byte {
+	var buf [8]byte
+	for len(src) > 0 {
+		// invalid sequences decode as RuneError, which is never mapped
+		r, _ := cs.DecodeRune(src)
+		width := nextCodepointWidth(cs, src)
+		if mapped := mapping(r); mapped != r {
+			n := cs.EncodeRune(buf[:], mapped)
+			// some charsets encode several codepoints the same way, so the mapped
+			// codepoint must survive a round trip to be part of the charset
+			if n > 0 && n <= len(buf) {
+				if decoded, _ := cs.DecodeRune(buf[:n]); decoded == mapped {
+					dst = append(dst, buf[:n]...)
+					src = src[width:]
+					continue
+				}
+			}
+		}
+		dst = append(dst, src[:width]...)
+		src = src[width:]
+	}
+	return dst
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collations
+
+import (
+	"testing"
+
+	"vitess.io/vitess/go/mysql/collations/internal/charset"
+)
+
+func TestUpperLower(t *testing.T) {
+	var cases = []struct {
+		collation    string
+		src          string
+		upper, lower string
+	}{
+		{"utf8mb4_0900_ai_ci", "Hello, World", "HELLO, WORLD", "hello, world"},
+		{"utf8mb4_0900_ai_ci", "Ça va très bien", "ÇA VA TRÈS BIEN", "ça va très bien"},
+		// simple case mappings never expand
+		{"utf8mb4_0900_ai_ci", "straße", "STRAßE", "straße"},
+		{"utf8mb4_0900_ai_ci", "İstanbul", "İSTANBUL", "istanbul"},
+		{"utf8mb4_general_ci", "Iıiİ", "IIIİ", "iıii"},
+		{"utf8mb4_turkish_ci", "Iıiİ", "IIİİ", "ııii"},
+		{"utf8mb4_turkish_ci", "istanbul", "İSTANBUL", "istanbul"},
+		{"utf8mb4_tr_0900_ai_ci", "Isparta", "ISPARTA", "ısparta"},
+		{"utf8mb4_0900_as_cs", "Iıiİ", "IIIİ", "iıii"},
+		{"utf8mb4_bin", "😀 Ωμέγα", "😀 ΩΜΈΓΑ", "😀 ωμέγα"},
+		{"latin1_swedish_ci", "caf\xe9", "CAF\xc9", "caf\xe9"},
+		// latin5 has both the dotted and the dotless I
+		{"latin5_turkish_ci", "Ii\xdd\xfd", "I\xdd\xddI", "\xfdii\xfd"},
+		{"utf8_turkish_ci", "iI", "İI", "iı"},
+		{"utf16_general_ci", "\x00a\x00B", "\x00A\x00B", "\x00a\x00b"},
+		{"sjis_japanese_ci", "a\x83\x41b", "A\x83\x41B", "a\x83\x41b"},
+		// invalid sequences are kept as-is
+		{"utf8mb4_0900_ai_ci", "a\xffb", "A\xffB", "a\xffb"},
+		{"binary", "Hello", "Hello", "Hello"},
+	}
+
+	for _, tc := range cases {
+		coll := testcollation(t, tc.collation)
+		src := []byte(tc.src)
+
+		if got := coll.Upper(nil, src); string(got) != tc.upper {
+			t.Errorf("%s: Upper(%q) = %q (expected %q)", tc.collation, tc.src, got, tc.upper)
+		}
+		if got := coll.Lower(nil, src); string(got) != tc.lower {
+			t.Errorf("%s: Lower(%q) = %q (expected %q)", tc.collation, tc.src, got, tc.lower)
+		}
+		if got := coll.Upper([]byte("prefix"), src); string(got) != "prefix"+tc.upper {
+			t.Errorf("%s: Upper(%q) does not append to dst: %q", tc.collation, tc.src, got)
+		}
+	}
+}
+
+func TestUpperLowerAllCollations(t *testing.T) {
+	for _, coll := range All() {
+		src, err := charset.ConvertFromUTF8(nil, coll.Charset(), []byte("Hello, World"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		upper, lower := []byte("HELLO, WORLD"), []byte("hello, world")
+		if coll.IsBinary() && coll.Charset().Name() == "binary" {
+			upper, lower = src, src
+		} else {
+			upper, _ = charset.ConvertFromUTF8(nil, coll.Charset(), upper)
+			lower, _ = charset.ConvertFromUTF8(nil, coll.Charset(), lower)
+		}
+
+		if got := coll.Upper(nil, src); string(got) != string(upper) {
+			t.Errorf("%s: Upper(%q) = %q (expected %q)", coll.Name(), src, got, upper)
+		}
+		if got := coll.Lower(nil, src); string(got) != string(lower) {
+			t.Errorf("%s: Lower(%q) = %q (expected %q)", coll.Name(), src, got, lower)
+		}
+	}
+}
@@ -319,3 +319,40 @@ coerceToRight:
 		return trans, right, err
 	}, nil
 }
+
+// Coerce returns the collation that must be used to compare two expressions with
+// the given collations and coercibilities, following MySQL's aggregation rules: the
+// expression with the lowest coercibility wins, and expressions with different charsets
+// can be compared as long as one of them can be converted to the charset of the other.
+// If the two collations cannot be combined, an "Illegal mix of collations" error is
+// returned. Just like in MySQL comparisons, combining two different collations with
+// the same coercibility and charset is also an error, because the result would have
+// a coercibility of CoerceNone.
+//
+// This is a simplified version of MergeCollations for callers that only care about
+// the resulting collation; both expressions are assumed to have a Unicode repertoire.
+func Coerce(left, right Collation, leftCoercibility, rightCoercibility Coercibility) (Collation, error) {
+	leftTyped := &TypedCollation{
+		Collation:    left,
+		Coercibility: leftCoercibility,
+		Repertoire:   RepertoireUnicode,
+	}
+	rightTyped := &TypedCollation{
+		Collation:    right,
+		Coercibility: rightCoercibility,
+		Repertoire:   RepertoireUnicode,
+	}
+
+	merged, _, err := MergeCollations(leftTyped, rightTyped, CoercionOptions{
+		ConvertToSuperset:   true,
+		ConvertWithCoercion: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if merged.Coercibility == CoerceNone {
+		return nil, fmt.Errorf("Illegal mix of collations (%s,%s) and (%s,%s)",
+			left.Name(), leftCoercibility, right.Name(), rightCoercibility)
+	}
+	return merged.Collation, nil
+}
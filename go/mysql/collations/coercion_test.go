@@ -0,0 +1,81 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collations
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCoerce(t *testing.T) {
+	var cases = []struct {
+		left, right             string
+		leftCoerce, rightCoerce Coercibility
+		expected                string
+	}{
+		// the same collation on both sides always wins
+		{"utf8mb4_general_ci", "utf8mb4_general_ci", CoerceImplicit, CoerceImplicit, "utf8mb4_general_ci"},
+		{"utf8mb4_general_ci", "utf8mb4_general_ci", CoerceExplicit, CoerceExplicit, "utf8mb4_general_ci"},
+
+		// EXPLICIT wins over everything else
+		{"utf8mb4_0900_as_cs", "utf8mb4_general_ci", CoerceExplicit, CoerceImplicit, "utf8mb4_0900_as_cs"},
+		{"utf8mb4_general_ci", "utf8mb4_0900_as_cs", CoerceImplicit, CoerceExplicit, "utf8mb4_0900_as_cs"},
+		{"utf8mb4_0900_as_cs", "utf8mb4_general_ci", CoerceExplicit, CoerceCoercible, "utf8mb4_0900_as_cs"},
+		{"utf8mb4_general_ci", "utf8mb4_0900_as_cs", CoerceExplicit, CoerceExplicit, ""},
+
+		// IMPLICIT wins over COERCIBLE
+		{"utf8mb4_general_ci", "utf8mb4_0900_ai_ci", CoerceImplicit, CoerceCoercible, "utf8mb4_general_ci"},
+		{"utf8mb4_0900_ai_ci", "utf8mb4_general_ci", CoerceCoercible, CoerceImplicit, "utf8mb4_general_ci"},
+		{"latin1_swedish_ci", "utf8mb4_0900_ai_ci", CoerceImplicit, CoerceCoercible, "latin1_swedish_ci"},
+
+		// two different IMPLICIT collations can only be combined if one of them is binary
+		{"utf8mb4_general_ci", "utf8mb4_0900_ai_ci", CoerceImplicit, CoerceImplicit, ""},
+		{"utf8mb4_general_ci", "utf8mb4_bin", CoerceImplicit, CoerceImplicit, "utf8mb4_bin"},
+
+		// or if one of their charsets is a superset of the other, even if they're EXPLICIT
+		{"latin1_swedish_ci", "utf8mb4_0900_ai_ci", CoerceImplicit, CoerceImplicit, "utf8mb4_0900_ai_ci"},
+		{"utf8mb4_0900_ai_ci", "latin1_swedish_ci", CoerceImplicit, CoerceImplicit, "utf8mb4_0900_ai_ci"},
+		{"latin1_swedish_ci", "utf8mb4_0900_ai_ci", CoerceExplicit, CoerceExplicit, "utf8mb4_0900_ai_ci"},
+		{"latin1_swedish_ci", "utf8mb4_0900_ai_ci", CoerceExplicit, CoerceImplicit, ""},
+		{"latin1_swedish_ci", "ujis_japanese_ci", CoerceImplicit, CoerceImplicit, ""},
+	}
+
+	for _, tc := range cases {
+		left := testcollation(t, tc.left)
+		right := testcollation(t, tc.right)
+
+		result, err := Coerce(left, right, tc.leftCoerce, tc.rightCoerce)
+		if tc.expected == "" {
+			if err == nil {
+				t.Errorf("Coerce(%s/%s, %s/%s) should fail, got %s",
+					tc.left, tc.leftCoerce, tc.right, tc.rightCoerce, result.Name())
+			} else if !strings.HasPrefix(err.Error(), "Illegal mix of collations") {
+				t.Errorf("Coerce(%s/%s, %s/%s) returned unexpected error: %v",
+					tc.left, tc.leftCoerce, tc.right, tc.rightCoerce, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Coerce(%s/%s, %s/%s) failed: %v", tc.left, tc.leftCoerce, tc.right, tc.rightCoerce, err)
+			continue
+		}
+		if result.Name() != tc.expected {
+			t.Errorf("Coerce(%s/%s, %s/%s) = %s (expected %s)",
+				tc.left, tc.leftCoerce, tc.right, tc.rightCoerce, result.Name(), tc.expected)
+		}
+	}
+}
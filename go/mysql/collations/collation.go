@@ -19,6 +19,10 @@ package collations
 import (
 	"fmt"
 	"math"
+	"reflect"
+	"sort"
+	"sync"
+	"unsafe"
 
 	"vitess.io/vitess/go/mysql/collations/internal/charset"
 )
@@ -26,6 +30,9 @@ import (
 // Generate mysqldata.go from the JSON information dumped from MySQL
 //go:generate go run ./tools/makemysqldata/
 
+// Generate the UCA weight tables in internal/uca from the JSON information dumped from MySQL
+//go:generate go run ./tools/maketables/
+
 // ID is a numeric identifier for a collation. These identifiers are defined by MySQL, not by Vitess.
 type ID uint16
 
@@ -52,8 +59,17 @@ type Collation interface {
 	// being collation-aware.
 	// It returns a numeric value like a normal comparison function: <0 if left < right,
 	// 0 if left == right, >0 if left > right
+	// In PAD SPACE collations (see Capabilities), the shorter string is compared as if it
+	// was padded with spaces, so trailing spaces are not significant unless `isPrefix` is true.
 	Collate(left, right []byte, isPrefix bool) int
 
+	// Equal returns whether `left` and `right` are equal under this collation. This is
+	// equivalent to `Collate(left, right, false) == 0`, but it can be cheaper because
+	// it stops comparing the strings as soon as it finds any difference between them.
+	// Note that strings can be equal under a collation even if they're not byte-wise
+	// equal: e.g. "café" and "cafe" are equal in an accent insensitive collation.
+	Equal(left, right []byte) bool
+
 	// WeightString returns a weight string for the given `src` string. A weight string
 	// is a binary representation of the weights for the given string, that can be
 	// compared byte-wise to return identical results to collating this string.
@@ -74,7 +90,8 @@ type Collation interface {
 	//		weight string will be padded with the weight for the SPACE character until it becomes
 	//		wide enough to fill the `CHAR` column. This is necessary to perform weight comparisons
 	//		in fixed-`CHAR` columns. If `numCodepoints` is smaller than the actual amount of
-	//		codepoints stored in `src`, the result is unspecified.
+	//		codepoints stored in `src`, `src` is truncated to its first `numCodepoints` codepoints,
+	//		like `WEIGHT_STRING(src AS CHAR(numCodepoints))` does in MySQL.
 	//
 	//		- if `numCodepoints` is zero, this is equivalent to `numCodepoints = RuneCount(src)`,
 	//		meaning that the resulting weight string will have no padding at the end: it'll only have
@@ -91,14 +108,18 @@ type Collation interface {
 	//	- For collations that have NO PAD (this is, the newly introduced UCA v9.0.0 utf8mb4 collations
 	//	in MySQL 8.0), `numCodepoints` can only have the special constant `PadToMax`, which will make
 	//	the weight string padding equivalent to a PAD SPACE collation (as explained in the previous
-	//	section). All other values for `numCodepoints` are ignored, because NO PAD collations always
-	//	return the weights for the codepoints in their strings, with no further padding at the end.
+	//	section). Any other positive value for `numCodepoints` truncates `src` to its first
+	//	`numCodepoints` codepoints, but the weight string is never padded, because NO PAD collations
+	//	always return the weights for the codepoints in their strings, with no further padding at
+	//	the end.
 	//
 	// The resulting weight string is written to `dst`, which can be pre-allocated to
 	// WeightStringLen() bytes to prevent growing the slice. `dst` can also be nil, in which
 	// case it will grow dynamically. If `numCodepoints` has the special PadToMax value explained
 	// earlier, `dst` MUST be pre-allocated to the target size or the function will return an
-	// empty slice.
+	// empty slice. Note that PadToMax writes over the whole capacity of `dst`, including any
+	// bytes past `len(dst)` that the caller may still be using, e.g. in a pooled buffer; use
+	// WeightStringPadded or WeightStringAppend when the capacity of `dst` is not exact.
 	WeightString(dst, src []byte, numCodepoints int) []byte
 
 	// WeightStringLen returns a size (in bytes) that would fit any weight strings for a string
@@ -107,13 +128,100 @@ type Collation interface {
 	// returned value.
 	WeightStringLen(numCodepoints int) int
 
+	// Hash returns a 64-bit hash of the weight string that WeightString would generate
+	// for `src` with the given `numCodepoints`, without allocating the weight string.
+	// Since equal strings under a collation have the same weight string, `Collate(a, b, false) == 0`
+	// always implies that `Hash(a) == Hash(b)`. Because there's no destination slice
+	// to fill, PadToMax is handled the same as 0, and in PAD SPACE collations, trailing
+	// spaces are not hashed unless the string is padded to `numCodepoints`. The hash is
+	// stable across processes, so it can be persisted, but hashes from different collations
+	// must not be compared.
+	Hash(src []byte, numCodepoints int) uint64
+
+	// Like returns whether `input` matches the SQL LIKE `pattern`. Both strings must
+	// be encoded in this collation's charset. The `%` and `_` wildcards match any sequence
+	// of codepoints and exactly one codepoint respectively, while every other codepoint
+	// in the pattern must be equal to the corresponding codepoint in the input under this
+	// collation: e.g. in an accent insensitive collation, "é" in the input matches "e"
+	// in the pattern. Codepoints in the pattern that follow the `escape` codepoint are
+	// matched literally, even if they're wildcards; pass 0 as `escape` to disable escaping.
+	Like(pattern, input []byte, escape rune) bool
+
+	// Min returns the smaller of `a` and `b` when compared with this collation, as
+	// the MIN() aggregation would. If both strings are equal under the collation (which
+	// they can be even if they're not byte-wise equal), `a` is returned.
+	Min(a, b []byte) []byte
+
+	// Max returns the larger of `a` and `b` when compared with this collation, as
+	// the MAX() aggregation would. If both strings are equal under the collation, `a`
+	// is returned.
+	Max(a, b []byte) []byte
+
+	// TrimLeft returns the subslice of `src` without its leading codepoints that are equal
+	// under this collation to any of the codepoints in `cutset`: e.g. in an accent insensitive
+	// collation, "é" is trimmed if `cutset` contains "e". Just like in Like, codepoints are
+	// compared one at a time, and codepoints are never split. If `cutset` is empty, the
+	// padding character of the collation (a space) is trimmed instead.
+	TrimLeft(src, cutset []byte) []byte
+
+	// TrimRight returns the subslice of `src` without its trailing codepoints that are equal
+	// under this collation to any of the codepoints in `cutset`, like TrimLeft does.
+	TrimRight(src, cutset []byte) []byte
+
+	// Trim returns the subslice of `src` without its leading and trailing codepoints that
+	// are equal under this collation to any of the codepoints in `cutset`, like TrimLeft does.
+	Trim(src, cutset []byte) []byte
+
+	// Index returns the byte offset of the first occurrence of `needle` in `haystack` under
+	// this collation, or -1 if `needle` cannot be found, with the same semantics as the
+	// INSTR and LOCATE functions in MySQL: the occurrence always starts at a codepoint
+	// boundary and is exactly as long as `needle`, but it can differ from it byte-wise,
+	// e.g. "ß" is found at offset 4 in "strasse" in utf8mb4_0900_ai_ci. An empty `needle`
+	// is always found at offset 0.
+	Index(haystack, needle []byte) int
+
+	// Upper appends to `dst` the result of UPPER(`src`) in this collation and returns the
+	// extended slice. Like in MySQL, codepoints are mapped one-to-one with the simple Unicode
+	// case mappings, tailored for the language of the collation where needed: in the Turkish
+	// collations, 'i' is uppercased to the dotted 'İ'. The mappings that cannot be represented
+	// in the charset of the collation are not applied, and binary strings are never changed.
+	Upper(dst, src []byte) []byte
+
+	// Lower appends to `dst` the result of LOWER(`src`) in this collation and returns the
+	// extended slice, following the same rules as Upper: in the Turkish collations,
+	// 'I' is lowercased to the dotless 'ı'.
+	Lower(dst, src []byte) []byte
+
 	// Charset returns the Charset with which this collation is encoded
 	Charset() charset.Charset
 
 	// IsBinary returns whether this collation is a binary collation
 	IsBinary() bool
+
+	// Capabilities describes how this collation compares strings, so that callers can
+	// reason about comparisons under the collation without relying on its name
+	Capabilities() CollationCaps
+}
+
+// CollationCaps describes the comparison semantics of a Collation
+type CollationCaps struct {
+	// CaseSensitive is true if strings that only differ in their case are not equal
+	CaseSensitive bool
+	// AccentSensitive is true if strings that only differ in their accents are not equal
+	AccentSensitive bool
+	// Binary is true if the collation is a binary collation, as returned by IsBinary
+	Binary bool
+	// PadSpace is true if the collation compares strings as if the shorter one was padded
+	// with spaces, and false if the collation has NO PAD
+	PadSpace bool
+	// Levels is the amount of weight levels that are compared by the collation
+	Levels int
 }
 
+// PadToMax is the special value for `numCodepoints` in WeightString that pads the weight
+// string until it fills the whole capacity of the destination slice. Since the amount of
+// padding depends on `cap(dst)`, which can be larger than requested when the slice comes
+// from `append` or a pool, WeightStringPadded is the safer alternative.
 const PadToMax = math.MaxInt32
 
 func minInt(i1, i2 int) int {
@@ -123,52 +231,217 @@ func minInt(i1, i2 int) int {
 	return i2
 }
 
+// CollateString compares two Go strings using the given collation. It is equivalent
+// to `coll.Collate([]byte(left), []byte(right), rightIsPrefix)`, but the strings
+// are never copied, so it does not allocate when e.g. sorting a []string.
+func CollateString(coll Collation, left, right string, rightIsPrefix bool) int {
+	if _, ok := coll.(*Collation_binary); ok {
+		return collationBinaryString(left, right, rightIsPrefix)
+	}
+	return coll.Collate(stringBytes(left), stringBytes(right), rightIsPrefix)
+}
+
+// WeightStringPadded appends the weight string for `src` to `dst`, padded with the weights
+// of the collation's padding character until the appended weight string is exactly
+// `padToBytes` bytes long, as required for the fixed-size keys of a filesort. It is
+// equivalent to WeightString with PadToMax, but the padding never depends on the
+// capacity of `dst`. If the weight string is longer than `padToBytes`, it is not truncated.
+func WeightStringPadded(coll Collation, dst, src []byte, padToBytes int) []byte {
+	start := len(dst)
+	if cap(dst)-start < padToBytes {
+		grown := make([]byte, start, start+padToBytes)
+		copy(grown, dst)
+		dst = grown
+	}
+	return coll.WeightString(dst[:start:start+padToBytes], src, PadToMax)
+}
+
+// WeightStringAppend appends the weight string for `src` to `dst` and returns the extended
+// slice, like the built-in append: only the bytes of the weight string are appended, and
+// the slice is only reallocated if they don't fit in `cap(dst)`. Since the capacity of `dst`
+// is never used to decide the size of the weight string, PadToMax is handled just like 0,
+// so `dst` can safely be a slice into a larger buffer that is being reused. Any other
+// `numCodepoints` is passed to WeightString as-is.
+func WeightStringAppend(coll Collation, dst, src []byte, numCodepoints int) []byte {
+	if numCodepoints == PadToMax {
+		numCodepoints = 0
+	}
+	return coll.WeightString(dst, src, numCodepoints)
+}
+
+// WeightStringPooled returns the weight string for `src`, written into a buffer taken from
+// `pool` instead of a newly allocated one. The pool must only hold *[]byte values; if it is
+// empty, a new buffer is allocated. The returned buffer holds the weight string and belongs
+// to the caller until it is put back into the pool with `pool.Put`. Buffers are grown as
+// needed and keep their capacity across uses, so once the pool is warm, computing a weight
+// string with the collations that have a fast path never allocates.
+// Since the capacity of a pooled buffer is arbitrary, PadToMax is handled just like in
+// WeightStringAppend.
+func WeightStringPooled(coll Collation, pool *sync.Pool, src []byte, numCodepoints int) *[]byte {
+	buf, _ := pool.Get().(*[]byte)
+	if buf == nil {
+		buf = new([]byte)
+	}
+	*buf = WeightStringAppend(coll, (*buf)[:0], src, numCodepoints)
+	return buf
+}
+
+// WeightStringLenExact returns the exact size (in bytes) of the weight string that WeightString
+// generates for `src` with the given `numCodepoints`, unlike WeightStringLen, which is an upper
+// bound for any string of a given size. A `dst` allocated with exactly this capacity never has
+// to grow. Like in WeightStringAppend, PadToMax is handled just like 0. The size for the binary
+// and single-byte collations is computed from the length of `src`, and the UCA collations count
+// the weights that their iterators yield for `src` without building the weight string, so the
+// expansions and contractions in the input are accounted for. For the rest of the collations,
+// the weight string is built in a pooled buffer and its length is returned.
+func WeightStringLenExact(coll Collation, src []byte, numCodepoints int) int {
+	if numCodepoints == PadToMax {
+		numCodepoints = 0
+	}
+
+	switch coll := coll.(type) {
+	case *Collation_binary:
+		// binary weight strings are truncated, but never padded
+		if numCodepoints > 0 {
+			return minInt(len(src), numCodepoints)
+		}
+		return len(src)
+	case *Collation_8bit_bin, *Collation_8bit_simple_ci:
+		// one weight per byte, truncated or padded to `numCodepoints`
+		if numCodepoints > 0 {
+			return numCodepoints
+		}
+		return len(src)
+	case *Collation_utf8mb4_0900_bin:
+		if numCodepoints > 0 {
+			src = charset.Truncate(coll.Charset(), src, numCodepoints)
+		}
+		return len(src)
+	case *Collation_utf8mb4_uca_0900:
+		return coll.weightStringLenExact(src, numCodepoints)
+	case *Collation_uca_legacy:
+		return coll.weightStringLenExact(src, numCodepoints)
+	}
+
+	buf := WeightStringPooled(coll, &weightStringLenPool, src, numCodepoints)
+	n := len(*buf)
+	weightStringLenPool.Put(buf)
+	return n
+}
+
+// weightStringLenPool holds the buffers for the weight strings that are built by
+// WeightStringLenExact only to measure them
+var weightStringLenPool sync.Pool
+
+// stringBytes returns the underlying bytes for a string without copying them.
+// This is only safe because none of the collation APIs modify their input.
+func stringBytes(s string) []byte {
+	var b []byte
+	hdr := (*reflect.SliceHeader)(unsafe.Pointer(&b))
+	hdr.Data = (*reflect.StringHeader)(unsafe.Pointer(&s)).Data
+	hdr.Cap = len(s)
+	hdr.Len = len(s)
+	return b
+}
+
+// bytesString returns a string that shares the underlying bytes of a slice without
+// copying them. The string must not outlive any modification of the slice, so it can
+// only be used for lookups.
+func bytesString(b []byte) string {
+	var s string
+	hdr := (*reflect.StringHeader)(unsafe.Pointer(&s))
+	hdr.Data = (*reflect.SliceHeader)(unsafe.Pointer(&b)).Data
+	hdr.Len = len(b)
+	return s
+}
+
 var collationsByName = make(map[string]Collation)
 var collationsById = make(map[ID]Collation)
 var binaryCollationByCharset = make(map[string]Collation)
 var defaultCollationByCharset = make(map[string]Collation)
 
-func register(c Collation, isDefault bool) {
-	duplicatedCharset := func(old Collation) {
-		panic(fmt.Sprintf("duplicated collation: %s[%d] (existing collation is %s[%d])",
+// Register adds a collation to the ones supported by this package, so that it can be
+// looked up by its name and ID with FromName, LookupByName, FromID, LookupByID and the
+// Environment of any MySQL version, just like the built-in collations. If `isDefault` is
+// true, the collation becomes the default collation for its charset. An error is returned,
+// and the collation is not registered, if its name or ID are already used by another
+// collation, or if it would become the second default or binary collation of its charset.
+//
+// Since the Collation interface has unexported methods, external collations must embed
+// one of the collation implementations in this package. The lookup tables are not
+// synchronized, so Register must be called before the first lookup of any collation,
+// i.e. from the init function of a package, and never concurrently with a lookup.
+func Register(c Collation, isDefault bool) error {
+	if c.ID() == Unknown || c.Name() == "" {
+		return fmt.Errorf("invalid collation: %q[%d]", c.Name(), c.ID())
+	}
+
+	duplicatedCharset := func(old Collation) error {
+		return fmt.Errorf("duplicated collation: %s[%d] (existing collation is %s[%d])",
 			c.Name(), c.ID(), old.Name(), old.ID(),
-		))
+		)
 	}
 	if old, found := collationsByName[c.Name()]; found {
-		duplicatedCharset(old)
+		return duplicatedCharset(old)
 	}
 	if old, found := collationsById[c.ID()]; found {
-		duplicatedCharset(old)
+		return duplicatedCharset(old)
 	}
-	collationsByName[c.Name()] = c
-	collationsById[c.ID()] = c
 
 	csname := c.Charset().Name()
-	if c.IsBinary() && c.Name() != "utf8mb4_bin" {
-		if old, found := binaryCollationByCharset[csname]; found {
-			panic(fmt.Sprintf("charset %s has more than one binary collation: %s and %s",
-				csname, c.Name(), old.Name(),
-			))
-		}
+	isBinary := c.IsBinary() && c.Name() != "utf8mb4_bin"
+	if old, found := binaryCollationByCharset[csname]; found && isBinary {
+		return fmt.Errorf("charset %s has more than one binary collation: %s and %s",
+			csname, c.Name(), old.Name(),
+		)
+	}
+	if old, found := defaultCollationByCharset[csname]; found && isDefault {
+		return fmt.Errorf("charset %s has more than one default collation: %s and %s",
+			csname, c.Name(), old.Name(),
+		)
+	}
+
+	collationsByName[c.Name()] = c
+	collationsById[c.ID()] = c
+	charset.Register(c.Charset())
+	if isBinary {
 		binaryCollationByCharset[csname] = c
 	}
 	if isDefault {
-		if old, found := defaultCollationByCharset[csname]; found {
-			panic(fmt.Sprintf("charset %s has more than one default collation: %s and %s",
-				csname, c.Name(), old.Name(),
-			))
-		}
+		defaultCollationByCharset[csname] = c
+	}
+	return nil
+}
+
+// register adds one of the built-in collations, which can never conflict with each other
+func register(c Collation, isDefault bool) {
+	if err := Register(c, isDefault); err != nil {
+		panic(err)
 	}
 }
 
-// FromName returns the collation with the given name. The collation
-// is initialized if it's the first time being accessed.
+// FromName returns the collation with the given name, or nil if the collation
+// is not supported. The collation is initialized if it's the first time being accessed.
 func FromName(name string) Collation {
+	coll, _ := LookupByName(name)
+	return coll
+}
+
+// LookupByName returns the collation with the given name. If the collation is not
+// supported, the returned error describes whether the collation is known to MySQL
+// but not supported by this package, or whether it's entirely unknown, in which case
+// the error lists the supported collations with the closest names.
+// The collation is initialized if it's the first time being accessed.
+func LookupByName(name string) (Collation, error) {
 	coll := collationsByName[name]
-	if coll != nil {
-		coll.init()
+	if coll == nil {
+		if _, known := collationsUnsupportedByName[name]; known {
+			return nil, fmt.Errorf("collation %q is not supported", name)
+		}
+		return nil, &unknownCollationError{name: name}
 	}
-	return coll
+	coll.init()
+	return coll, nil
 }
 
 // IDFromName returns the collation ID for the given name, and whether
@@ -183,14 +456,53 @@ func IDFromName(name string) (ID, bool) {
 	return Unknown, false
 }
 
-// FromID returns the collation with the given numerical identifier. The collation
-// is initialized if it's the first time being accessed.
+// FromID returns the collation with the given numerical identifier, or nil if the
+// collation is not supported. The collation is initialized if it's the first time being accessed.
 func FromID(id ID) Collation {
+	coll, _ := LookupByID(id)
+	return coll
+}
+
+// FromHandshake returns the default collation advertised by a MySQL server in its
+// initial handshake packet, or nil if the collation is not supported.
+// The handshake only has room for the lower 8 bits of the collation ID, so a server
+// whose default collation has an ID above 255 (e.g. utf8mb4_0900_as_cs, with ID 278)
+// advertises a truncated ID that belongs to an unrelated collation. Use
+// FromHandshakeExtended if the full ID of the collation is known.
+func FromHandshake(collationID uint8) Collation {
+	return FromHandshakeExtended(collationID, 0)
+}
+
+// FromHandshakeExtended returns the collation advertised in the initial handshake packet of
+// a MySQL server, like FromHandshake, but it takes into account the full ID of the collation
+// if it has been received separately, e.g. from `@@collation_server` or from the 16-bit
+// character set in column definitions. The extended ID is only used if its lower 8 bits
+// match the ID in the handshake; it can be 0 if it's not known.
+func FromHandshakeExtended(collationID uint8, extendedID uint16) Collation {
+	id := ID(collationID)
+	if extendedID > 0xff && uint8(extendedID) == collationID {
+		id = ID(extendedID)
+	}
+	return FromID(id)
+}
+
+// LookupByID returns the collation with the given numerical identifier, as sent by
+// MySQL in the wire protocol and in the column metadata of result sets. If the collation
+// is not supported, the returned error describes whether the ID belongs to a collation
+// that is known to MySQL but not supported by this package, or whether it's entirely unknown.
+// The collation is initialized if it's the first time being accessed.
+func LookupByID(id ID) (Collation, error) {
 	coll := collationsById[id]
-	if coll != nil {
-		coll.init()
+	if coll == nil {
+		for name, unsupported := range collationsUnsupportedByName {
+			if unsupported == id {
+				return nil, fmt.Errorf("collation %q (%d) is not supported", name, id)
+			}
+		}
+		return nil, fmt.Errorf("unknown collation ID %d", id)
 	}
-	return coll
+	coll.init()
+	return coll, nil
 }
 
 // DefaultForCharset returns the default collation for a charset
@@ -202,14 +514,43 @@ func DefaultForCharset(charset string) Collation {
 	return coll
 }
 
-// All returns a slice with all known collations in Vitess. This is an expensive call because
-// it will initialize the internal state of all the collations before returning them.
+// IsDefaultForCharset returns whether the given collation is the default collation for its
+// charset in the newest supported version of MySQL, as shown in the Default column of
+// `SHOW COLLATION`. Use Environment.IsDefaultForCharset for a specific MySQL version.
+func IsDefaultForCharset(coll Collation) bool {
+	return coll != nil && defaultCollationByCharset[coll.Charset().Name()] == coll
+}
+
+// DefaultCollationForCharset returns the default collation for the given Charset in
+// the newest supported version of MySQL. Use Environment.DefaultCollationForCharset
+// to get the default collation for a specific MySQL version.
+func DefaultCollationForCharset(cs charset.Charset) Collation {
+	return DefaultForCharset(cs.Name())
+}
+
+// All returns a slice with all known collations in Vitess, sorted by their ID. This is an
+// expensive call because it will initialize the internal state of all the collations before
+// returning them.
 // Used for testing/debugging.
 func All() (all []Collation) {
+	all = AllUninitialized()
+	for _, col := range all {
+		col.init()
+	}
+	return
+}
+
+// AllUninitialized returns a slice with all known collations in Vitess, sorted by their ID,
+// without initializing them. This is much cheaper than All, but the returned collations can
+// only be used to access their metadata with ID, Name, Charset and IsBinary. Any other methods
+// may panic unless the collation has been initialized by looking it up with FromID or FromName.
+func AllUninitialized() (all []Collation) {
 	all = make([]Collation, 0, len(collationsById))
 	for _, col := range collationsById {
-		col.init()
 		all = append(all, col)
 	}
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].ID() < all[j].ID()
+	})
 	return
 }
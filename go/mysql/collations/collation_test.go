@@ -0,0 +1,789 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collations
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+
+	"vitess.io/vitess/go/mysql/collations/internal/charset"
+)
+
+func TestLookupByName(t *testing.T) {
+	coll, err := LookupByName("utf8mb4_0900_ai_ci")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if coll.Name() != "utf8mb4_0900_ai_ci" {
+		t.Fatalf("LookupByName returned %s", coll.Name())
+	}
+	if FromName("utf8mb4_0900_ai_ci") != coll {
+		t.Fatalf("FromName returned a different collation than LookupByName")
+	}
+
+	var cases = []struct {
+		name        string
+		suggestions []string
+	}{
+		{"utf8mb4_generl_ci", []string{"utf8mb4_general_ci"}},
+		{"utf8mb3_general_ci", []string{"utf8_general_ci", "utf8mb4_general_ci"}},
+		{"utf8mb4_0900_ai_cs", []string{"utf8mb4_0900_ai_ci", "utf8mb4_0900_as_cs"}},
+		{"latin1_swedish", []string{"latin1_swedish_ci"}},
+		{"not a collation at all", nil},
+	}
+
+	for _, tc := range cases {
+		coll, err := LookupByName(tc.name)
+		if coll != nil || err == nil {
+			t.Errorf("LookupByName(%q) should fail", tc.name)
+			continue
+		}
+		if FromName(tc.name) != nil {
+			t.Errorf("FromName(%q) should return nil", tc.name)
+		}
+
+		msg := err.Error()
+		if !strings.Contains(msg, "unknown collation") {
+			t.Errorf("LookupByName(%q): unexpected error %q", tc.name, msg)
+		}
+		for _, s := range tc.suggestions {
+			if !strings.Contains(msg, s) {
+				t.Errorf("LookupByName(%q): error %q should suggest %s", tc.name, msg, s)
+			}
+		}
+		if tc.suggestions == nil && strings.Contains(msg, "did you mean") {
+			t.Errorf("LookupByName(%q): error %q should not have suggestions", tc.name, msg)
+		}
+	}
+}
+
+func TestLookupByNameUnsupported(t *testing.T) {
+	for name := range collationsUnsupportedByName {
+		_, err := LookupByName(name)
+		if err == nil || !strings.Contains(err.Error(), "not supported") {
+			t.Errorf("LookupByName(%q) should fail as unsupported, got %v", name, err)
+		}
+		break
+	}
+}
+
+func TestLookupByID(t *testing.T) {
+	for _, coll := range AllUninitialized() {
+		found, err := LookupByID(coll.ID())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if found != coll || FromID(coll.ID()) != coll {
+			t.Errorf("LookupByID(%d) returned %s (expected %s)", coll.ID(), found.Name(), coll.Name())
+		}
+	}
+
+	if coll, err := LookupByID(309); err != nil || coll.Name() != "utf8mb4_0900_bin" {
+		t.Errorf("LookupByID(309) = %v, %v", coll, err)
+	}
+	gbk, _ := IDFromName("gbk_chinese_ci")
+	if _, err := LookupByID(gbk); err == nil || !strings.Contains(err.Error(), "not supported") {
+		t.Errorf("LookupByID(%d) should fail as unsupported, got %v", gbk, err)
+	}
+	if _, err := LookupByID(Unknown); err == nil || !strings.Contains(err.Error(), "unknown collation ID") {
+		t.Errorf("LookupByID(Unknown) should fail as unknown, got %v", err)
+	}
+	if FromID(Unknown) != nil {
+		t.Errorf("FromID(Unknown) should return nil")
+	}
+}
+
+func TestFromHandshake(t *testing.T) {
+	var cases = []struct {
+		id       uint8
+		extended uint16
+		expected string
+	}{
+		{33, 0, "utf8_general_ci"},
+		{255, 0, "utf8mb4_0900_ai_ci"},
+		{63, 0, "binary"},
+		// utf8mb4_0900_as_cs (278) is truncated to koi8u_general_ci (22) in the handshake
+		{22, 278, "utf8mb4_0900_as_cs"},
+		{22, 0, "koi8u_general_ci"},
+		// the extended ID doesn't match the handshake, so it's ignored
+		{33, 278, "utf8_general_ci"},
+		{33, 33, "utf8_general_ci"},
+		{0, 0, ""},
+	}
+
+	for _, tc := range cases {
+		coll := FromHandshakeExtended(tc.id, tc.extended)
+		var name string
+		if coll != nil {
+			name = coll.Name()
+		}
+		if name != tc.expected {
+			t.Errorf("FromHandshakeExtended(%d, %d) = %q (expected %q)", tc.id, tc.extended, name, tc.expected)
+		}
+		if tc.extended == 0 && FromHandshake(tc.id) != coll {
+			t.Errorf("FromHandshake(%d) does not match FromHandshakeExtended", tc.id)
+		}
+	}
+}
+
+func TestAllSortedByID(t *testing.T) {
+	uninitialized := AllUninitialized()
+	all := All()
+
+	if len(all) != len(collationsById) || len(uninitialized) != len(all) {
+		t.Fatalf("expected %d collations, got %d (%d uninitialized)", len(collationsById), len(all), len(uninitialized))
+	}
+	for i, coll := range all {
+		if i > 0 && all[i-1].ID() >= coll.ID() {
+			t.Errorf("collations are not sorted by ID: %s (%d) comes after %s (%d)",
+				coll.Name(), coll.ID(), all[i-1].Name(), all[i-1].ID())
+		}
+		if uninitialized[i] != coll {
+			t.Errorf("All and AllUninitialized return different collations at position %d", i)
+		}
+		if coll.Name() == "" || coll.Charset() == nil {
+			t.Errorf("collation %d is missing metadata", coll.ID())
+		}
+	}
+}
+
+func TestDefaultCollationForCharset(t *testing.T) {
+	var cases = []struct {
+		env      *Environment
+		charset  charset.Charset
+		expected string
+	}{
+		{nil, charset.Charset_latin1{}, "latin1_swedish_ci"},
+		{nil, charset.Charset_utf8mb4{}, "utf8mb4_0900_ai_ci"},
+		{nil, charset.Charset_binary{}, "binary"},
+		{testenvironment(t, "5.7.31"), charset.Charset_latin1{}, "latin1_swedish_ci"},
+		{testenvironment(t, "5.7.31"), charset.Charset_utf8mb4{}, "utf8mb4_general_ci"},
+		{testenvironment(t, "8.0.26"), charset.Charset_utf8mb4{}, "utf8mb4_0900_ai_ci"},
+	}
+
+	for _, tc := range cases {
+		var coll Collation
+		if tc.env == nil {
+			coll = DefaultCollationForCharset(tc.charset)
+		} else {
+			coll = tc.env.DefaultCollationForCharset(tc.charset)
+		}
+		if coll == nil || coll.Name() != tc.expected {
+			t.Errorf("DefaultCollationForCharset(%s) = %v (expected %s)", tc.charset.Name(), coll, tc.expected)
+		}
+	}
+
+	for _, coll := range All() {
+		// some charsets have a default collation that is not supported
+		def := DefaultCollationForCharset(coll.Charset())
+		if def != nil && def.Charset().Name() != coll.Charset().Name() {
+			t.Errorf("default collation for %s is %s", coll.Charset().Name(), def.Name())
+		}
+	}
+}
+
+func TestIsDefaultForCharset(t *testing.T) {
+	defaults := make(map[string]string)
+	for _, coll := range All() {
+		if !IsDefaultForCharset(coll) {
+			continue
+		}
+		csname := coll.Charset().Name()
+		if other, found := defaults[csname]; found {
+			t.Errorf("charset %s has two default collations: %s and %s", csname, other, coll.Name())
+		}
+		defaults[csname] = coll.Name()
+		if DefaultForCharset(csname) != coll {
+			t.Errorf("%s is the default for %s, but DefaultForCharset returns %v", coll.Name(), csname, DefaultForCharset(csname))
+		}
+	}
+
+	for _, name := range []string{"utf8mb4_0900_ai_ci", "latin1_swedish_ci", "binary", "utf8_general_ci"} {
+		if !IsDefaultForCharset(FromName(name)) {
+			t.Errorf("%s should be the default collation for its charset", name)
+		}
+	}
+	for _, name := range []string{"utf8mb4_general_ci", "utf8mb4_bin", "latin1_bin"} {
+		if IsDefaultForCharset(FromName(name)) {
+			t.Errorf("%s should not be the default collation for its charset", name)
+		}
+	}
+	if IsDefaultForCharset(nil) {
+		t.Errorf("IsDefaultForCharset(nil) should be false")
+	}
+}
+
+func TestCollateString(t *testing.T) {
+	var inputs = []string{"", "a", "A", "ab", "abc", "abc ", "ABC", "b", "café", "cafe", "Straße", "strasse"}
+
+	for _, coll := range All() {
+		for _, left := range inputs {
+			for _, right := range inputs {
+				for _, prefix := range []bool{false, true} {
+					expected := sign(coll.Collate([]byte(left), []byte(right), prefix))
+					got := sign(CollateString(coll, left, right, prefix))
+					if got != expected {
+						t.Errorf("%s: CollateString(%q, %q, %v) = %d (expected %d)",
+							coll.Name(), left, right, prefix, got, expected)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestCollateStringAllocs(t *testing.T) {
+	for _, name := range []string{"binary", "utf8mb4_bin", "latin1_swedish_ci", "utf8mb4_0900_ai_ci"} {
+		coll := testcollation(t, name)
+		allocs := testing.AllocsPerRun(100, func() {
+			CollateString(coll, "hello world", "Hello World!", false)
+		})
+		if allocs > 0 {
+			t.Errorf("%s: CollateString allocated %.1f times per run", name, allocs)
+		}
+	}
+}
+
+func TestWeightStringPooled(t *testing.T) {
+	var pool sync.Pool
+	for _, name := range []string{"utf8mb4_0900_ai_ci", "utf8mb4_0900_as_cs", "utf8mb4_general_ci", "latin1_swedish_ci"} {
+		coll := testcollation(t, name)
+		for _, input := range []string{"", "hello world", "Chleba カード 😀", strings.Repeat("long input ", 20)} {
+			expected := coll.WeightString(nil, []byte(input), 0)
+			buf := WeightStringPooled(coll, &pool, []byte(input), PadToMax)
+			if !bytes.Equal(*buf, expected) {
+				t.Errorf("%s: WeightStringPooled(%q) = %x (expected %x)", name, input, *buf, expected)
+			}
+			pool.Put(buf)
+		}
+	}
+}
+
+func TestWeightStringPooledAllocs(t *testing.T) {
+	var pool sync.Pool
+	input := []byte("Chleba カード")
+
+	for _, name := range []string{"utf8mb4_0900_ai_ci", "utf8mb4_0900_as_cs"} {
+		coll := testcollation(t, name)
+		pool.Put(WeightStringPooled(coll, &pool, input, 0))
+
+		allocs := testing.AllocsPerRun(100, func() {
+			pool.Put(WeightStringPooled(coll, &pool, input, 0))
+		})
+		if allocs > 0 {
+			t.Errorf("%s: WeightStringPooled allocated %.1f times per run", name, allocs)
+		}
+	}
+}
+
+func BenchmarkWeightStringPooled(b *testing.B) {
+	coll := testcollation(b, "utf8mb4_0900_ai_ci")
+	input := []byte("The quick brown fox jumps over the lazy dog")
+
+	b.Run("WeightString", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = coll.WeightString(nil, input, 0)
+		}
+	})
+
+	b.Run("Pooled", func(b *testing.B) {
+		var pool sync.Pool
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			pool.Put(WeightStringPooled(coll, &pool, input, 0))
+		}
+	})
+}
+
+// externalCollation is a collation defined outside of this package, which can only
+// implement Collation by embedding one of the built-in collations
+type externalCollation struct {
+	Collation
+	id   ID
+	name string
+}
+
+func (c *externalCollation) ID() ID {
+	return c.id
+}
+
+func (c *externalCollation) Name() string {
+	return c.name
+}
+
+func TestRegister(t *testing.T) {
+	base := testcollation(t, "latin1_swedish_ci")
+	external := &externalCollation{Collation: base, id: 1000, name: "latin1_external_ci"}
+
+	if err := Register(external, false); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		delete(collationsByName, external.name)
+		delete(collationsById, external.id)
+	})
+
+	if coll := FromName("latin1_external_ci"); coll != external {
+		t.Fatalf("FromName returned %v", coll)
+	}
+	if coll, err := LookupByID(1000); err != nil || coll != external {
+		t.Fatalf("LookupByID returned %v, %v", coll, err)
+	}
+	if id, ok := IDFromName("latin1_external_ci"); !ok || id != 1000 {
+		t.Fatalf("IDFromName returned %d, %v", id, ok)
+	}
+
+	var conflicts = []struct {
+		coll      Collation
+		isDefault bool
+	}{
+		{&externalCollation{Collation: base, id: 1000, name: "latin1_other_ci"}, false},
+		{&externalCollation{Collation: base, id: 1001, name: "latin1_external_ci"}, false},
+		{&externalCollation{Collation: base, id: 1001, name: "latin1_swedish_ci"}, false},
+		{&externalCollation{Collation: base, id: 8, name: "latin1_other_ci"}, false},
+		{&externalCollation{Collation: base, id: 1001, name: "latin1_other_ci"}, true},
+		{&externalCollation{Collation: testcollation(t, "latin1_bin"), id: 1001, name: "latin1_other_bin"}, false},
+		{&externalCollation{Collation: base, id: Unknown, name: "latin1_other_ci"}, false},
+		{&externalCollation{Collation: base, id: 1001, name: ""}, false},
+	}
+	for _, tc := range conflicts {
+		if err := Register(tc.coll, tc.isDefault); err == nil {
+			t.Errorf("Register(%q[%d], %v) should fail", tc.coll.Name(), tc.coll.ID(), tc.isDefault)
+		}
+	}
+	if FromName("latin1_other_ci") != nil || FromName("latin1_other_bin") != nil || FromID(1001) != nil {
+		t.Errorf("failed registrations should not register the collation")
+	}
+	if DefaultForCharset("latin1").Name() != "latin1_swedish_ci" {
+		t.Errorf("failed registrations should not change the default collation")
+	}
+}
+
+func TestCharsetByName(t *testing.T) {
+	for _, coll := range AllUninitialized() {
+		cs, err := charset.ByName(coll.Charset().Name())
+		if err != nil {
+			t.Errorf("%s: %v", coll.Name(), err)
+			continue
+		}
+		if cs.Name() != coll.Charset().Name() {
+			t.Errorf("%s: charset.ByName(%q) = %s", coll.Name(), coll.Charset().Name(), cs.Name())
+		}
+	}
+}
+
+func TestMaxBytesPerCharFitsEncodings(t *testing.T) {
+	var buf [8]byte
+	for _, coll := range AllUninitialized() {
+		cs := coll.Charset()
+		if _, ok := cs.(charset.Charset_binary); ok {
+			continue
+		}
+		for _, cp := range []rune{'a', 0xE9, 0x65E5, 0xFF76, 0x1F600} {
+			if w := cs.EncodeRune(buf[:], cp); w > cs.MaxBytesPerChar() {
+				t.Errorf("%s: U+%04X takes %d bytes, MaxBytesPerChar() = %d", cs.Name(), cp, w, cs.MaxBytesPerChar())
+			}
+		}
+	}
+}
+
+func TestWeightStringPadded(t *testing.T) {
+	for _, coll := range All() {
+		const padToBytes = 64
+		src := []byte("abc")
+
+		expected := coll.WeightString(make([]byte, 0, padToBytes), src, PadToMax)
+		if len(expected) != padToBytes {
+			t.Fatalf("%s: WeightString with PadToMax returned %d bytes (expected %d)", coll.Name(), len(expected), padToBytes)
+		}
+
+		prefix := []byte("prefix")
+		for _, dst := range [][]byte{nil, make([]byte, 0, 1024), append(make([]byte, 0, 1024), prefix...)} {
+			start := len(dst)
+			got := WeightStringPadded(coll, dst, src, padToBytes)
+			if string(got[:start]) != string(dst) || string(got[start:]) != string(expected) {
+				t.Errorf("%s: WeightStringPadded(cap=%d) = %x (expected %x)", coll.Name(), cap(dst), got[start:], expected)
+			}
+		}
+	}
+}
+
+func TestWeightStringTruncates(t *testing.T) {
+	for _, coll := range All() {
+		long, err := charset.ConvertFromUTF8(nil, coll.Charset(), []byte("abcdef ghi"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		short, err := charset.ConvertFromUTF8(nil, coll.Charset(), []byte("abc"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected := coll.WeightString(nil, short, 3)
+		if got := coll.WeightString(nil, long, 3); string(got) != string(expected) {
+			t.Errorf("%s: WeightString(%q, 3) = %x (expected %x)", coll.Name(), long, got, expected)
+		}
+	}
+}
+
+func TestWeightStringAppend(t *testing.T) {
+	for _, coll := range All() {
+		src := []byte("abc")
+		expected := coll.WeightString(nil, src, 0)
+
+		// a pooled buffer whose tail past `len(dst)` must not be overwritten by padding
+		pool := make([]byte, 1024)
+		for i := range pool {
+			pool[i] = 0xAA
+		}
+		dst := pool[:4]
+		got := WeightStringAppend(coll, dst, src, PadToMax)
+		if string(got[4:]) != string(expected) {
+			t.Errorf("%s: WeightStringAppend = %x (expected %x)", coll.Name(), got[4:], expected)
+		}
+		for i := len(got); i < len(pool); i++ {
+			if pool[i] != 0xAA {
+				t.Fatalf("%s: WeightStringAppend wrote past the appended weights at offset %d", coll.Name(), i)
+			}
+		}
+	}
+}
+
+func TestWeightStringLenExact(t *testing.T) {
+	var inputs = []string{
+		"", "abc", "abc   ", ExampleString, JapaneseString, HungarianString, "Straße", "chleba", "dzsungel", "ǅ́", "ab\xffcd", "\xe6\x97",
+	}
+	for _, coll := range All() {
+		for _, input := range inputs {
+			src, err := charset.ConvertFromUTF8(nil, coll.Charset(), []byte(input))
+			if err != nil {
+				src = []byte(input)
+			}
+			for _, numCodepoints := range []int{0, 1, 4, 16, 128, PadToMax} {
+				weightCodepoints := numCodepoints
+				if weightCodepoints == PadToMax {
+					weightCodepoints = 0
+				}
+				expected := len(coll.WeightString(nil, src, weightCodepoints))
+				if got := WeightStringLenExact(coll, src, numCodepoints); got != expected {
+					t.Errorf("%s: WeightStringLenExact(%q, %d) = %d (expected %d)", coll.Name(), src, numCodepoints, got, expected)
+				}
+			}
+		}
+	}
+
+	// an expansion yields more weights than codepoints in the input
+	root := testcollation(t, "utf8mb4_0900_ai_ci")
+	if WeightStringLenExact(root, []byte("ß"), 0) != 4 || WeightStringLenExact(root, []byte("s"), 0) != 2 {
+		t.Errorf("expected \"ß\" to expand to two weights in %s", root.Name())
+	}
+
+	src := []byte(HungarianString)
+	if allocs := testing.AllocsPerRun(100, func() { WeightStringLenExact(root, src, 0) }); allocs > 0 {
+		t.Errorf("WeightStringLenExact allocated %v times for %s", allocs, root.Name())
+	}
+}
+
+func TestGeneralAndUnicodeCI(t *testing.T) {
+	general := FromName("utf8mb4_general_ci")
+	unicode := FromName("utf8mb4_unicode_ci")
+	if _, ok := general.(*Collation_unicode_general_ci); !ok {
+		t.Fatalf("utf8mb4_general_ci should use the simplified MySQL tables, got %T", general)
+	}
+	if _, ok := unicode.(*Collation_uca_legacy); !ok {
+		t.Fatalf("utf8mb4_unicode_ci should use the UCA 4.0.0 tables, got %T", unicode)
+	}
+
+	var cases = []struct {
+		left, right      string
+		general, unicode bool
+	}{
+		// general_ci only folds single codepoints, so ß is equal to a single s
+		{"ß", "s", true, false},
+		{"ß", "ss", false, true},
+		{"æ", "ae", false, false},
+		{"résumé", "RESUME", true, true},
+		{"ｂ", "b", false, true},
+	}
+	for _, tc := range cases {
+		if eq := general.Collate([]byte(tc.left), []byte(tc.right), false) == 0; eq != tc.general {
+			t.Errorf("utf8mb4_general_ci: %q = %q should be %v", tc.left, tc.right, tc.general)
+		}
+		if eq := unicode.Collate([]byte(tc.left), []byte(tc.right), false) == 0; eq != tc.unicode {
+			t.Errorf("utf8mb4_unicode_ci: %q = %q should be %v", tc.left, tc.right, tc.unicode)
+		}
+	}
+}
+
+func TestCapabilities(t *testing.T) {
+	differ := func(coll Collation, left, right string) (bool, bool) {
+		l, err1 := charset.ConvertFromUTF8(nil, coll.Charset(), []byte(left))
+		r, err2 := charset.ConvertFromUTF8(nil, coll.Charset(), []byte(right))
+		if err1 != nil || err2 != nil {
+			return false, false
+		}
+		return coll.Collate(l, r, false) != 0, true
+	}
+
+	for _, coll := range All() {
+		caps := coll.Capabilities()
+		if caps.Binary != coll.IsBinary() {
+			t.Errorf("%s: Capabilities().Binary = %v, IsBinary() = %v", coll.Name(), caps.Binary, coll.IsBinary())
+		}
+		if caps.Levels < 1 {
+			t.Errorf("%s: Capabilities().Levels = %d", coll.Name(), caps.Levels)
+		}
+		if _, ok := coll.Charset().(charset.Charset_utf16le); ok {
+			// the ASCII-only test strings can't tell apart the case of utf16le
+			// from its binary collation, which compares the raw bytes
+			continue
+		}
+		if cs, ok := differ(coll, "a", "A"); ok && cs != caps.CaseSensitive {
+			t.Errorf("%s: Capabilities().CaseSensitive = %v, but 'a' and 'A' differ = %v", coll.Name(), caps.CaseSensitive, cs)
+		}
+		if as, ok := differ(coll, "e", "è"); ok && as != caps.AccentSensitive {
+			t.Errorf("%s: Capabilities().AccentSensitive = %v, but 'e' and 'è' differ = %v", coll.Name(), caps.AccentSensitive, as)
+		}
+	}
+
+	var cases = []struct {
+		name string
+		caps CollationCaps
+	}{
+		{"utf8mb4_0900_ai_ci", CollationCaps{Levels: 1}},
+		{"utf8mb4_0900_as_ci", CollationCaps{AccentSensitive: true, Levels: 2}},
+		{"utf8mb4_0900_as_cs", CollationCaps{CaseSensitive: true, AccentSensitive: true, Levels: 3}},
+		{"utf8mb4_ja_0900_as_cs_ks", CollationCaps{CaseSensitive: true, AccentSensitive: true, Levels: 4}},
+		{"utf8mb4_0900_bin", CollationCaps{CaseSensitive: true, AccentSensitive: true, Binary: true, Levels: 1}},
+		{"utf8mb4_general_ci", CollationCaps{PadSpace: true, Levels: 1}},
+		{"utf8mb4_bin", CollationCaps{CaseSensitive: true, AccentSensitive: true, Binary: true, PadSpace: true, Levels: 1}},
+		{"latin1_swedish_ci", CollationCaps{PadSpace: true, Levels: 1}},
+		{"latin1_general_cs", CollationCaps{CaseSensitive: true, AccentSensitive: true, PadSpace: true, Levels: 1}},
+		{"binary", CollationCaps{CaseSensitive: true, AccentSensitive: true, Binary: true, Levels: 1}},
+	}
+	for _, tc := range cases {
+		if caps := FromName(tc.name).Capabilities(); caps != tc.caps {
+			t.Errorf("%s: Capabilities() = %+v (expected %+v)", tc.name, caps, tc.caps)
+		}
+	}
+}
+
+func TestCaseSensitive8bit(t *testing.T) {
+	var cases = []struct {
+		collation string
+		ordered   []string
+	}{
+		// case sensitive collations sort the uppercase letter right before the lowercase one
+		{"latin1_general_cs", []string{"A", "a", "B", "b", "E", "e", "\xc9", "\xe9", "Z", "z"}},
+		{"latin7_general_cs", []string{"A", "a", "B", "b", "Z", "z"}},
+		{"cp1251_general_cs", []string{"A", "a", "B", "b", "Z", "z"}},
+		// binary collations sort by the value of each byte
+		{"latin1_bin", []string{"A", "B", "Z", "a", "b", "z", "\xc9", "\xe9"}},
+		{"cp1251_bin", []string{"A", "B", "Z", "a", "b", "z"}},
+	}
+	for _, tc := range cases {
+		coll := FromName(tc.collation)
+		for i := 1; i < len(tc.ordered); i++ {
+			left, right := []byte(tc.ordered[i-1]), []byte(tc.ordered[i])
+			if coll.Collate(left, right, false) >= 0 || coll.Collate(right, left, false) <= 0 {
+				t.Errorf("%s: expected %q < %q", tc.collation, left, right)
+			}
+		}
+		if tc.collation != "latin1_general_cs" {
+			continue
+		}
+
+		// every byte sorts on its own, so no two bytes are equal; the case insensitive
+		// collation for the same charset finds the case variants equal
+		for a := 0; a < 256; a++ {
+			for b := a + 1; b < 256; b++ {
+				if coll.Collate([]byte{byte(a)}, []byte{byte(b)}, false) == 0 {
+					t.Errorf("%s: expected 0x%02X != 0x%02X", tc.collation, a, b)
+				}
+			}
+		}
+		if ci := FromName("latin1_general_ci"); ci.Collate([]byte("A"), []byte("a"), false) != 0 {
+			t.Errorf("latin1_general_ci: expected %q == %q", "A", "a")
+		}
+	}
+}
+
+func TestCollateEmpty(t *testing.T) {
+	var cases = []struct {
+		left, right     string
+		isPrefix        bool
+		padSpace, noPad int
+	}{
+		{"", "", false, 0, 0},
+		{"", "", true, 0, 0},
+		{"", "abc", false, -1, -1},
+		{"", "abc", true, -1, -1},
+		{"abc", "", false, 1, 1},
+		{"abc", "", true, 0, 0},
+		{" ", "", true, 0, 0},
+		{"a", "abc", true, -1, -1},
+		{"abc", "a", true, 0, 0},
+		{"", " ", false, 0, -1},
+		{" ", "", false, 0, 1},
+		{"", " ", true, -1, -1},
+	}
+
+	for _, coll := range All() {
+		padSpace := coll.Capabilities().PadSpace
+		for _, tc := range cases {
+			left, _ := charset.ConvertFromUTF8(nil, coll.Charset(), []byte(tc.left))
+			right, _ := charset.ConvertFromUTF8(nil, coll.Charset(), []byte(tc.right))
+
+			expected := tc.noPad
+			if padSpace {
+				expected = tc.padSpace
+			}
+			if got := sign(coll.Collate(left, right, tc.isPrefix)); got != expected {
+				t.Errorf("%s: Collate(%q, %q, %v) = %d (expected %d)", coll.Name(), tc.left, tc.right, tc.isPrefix, got, expected)
+			}
+		}
+
+		if coll.Collate(nil, []byte{}, false) != 0 || coll.Collate([]byte{}, nil, true) != 0 {
+			t.Errorf("%s: nil and empty inputs should be equal", coll.Name())
+		}
+	}
+}
+
+// dirtyBuffer returns a slice of length `n` into a large buffer whose bytes are all set to
+// `garbage`, like a buffer that is reused after holding other weight strings
+func dirtyBuffer(n int, garbage byte) []byte {
+	buf := make([]byte, 4096)
+	for i := range buf {
+		buf[i] = garbage
+	}
+	return buf[:n]
+}
+
+// checkWeightString verifies that the weight string returned by WeightString keeps the
+// contents of `dst` as its prefix, and that the weights appended to it are `expected`
+func checkWeightString(t *testing.T, coll Collation, dst, src []byte, numCodepoints int, expected []byte) {
+	t.Helper()
+	prefix := append([]byte(nil), dst...)
+	got := coll.WeightString(dst, src, numCodepoints)
+	if !bytes.Equal(got[:len(prefix)], prefix) {
+		t.Errorf("%s: WeightString(%q, %d) overwrote the contents of dst", coll.Name(), src, numCodepoints)
+		return
+	}
+	if !bytes.Equal(got[len(prefix):], expected) {
+		t.Errorf("%s: WeightString(%q, %d) with cap(dst)=%d = %x (expected %x)",
+			coll.Name(), src, numCodepoints, cap(dst), got[len(prefix):], expected)
+	}
+}
+
+func TestWeightStringDeterministic(t *testing.T) {
+	var inputs = []string{
+		"",
+		"abc",
+		"abc   ",
+		"Straße 日本",
+		"the quick brown fox jumps over the lazy dog",
+	}
+
+	for _, coll := range All() {
+		for _, input := range inputs {
+			src, err := charset.ConvertFromUTF8(nil, coll.Charset(), []byte(input))
+			if err != nil {
+				continue
+			}
+
+			for _, numCodepoints := range []int{0, 3, 20} {
+				expected := coll.WeightString(nil, src, numCodepoints)
+				for _, garbage := range []byte{0x00, 0x20, 0xFF} {
+					checkWeightString(t, coll, dirtyBuffer(0, garbage), src, numCodepoints, expected)
+					checkWeightString(t, coll, dirtyBuffer(7, garbage), src, numCodepoints, expected)
+					// a capacity that barely fits the weights, so that the last ones are appended
+					// through a different code path than the first ones
+					checkWeightString(t, coll, dirtyBuffer(0, garbage)[:0:len(expected)+5], src, numCodepoints, expected)
+				}
+			}
+
+			// with PadToMax, the padding depends on the capacity of `dst`, but never on
+			// the bytes that were left in it
+			padded := coll.WeightString(make([]byte, 0, 256), src, PadToMax)
+			for _, garbage := range []byte{0x00, 0x20, 0xFF} {
+				checkWeightString(t, coll, dirtyBuffer(0, garbage)[:0:256], src, PadToMax, padded)
+			}
+		}
+	}
+}
+
+func TestCollateSymmetric(t *testing.T) {
+	// strings that only differ in their length, trailing spaces, ignorable codepoints,
+	// expansions and contractions, which are the edge cases for the comparison
+	var inputs = []string{
+		"",
+		" ",
+		"  ",
+		"\t",
+		"a",
+		"a ",
+		"a  ",
+		"A",
+		"ab",
+		"a b",
+		"ab ",
+		"a\u0000",
+		"a­",
+		"­b",
+		"ä",
+		"ä",
+		"ß",
+		"ss",
+		"æ",
+		"ae",
+		"ch",
+		"c",
+		"ll",
+		"日本",
+		"日本語",
+		"😀",
+		"😀 ",
+		"\xff",
+		"a\xff",
+	}
+
+	for _, coll := range All() {
+		var encoded [][]byte
+		for _, input := range inputs {
+			src, err := charset.ConvertFromUTF8(nil, coll.Charset(), []byte(input))
+			if err != nil {
+				// keep the invalid sequences as they are: they must be collated symmetrically too
+				src = []byte(input)
+			}
+			encoded = append(encoded, src)
+		}
+
+		for i, left := range encoded {
+			for _, right := range encoded[i:] {
+				cmp1 := sign(coll.Collate(left, right, false))
+				cmp2 := sign(coll.Collate(right, left, false))
+				if cmp1 != -cmp2 {
+					t.Errorf("%s: Collate(%q, %q) = %d, but Collate(%q, %q) = %d",
+						coll.Name(), left, right, cmp1, right, left, cmp2)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,196 @@
+//go:build gofuzz
+// +build gofuzz
+
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collations
+
+import (
+	"fmt"
+	"sync"
+
+	"vitess.io/vitess/go/mysql/collations/internal/charset"
+)
+
+var fuzzCollations struct {
+	sync.Once
+	all []Collation
+}
+
+// FuzzCollateTransitivity splits its input into three strings and verifies that
+// Collate is a total order for all of them in every registered collation: comparing
+// two strings must give opposite results when the arguments are swapped, and if
+// a <= b and b <= c, then a <= c. Any violation panics with the smallest triple of
+// strings that still reproduces it. Collations are skipped for inputs that are not
+// well-formed in their charset, since the ordering of malformed strings is not defined.
+func FuzzCollateTransitivity(data []byte) int {
+	if len(data) < 2 {
+		return -1
+	}
+	lenA := minInt(int(data[0]), len(data)-2)
+	lenB := minInt(int(data[1]), len(data)-2-lenA)
+	data = data[2:]
+
+	a := data[:lenA]
+	b := data[lenA : lenA+lenB]
+	c := data[lenA+lenB:]
+
+	fuzzCollations.Do(func() { fuzzCollations.all = All() })
+	for _, coll := range fuzzCollations.all {
+		cs := coll.Charset()
+		if !charset.Validate(cs, a) || !charset.Validate(cs, b) || !charset.Validate(cs, c) {
+			continue
+		}
+		if violation := collateViolation(coll, a, b, c); violation != "" {
+			a, b, c = minimizeViolation(coll, a, b, c)
+			panic(fmt.Sprintf("%s (%d): %s\nminimal triple: a=%q, b=%q, c=%q",
+				coll.Name(), coll.ID(), collateViolation(coll, a, b, c), a, b, c))
+		}
+	}
+	return 1
+}
+
+// FuzzCollateWeightString splits its input into two strings and verifies that, in every
+// UCA collation, comparing the weight string of the first one with the second one using
+// CollateWeightString gives the same result as comparing both strings with Collate.
+func FuzzCollateWeightString(data []byte) int {
+	if len(data) < 1 {
+		return -1
+	}
+	lenLeft := minInt(int(data[0]), len(data)-1)
+	left, right := data[1:1+lenLeft], data[1+lenLeft:]
+
+	fuzzCollations.Do(func() { fuzzCollations.all = All() })
+	for _, coll := range fuzzCollations.all {
+		uca, ok := coll.(CollationUCA)
+		if !ok {
+			continue
+		}
+		cs := coll.Charset()
+		if !charset.Validate(cs, left) || !charset.Validate(cs, right) {
+			continue
+		}
+		weights := coll.WeightString(nil, left, 0)
+		for _, prefix := range []bool{false, true} {
+			expected := fuzzSign(coll.Collate(left, right, prefix))
+			if got := fuzzSign(uca.CollateWeightString(weights, right, prefix)); got != expected {
+				panic(fmt.Sprintf("%s (%d): CollateWeightString(%q, %q, %v) = %d, but Collate returned %d",
+					coll.Name(), coll.ID(), left, right, prefix, got, expected))
+			}
+		}
+	}
+	return 1
+}
+
+// FuzzCollateASCII splits its input into two strings and verifies that, in every UCA 0900
+// collation with a fast path for ASCII strings, Collate gives the same result as the
+// general UCA iterator, with and without non-ASCII characters in the strings.
+func FuzzCollateASCII(data []byte) int {
+	if len(data) < 1 {
+		return -1
+	}
+	lenLeft := minInt(int(data[0]), len(data)-1)
+	left, right := data[1:1+lenLeft], data[1+lenLeft:]
+
+	fuzzCollations.Do(func() { fuzzCollations.all = All() })
+	for _, coll := range fuzzCollations.all {
+		fast, ok := coll.(*Collation_utf8mb4_uca_0900)
+		if !ok || fast.asciiPrimary == nil {
+			continue
+		}
+		slow := fast.WithLevels(fast.levelsForCompare).(*Collation_utf8mb4_uca_0900)
+		slow.asciiPrimary = nil
+
+		for _, prefix := range []bool{false, true} {
+			expected := fuzzSign(slow.Collate(left, right, prefix))
+			if got := fuzzSign(fast.Collate(left, right, prefix)); got != expected {
+				panic(fmt.Sprintf("%s (%d): Collate(%q, %q, %v) = %d with the ASCII fast path, but %d without it",
+					coll.Name(), coll.ID(), left, right, prefix, got, expected))
+			}
+		}
+	}
+	return 1
+}
+
+func fuzzSign(cmp int) int {
+	switch {
+	case cmp < 0:
+		return -1
+	case cmp > 0:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// collateViolation returns a description of the first way in which Collate fails to
+// be a total order for the given strings, or an empty string if it doesn't fail.
+func collateViolation(coll Collation, a, b, c []byte) string {
+	strs := [3][]byte{a, b, c}
+
+	var cmp [3][3]int
+	for i := range strs {
+		for j := range strs {
+			cmp[i][j] = fuzzSign(coll.Collate(strs[i], strs[j], false))
+		}
+	}
+
+	for i := range strs {
+		if cmp[i][i] != 0 {
+			return fmt.Sprintf("Collate(%q, %q) = %d", strs[i], strs[i], cmp[i][i])
+		}
+		for j := range strs {
+			if cmp[i][j] != -cmp[j][i] {
+				return fmt.Sprintf("antisymmetry: Collate(%q, %q) = %d, but Collate(%q, %q) = %d",
+					strs[i], strs[j], cmp[i][j], strs[j], strs[i], cmp[j][i])
+			}
+		}
+	}
+
+	for i := range strs {
+		for j := range strs {
+			for k := range strs {
+				if cmp[i][j] <= 0 && cmp[j][k] <= 0 && cmp[i][k] > 0 {
+					return fmt.Sprintf("transitivity: %q <= %q and %q <= %q, but %q > %q",
+						strs[i], strs[j], strs[j], strs[k], strs[i], strs[k])
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// minimizeViolation greedily removes single bytes from the given strings for as long
+// as Collate keeps violating its ordering guarantees for the resulting triple.
+func minimizeViolation(coll Collation, a, b, c []byte) ([]byte, []byte, []byte) {
+	strs := [3][]byte{a, b, c}
+	for shrunk := true; shrunk; {
+		shrunk = false
+		for s := range strs {
+			for i := 0; i < len(strs[s]); i++ {
+				candidate := strs
+				candidate[s] = append(append([]byte(nil), strs[s][:i]...), strs[s][i+1:]...)
+				if charset.Validate(coll.Charset(), candidate[s]) && collateViolation(coll, candidate[0], candidate[1], candidate[2]) != "" {
+					strs = candidate
+					shrunk = true
+					i--
+				}
+			}
+		}
+	}
+	return strs[0], strs[1], strs[2]
+}
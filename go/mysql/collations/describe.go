@@ -0,0 +1,266 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collations
+
+import (
+	"fmt"
+	"strings"
+
+	"vitess.io/vitess/go/mysql/collations/internal/charset"
+)
+
+// DescribeWeightString returns a human-readable representation of a weight string
+// generated by one of the UCA collations, to help debug mismatches between weight strings.
+// The weight string is split into 16-bit weights, and each comparison level is displayed
+// in its own line, using the NULL weights that separate the levels in the weight strings
+// for multi-level collations (these are also accounted for in WeightStringLen).
+// The resulting representation looks like this:
+//
+//	level 1: [1C47 1C60 1C7A]
+//	level 2: [0020 0020 0020]
+//	level 3: [0002 0002 0008]
+//
+// Weight strings for non-UCA collations have no separators and are not necessarily made of
+// 16-bit weights, so they are always displayed as a single level of 16-bit words. Note that
+// padding with PadToMax is displayed as a sequence of empty levels.
+func DescribeWeightString(data []byte) string {
+	var buf strings.Builder
+	level := 1
+	first := true
+
+	fmt.Fprintf(&buf, "level %d: [", level)
+	for i := 0; i+1 < len(data); i += 2 {
+		weight := uint16(data[i])<<8 | uint16(data[i+1])
+		if weight == 0 {
+			level++
+			first = true
+			fmt.Fprintf(&buf, "]\nlevel %d: [", level)
+			continue
+		}
+		if !first {
+			buf.WriteByte(' ')
+		}
+		first = false
+		fmt.Fprintf(&buf, "%04X", weight)
+	}
+	buf.WriteByte(']')
+
+	if len(data)%2 != 0 {
+		fmt.Fprintf(&buf, "\ntrailing: [%02X]", data[len(data)-1])
+	}
+	return buf.String()
+}
+
+// DiffWeightStrings compares a local and a remote weight string for the same input and
+// returns a human-readable report of their differences, or an empty string if they are
+// equal. The weight strings are split into 16-bit weights like in DescribeWeightString,
+// and displayed side by side, one weight per line, with the level of each weight and a
+// marker on the first weight that differs:
+//
+//	weight strings differ at weight 3 (byte 7), level 2
+//	   #  level  local  remote
+//	   0      1  1C47   1C47
+//	   1      1  0000   0000
+//	   2      2  0020   0020
+//	   3      2  0020   0021  <--
+//
+// Weights that are only present in one of the weight strings are displayed as `----`.
+func DiffWeightStrings(local, remote []byte) string {
+	diff := 0
+	for diff < len(local) && diff < len(remote) && local[diff] == remote[diff] {
+		diff++
+	}
+	if diff == len(local) && diff == len(remote) {
+		return ""
+	}
+
+	// the weight strings are the same until the first differing weight, so the
+	// level can be counted from either of them
+	diffWeight := diff / 2
+	level := 1
+	for i := 0; i < diffWeight; i++ {
+		if local[2*i] == 0 && local[2*i+1] == 0 {
+			level++
+		}
+	}
+
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "weight strings differ at weight %d (byte %d), level %d\n", diffWeight, diff, level)
+	fmt.Fprintf(&buf, "%4s  %5s  %-5s  %s\n", "#", "level", "local", "remote")
+
+	level = 1
+	weights := (len(local) + 1) / 2
+	if w := (len(remote) + 1) / 2; w > weights {
+		weights = w
+	}
+	for i := 0; i < weights; i++ {
+		l, r := describeWeightAt(local, i), describeWeightAt(remote, i)
+		line := fmt.Sprintf("%4d  %5d  %-5s  %-4s", i, level, l, r)
+		if i == diffWeight {
+			line += "  <--"
+		}
+		buf.WriteString(strings.TrimRight(line, " "))
+		buf.WriteByte('\n')
+		if l == "0000" {
+			level++
+		}
+	}
+	return buf.String()
+}
+
+// describeWeightAt returns the 16-bit weight at the given index of the weight string as
+// hex, or `----` if the weight string is shorter than that. A trailing byte in a weight
+// string with an odd length is displayed on its own.
+func describeWeightAt(data []byte, idx int) string {
+	switch i := idx * 2; {
+	case i+1 < len(data):
+		return fmt.Sprintf("%02X%02X", data[i], data[i+1])
+	case i < len(data):
+		return fmt.Sprintf("%02X", data[i])
+	default:
+		return "----"
+	}
+}
+
+// explainCollation implements Explain for the UCA collations, which compare the weights for
+// `levels` levels. The comparison is performed with CollateDetailed, and the amount of weights
+// it matched is used to find the weight where both strings diverged in the weight streams.
+func explainCollation(coll CollationUCA, levels int, left, right []byte) string {
+	cmp, matched := coll.CollateDetailed(left, right)
+	lweights := explainWeights(coll, left, levels)
+	rweights := explainWeights(coll, right, levels)
+
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "collation: %s\n", coll.Name())
+	explainCodepoints(&buf, "left: ", coll.Charset(), left)
+	explainCodepoints(&buf, "right:", coll.Charset(), right)
+
+	// the weights of every level before the one where the strings diverged are all equal
+	diverged, idx := -1, 0
+	if cmp != 0 {
+		for level := 0; level < levels; level++ {
+			l, r := lweights[level], rweights[level]
+			if len(l) == len(r) && matched >= len(l) && equalWeights(l, r) {
+				matched -= len(l)
+				continue
+			}
+			diverged, idx = level, matched
+			break
+		}
+	}
+
+	for level := 0; level < levels; level++ {
+		fmt.Fprintf(&buf, "level %d:\n", level+1)
+		fmt.Fprintf(&buf, "  left:  %s\n", explainLevel(lweights[level]))
+		fmt.Fprintf(&buf, "  right: %s\n", explainLevel(rweights[level]))
+	}
+
+	switch {
+	case cmp == 0:
+		buf.WriteString("equal in every level\n")
+	case diverged < 0:
+		// CollateDetailed and the weight streams should always agree, but the result of
+		// the comparison is still reported if they don't
+		buf.WriteString("diverged at an unknown weight\n")
+	default:
+		fmt.Fprintf(&buf, "diverged at level %d, weight %d: %s %s %s\n", diverged+1, idx,
+			explainWeightAt(coll, lweights[diverged], idx), explainSign(cmp), explainWeightAt(coll, rweights[diverged], idx))
+	}
+	fmt.Fprintf(&buf, "result: left %s right", explainSign(cmp))
+	return buf.String()
+}
+
+// explainWeights returns the weights for `src` in each of the first `levels` levels
+func explainWeights(coll CollationUCA, src []byte, levels int) [][]uint16 {
+	weights := make([][]uint16, levels)
+	next := coll.Weights(src)
+	for {
+		w, level, ok := next()
+		if !ok {
+			return weights
+		}
+		weights[level] = append(weights[level], w)
+	}
+}
+
+// explainCodepoints writes the codepoints in `src` after `label`; bytes that are not part
+// of a valid codepoint are displayed on their own
+func explainCodepoints(buf *strings.Builder, label string, cs charset.Charset, src []byte) {
+	fmt.Fprintf(buf, "%s %q [", label, src)
+	for pos := 0; pos < len(src); {
+		if pos > 0 {
+			buf.WriteByte(' ')
+		}
+		cp, width := cs.DecodeRune(src[pos:])
+		if cp == charset.RuneError && width < 3 {
+			fmt.Fprintf(buf, "invalid:%02X", src[pos])
+			pos++
+			continue
+		}
+		fmt.Fprintf(buf, "U+%04X", cp)
+		pos += width
+	}
+	buf.WriteString("]\n")
+}
+
+// explainLevel returns the weights of a level in the same format as DescribeWeightString
+func explainLevel(weights []uint16) string {
+	var buf strings.Builder
+	buf.WriteByte('[')
+	for i, w := range weights {
+		if i > 0 {
+			buf.WriteByte(' ')
+		}
+		fmt.Fprintf(&buf, "%04X", w)
+	}
+	buf.WriteByte(']')
+	return buf.String()
+}
+
+// explainWeightAt returns the weight at the given index of a level as hex. If the level
+// is shorter than that, the weight for the spaces that pad it is returned in PAD SPACE
+// collations, and `----` in NO PAD ones.
+func explainWeightAt(coll CollationUCA, weights []uint16, idx int) string {
+	switch {
+	case idx < len(weights):
+		return fmt.Sprintf("%04X", weights[idx])
+	case coll.PadWeight() != 0:
+		return fmt.Sprintf("%04X (padding)", coll.PadWeight())
+	default:
+		return "----"
+	}
+}
+
+func explainSign(cmp int) string {
+	switch {
+	case cmp < 0:
+		return "<"
+	case cmp > 0:
+		return ">"
+	default:
+		return "="
+	}
+}
+
+func equalWeights(a, b []uint16) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collations
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffWeightStrings(t *testing.T) {
+	weights := []byte{0x1C, 0x47, 0x00, 0x00, 0x00, 0x20, 0x00, 0x20}
+	assert.Empty(t, DiffWeightStrings(weights, weights))
+
+	var cases = []struct {
+		local, remote []byte
+		expected      string
+	}{
+		{
+			local:  weights,
+			remote: []byte{0x1C, 0x47, 0x00, 0x00, 0x00, 0x20, 0x00, 0x21},
+			expected: "weight strings differ at weight 3 (byte 7), level 2\n" +
+				"   #  level  local  remote\n" +
+				"   0      1  1C47   1C47\n" +
+				"   1      1  0000   0000\n" +
+				"   2      2  0020   0020\n" +
+				"   3      2  0020   0021  <--\n",
+		},
+		{
+			local:  []byte{0x1C, 0x47},
+			remote: []byte{0x1C, 0x47, 0x1C},
+			expected: "weight strings differ at weight 1 (byte 2), level 1\n" +
+				"   #  level  local  remote\n" +
+				"   0      1  1C47   1C47\n" +
+				"   1      1  ----   1C    <--\n",
+		},
+	}
+	for _, tc := range cases {
+		assert.Equal(t, tc.expected, DiffWeightStrings(tc.local, tc.remote))
+	}
+}
+
+func TestExplain(t *testing.T) {
+	coll := testcollation(t, "utf8mb4_0900_as_cs").(CollationUCA)
+	expected := "collation: utf8mb4_0900_as_cs\n" +
+		"left:  \"Cafe\" [U+0043 U+0061 U+0066 U+0065]\n" +
+		"right: \"café\" [U+0063 U+0061 U+0066 U+00E9]\n" +
+		"level 1:\n" +
+		"  left:  [1C7A 1C47 1CE5 1CAA]\n" +
+		"  right: [1C7A 1C47 1CE5 1CAA]\n" +
+		"level 2:\n" +
+		"  left:  [0020 0020 0020 0020]\n" +
+		"  right: [0020 0020 0020 0020 0024]\n" +
+		"level 3:\n" +
+		"  left:  [0008 0002 0002 0002]\n" +
+		"  right: [0002 0002 0002 0002 0002]\n" +
+		"diverged at level 2, weight 4: ---- < 0024\n" +
+		"result: left < right"
+	assert.Equal(t, expected, coll.Explain([]byte("Cafe"), []byte("café")))
+
+	legacy := testcollation(t, "utf8mb4_unicode_ci").(CollationUCA)
+	assert.Contains(t, legacy.Explain([]byte("abc"), []byte("abc  x")), "diverged at level 1, weight 5: 0209 (padding) < 105A\n")
+	assert.Contains(t, legacy.Explain([]byte("ab"), []byte("ab ")), "equal in every level\nresult: left = right")
+
+	var pairs = [][2]string{
+		{"", ""}, {"abc", "ABC"}, {"abc", "abd"}, {"ab", "ab "}, {"chleba", "cukr"}, {"の東京ノ", "ノ東京の"},
+		{ExampleString, JapaneseString}, {HungarianString, WhitespaceString}, {"a\xffb", "a\xffc"},
+	}
+	for _, coll := range All() {
+		coll, ok := coll.(CollationUCA)
+		if !ok {
+			continue
+		}
+		for _, p := range pairs {
+			left, right := []byte(p[0]), []byte(p[1])
+			explain := coll.Explain(left, right)
+			result := "result: left " + explainSign(coll.Collate(left, right, false)) + " right"
+			if !strings.HasSuffix(explain, result) || strings.Contains(explain, "unknown weight") {
+				t.Errorf("%s: unexpected Explain(%q, %q):\n%s", coll.Name(), left, right, explain)
+			}
+		}
+	}
+}
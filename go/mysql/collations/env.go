@@ -0,0 +1,222 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collations
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"vitess.io/vitess/go/mysql/collations/internal/charset"
+)
+
+type collver byte
+
+const (
+	collverUndefined collver = iota
+	collverMySQL5
+	collverMySQL80
+)
+
+// collverNewest is the version used for environments whose MySQL version is unknown
+const collverNewest = collverMySQL80
+
+func (v collver) String() string {
+	switch v {
+	case collverMySQL5:
+		return "MySQL 5.x"
+	case collverMySQL80:
+		return "MySQL 8.0"
+	default:
+		return "undefined"
+	}
+}
+
+// parseCollver returns the collation version for a MySQL server version string,
+// as returned in the server's handshake or by `SELECT @@version`
+func parseCollver(version string) collver {
+	version = strings.TrimSpace(version)
+	if dash := strings.IndexByte(version, '-'); dash >= 0 {
+		version = version[:dash]
+	}
+
+	parts := strings.SplitN(version, ".", 3)
+	if len(parts) < 2 {
+		return collverUndefined
+	}
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return collverUndefined
+	}
+	minor, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return collverUndefined
+	}
+
+	switch {
+	case major == 5:
+		return collverMySQL5
+	case major == 8 && minor == 0:
+		return collverMySQL80
+	default:
+		return collverUndefined
+	}
+}
+
+// supportsCollation returns whether the given collation exists in this version of MySQL
+func (v collver) supportsCollation(coll Collation) bool {
+	switch v {
+	case collverMySQL5:
+		// all the UCA 9.0.0 collations were introduced in MySQL 8.0
+		return !strings.Contains(coll.Name(), "_0900_")
+	default:
+		return true
+	}
+}
+
+// defaultCollationOverrides are the default collations for each version that are
+// different from the defaults in the newest MySQL version
+var defaultCollationOverrides = map[collver]map[string]string{
+	collverMySQL5: {
+		"utf8mb4": "utf8mb4_general_ci",
+	},
+}
+
+// Environment is a catalog of the collations that are available in a specific
+// version of MySQL, together with the default collations for each charset in
+// that version.
+// Note that the weight tables for all the collations in this package are the ones
+// from the newest supported MySQL version; an Environment only affects which
+// collations are available and which ones are the default.
+type Environment struct {
+	version   collver
+	byName    map[string]Collation
+	byID      map[ID]Collation
+	byCharset map[string]Collation
+}
+
+var environments struct {
+	sync.Mutex
+	cache map[collver]*Environment
+}
+
+// NewEnvironment returns the collation Environment for the given MySQL server version,
+// e.g. "5.7.31-log" or "8.0.26". If the version is not recognized, the Environment for
+// the newest known version of MySQL is returned together with an error, so the caller
+// can decide whether to report the unknown version or to use that Environment anyway.
+// Environments are immutable and shared, so this function can be called repeatedly.
+func NewEnvironment(serverVersion string) (*Environment, error) {
+	var err error
+	version := parseCollver(serverVersion)
+	if version == collverUndefined {
+		err = fmt.Errorf("unknown MySQL version %q, using the collations for %s", serverVersion, collverNewest)
+		version = collverNewest
+	}
+
+	environments.Lock()
+	defer environments.Unlock()
+
+	if env, ok := environments.cache[version]; ok {
+		return env, err
+	}
+	if environments.cache == nil {
+		environments.cache = make(map[collver]*Environment)
+	}
+	env := makeEnvironment(version)
+	environments.cache[version] = env
+	return env, err
+}
+
+func makeEnvironment(version collver) *Environment {
+	env := &Environment{
+		version:   version,
+		byName:    make(map[string]Collation),
+		byID:      make(map[ID]Collation),
+		byCharset: make(map[string]Collation),
+	}
+
+	for name, coll := range collationsByName {
+		if !version.supportsCollation(coll) {
+			continue
+		}
+		env.byName[name] = coll
+		env.byID[coll.ID()] = coll
+	}
+	for csname, coll := range defaultCollationByCharset {
+		if version.supportsCollation(coll) {
+			env.byCharset[csname] = coll
+		}
+	}
+	for csname, collname := range defaultCollationOverrides[version] {
+		env.byCharset[csname] = collationsByName[collname]
+	}
+	return env
+}
+
+// LookupByName returns the collation with the given name in this Environment. Like the
+// package-level LookupByName, it returns an error if the collation is not supported by this
+// package, and also if the collation is supported but not available in this version of MySQL.
+// The collation is initialized if it's the first time being accessed.
+func (env *Environment) LookupByName(name string) (Collation, error) {
+	coll := env.byName[name]
+	if coll == nil {
+		if _, err := LookupByName(name); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("collation %q is not available in %s", name, env.version)
+	}
+	coll.init()
+	return coll, nil
+}
+
+// LookupByID returns the collation with the given numerical identifier in this Environment.
+// Like the package-level LookupByID, it returns an error if the collation is not supported by
+// this package, and also if the collation is supported but not available in this version of
+// MySQL. The collation is initialized if it's the first time being accessed.
+func (env *Environment) LookupByID(id ID) (Collation, error) {
+	coll := env.byID[id]
+	if coll == nil {
+		if _, err := LookupByID(id); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("collation %d is not available in %s", id, env.version)
+	}
+	coll.init()
+	return coll, nil
+}
+
+// DefaultForCharset returns the default collation for a charset in this Environment
+func (env *Environment) DefaultForCharset(charset string) Collation {
+	coll := env.byCharset[charset]
+	if coll != nil {
+		coll.init()
+	}
+	return coll
+}
+
+// IsDefaultForCharset returns whether the given collation is the default collation
+// for its charset in this Environment, as shown in the Default column of `SHOW COLLATION`
+func (env *Environment) IsDefaultForCharset(coll Collation) bool {
+	return coll != nil && env.byCharset[coll.Charset().Name()] == coll
+}
+
+// DefaultCollationForCharset returns the default collation for the given Charset
+// in this Environment
+func (env *Environment) DefaultCollationForCharset(cs charset.Charset) Collation {
+	return env.DefaultForCharset(cs.Name())
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collations
+
+import "testing"
+
+func testenvironment(t testing.TB, version string) *Environment {
+	t.Helper()
+	env, err := NewEnvironment(version)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return env
+}
+
+func TestParseCollver(t *testing.T) {
+	var cases = []struct {
+		version  string
+		expected collver
+	}{
+		{"5.6.33-0ubuntu0.14.04.1-log", collverMySQL5},
+		{"5.7.31", collverMySQL5},
+		{"5.7.31-log", collverMySQL5},
+		{"5.5.5-10.4.13-MariaDB", collverMySQL5},
+		{"8.0.26", collverMySQL80},
+		{"8.0.26-vitess", collverMySQL80},
+		{"8.1.0", collverUndefined},
+		{"", collverUndefined},
+		{"garbage", collverUndefined},
+	}
+
+	for _, tc := range cases {
+		if got := parseCollver(tc.version); got != tc.expected {
+			t.Errorf("parseCollver(%q) = %s (expected %s)", tc.version, got, tc.expected)
+		}
+	}
+}
+
+func TestEnvironment(t *testing.T) {
+	mysql57 := testenvironment(t, "5.7.31")
+	mysql80 := testenvironment(t, "8.0.26")
+
+	if coll, err := mysql57.LookupByName("utf8mb4_0900_ai_ci"); coll != nil || err == nil {
+		t.Errorf("utf8mb4_0900_ai_ci should not be available in MySQL 5.7")
+	}
+	if coll, err := mysql57.LookupByID(255); coll != nil || err == nil {
+		t.Errorf("collation 255 should not be available in MySQL 5.7")
+	}
+	if _, err := mysql80.LookupByName("utf8mb4_0900_ai_ci"); err != nil {
+		t.Errorf("utf8mb4_0900_ai_ci should be available in MySQL 8.0: %v", err)
+	}
+	if coll, err := mysql80.LookupByID(255); err != nil || coll.Name() != "utf8mb4_0900_ai_ci" {
+		t.Errorf("collation 255 should be utf8mb4_0900_ai_ci in MySQL 8.0")
+	}
+
+	// the errors for collations that are not supported at all are the ones from the package-level lookups
+	_, expected := LookupByName("utf8mb4_foobar_ci")
+	if _, err := mysql80.LookupByName("utf8mb4_foobar_ci"); err == nil || err.Error() != expected.Error() {
+		t.Errorf("LookupByName for an unknown collation returned %v (expected %v)", err, expected)
+	}
+	if _, err := mysql80.LookupByID(9999); err == nil {
+		t.Errorf("LookupByID for an unknown collation should fail")
+	}
+
+	for _, env := range []*Environment{mysql57, mysql80} {
+		for _, name := range []string{"utf8mb4_general_ci", "latin1_swedish_ci", "utf8_unicode_ci", "binary"} {
+			coll, err := env.LookupByName(name)
+			if err != nil {
+				t.Errorf("%s should be available in %s: %v", name, env.version, err)
+				continue
+			}
+			if byID, _ := env.LookupByID(coll.ID()); byID != coll {
+				t.Errorf("LookupByID(%d) returned a different collation than LookupByName(%q)", coll.ID(), name)
+			}
+		}
+	}
+
+	var defaults = []struct {
+		env      *Environment
+		charset  string
+		expected string
+	}{
+		{mysql57, "utf8mb4", "utf8mb4_general_ci"},
+		{mysql80, "utf8mb4", "utf8mb4_0900_ai_ci"},
+		{mysql57, "latin1", "latin1_swedish_ci"},
+		{mysql80, "latin1", "latin1_swedish_ci"},
+		{mysql80, "utf8", "utf8_general_ci"},
+	}
+	for _, tc := range defaults {
+		coll := tc.env.DefaultForCharset(tc.charset)
+		if coll == nil || coll.Name() != tc.expected {
+			t.Errorf("DefaultForCharset(%q) in %s should be %s", tc.charset, tc.env.version, tc.expected)
+		}
+		if !tc.env.IsDefaultForCharset(coll) {
+			t.Errorf("IsDefaultForCharset(%s) in %s should be true", tc.expected, tc.env.version)
+		}
+	}
+	if mysql57.IsDefaultForCharset(FromName("utf8mb4_0900_ai_ci")) || mysql80.IsDefaultForCharset(FromName("utf8mb4_general_ci")) {
+		t.Errorf("IsDefaultForCharset should depend on the MySQL version")
+	}
+}
+
+func TestEnvironmentUnknownVersion(t *testing.T) {
+	env, err := NewEnvironment("99.1.2")
+	if err == nil {
+		t.Errorf("unknown versions should return an error")
+	}
+	if env != testenvironment(t, "8.0.26") {
+		t.Errorf("unknown versions should use the newest environment")
+	}
+	if env.version != collverNewest {
+		t.Errorf("unknown version resolved to %s (expected %s)", env.version, collverNewest)
+	}
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collations
+
+import (
+	"sort"
+	"unicode/utf8"
+
+	"vitess.io/vitess/go/mysql/collations/internal/charset"
+)
+
+// codepointCollation is implemented by the collations that compare strings one codepoint
+// at a time, using a single weight for every codepoint: two strings compare like the
+// sequences of the weights of their codepoints, and the shortest one is padded with spaces
+// if the collation is PAD SPACE.
+type codepointCollation interface {
+	Collation
+	// codepointWeight returns the weight of the given codepoint, or false if the codepoint
+	// cannot be represented in the charset of the collation. The weights are only meaningful
+	// when compared with other weights of the same collation.
+	codepointWeight(r rune) (uint32, bool)
+}
+
+// encodedWeight returns the encoding of the given codepoint in the charset as a weight,
+// for the collations that compare their strings byte by byte. Since no valid encoding of
+// a codepoint is a prefix of the encoding of another one, left-aligning the encoded bytes
+// makes the weights compare like the encodings.
+func encodedWeight(cs charset.Charset, r rune) (uint32, bool) {
+	var buf [4]byte
+	n := cs.EncodeRune(buf[:], r)
+	if n <= 0 || n > len(buf) {
+		return 0, false
+	}
+	// some charsets map several codepoints to the same encoding, so only the ones
+	// that survive a round trip are actually part of the charset
+	if decoded, _ := cs.DecodeRune(buf[:n]); decoded != r {
+		return 0, false
+	}
+	return uint32(buf[0])<<24 | uint32(buf[1])<<16 | uint32(buf[2])<<8 | uint32(buf[3]), true
+}
+
+// SortOrderEquivalent returns whether sorting strings with the collation `dst` yields the
+// same order as sorting them with `src`, once they have been transcoded from the charset
+// of `src` into the charset of `dst`. This tells, for instance, whether an index on a column
+// needs to be rebuilt when the column is migrated from latin1 to utf8mb4. Only the strings
+// made of codepoints that can be represented in both charsets are considered, since the other
+// ones cannot be transcoded.
+//
+// The analysis compares the weights that both collations give to every shared codepoint,
+// which is only possible for the collations that weight strings one codepoint at a time.
+// It is conservative, and returns false, unless `src` and `dst` are the same collation, for:
+//   - a pair of collations where one is PAD SPACE and the other is NO PAD
+//   - the UCA collations, whose contractions, expansions and multiple levels of weights
+//     mean that the order of two strings is not decided by the order of their codepoints
+//   - the binary collation, whose strings are not transcoded but reinterpreted
+func SortOrderEquivalent(src, dst Collation) bool {
+	if src.ID() == dst.ID() {
+		return true
+	}
+	if src.Capabilities().PadSpace != dst.Capabilities().PadSpace {
+		return false
+	}
+	srcCodepoints, ok := src.(codepointCollation)
+	if !ok {
+		return false
+	}
+	dstCodepoints, ok := dst.(codepointCollation)
+	if !ok {
+		return false
+	}
+
+	type weights struct{ src, dst uint32 }
+	var shared []weights
+	for r := rune(0); r <= utf8.MaxRune; r++ {
+		if r >= 0xD800 && r <= 0xDFFF {
+			// surrogates are not codepoints
+			continue
+		}
+		srcWeight, ok := srcCodepoints.codepointWeight(r)
+		if !ok {
+			continue
+		}
+		dstWeight, ok := dstCodepoints.codepointWeight(r)
+		if !ok {
+			continue
+		}
+		shared = append(shared, weights{srcWeight, dstWeight})
+	}
+
+	// the order is preserved if the weights in `dst` are a strictly increasing function of
+	// the weights in `src`: the codepoints that are equal in `src` must be equal in `dst`,
+	// and the ones that sort before others in `src` must sort before them in `dst` too
+	sort.Slice(shared, func(i, j int) bool {
+		return shared[i].src < shared[j].src
+	})
+	for i := 1; i < len(shared); i++ {
+		prev, cur := shared[i-1], shared[i]
+		if cur.src == prev.src && cur.dst != prev.dst {
+			return false
+		}
+		if cur.src > prev.src && cur.dst <= prev.dst {
+			return false
+		}
+	}
+	return true
+}
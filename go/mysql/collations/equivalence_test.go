@@ -0,0 +1,123 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collations
+
+import (
+	"math/rand"
+	"testing"
+
+	"vitess.io/vitess/go/mysql/collations/internal/charset"
+)
+
+func TestSortOrderEquivalent(t *testing.T) {
+	var cases = []struct {
+		src, dst string
+		expected bool
+	}{
+		{"utf8mb4_0900_ai_ci", "utf8mb4_0900_ai_ci", true},
+		{"ascii_bin", "utf8mb4_bin", true},
+		{"ascii_bin", "latin1_bin", true},
+		{"utf8_bin", "utf8mb4_bin", true},
+		{"utf8mb4_bin", "utf32_bin", true},
+		{"utf8_general_ci", "utf8mb4_general_ci", true},
+		{"ascii_general_ci", "latin1_general_ci", true},
+		// latin1 has the cp1252 characters in 0x80-0x9F, like € (U+20AC) in 0x80
+		{"latin1_bin", "utf8mb4_bin", false},
+		// surrogate pairs sort before U+E000-U+FFFF in UTF-16
+		{"utf8mb4_bin", "utf16_bin", false},
+		{"latin1_swedish_ci", "latin1_bin", false},
+		{"utf8mb4_general_ci", "utf8mb4_bin", false},
+		// PAD SPACE and NO PAD
+		{"utf8mb4_bin", "utf8mb4_0900_bin", false},
+		// UCA collations are never analyzed
+		{"utf8mb4_unicode_ci", "utf8mb4_unicode_ci", true},
+		{"utf8_unicode_ci", "utf8mb4_unicode_ci", false},
+		{"utf8mb4_0900_ai_ci", "utf8mb4_0900_as_cs", false},
+		// binary strings are not transcoded
+		{"binary", "latin1_bin", false},
+	}
+
+	for _, tc := range cases {
+		src := testcollation(t, tc.src)
+		dst := testcollation(t, tc.dst)
+		if got := SortOrderEquivalent(src, dst); got != tc.expected {
+			t.Errorf("SortOrderEquivalent(%s, %s) = %v (expected %v)", tc.src, tc.dst, got, tc.expected)
+		}
+	}
+}
+
+// TestSortOrderEquivalentStrings verifies that the collations that are equivalent according
+// to SortOrderEquivalent really sort random strings in the same order once transcoded
+func TestSortOrderEquivalentStrings(t *testing.T) {
+	var pairs = [][2]string{
+		{"ascii_bin", "utf8mb4_bin"},
+		{"utf8_bin", "utf8mb4_bin"},
+		{"utf8mb4_bin", "utf32_bin"},
+		{"utf8_general_ci", "utf8mb4_general_ci"},
+		{"ascii_general_ci", "latin1_general_ci"},
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	for _, pair := range pairs {
+		src := testcollation(t, pair[0])
+		dst := testcollation(t, pair[1])
+		if !SortOrderEquivalent(src, dst) {
+			t.Fatalf("%s and %s are expected to be equivalent", pair[0], pair[1])
+		}
+
+		// the shared codepoints are biased towards ASCII and spaces, so that the
+		// random strings are often equal or padded with spaces
+		var shared []rune
+		for _, r := range []rune{' ', 'a', 'A', 'b', '\t', 'é', 'É', 'ß', '日', '😀', 0xFFFD, 0xE000} {
+			if _, ok := src.(codepointCollation).codepointWeight(r); !ok {
+				continue
+			}
+			if _, ok := dst.(codepointCollation).codepointWeight(r); !ok {
+				continue
+			}
+			shared = append(shared, r)
+		}
+
+		randomString := func() []byte {
+			var str []byte
+			var buf [4]byte
+			for n := rng.Intn(5); n > 0; n-- {
+				w := src.Charset().EncodeRune(buf[:], shared[rng.Intn(len(shared))])
+				str = append(str, buf[:w]...)
+			}
+			return str
+		}
+
+		for i := 0; i < 1000; i++ {
+			left, right := randomString(), randomString()
+			leftDst, err := charset.Convert(nil, dst.Charset(), left, src.Charset())
+			if err != nil {
+				t.Fatal(err)
+			}
+			rightDst, err := charset.Convert(nil, dst.Charset(), right, src.Charset())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			want := sign(src.Collate(left, right, false))
+			if got := sign(dst.Collate(leftDst, rightDst, false)); got != want {
+				t.Errorf("%s -> %s: %q and %q compare as %d after transcoding (expected %d)",
+					pair[0], pair[1], left, right, got, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collations
+
+const (
+	fnvOffset64 = 14695981039346656037
+	fnvPrime64  = 1099511628211
+)
+
+// weightHasher is a 64-bit FNV-1a hash that is fed one byte at a time, so the
+// weight strings for a collation can be hashed while they're being generated,
+// without ever storing them in memory.
+type weightHasher uint64
+
+func newWeightHasher() weightHasher {
+	return fnvOffset64
+}
+
+func (h *weightHasher) writeByte(b byte) {
+	*h = (*h ^ weightHasher(b)) * fnvPrime64
+}
+
+func (h *weightHasher) write(p []byte) {
+	hh := *h
+	for _, b := range p {
+		hh = (hh ^ weightHasher(b)) * fnvPrime64
+	}
+	*h = hh
+}
+
+func (h *weightHasher) writeUint16(w uint16) {
+	h.writeByte(byte(w >> 8))
+	h.writeByte(byte(w))
+}
+
+// pad writes `count` copies of the given padding weight, as WeightString does
+// when padding a string up to a fixed number of codepoints
+func (h *weightHasher) pad(padding []byte, count int) {
+	for ; count > 0; count-- {
+		h.write(padding)
+	}
+}
+
+func (h weightHasher) sum64() uint64 {
+	return uint64(h)
+}
+
+var (
+	hashPadding8  = []byte{' '}
+	hashPadding16 = []byte{0x00, 0x20}
+	hashPadding24 = []byte{0x00, 0x00, 0x20}
+)
+
+// hashLimit returns the maximum amount of codepoints from `src` that must be hashed
+// for the given `numCodepoints` argument, and whether the hash must be padded up to
+// that amount of codepoints. Since there's no pre-allocated slice to fill when hashing,
+// PadToMax is handled just like 0.
+func hashLimit(src []byte, numCodepoints int) (int, bool) {
+	if numCodepoints == 0 || numCodepoints == PadToMax {
+		// every codepoint takes at least one byte, so this is always enough
+		return len(src), false
+	}
+	return numCodepoints, true
+}
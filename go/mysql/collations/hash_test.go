@@ -0,0 +1,93 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collations
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	"vitess.io/vitess/go/mysql/collations/internal/charset"
+)
+
+func TestHashMatchesWeightString(t *testing.T) {
+	var inputs = append([]string{"", "a", "Abc  ", "Straße"}, AllTestStrings...)
+
+	for _, coll := range All() {
+		for _, input := range inputs {
+			converted, err := charset.ConvertFromUTF8(nil, coll.Charset(), []byte(input))
+			if err != nil {
+				continue
+			}
+			// trailing spaces are not significant in PAD SPACE collations, so they're
+			// not hashed unless the string is padded anyway
+			trimmed, _ := charset.ConvertFromUTF8(nil, coll.Charset(), []byte(strings.TrimRight(input, " ")))
+
+			for _, numCodepoints := range []int{0, 1, 8, 256} {
+				weighted := converted
+				if numCodepoints == 0 && coll.Capabilities().PadSpace {
+					weighted = trimmed
+				}
+				h := newWeightHasher()
+				h.write(coll.WeightString(nil, weighted, numCodepoints))
+
+				if hash := coll.Hash(converted, numCodepoints); hash != h.sum64() {
+					t.Errorf("%s: Hash(%q, %d) = %x, hash of the weight string is %x",
+						coll.Name(), input, numCodepoints, hash, h.sum64())
+				}
+			}
+		}
+	}
+}
+
+// TestHashFuzz generates random strings out of fragments that are often equal to
+// each other under a collation, and verifies that equal strings always hash the same.
+func TestHashFuzz(t *testing.T) {
+	var fragments = []string{"a", "A", "á", "Á", "b", "B", "ss", "ß", "SS", "ae", "æ", "Æ", " ", "ｂ", "­"}
+	var rng = rand.New(rand.NewSource(0xC0FFEE))
+
+	randomString := func() string {
+		var b strings.Builder
+		for n := rng.Intn(4); n > 0; n-- {
+			b.WriteString(fragments[rng.Intn(len(fragments))])
+		}
+		return b.String()
+	}
+
+	for _, coll := range All() {
+		var inputs [][]byte
+		for len(inputs) < 64 {
+			converted, err := charset.ConvertFromUTF8(nil, coll.Charset(), []byte(randomString()))
+			if err != nil {
+				continue
+			}
+			inputs = append(inputs, converted)
+		}
+
+		for _, left := range inputs {
+			for _, right := range inputs {
+				if coll.Collate(left, right, false) != 0 {
+					continue
+				}
+				if hl, hr := coll.Hash(left, 0), coll.Hash(right, 0); hl != hr {
+					t.Errorf("%s: %q and %q are equal, but have different hashes (%x vs %x)",
+						coll.Name(), left, right, hl, hr)
+				}
+			}
+		}
+	}
+}
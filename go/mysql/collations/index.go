@@ -0,0 +1,42 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collations
+
+import "bytes"
+
+// collationIndex implements Index for any collation the same way MySQL implements
+// INSTR and LOCATE for multibyte charsets: starting at every codepoint boundary in
+// `haystack`, the window with as many bytes as `needle` is compared with `needle`
+// using the collation. Expansions and contractions are therefore matched like in
+// MySQL: "ß" is found in "strasse" because "ss" is as long as its UTF-8 encoding
+// and both are equal in the UCA collations, but the window is never resized to
+// match an expansion with a different length.
+func collationIndex(coll Collation, haystack, needle []byte) int {
+	if len(needle) == 0 {
+		return 0
+	}
+
+	cs := coll.Charset()
+	for pos := 0; pos+len(needle) <= len(haystack); {
+		window := haystack[pos : pos+len(needle)]
+		if bytes.Equal(window, needle) || coll.Equal(window, needle) {
+			return pos
+		}
+		pos += nextCodepointWidth(cs, haystack[pos:])
+	}
+	return -1
+}
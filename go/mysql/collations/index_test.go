@@ -0,0 +1,70 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collations
+
+import (
+	"testing"
+)
+
+func TestIndex(t *testing.T) {
+	var cases = []struct {
+		collation        string
+		haystack, needle string
+		expected         int
+	}{
+		{"utf8mb4_0900_ai_ci", "hello world", "world", 6},
+		{"utf8mb4_0900_ai_ci", "hello world", "WORLD", 6},
+		{"utf8mb4_0900_ai_ci", "hello world", "", 0},
+		{"utf8mb4_0900_ai_ci", "", "a", -1},
+		// like in MySQL, "é" is never compared with "e" because it's two bytes long
+		{"utf8mb4_0900_ai_ci", "résumé", "e", -1},
+		{"utf8mb4_0900_ai_ci", "résumé", "E", -1},
+		{"utf8mb4_0900_ai_ci", "résumé", "É", 1},
+		{"utf8mb4_0900_as_cs", "résumé", "e", -1},
+		{"utf8mb4_0900_as_cs", "résumé", "é", 1},
+		// expansions are only found when their length matches the window
+		{"utf8mb4_0900_ai_ci", "strasse", "ß", 4},
+		{"utf8mb4_0900_ai_ci", "straße", "ss", 4},
+		{"utf8mb4_0900_ai_ci", "straße", "s", 0},
+		{"utf8mb4_0900_ai_ci", "aße", "sse", 1},
+		{"utf8mb4_0900_ai_ci", "aße", "se", -1},
+		{"utf8mb4_de_pb_0900_ai_ci", "strasse", "ß", 4},
+		{"utf8mb4_0900_ai_ci", "encyclopædia", "ae", 8},
+		// contractions are only matched when they are fully inside the window: "ch" is a
+		// single letter in Czech, but "c" and "h" are still found on their own
+		{"utf8mb4_cs_0900_ai_ci", "chleba", "CH", 0},
+		{"utf8mb4_cs_0900_ai_ci", "chleba", "h", 1},
+		{"utf8mb4_cs_0900_ai_ci", "chleba", "c", 0},
+		// windows always start at a codepoint boundary
+		{"utf8mb4_0900_ai_ci", "日本語", "語", 6},
+		{"utf8mb4_0900_ai_ci", "\xe6\x97\xa5a", "\x97\xa5a", -1},
+		{"utf8mb4_general_ci", "Straße", "STRASSE", -1},
+		{"utf8mb4_general_ci", "Straße", "SS", -1},
+		{"utf8mb4_general_ci", "xXx", "X", 0},
+		{"utf8mb4_bin", "xXx", "X", 1},
+		{"latin1_swedish_ci", "caf\xe9", "E", 3},
+		{"binary", "aAa", "A", 1},
+		{"sjis_japanese_ci", "\x83\x41A", "A", 2},
+	}
+
+	for _, tc := range cases {
+		coll := testcollation(t, tc.collation)
+		if got := coll.Index([]byte(tc.haystack), []byte(tc.needle)); got != tc.expected {
+			t.Errorf("%s: Index(%q, %q) = %d (expected %d)", tc.collation, tc.haystack, tc.needle, got, tc.expected)
+		}
+	}
+}
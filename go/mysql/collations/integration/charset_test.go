@@ -70,6 +70,18 @@ func TestLocalEncodings(t *testing.T) {
 			collation: "latin1_swedish_ci",
 			input:     []byte("abcdABCD01234"),
 		},
+		{
+			collation: "utf16_general_ci",
+			input:     []byte("abcd é 日本語 😀 \U0010FFFF"),
+		},
+		{
+			collation: "utf16le_general_ci",
+			input:     []byte("abcd é 日本語 😀 \U0010FFFF"),
+		},
+		{
+			collation: "utf32_general_ci",
+			input:     []byte("abcd é 日本語 😀 \U0010FFFF"),
+		},
 	}
 
 	conn := mysqlconn(t)
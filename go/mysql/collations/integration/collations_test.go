@@ -25,8 +25,10 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"sort"
 	"strings"
 	"testing"
+	"unicode/utf8"
 
 	"golang.org/x/text/encoding/unicode/utf32"
 
@@ -201,6 +203,184 @@ func TestRemoteKanaSensitivity(t *testing.T) {
 	})
 }
 
+func TestRemoteAccentAndCaseSensitivity(t *testing.T) {
+	var inputs = [][]byte{
+		[]byte("resume"), []byte("résumé"), []byte("Resume"), []byte("RÉSUMÉ"),
+		[]byte("Straße"), []byte("strasse"), []byte("ǅemal"), []byte("Ǆemal"),
+	}
+
+	var weights []testweight
+	var comparisons []testcmp
+	for i, left := range inputs {
+		weights = append(weights, testweight{"utf8mb4_0900_as_cs", left})
+		for _, right := range inputs[i+1:] {
+			comparisons = append(comparisons, testcmp{"utf8mb4_0900_as_cs", left, right})
+		}
+	}
+
+	testRemoteWeights(t, nil, weights)
+	testRemoteComparison(t, nil, comparisons)
+}
+
+func TestRemoteTrailingSpaces(t *testing.T) {
+	var inputs = [][]byte{
+		[]byte("abc"), []byte("abc "), []byte("abc   "), []byte("abc\t"), []byte("abc \t"), []byte("abc\x00"), []byte(""), []byte(" "),
+	}
+	var collationNames = []string{
+		// NO PAD collations
+		"utf8mb4_0900_ai_ci", "utf8mb4_0900_as_cs", "utf8mb4_0900_bin", "binary",
+		// PAD SPACE collations
+		"utf8mb4_bin", "utf8mb4_general_ci", "utf8mb4_unicode_ci", "latin1_swedish_ci", "latin1_bin",
+	}
+
+	var comparisons []testcmp
+	for _, collation := range collationNames {
+		for i, left := range inputs {
+			for _, right := range inputs[i+1:] {
+				comparisons = append(comparisons, testcmp{collation, left, right})
+			}
+		}
+	}
+	testRemoteComparison(t, nil, comparisons)
+}
+
+// TestRemote8bitFullRange verifies the ordering of every byte in the single-byte collations,
+// and in particular that the case sensitive ones don't sort like the case insensitive defaults.
+// The bytes are sorted locally, so comparing every pair of consecutive bytes with the server
+// is enough to verify the whole order.
+func TestRemote8bitFullRange(t *testing.T) {
+	var collationNames = []string{
+		"latin1_general_cs", "latin1_bin", "latin7_general_cs", "latin7_estonian_cs", "cp1251_general_cs", "cp1251_bin",
+		"latin1_swedish_ci", "latin1_general_ci",
+	}
+
+	var comparisons []testcmp
+	for _, collation := range collationNames {
+		local := collations.FromName(collation)
+		inputs := make([][]byte, 256)
+		for b := range inputs {
+			inputs[b] = []byte{byte(b)}
+		}
+		sort.SliceStable(inputs, func(i, j int) bool {
+			return local.Collate(inputs[i], inputs[j], false) < 0
+		})
+		for i := 1; i < len(inputs); i++ {
+			comparisons = append(comparisons, testcmp{collation, inputs[i-1], inputs[i]})
+		}
+	}
+	testRemoteComparison(t, nil, comparisons)
+}
+
+func TestRemoteEmptyStrings(t *testing.T) {
+	var inputs = [][]byte{[]byte(""), []byte(" "), []byte("a"), []byte("\x00"), []byte("\u200b"), []byte("\u0301")}
+	var collationNames = []string{
+		"utf8mb4_0900_ai_ci", "utf8mb4_0900_as_cs", "utf8mb4_0900_bin", "utf8mb4_ja_0900_as_cs_ks",
+		"utf8mb4_unicode_ci", "utf8mb4_unicode_520_ci", "utf8mb4_general_ci", "utf8mb4_bin", "binary",
+	}
+
+	var comparisons []testcmp
+	for _, collation := range collationNames {
+		for _, left := range inputs {
+			for _, right := range inputs {
+				if len(left) == 0 || len(right) == 0 {
+					comparisons = append(comparisons, testcmp{collation, left, right})
+				}
+			}
+		}
+	}
+	testRemoteComparison(t, nil, comparisons)
+}
+
+// TestRemoteLocate verifies that Index finds the same occurrences as LOCATE in MySQL for
+// needles that are equal to parts of the haystack because of expansions, contractions,
+// accents or case, even if they're not equal byte-wise
+func TestRemoteLocate(t *testing.T) {
+	conn := mysqlconn(t)
+	defer conn.Close()
+
+	var cases = []struct {
+		collation        string
+		haystack, needle string
+	}{
+		{"utf8mb4_0900_ai_ci", "strasse", "ß"},
+		{"utf8mb4_0900_ai_ci", "straße", "ss"},
+		{"utf8mb4_0900_ai_ci", "straße", "SS"},
+		{"utf8mb4_0900_as_cs", "straße", "ss"},
+		{"utf8mb4_de_pb_0900_ai_ci", "strasse", "ß"},
+		{"utf8mb4_de_pb_0900_ai_ci", "Straße", "sse"},
+		{"utf8mb4_0900_ai_ci", "aße", "se"},
+		{"utf8mb4_0900_ai_ci", "encyclopædia", "ae"},
+		{"utf8mb4_0900_ai_ci", "résumé", "e"},
+		{"utf8mb4_0900_ai_ci", "résumé", "É"},
+		{"utf8mb4_0900_as_cs", "résumé", "É"},
+		{"utf8mb4_cs_0900_ai_ci", "chleba", "CH"},
+		{"utf8mb4_cs_0900_ai_ci", "chleba", "h"},
+		{"utf8mb4_cs_0900_ai_ci", "chleba", "c"},
+		{"utf8mb4_ja_0900_as_cs_ks", "の東京ノ", "ノ"},
+		{"utf8mb4_0900_ai_ci", "の東京ノ", "ノ"},
+		{"utf8mb4_general_ci", "Straße", "SS"},
+		{"utf8mb4_unicode_ci", "Straße", "SS"},
+		{"utf8mb4_bin", "xXx", "X"},
+		{"latin1_swedish_ci", "caf\xe9", "E"},
+		{"utf8mb4_0900_ai_ci", "hello", ""},
+	}
+
+	for _, tc := range cases {
+		local := collations.FromName(tc.collation)
+		remote := remote.ForName(conn, tc.collation)
+		haystack, needle := []byte(tc.haystack), []byte(tc.needle)
+
+		remoteResult := remote.Locate(haystack, needle)
+		checkRemoteError(t, remote.LastError())
+
+		// LOCATE returns a position in codepoints starting at 1, while Index returns an offset in bytes
+		localResult := 0
+		if offset := local.Index(haystack, needle); offset >= 0 {
+			localResult = utf8.RuneCount(haystack[:offset]) + 1
+		}
+		if localResult != remoteResult {
+			t.Errorf("%s: expected LOCATE(%q, %q) = %d (got %d)", tc.collation, tc.needle, tc.haystack, remoteResult, localResult)
+		}
+	}
+}
+
+func TestRemoteUpperLower(t *testing.T) {
+	conn := mysqlconn(t)
+	defer conn.Close()
+
+	var cases = []struct {
+		collation string
+		input     string
+	}{
+		{"utf8mb4_turkish_ci", "iIıİ istanbul ISPARTA"},
+		{"utf8mb4_general_ci", "iIıİ istanbul ISPARTA"},
+		{"utf8mb4_tr_0900_ai_ci", "iIıİ istanbul ISPARTA"},
+		{"utf8mb4_0900_ai_ci", "iIıİ istanbul ISPARTA"},
+		{"utf8mb4_0900_ai_ci", "Ça va très bien, straße"},
+		{"utf8mb4_bin", "😀 Ωμέγα"},
+		{"latin1_swedish_ci", "caf\xe9 CAF\xc9"},
+		{"latin5_turkish_ci", "Ii\xdd\xfd"},
+	}
+
+	for _, tc := range cases {
+		local := collations.FromName(tc.collation)
+		remote := remote.ForName(conn, tc.collation)
+		input := []byte(tc.input)
+
+		remoteUpper := remote.Upper(nil, input)
+		checkRemoteError(t, remote.LastError())
+		if localUpper := local.Upper(nil, input); !bytes.Equal(localUpper, remoteUpper) {
+			t.Errorf("%s: expected UPPER(%q) = %q (got %q)", tc.collation, tc.input, remoteUpper, localUpper)
+		}
+
+		remoteLower := remote.Lower(nil, input)
+		checkRemoteError(t, remote.LastError())
+		if localLower := local.Lower(nil, input); !bytes.Equal(localLower, remoteLower) {
+			t.Errorf("%s: expected LOWER(%q) = %q (got %q)", tc.collation, tc.input, remoteLower, localLower)
+		}
+	}
+}
+
 const ExampleString = "abc æøå 日本語"
 
 func TestCollationWithSpace(t *testing.T) {
@@ -225,3 +405,117 @@ func TestCollationWithSpace(t *testing.T) {
 		})
 	}
 }
+
+// TestLegacyUnicodeCollations verifies the two most common collations from MySQL 5.7,
+// which are still widely used in existing schemas: utf8mb4_general_ci, which uses the
+// simplified case folding tables of MySQL instead of UCA, and utf8mb4_unicode_ci, which
+// uses the UCA 4.0.0 weights. Unlike the rest of the integration tests, this one also
+// runs against MySQL 5.7, where these collations must behave exactly like in 8.0.
+func TestLegacyUnicodeCollations(t *testing.T) {
+	conn := mysqlconnVersions(t, "5.7.", "8.0.")
+	defer conn.Close()
+
+	var inputs = [][]byte{
+		[]byte("Straße"), []byte("strasse"), []byte("strase"),
+		[]byte("æ"), []byte("ae"), []byte("Æ"),
+		[]byte("résumé"), []byte("RESUME"), []byte("ǅemal"), []byte("dzemal"),
+		[]byte("ｂ"), []byte("b"), []byte("abc  "), []byte("abc"),
+		[]byte("日本語"), []byte("😀"), []byte("🐶"),
+	}
+
+	for _, collName := range []string{"utf8mb4_general_ci", "utf8mb4_unicode_ci"} {
+		t.Run(collName, func(t *testing.T) {
+			local := collations.FromName(collName)
+			remote := remote.ForName(conn, collName)
+
+			for i, left := range inputs {
+				verifyWeightString(t, local, remote, left)
+
+				for _, right := range inputs[i+1:] {
+					localResult := normalizeCollate(local.Collate(left, right, false))
+					remoteResult := remote.Collate(left, right, false)
+					checkRemoteError(t, remote.LastError())
+
+					if localResult != remoteResult {
+						t.Errorf("expected STRCMP(%q, %q) = %d (got %d)", left, right, remoteResult, localResult)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestHandshakeCollation(t *testing.T) {
+	conn := mysqlconnVersions(t, "5.", "8.")
+	defer conn.Close()
+
+	res, err := conn.ExecuteFetch("SELECT id, collation_name FROM information_schema.collations WHERE collation_name = @@collation_server", 1, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Rows) != 1 {
+		t.Fatalf("unexpected result for @@collation_server: %v", res.Rows)
+	}
+	serverID, err := res.Rows[0][0].ToUint64()
+	if err != nil {
+		t.Fatal(err)
+	}
+	serverName := res.Rows[0][1].ToString()
+
+	coll := collations.FromHandshakeExtended(conn.CharacterSet, uint16(serverID))
+	if coll == nil {
+		t.Skipf("the server collation %s is not supported", serverName)
+	}
+	if coll.Name() != serverName {
+		t.Errorf("handshake collation (%d) resolved to %s (expected %s)", conn.CharacterSet, coll.Name(), serverName)
+	}
+	if serverID <= 0xff && collations.FromHandshake(conn.CharacterSet) != coll {
+		t.Errorf("FromHandshake(%d) does not match the server collation %s", conn.CharacterSet, serverName)
+	}
+}
+
+func TestCollationMetadata(t *testing.T) {
+	conn := mysqlconn(t)
+	defer conn.Close()
+
+	res, err := conn.ExecuteFetch("SELECT COLLATION_NAME, CHARACTER_SET_NAME, ID, IS_DEFAULT, IS_COMPILED, SORTLEN, PAD_ATTRIBUTE FROM information_schema.COLLATIONS", 1000, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	env, err := collations.NewEnvironment(conn.ServerVersion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, row := range res.Rows {
+		name := row[0].ToString()
+		coll, err := env.LookupByName(name)
+		if err != nil {
+			continue
+		}
+		id, err := row[2].ToUint64()
+		if err != nil {
+			t.Fatal(err)
+		}
+		sortlen, err := row[5].ToInt64()
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := collations.CollationInfo{
+			Name:         name,
+			Charset:      row[1].ToString(),
+			ID:           collations.ID(id),
+			IsDefault:    row[3].ToString() == "Yes",
+			IsCompiled:   row[4].ToString() == "Yes",
+			SortLen:      int(sortlen),
+			PadAttribute: row[6].ToString(),
+		}
+		// newer versions of MySQL call the utf8 charset utf8mb3
+		if expected.Charset == "utf8mb3" {
+			expected.Charset = "utf8"
+		}
+		if got := env.Metadata(coll); got != expected {
+			t.Errorf("%s: Metadata = %+v\nexpected %+v", name, got, expected)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+
+	gomysql "github.com/go-sql-driver/mysql"
+
+	"vitess.io/vitess/go/mysql"
+)
+
+// Connect opens a connection to the MySQL server described by `dsn`, so that the local
+// collations can be compared with the ones in the server using the remote package outside
+// of `go test`, e.g. to generate the golden files for the tests. The DSN has the same
+// format as in the go-sql-driver/mysql package: `user:password@unix(/path/to/mysql.sock)/db`
+// for a Unix socket, or `user:password@tcp(host:port)/db` for a TCP connection.
+func Connect(dsn string) (*mysql.Conn, error) {
+	params, err := connParamsFromDSN(dsn)
+	if err != nil {
+		return nil, err
+	}
+	return connect(params)
+}
+
+func connect(params *mysql.ConnParams) (*mysql.Conn, error) {
+	return mysql.Connect(context.Background(), params)
+}
+
+func connParamsFromDSN(dsn string) (*mysql.ConnParams, error) {
+	cfg, err := gomysql.ParseDSN(dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	params := &mysql.ConnParams{
+		Uname:  cfg.User,
+		Pass:   cfg.Passwd,
+		DbName: cfg.DBName,
+	}
+	switch cfg.Net {
+	case "unix":
+		params.UnixSocket = cfg.Addr
+	case "tcp":
+		host, port, err := net.SplitHostPort(cfg.Addr)
+		if err != nil {
+			return nil, err
+		}
+		params.Host = host
+		if params.Port, err = strconv.Atoi(port); err != nil {
+			return nil, fmt.Errorf("invalid port in DSN: %q", port)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported network in DSN: %q", cfg.Net)
+	}
+	return params, nil
+}
@@ -0,0 +1,48 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package integration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"vitess.io/vitess/go/mysql"
+)
+
+func TestConnParamsFromDSN(t *testing.T) {
+	var cases = []struct {
+		dsn      string
+		expected mysql.ConnParams
+	}{
+		{"root@unix(/tmp/mysql.sock)/vttest", mysql.ConnParams{Uname: "root", UnixSocket: "/tmp/mysql.sock", DbName: "vttest"}},
+		{"user:secret@tcp(127.0.0.1:3307)/", mysql.ConnParams{Uname: "user", Pass: "secret", Host: "127.0.0.1", Port: 3307}},
+		{"root@tcp(localhost)/", mysql.ConnParams{Uname: "root", Host: "localhost", Port: 3306}},
+		{"/", mysql.ConnParams{Host: "127.0.0.1", Port: 3306}},
+	}
+
+	for _, tc := range cases {
+		params, err := connParamsFromDSN(tc.dsn)
+		require.NoError(t, err, tc.dsn)
+		require.Equal(t, tc.expected, *params, tc.dsn)
+	}
+
+	for _, dsn := range []string{"root@pipe(mysql)/", "root@tcp(localhost:mysql)/", "invalid"} {
+		_, err := connParamsFromDSN(dsn)
+		require.Error(t, err, dsn)
+	}
+}
@@ -19,6 +19,7 @@ package integration
 import (
 	"bytes"
 	"encoding/hex"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
@@ -46,16 +47,34 @@ func testRemoteWeights(t *testing.T, golden io.Writer, cases []testweight) {
 	conn := mysqlconn(t)
 	defer conn.Close()
 
+	// fetch the weight strings for all the cases of each collation with a single query
+	var inputsByCollation = make(map[string][][]byte)
 	for _, tc := range cases {
+		inputsByCollation[tc.collation] = append(inputsByCollation[tc.collation], tc.input)
+	}
+	var remoteWeights = make(map[string][][]byte)
+	var remoteErrors = make(map[string]error)
+	for collation, inputs := range inputsByCollation {
+		weights, err := remote.BatchWeightStrings(conn, collation, inputs)
+		if err != nil {
+			remoteErrors[collation] = err
+			continue
+		}
+		remoteWeights[collation] = weights
+	}
+
+	for _, tc := range cases {
+		var remoteResult []byte
+		if weights := remoteWeights[tc.collation]; len(weights) > 0 {
+			remoteResult = weights[0]
+			remoteWeights[tc.collation] = weights[1:]
+		}
+
 		t.Run(tc.collation, func(t *testing.T) {
+			checkRemoteError(t, remoteErrors[tc.collation])
+
 			local := collations.FromName(tc.collation)
-			remote := remote.ForName(conn, tc.collation)
 			localResult := local.WeightString(nil, tc.input, 0)
-			remoteResult := remote.WeightString(nil, tc.input, 0)
-
-			if err := remote.LastError(); err != nil {
-				t.Fatalf("remote collation failed: %v", err)
-			}
 
 			if !bytes.Equal(localResult, remoteResult) {
 				t.Errorf("expected WEIGHT_STRING(%#v) = %#v (got %#v)", tc.input, remoteResult, localResult)
@@ -68,16 +87,30 @@ func testRemoteWeights(t *testing.T, golden io.Writer, cases []testweight) {
 	}
 }
 
-func testRemoteComparison(t *testing.T, golden io.Writer, cases []testcmp) {
-	normalizecmp := func(res int) int {
-		if res < 0 {
-			return -1
-		}
-		if res > 0 {
-			return 1
-		}
-		return 0
+// checkRemoteError fails the test if a query to the remote collation failed, or skips it
+// if the remote server doesn't support the collation at all
+func checkRemoteError(t *testing.T, err error) {
+	t.Helper()
+	if errors.Is(err, remote.ErrUnsupported) {
+		t.Skipf("skipping: %v", err)
 	}
+	if err != nil {
+		t.Fatalf("remote collation failed: %v", err)
+	}
+}
+
+// normalizeCollate normalizes the result of Collate to -1, 0 or 1, like STRCMP in MySQL
+func normalizeCollate(res int) int {
+	if res < 0 {
+		return -1
+	}
+	if res > 0 {
+		return 1
+	}
+	return 0
+}
+
+func testRemoteComparison(t *testing.T, golden io.Writer, cases []testcmp) {
 
 	conn := mysqlconn(t)
 	defer conn.Close()
@@ -86,12 +119,10 @@ func testRemoteComparison(t *testing.T, golden io.Writer, cases []testcmp) {
 		t.Run(tc.collation, func(t *testing.T) {
 			local := collations.FromName(tc.collation)
 			remote := remote.ForName(conn, tc.collation)
-			localResult := normalizecmp(local.Collate(tc.left, tc.right, false))
+			localResult := normalizeCollate(local.Collate(tc.left, tc.right, false))
 			remoteResult := remote.Collate(tc.left, tc.right, false)
 
-			if err := remote.LastError(); err != nil {
-				t.Fatalf("remote collation failed: %v", err)
-			}
+			checkRemoteError(t, remote.LastError())
 			if localResult != remoteResult {
 				t.Errorf("expected STRCMP(%q, %q) = %d (got %d)", string(tc.left), string(tc.right), remoteResult, localResult)
 			}
@@ -124,9 +155,7 @@ func verifyWeightString(t *testing.T, local collations.Collation, remote *remote
 	localResult := local.WeightString(nil, text, 0)
 	remoteResult := remote.WeightString(nil, text, 0)
 
-	if err := remote.LastError(); err != nil {
-		t.Fatalf("remote collation failed: %v", err)
-	}
+	checkRemoteError(t, remote.LastError())
 
 	if len(remoteResult) == 0 {
 		t.Logf("remote collation %s returned empty string", remote.Name())
@@ -143,10 +172,11 @@ func verifyWeightString(t *testing.T, local collations.Collation, remote *remote
 			bad.Write(text)
 			bad.Close()
 
-			colldumpDebug = fmt.Sprintf("manual debugging:\n\tcolldump --test %s < %s\n\n", local.Name(), bad.Name())
+			colldumpDebug = fmt.Sprintf("manual debugging:\n\tcolldiff -test -dsn <dsn> %s < %s\n\n",
+				local.Name(), bad.Name())
 		}
-		t.Fatalf("WEIGHT_STRING mismatch with collation %s (charset %s)\ninput:\n%s\nremote:\n%s\nlocal:\n%s\ngolden:\n%#v\n\n%s",
-			local.Name(), local.Charset().Name(), hex.Dump(text), hex.Dump(remoteResult), hex.Dump(localResult), text, colldumpDebug)
+		t.Fatalf("WEIGHT_STRING mismatch with collation %s (charset %s)\ninput:\n%s\n%s\ngolden:\n%#v\n\n%s",
+			local.Name(), local.Charset().Name(), hex.Dump(text), collations.DiffWeightStrings(localResult, remoteResult), text, colldumpDebug)
 	}
 }
 
@@ -159,10 +189,9 @@ func exec(t *testing.T, conn *mysql.Conn, query string) *sqltypes.Result {
 }
 
 func GoldenWeightString(t *testing.T, conn *mysql.Conn, collation string, input []byte) []byte {
-	coll := remote.ForName(conn, collation)
-	weightString := coll.WeightString(nil, input, 0)
-	if weightString == nil {
-		t.Fatal(coll.LastError())
+	weights, err := remote.BatchWeightStrings(conn, collation, [][]byte{input})
+	if err != nil {
+		t.Fatal(err)
 	}
-	return weightString
+	return weights[0]
 }
@@ -17,7 +17,6 @@ limitations under the License.
 package integration
 
 import (
-	"context"
 	"flag"
 	"fmt"
 	"os"
@@ -38,15 +37,25 @@ var (
 var waitmysql = flag.Bool("waitmysql", false, "")
 
 func mysqlconn(t *testing.T) *mysql.Conn {
-	conn, err := mysql.Connect(context.Background(), &connParams)
+	return mysqlconnVersions(t, "8.0.")
+}
+
+// mysqlconnVersions connects to the test server, and skips the test unless the version
+// of the server starts with one of the given prefixes
+func mysqlconnVersions(t *testing.T, versions ...string) *mysql.Conn {
+	conn, err := connect(&connParams)
 	if err != nil {
 		t.Fatal(err)
 	}
-	if !strings.HasPrefix(conn.ServerVersion, "8.0.") {
-		conn.Close()
-		t.Skipf("collation integration tests are only supported in MySQL 8.0+")
+	for _, version := range versions {
+		if strings.HasPrefix(conn.ServerVersion, version) {
+			return conn
+		}
 	}
-	return conn
+	conn.Close()
+	t.Skipf("this collation integration test is only supported in MySQL %s (server is %s)",
+		strings.Join(versions, ", "), conn.ServerVersion)
+	return nil
 }
 
 func TestMain(m *testing.M) {
@@ -17,6 +17,7 @@ limitations under the License.
 package integration
 
 import (
+	"bytes"
 	"sort"
 	"testing"
 
@@ -40,6 +41,58 @@ func TestFastIterators(t *testing.T) {
 	})
 }
 
+func TestInvalidUTF8(t *testing.T) {
+	var inputs = [][]byte{
+		[]byte("abc\xff"),
+		[]byte("abc\xe6\x97 def"),
+		[]byte("\xed\xa0\x80"),
+		[]byte("日本\xf0\x9f\x98語"),
+		[]byte("\xc0\xaf"),
+	}
+
+	var cases []testweight
+	for _, collation := range []string{"utf8mb4_0900_as_cs", "utf8mb4_0900_ai_ci", "utf8mb4_ja_0900_as_cs"} {
+		for _, input := range inputs {
+			cases = append(cases, testweight{collation, input})
+		}
+	}
+	testRemoteWeights(t, nil, cases)
+}
+
+func TestWeightStringLevels(t *testing.T) {
+	// MySQL 8.0 ignores the LEVEL clause in WEIGHT_STRING, so the weights for the first
+	// levels of a collation are compared against the collation that only has those levels
+	var levels = []struct {
+		collation string
+		level     int
+		remote    string
+	}{
+		{"utf8mb4_0900_as_cs", 1, "utf8mb4_0900_ai_ci"},
+		{"utf8mb4_0900_as_cs", 2, "utf8mb4_0900_as_ci"},
+		{"utf8mb4_es_0900_as_cs", 1, "utf8mb4_es_0900_ai_ci"},
+	}
+	var inputs = [][]byte{[]byte("abc"), []byte("Résumé ñandú"), []byte("ｶｰﾄﾞ カード かーど"), []byte(ExampleString)}
+
+	conn := mysqlconn(t)
+	defer conn.Close()
+
+	for _, tc := range levels {
+		t.Run(tc.collation, func(t *testing.T) {
+			local := collations.FromName(tc.collation).(*collations.Collation_utf8mb4_uca_0900)
+			remote := remote.ForName(conn, tc.remote)
+
+			for _, input := range inputs {
+				localResult := local.WeightStringLevels(nil, input, 0, tc.level)
+				remoteResult := remote.WeightString(nil, input, 0)
+				checkRemoteError(t, remote.LastError())
+				if !bytes.Equal(localResult, remoteResult) {
+					t.Errorf("WEIGHT_STRING(%q) LEVEL %d: expected %#v (got %#v)", input, tc.level, remoteResult, localResult)
+				}
+			}
+		})
+	}
+}
+
 func TestWeightStringsComprehensive(t *testing.T) {
 	type collationsForCharset struct {
 		charset charset.Charset
@@ -95,3 +148,46 @@ func TestWeightStringsComprehensive(t *testing.T) {
 			c4cs.charset.Name(), len(c4cs.locals), tested, len(c4cs.locals)*tested)
 	}
 }
+
+func TestWeightStringAsChar(t *testing.T) {
+	conn := mysqlconn(t)
+	defer conn.Close()
+
+	var inputs = []string{
+		"abc",
+		"Straße and more",
+		"abc æøå 日本語",
+		"  trailing spaces  ",
+		"阿咗𬺡āabūuUǖZ𔙆𗆠𬺢𮯠𳌳",
+	}
+	var collationNames = []string{
+		"utf8mb4_0900_ai_ci", "utf8mb4_0900_as_cs", "utf8mb4_0900_bin",
+		"utf8mb4_unicode_ci", "utf8mb4_unicode_520_ci", "utf8mb4_general_ci", "utf8mb4_bin",
+		"utf16_unicode_ci", "ucs2_general_ci", "latin1_swedish_ci", "latin1_bin",
+		"ujis_japanese_ci", "binary",
+	}
+
+	for _, collation := range collationNames {
+		t.Run(collation, func(t *testing.T) {
+			local := collations.FromName(collation)
+			remote := remote.ForName(conn, collation)
+
+			for _, input := range inputs {
+				converted, err := charset.ConvertFromUTF8(nil, local.Charset(), []byte(input))
+				if err != nil {
+					continue
+				}
+				for _, numCodepoints := range []int{1, 3, 8, 32} {
+					localResult := local.WeightString(nil, converted, numCodepoints)
+					remoteResult := remote.WeightString(nil, converted, numCodepoints)
+					checkRemoteError(t, remote.LastError())
+
+					if !bytes.Equal(localResult, remoteResult) {
+						t.Errorf("WEIGHT_STRING(%q AS CHAR(%d)) mismatch\n%s",
+							input, numCodepoints, collations.DiffWeightStrings(localResult, remoteResult))
+					}
+				}
+			}
+		})
+	}
+}
@@ -94,3 +94,26 @@ func IsUnicodeByName(csname string) bool {
 		return false
 	}
 }
+
+// Truncate returns the prefix of `src` that contains at most `numCodepoints` codepoints
+// in the given charset. Invalid sequences in `src` are counted as a single codepoint,
+// like MySQL does when truncating strings.
+func Truncate(cs Charset, src []byte, numCodepoints int) []byte {
+	switch cs.(type) {
+	case Charset_binary, Charset_latin1, *Charset_8bit:
+		if numCodepoints < len(src) {
+			return src[:numCodepoints]
+		}
+		return src
+	}
+
+	offset := 0
+	for ; offset < len(src) && numCodepoints > 0; numCodepoints-- {
+		_, width := cs.DecodeRune(src[offset:])
+		if width <= 0 {
+			width = 1
+		}
+		offset += width
+	}
+	return src[:offset]
+}
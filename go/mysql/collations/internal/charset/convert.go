@@ -73,6 +73,12 @@ func convertSlow(dst []byte, dstCharset Charset, src []byte, srcCharset Charset)
 			failed++
 			cp = '?'
 		}
+		// decoders for multibyte charsets can report a width that goes past the
+		// end of the input when the last sequence is truncated, or no width at
+		// all when the input is too short to start decoding a sequence
+		if width <= 0 || width > len(src) {
+			width = len(src)
+		}
 		src = src[width:]
 
 		if len(dst)-nDst < 4 {
@@ -125,3 +131,49 @@ func Convert(dst []byte, dstCharset Charset, src []byte, srcCharset Charset) ([]
 func ConvertFromUTF8(dst []byte, dstCharset Charset, src []byte) ([]byte, error) {
 	return Convert(dst, dstCharset, src, Charset_utf8mb4{})
 }
+
+// ConvertToUTF8 transforms `src`, encoded with Charset `srcCharset`, into UTF-8.
+// It is the inverse of ConvertFromUTF8: any invalid sequences in `src` are replaced
+// with '?', and an ErrFailedConversion is returned with the amount of sequences
+// that had to be replaced.
+func ConvertToUTF8(dst []byte, srcCharset Charset, src []byte) ([]byte, error) {
+	return Convert(dst, Charset_utf8mb4{}, src, srcCharset)
+}
+
+// ErrInvalidSequence is returned by ConvertToUTF8Strict when the input contains a
+// sequence of bytes that is not valid in its charset
+type ErrInvalidSequence struct {
+	Charset string
+	Offset  int
+}
+
+func (e *ErrInvalidSequence) Error() string {
+	return fmt.Sprintf("invalid %s sequence at offset %d", e.Charset, e.Offset)
+}
+
+// ConvertToUTF8Strict transforms `src`, encoded with Charset `srcCharset`, into UTF-8
+// and appends the result to `dst`. Unlike ConvertToUTF8, invalid sequences in `src`
+// are never replaced: the conversion stops at the first invalid sequence and returns
+// the result up to that point, together with the offset of the invalid sequence in
+// `src` and an *ErrInvalidSequence. If the whole input is valid, the returned offset
+// is len(src).
+func ConvertToUTF8Strict(dst []byte, srcCharset Charset, src []byte) ([]byte, int, error) {
+	var offset int
+	var buf [utf8.UTFMax]byte
+
+	for offset < len(src) {
+		cp, width := srcCharset.DecodeRune(src[offset:])
+		if cp == utf8.RuneError && width < 3 {
+			return dst, offset, &ErrInvalidSequence{Charset: srcCharset.Name(), Offset: offset}
+		}
+		offset += width
+
+		if cp < utf8.RuneSelf {
+			dst = append(dst, byte(cp))
+			continue
+		}
+		w := utf8.EncodeRune(buf[:], cp)
+		dst = append(dst, buf[:w]...)
+	}
+	return dst, offset, nil
+}
@@ -0,0 +1,246 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package charset
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestConvertToUTF8Strict(t *testing.T) {
+	var cases = []struct {
+		charset  Charset
+		input    string
+		expected string
+		offset   int
+	}{
+		{Charset_utf8mb4{}, "", "", -1},
+		{Charset_utf8mb4{}, "abc 日本語 😀", "abc 日本語 😀", -1},
+		{Charset_utf8mb4{}, "abc\xffdef", "abc", 3},
+		{Charset_utf8mb4{}, "日本\xe8\xaa", "日本", 6},
+		{Charset_utf8{}, "abc 日本語", "abc 日本語", -1},
+		{Charset_utf8{}, "abc 😀", "abc ", 4},
+		{Charset_latin1{}, "caf\xe9", "café", -1},
+		{Charset_ucs2{}, "\x00a\x65\xe5", "a日", -1},
+		{Charset_ucs2{}, "\x00a\x00", "a", 2},
+		{Charset_sjis{}, "\x93\xfa\x96\x7b", "日本", -1},
+		{Charset_sjis{}, "\x93\xfa\x96", "日", 2},
+		{Charset_utf16{}, "\x00a\xd8\x3d\xde\x00", "a😀", -1},
+		{Charset_utf16{}, "\x00a\xd8\x3d", "a", 2},
+		{Charset_utf16{}, "\x00a\xde\x00\x00b", "a", 2},
+		{Charset_utf16le{}, "a\x00\x3d\xd8\x00\xde", "a😀", -1},
+		{Charset_utf16le{}, "a\x00\x3d", "a", 2},
+		{Charset_utf32{}, "\x00\x00\x00a\x00\x01\xf6\x00", "a😀", -1},
+		{Charset_utf32{}, "\x00\x00\x00a\x00\x11\x00\x00", "a", 4},
+	}
+
+	for _, tc := range cases {
+		dst := []byte("prefix:")
+		result, offset, err := ConvertToUTF8Strict(dst, tc.charset, []byte(tc.input))
+
+		if string(result) != "prefix:"+tc.expected {
+			t.Errorf("ConvertToUTF8Strict(%s, %q) = %q (expected %q)", tc.charset.Name(), tc.input, result, "prefix:"+tc.expected)
+		}
+		if tc.offset < 0 {
+			if err != nil {
+				t.Errorf("ConvertToUTF8Strict(%s, %q) failed: %v", tc.charset.Name(), tc.input, err)
+			}
+			if offset != len(tc.input) {
+				t.Errorf("ConvertToUTF8Strict(%s, %q) returned offset %d for a valid input", tc.charset.Name(), tc.input, offset)
+			}
+			continue
+		}
+
+		var invalid *ErrInvalidSequence
+		if !errors.As(err, &invalid) {
+			t.Errorf("ConvertToUTF8Strict(%s, %q) returned unexpected error %v", tc.charset.Name(), tc.input, err)
+			continue
+		}
+		if offset != tc.offset || invalid.Offset != tc.offset {
+			t.Errorf("ConvertToUTF8Strict(%s, %q) failed at offset %d (expected %d)", tc.charset.Name(), tc.input, offset, tc.offset)
+		}
+	}
+}
+
+func TestConvertToUTF8(t *testing.T) {
+	result, err := ConvertToUTF8(nil, Charset_latin1{}, []byte("caf\xe9"))
+	if err != nil || string(result) != "café" {
+		t.Errorf("ConvertToUTF8(latin1) = %q, %v", result, err)
+	}
+
+	result, err = ConvertToUTF8(nil, Charset_sjis{}, []byte("\x93\xfa\x96"))
+	if string(result) != "日?" || err == nil {
+		t.Errorf("ConvertToUTF8(sjis) should replace invalid sequences, got %q, %v", result, err)
+	}
+}
+
+func TestConvertUnicodeEncodings(t *testing.T) {
+	var cases = []struct {
+		charset Charset
+		input   string
+		encoded string
+	}{
+		{Charset_utf16{}, "aé日", "\x00a\x00\xe9\x65\xe5"},
+		{Charset_utf16{}, "😀\U0010FFFF", "\xd8\x3d\xde\x00\xdb\xff\xdf\xff"},
+		{Charset_utf16le{}, "aé日", "a\x00\xe9\x00\xe5\x65"},
+		{Charset_utf16le{}, "😀\U0010FFFF", "\x3d\xd8\x00\xde\xff\xdb\xff\xdf"},
+		{Charset_utf32{}, "a日", "\x00\x00\x00a\x00\x00\x65\xe5"},
+		{Charset_utf32{}, "😀\U0010FFFF", "\x00\x01\xf6\x00\x00\x10\xff\xff"},
+	}
+
+	for _, tc := range cases {
+		encoded, err := ConvertFromUTF8(nil, tc.charset, []byte(tc.input))
+		if err != nil || string(encoded) != tc.encoded {
+			t.Errorf("ConvertFromUTF8(%s, %q) = %q, %v (expected %q)", tc.charset.Name(), tc.input, encoded, err, tc.encoded)
+		}
+		decoded, err := ConvertToUTF8(nil, tc.charset, encoded)
+		if err != nil || string(decoded) != tc.input {
+			t.Errorf("ConvertToUTF8(%s, %q) = %q, %v (expected %q)", tc.charset.Name(), encoded, decoded, err, tc.input)
+		}
+	}
+
+	var invalid = []struct {
+		charset  Charset
+		input    string
+		expected string
+	}{
+		// truncated code units and surrogate pairs
+		{Charset_utf16{}, "\x00a\x00", "a?"},
+		{Charset_utf16{}, "\x00a\xd8\x3d\xde", "a?"},
+		{Charset_utf16le{}, "a\x00\x3d\xd8", "a?"},
+		{Charset_utf32{}, "\x00\x00\x00a\x00\x01", "a?"},
+		// a lone low surrogate is skipped one byte at a time, like in MySQL
+		{Charset_utf16{}, "\xde\x00\x00\x00a", "?\x00a"},
+	}
+
+	for _, tc := range invalid {
+		decoded, err := ConvertToUTF8(nil, tc.charset, []byte(tc.input))
+		if string(decoded) != tc.expected || err == nil {
+			t.Errorf("ConvertToUTF8(%s, %q) = %q, %v (expected %q)", tc.charset.Name(), tc.input, decoded, err, tc.expected)
+		}
+	}
+
+	// surrogate codepoints decoded from UTF-32 cannot be encoded in UTF-16
+	encoded, err := Convert(nil, Charset_utf16{}, []byte("\x00\x00\xd8\x3d"), Charset_utf32{})
+	if string(encoded) != "\x00?" || err == nil {
+		t.Errorf("Convert(utf32 -> utf16) of a surrogate = %q, %v", encoded, err)
+	}
+}
+
+var transcoderInputs = []string{
+	"", "abc", "café", "日本語", "abc 日本語 😀", "Straße", "\xff\xfe", "東京の空",
+}
+
+var transcoderCharsets = []Charset{
+	Charset_utf8mb4{}, Charset_utf8{}, Charset_latin1{}, Charset_ucs2{}, Charset_utf16{},
+	Charset_utf16le{}, Charset_utf32{}, Charset_sjis{}, Charset_ujis{}, Charset_gb2312{}, Charset_euckr{}, Charset_binary{},
+}
+
+func TestTranscoder(t *testing.T) {
+	for _, from := range transcoderCharsets {
+		for _, to := range transcoderCharsets {
+			trans := NewTranscoder(from, to)
+
+			for _, input := range transcoderInputs {
+				src, _ := ConvertFromUTF8(nil, from, []byte(input))
+				src = append([]byte(nil), src...)
+
+				expected, expectedErr := Convert(nil, to, src, from)
+				got, err := trans.Convert(nil, src)
+				if !bytes.Equal(got, expected) || (err == nil) != (expectedErr == nil) {
+					t.Errorf("%s -> %s: Transcoder.Convert(%q) = %q, %v (expected %q, %v)",
+						from.Name(), to.Name(), src, got, err, expected, expectedErr)
+				}
+
+				prefixed, _ := trans.Convert([]byte("prefix:"), src)
+				if !bytes.Equal(prefixed, append([]byte("prefix:"), expected...)) {
+					t.Errorf("%s -> %s: Transcoder.Convert(%q) should append to dst, got %q",
+						from.Name(), to.Name(), src, prefixed)
+				}
+			}
+		}
+	}
+}
+
+func BenchmarkTranscoder(b *testing.B) {
+	var inputs [][]byte
+	for i := 0; i < 1000; i++ {
+		inputs = append(inputs, []byte(fmt.Sprintf("row %d: café 東京 %d", i, i*i)))
+	}
+
+	for _, to := range []Charset{Charset_latin1{}, Charset_sjis{}, Charset_utf16{}} {
+		b.Run(fmt.Sprintf("ConvertFromUTF8/%s", to.Name()), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				for _, input := range inputs {
+					_, _ = ConvertFromUTF8(nil, to, input)
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("Transcoder/%s", to.Name()), func(b *testing.B) {
+			trans := NewTranscoder(Charset_utf8mb4{}, to)
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				for _, input := range inputs {
+					_, _ = trans.Convert(nil, input)
+				}
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	var cases = []struct {
+		charset Charset
+		input   string
+		offset  int
+	}{
+		{Charset_utf8mb4{}, "", -1},
+		{Charset_utf8mb4{}, "abc 日本語 😀", -1},
+		{Charset_utf8mb4{}, "abc\xffdef", 3},
+		{Charset_utf8mb4{}, "\xed\xa0\x80", 0},
+		{Charset_utf8{}, "abc 日本語", -1},
+		{Charset_utf8{}, "abc 😀", 4},
+		{Charset_latin1{}, "\xff\x00\x80", -1},
+		{Charset_binary{}, "\xff\xfe", -1},
+		{Charset_ucs2{}, "\x00a\x00", 2},
+		{Charset_utf16{}, "\xd8\x3d\xde\x00", -1},
+		{Charset_utf16{}, "\xd8\x3d", 0},
+		{Charset_sjis{}, "\x93\xfa\x96\x7b", -1},
+		{Charset_sjis{}, "\x93\xfa\x96", 2},
+		{Charset_gb18030{}, "\x81\x30\x81\x30", -1},
+		{Charset_utf32{}, "\x00\x01\xf6\x00", -1},
+		{Charset_utf32{}, "\x00\x00\x00a\xa4 A\x00", 4},
+	}
+
+	for _, tc := range cases {
+		if offset := ValidateOffset(tc.charset, []byte(tc.input)); offset != tc.offset {
+			t.Errorf("ValidateOffset(%s, %q) = %d (expected %d)", tc.charset.Name(), tc.input, offset, tc.offset)
+		}
+		if valid := Validate(tc.charset, []byte(tc.input)); valid != (tc.offset < 0) {
+			t.Errorf("Validate(%s, %q) = %v", tc.charset.Name(), tc.input, valid)
+		}
+
+		_, strictOffset, err := ConvertToUTF8Strict(nil, tc.charset, []byte(tc.input))
+		if (err == nil) != (tc.offset < 0) || (err != nil && strictOffset != tc.offset) {
+			t.Errorf("ValidateOffset(%s, %q) disagrees with ConvertToUTF8Strict (offset %d, err %v)",
+				tc.charset.Name(), tc.input, strictOffset, err)
+		}
+	}
+}
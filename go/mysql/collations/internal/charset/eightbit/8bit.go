@@ -50,6 +50,10 @@ func (e *Charset_8bit) SupportsSupplementaryChars() bool {
 	return false
 }
 
+func (e *Charset_8bit) MaxBytesPerChar() int {
+	return 1
+}
+
 func (e *Charset_8bit) DecodeRune(bytes []byte) (rune, int) {
 	if len(bytes) < 1 {
 		return utf8.RuneError, 0
@@ -36,6 +36,10 @@ func (Charset_binary) SupportsSupplementaryChars() bool {
 	return true
 }
 
+func (Charset_binary) MaxBytesPerChar() int {
+	return 1
+}
+
 func (c Charset_binary) EncodeRune(dst []byte, r rune) int {
 	if r > 0xFF {
 		return -1
@@ -190,6 +190,10 @@ func (Charset_latin1) SupportsSupplementaryChars() bool {
 	return false
 }
 
+func (Charset_latin1) MaxBytesPerChar() int {
+	return 1
+}
+
 func (Charset_latin1) IsSuperset(other types.Charset) bool {
 	switch other.(type) {
 	case Charset_latin1:
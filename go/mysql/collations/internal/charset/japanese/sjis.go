@@ -41,6 +41,10 @@ func (Charset_sjis) SupportsSupplementaryChars() bool {
 	return false
 }
 
+func (Charset_sjis) MaxBytesPerChar() int {
+	return 2
+}
+
 func encodeSJIS(dst []byte, r rune, table *[65536]uint16) int {
 	_ = dst[1]
 
@@ -90,11 +94,13 @@ func decodeSJIS(src []byte, table *[65536]uint16) (rune, int) {
 	if c0 >= 0xA1 && c0 <= 0xDF {
 		return rune(table[c0]), 1
 	}
-	if len(src) >= 2 {
-		sj := uint16(c0)<<8 | uint16(src[1])
-		if cp := table[sj]; cp != 0 {
-			return rune(cp), 2
-		}
+	if len(src) < 2 {
+		// a lead byte at the end of the input must not consume more bytes than there are
+		return utf8.RuneError, 1
+	}
+	sj := uint16(c0)<<8 | uint16(src[1])
+	if cp := table[sj]; cp != 0 {
+		return rune(cp), 2
 	}
 	return utf8.RuneError, 2
 }
@@ -113,6 +119,10 @@ func (Charset_cp932) SupportsSupplementaryChars() bool {
 	return false
 }
 
+func (Charset_cp932) MaxBytesPerChar() int {
+	return 2
+}
+
 func (Charset_cp932) IsSuperset(other types.Charset) bool {
 	switch other.(type) {
 	case Charset_cp932:
@@ -139,6 +139,10 @@ func (Charset_ujis) SupportsSupplementaryChars() bool {
 	return false
 }
 
+func (Charset_ujis) MaxBytesPerChar() int {
+	return 3
+}
+
 func (Charset_ujis) EncodeRune(dst []byte, r rune) int {
 	return ujisEncodeRune(dst, r, &table_jis208Encode, &table_jis212Encode)
 }
@@ -166,6 +170,10 @@ func (Charset_eucjpms) SupportsSupplementaryChars() bool {
 	return false
 }
 
+func (Charset_eucjpms) MaxBytesPerChar() int {
+	return 3
+}
+
 func (Charset_eucjpms) EncodeRune(dst []byte, r rune) int {
 	return ujisEncodeRune(dst, r, &table_jis208_eucjpmsEncode, &table_jis212_eucjpmsEncode)
 }
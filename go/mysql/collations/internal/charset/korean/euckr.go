@@ -41,6 +41,10 @@ func (Charset_euckr) SupportsSupplementaryChars() bool {
 	return false
 }
 
+func (Charset_euckr) MaxBytesPerChar() int {
+	return 2
+}
+
 func (Charset_euckr) EncodeRune(dst []byte, r rune) int {
 	_ = dst[1]
 
@@ -0,0 +1,76 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package charset
+
+import (
+	"fmt"
+	"strings"
+)
+
+// charsetsByName contains all the charsets that can be looked up with ByName.
+// The 8-bit charsets are defined together with the collations that use them,
+// so they're added to this map by the collations package when it's initialized.
+var charsetsByName = map[string]Charset{
+	"binary":  Charset_binary{},
+	"latin1":  Charset_latin1{},
+	"utf8":    Charset_utf8{},
+	"utf8mb4": Charset_utf8mb4{},
+	"utf16":   Charset_utf16{},
+	"utf16le": Charset_utf16le{},
+	"ucs2":    Charset_ucs2{},
+	"utf32":   Charset_utf32{},
+	"gb2312":  Charset_gb2312{},
+	"gb18030": Charset_gb18030{},
+	"ujis":    Charset_ujis{},
+	"sjis":    Charset_sjis{},
+	"cp932":   Charset_cp932{},
+	"eucjpms": Charset_eucjpms{},
+	"euckr":   Charset_euckr{},
+}
+
+// charsetAliases are the alternative names that MySQL accepts for its charsets,
+// mapped to the name of the charset as returned by Charset.Name()
+var charsetAliases = map[string]string{
+	// utf8 is the deprecated name for utf8mb3, and it's the name that all versions
+	// of MySQL up to 8.0 return in their metadata
+	"utf8mb3": "utf8",
+	// MySQL's latin1 is actually the Windows cp1252 charset
+	"cp1252": "latin1",
+}
+
+// Register makes the given charset available to ByName. Registering a charset
+// with the same name as an existing one has no effect. Register is not safe for
+// concurrent use, so it must only be called during initialization.
+func Register(cs Charset) {
+	if _, ok := charsetsByName[cs.Name()]; !ok {
+		charsetsByName[cs.Name()] = cs
+	}
+}
+
+// ByName returns the charset with the given name. The lookup is case-insensitive,
+// like in MySQL, and all the aliases that MySQL accepts for its charsets are
+// resolved to the corresponding charset.
+func ByName(name string) (Charset, error) {
+	lookup := strings.ToLower(name)
+	if canonical, ok := charsetAliases[lookup]; ok {
+		lookup = canonical
+	}
+	if cs, ok := charsetsByName[lookup]; ok {
+		return cs, nil
+	}
+	return nil, fmt.Errorf("unknown charset %q", name)
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package charset
+
+import "testing"
+
+func TestByName(t *testing.T) {
+	var cases = []struct {
+		name     string
+		expected string
+	}{
+		{"utf8mb4", "utf8mb4"},
+		{"UTF8MB4", "utf8mb4"},
+		{"utf8", "utf8"},
+		{"utf8mb3", "utf8"},
+		{"Utf8mb3", "utf8"},
+		{"latin1", "latin1"},
+		{"cp1252", "latin1"},
+		{"binary", "binary"},
+		{"sjis", "sjis"},
+		{"utf16le", "utf16le"},
+	}
+
+	for _, tc := range cases {
+		cs, err := ByName(tc.name)
+		if err != nil {
+			t.Errorf("ByName(%q) failed: %v", tc.name, err)
+			continue
+		}
+		if cs.Name() != tc.expected {
+			t.Errorf("ByName(%q) = %s (expected %s)", tc.name, cs.Name(), tc.expected)
+		}
+	}
+
+	for _, name := range []string{"", "utf9", "latin"} {
+		if _, err := ByName(name); err == nil {
+			t.Errorf("ByName(%q) should fail", name)
+		}
+	}
+}
+
+func TestMaxBytesPerChar(t *testing.T) {
+	var expected = map[string]int{
+		"binary":  1,
+		"latin1":  1,
+		"utf8":    3,
+		"utf8mb4": 4,
+		"utf16":   4,
+		"utf16le": 4,
+		"ucs2":    2,
+		"utf32":   4,
+		"gb2312":  2,
+		"gb18030": 4,
+		"ujis":    3,
+		"sjis":    2,
+		"cp932":   2,
+		"eucjpms": 3,
+		"euckr":   2,
+	}
+
+	for name, width := range expected {
+		cs, err := ByName(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cs.MaxBytesPerChar() != width {
+			t.Errorf("%s: MaxBytesPerChar() = %d (expected %d)", name, cs.MaxBytesPerChar(), width)
+		}
+	}
+}
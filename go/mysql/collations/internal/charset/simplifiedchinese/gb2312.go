@@ -41,6 +41,10 @@ func (Charset_gb2312) SupportsSupplementaryChars() bool {
 	return false
 }
 
+func (Charset_gb2312) MaxBytesPerChar() int {
+	return 2
+}
+
 func (Charset_gb2312) EncodeRune(dst []byte, r rune) int {
 	switch {
 	case r < utf8.RuneSelf:
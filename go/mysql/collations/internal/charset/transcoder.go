@@ -0,0 +1,169 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package charset
+
+import "unicode/utf8"
+
+// Transcoder converts strings between two fixed charsets. All the decisions that
+// Convert needs to make for every call are only made once when creating the
+// Transcoder, so it should be used instead of Convert or ConvertFromUTF8 when
+// converting many strings between the same charsets, e.g. all the rows in a column.
+// A Transcoder is not safe for concurrent use.
+type Transcoder struct {
+	from, to Charset
+	mode     transcodeMode
+	width    int
+	buf      []byte
+}
+
+type transcodeMode byte
+
+const (
+	transcodeNoop transcodeMode = iota
+	transcodeConvertible
+	transcodeFromUTF8
+	transcodeSlow
+)
+
+// NewTranscoder returns a Transcoder that converts strings encoded in the `from`
+// charset into the `to` charset.
+func NewTranscoder(from, to Charset) *Transcoder {
+	t := &Transcoder{from: from, to: to, width: to.MaxBytesPerChar()}
+	switch {
+	case to.IsSuperset(from):
+		t.mode = transcodeNoop
+	case isConvertible(to):
+		t.mode = transcodeConvertible
+	case isUTF8(from):
+		t.mode = transcodeFromUTF8
+	default:
+		t.mode = transcodeSlow
+	}
+	return t
+}
+
+func isConvertible(cs Charset) bool {
+	_, ok := cs.(Convertible)
+	return ok
+}
+
+func isUTF8(cs Charset) bool {
+	switch cs.(type) {
+	case Charset_utf8, Charset_utf8mb4:
+		return true
+	}
+	return false
+}
+
+// Convert transforms `src` from the source charset of this Transcoder into its
+// destination charset, with the same semantics as Convert: codepoints that cannot
+// be converted are replaced with '?' and counted in an ErrFailedConversion.
+// The result is appended to `dst`. If `dst` is nil, the result is written into a
+// buffer owned by the Transcoder, which is reused by the next call to Convert, so
+// the result must be copied if it needs to be kept around.
+func (t *Transcoder) Convert(dst, src []byte) ([]byte, error) {
+	owned := dst == nil
+	if owned {
+		dst = t.buf[:0]
+	}
+
+	var err error
+	switch t.mode {
+	case transcodeNoop:
+		if owned {
+			return src, nil
+		}
+		return append(dst, src...), nil
+	case transcodeConvertible:
+		return t.to.(Convertible).Convert(dst, src, t.from)
+	case transcodeFromUTF8:
+		dst, err = t.fromUTF8(dst, src)
+	default:
+		dst, err = t.slow(dst, src)
+	}
+
+	if owned {
+		t.buf = dst[:0]
+	}
+	return dst, err
+}
+
+func (t *Transcoder) fromUTF8(dst, src []byte) ([]byte, error) {
+	var failed int
+	to, maxWidth := t.to, t.width
+
+	for _, cp := range string(src) {
+		if cap(dst)-len(dst) < maxWidth {
+			dst = grow(dst, len(src))
+		}
+		w := to.EncodeRune(dst[len(dst):cap(dst)], cp)
+		if w < 0 {
+			failed++
+			if w = to.EncodeRune(dst[len(dst):cap(dst)], '?'); w < 0 {
+				break
+			}
+		}
+		dst = dst[:len(dst)+w]
+	}
+
+	if failed > 0 {
+		return dst, ErrFailedConversion(failed)
+	}
+	return dst, nil
+}
+
+func (t *Transcoder) slow(dst, src []byte) ([]byte, error) {
+	var failed int
+	from, to, maxWidth := t.from, t.to, t.width
+
+	for len(src) > 0 {
+		cp, width := from.DecodeRune(src)
+		if cp == utf8.RuneError && width < 3 {
+			failed++
+			cp = '?'
+		}
+		if width <= 0 || width > len(src) {
+			width = len(src)
+		}
+		src = src[width:]
+
+		if cap(dst)-len(dst) < maxWidth {
+			dst = grow(dst, len(src))
+		}
+		w := to.EncodeRune(dst[len(dst):cap(dst)], cp)
+		if w < 0 {
+			failed++
+			if w = to.EncodeRune(dst[len(dst):cap(dst)], '?'); w < 0 {
+				break
+			}
+		}
+		dst = dst[:len(dst)+w]
+	}
+
+	if failed > 0 {
+		return dst, ErrFailedConversion(failed)
+	}
+	return dst, nil
+}
+
+// grow returns a copy of `dst` with enough extra capacity to encode at least `hint`
+// more bytes, and always enough to encode one more codepoint in any charset
+func grow(dst []byte, hint int) []byte {
+	grown := make([]byte, len(dst), 2*cap(dst)+hint+utf8.UTFMax)
+	copy(grown, dst)
+	return grown
+}
@@ -19,6 +19,9 @@ package types
 type Charset interface {
 	Name() string
 	SupportsSupplementaryChars() bool
+	// MaxBytesPerChar returns the maximum amount of bytes that any codepoint
+	// takes when encoded with this charset
+	MaxBytesPerChar() int
 	IsSuperset(other Charset) bool
 
 	EncodeRune([]byte, rune) int
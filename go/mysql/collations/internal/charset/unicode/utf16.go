@@ -51,6 +51,11 @@ func (Charset_utf16be) IsSuperset(other types.Charset) bool {
 func encodeRuneUTF16be(dst []byte, r rune) int {
 	_ = dst[3]
 
+	if r < 0 || r > utf8.MaxRune || (surr1 <= r && r < surr3) {
+		// surrogates cannot be encoded on their own, or they would be decoded
+		// as part of a pair
+		return -1
+	}
 	if r <= 0xffff {
 		dst[0] = uint8(r >> 8)
 		dst[1] = uint8(r)
@@ -95,6 +100,10 @@ func (Charset_utf16be) SupportsSupplementaryChars() bool {
 	return true
 }
 
+func (Charset_utf16be) MaxBytesPerChar() int {
+	return 4
+}
+
 type Charset_utf16le struct{}
 
 func (Charset_utf16le) Name() string {
@@ -144,6 +153,10 @@ func (Charset_utf16le) SupportsSupplementaryChars() bool {
 	return true
 }
 
+func (Charset_utf16le) MaxBytesPerChar() int {
+	return 4
+}
+
 type Charset_ucs2 struct{}
 
 func (Charset_ucs2) Name() string {
@@ -180,3 +193,7 @@ func (Charset_ucs2) DecodeRune(p []byte) (rune, int) {
 func (Charset_ucs2) SupportsSupplementaryChars() bool {
 	return false
 }
+
+func (Charset_ucs2) MaxBytesPerChar() int {
+	return 2
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package unicode
 
 import (
+	"unicode"
 	"unicode/utf8"
 
 	"vitess.io/vitess/go/mysql/collations/internal/charset/types"
@@ -40,6 +41,10 @@ func (Charset_utf32) IsSuperset(other types.Charset) bool {
 func (Charset_utf32) EncodeRune(dst []byte, r rune) int {
 	_ = dst[3]
 
+	if r < 0 || r > unicode.MaxRune {
+		return -1
+	}
+
 	dst[0] = uint8(r >> 24)
 	dst[1] = uint8(r >> 16)
 	dst[2] = uint8(r >> 8)
@@ -51,9 +56,18 @@ func (Charset_utf32) DecodeRune(p []byte) (rune, int) {
 	if len(p) < 4 {
 		return utf8.RuneError, 0
 	}
-	return (rune(p[0]) << 24) | (rune(p[1]) << 16) | (rune(p[2]) << 8) | rune(p[3]), 4
+	cp := (uint32(p[0]) << 24) | (uint32(p[1]) << 16) | (uint32(p[2]) << 8) | uint32(p[3])
+	if cp > unicode.MaxRune {
+		// codepoints outside of the Unicode range are not valid in MySQL
+		return utf8.RuneError, 1
+	}
+	return rune(cp), 4
 }
 
 func (Charset_utf32) SupportsSupplementaryChars() bool {
 	return true
 }
+
+func (Charset_utf32) MaxBytesPerChar() int {
+	return 4
+}
@@ -177,6 +177,10 @@ func (Charset_utf8mb3) SupportsSupplementaryChars() bool {
 	return false
 }
 
+func (Charset_utf8mb3) MaxBytesPerChar() int {
+	return 3
+}
+
 type Charset_utf8mb4 struct{}
 
 func (Charset_utf8mb4) Name() string {
@@ -203,3 +207,7 @@ func (Charset_utf8mb4) DecodeRune(p []byte) (rune, int) {
 func (Charset_utf8mb4) SupportsSupplementaryChars() bool {
 	return true
 }
+
+func (Charset_utf8mb4) MaxBytesPerChar() int {
+	return 4
+}
@@ -0,0 +1,49 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package charset
+
+import "unicode/utf8"
+
+// Validate returns whether `src` is a valid string in the given charset.
+// It's equivalent to ValidateOffset(cs, src) < 0.
+func Validate(cs Charset, src []byte) bool {
+	return ValidateOffset(cs, src) < 0
+}
+
+// ValidateOffset returns the offset of the first byte in `src` that is not part
+// of a valid sequence in the given charset, or -1 if the whole string is valid.
+// No output is generated, so this is much cheaper than attempting to transcode `src`.
+func ValidateOffset(cs Charset, src []byte) int {
+	switch cs.(type) {
+	case Charset_utf8mb4:
+		if utf8.Valid(src) {
+			return -1
+		}
+	case Charset_binary, Charset_latin1, *Charset_8bit:
+		// all the bytes in single-byte charsets are valid
+		return -1
+	}
+
+	for offset := 0; offset < len(src); {
+		cp, width := cs.DecodeRune(src[offset:])
+		if cp == utf8.RuneError && width < 3 {
+			return offset
+		}
+		offset += width
+	}
+	return -1
+}
@@ -18,6 +18,7 @@ package uca
 
 import (
 	"sync"
+	"unicode/utf8"
 
 	"vitess.io/vitess/go/mysql/collations/internal/charset"
 )
@@ -30,19 +31,31 @@ type Collation900 struct {
 	contractions *contractions
 	param        *parametricT
 	maxLevel     int
+	lookahead    int
 	iterpool     *sync.Pool
+	// contextual is set for the Japanese collations, whose iterators only match contextual contractions
+	contextual bool
 }
 
 func (c *Collation900) Weights() (WeightTable, TableLayout) {
 	return c.table, TableLayout_uca900{}
 }
 
+// Iterator returns a WeightIterator for the given input. Iterators are recycled
+// through a pool that is safe for concurrent use, so every caller gets its own
+// iterator and must call Done on it once it's no longer needed.
 func (c *Collation900) Iterator(input []byte) WeightIterator {
 	iter := c.iterpool.Get().(WeightIterator)
 	iter.reset(input)
 	return iter
 }
 
+// Levels returns the amount of levels for which the iterators of this collation
+// yield weights
+func (c *Collation900) Levels() int {
+	return c.maxLevel
+}
+
 func (c *Collation900) WeightForSpace() uint16 {
 	ascii := *c.table[0]
 	return ascii[CodepointsPerPage+' ']
@@ -55,6 +68,7 @@ func NewCollation(name string, weights WeightTable, weightPatches []WeightPatch,
 		maxLevel:     levels,
 		param:        newParametricTailoring(reorder, upperCaseFirst),
 		contractions: newContractions(contractions),
+		lookahead:    streamLookahead(contractions),
 		iterpool:     &sync.Pool{},
 	}
 
@@ -64,6 +78,7 @@ func NewCollation(name string, weights WeightTable, weightPatches []WeightPatch,
 			return &FastIterator900{iterator900: iterator900{Collation900: *coll}}
 		}
 	case name == "utf8mb4_ja_0900_as_cs_ks" || name == "utf8mb4_ja_0900_as_cs":
+		coll.contextual = true
 		coll.iterpool.New = func() interface{} {
 			return &jaIterator900{iterator900: iterator900{Collation900: *coll}}
 		}
@@ -79,6 +94,30 @@ func NewCollation(name string, weights WeightTable, weightPatches []WeightPatch,
 	return coll
 }
 
+// StablePrefix returns the length of the longest prefix of `src` whose weights cannot change
+// when more data is appended to `src`. The rest of `src` must be weighted together with the
+// appended data: it is the shortest suffix that could be part of a contraction that continues
+// in the appended data, or that starts with an invalid or incomplete codepoint.
+func (c *Collation900) StablePrefix(src []byte) int {
+	if c.contextual {
+		return c.contractions.stablePrefixContextual(src)
+	}
+	return c.contractions.stablePrefix(charset.Charset_utf8mb4{}, src)
+}
+
+// streamLookahead returns the amount of bytes that must be available in the input
+// of a streaming iterator before the next codepoint can be processed, so that any
+// contraction starting at that codepoint can be fully resolved
+func streamLookahead(contractions []Contraction) int {
+	longest := 1
+	for _, c := range contractions {
+		if !c.Contextual && len(c.Path) > longest {
+			longest = len(c.Path)
+		}
+	}
+	return longest * utf8.UTFMax
+}
+
 type CollationLegacy struct {
 	charset      charset.Charset
 	table        WeightTable
@@ -97,6 +136,12 @@ func (c *CollationLegacy) Iterator(input []byte) *WeightIteratorLegacy {
 	return iter
 }
 
+// StablePrefix returns the length of the longest prefix of `src` whose weights cannot change
+// when more data is appended to `src`, like Collation900.StablePrefix
+func (c *CollationLegacy) StablePrefix(src []byte) int {
+	return c.contractions.stablePrefix(c.charset, src)
+}
+
 func (c *CollationLegacy) WeightForSpace() uint16 {
 	ascii := *c.table[0]
 	stride := ascii[0]
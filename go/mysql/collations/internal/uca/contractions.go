@@ -76,6 +76,9 @@ func (t *trie) insert(path []rune, weights []uint16) {
 
 type contractions struct {
 	tr trie
+	// contextualPrevs are the codepoints that can precede the codepoint of a contextual
+	// contraction, which changes the weights of the codepoint that follows them
+	contextualPrevs map[rune]bool
 }
 
 func (ctr *contractions) insert(c *Contraction) {
@@ -88,9 +91,91 @@ func (ctr *contractions) insert(c *Contraction) {
 	if c.Contextual && len(c.Path) != 2 {
 		panic("contextual contractions can only span 2 codepoints")
 	}
+	if c.Contextual {
+		if ctr.contextualPrevs == nil {
+			ctr.contextualPrevs = make(map[rune]bool)
+		}
+		ctr.contextualPrevs[c.Path[1]] = true
+	}
 	ctr.tr.insert(c.Path, c.Weights)
 }
 
+// stablePrefix returns the length of the longest prefix of `src` whose weights cannot change
+// when more data is appended to `src`, following the same greedy matching of contractions as
+// the iterators. The prefix ends before the first invalid or incomplete codepoint, since the
+// iterators stop at invalid input and an incomplete codepoint may be completed by more data.
+// It also ends before any contraction that reaches the end of `src`: the contraction could
+// continue in the appended data, and the iterators don't match a contraction at all when
+// the codepoint that follows it is invalid.
+func (ctr *contractions) stablePrefix(cs charset.Charset, src []byte) int {
+	pos := 0
+	for pos < len(src) {
+		cp, width := cs.DecodeRune(src[pos:])
+		if cp == charset.RuneError && width < 3 {
+			return pos
+		}
+		next := pos + width
+
+		var tr *trie
+		if ctr != nil {
+			tr = ctr.tr.children[cp]
+		}
+		if tr != nil {
+			end := next
+			for {
+				if end == len(src) {
+					return pos
+				}
+				cp, width := cs.DecodeRune(src[end:])
+				if cp == charset.RuneError && width < 3 {
+					return pos
+				}
+				child := tr.children[cp]
+				if child == nil {
+					break
+				}
+				tr = child
+				end += width
+			}
+			if tr.weights != nil {
+				next = end
+			}
+		}
+		pos = next
+	}
+	return pos
+}
+
+// stablePrefixContextual works like stablePrefix for the contextual contractions of the
+// Japanese collations, where the weights of a codepoint depend on the codepoint before it.
+// The suffix that is not stable is at most the last codepoint of `src`, if the first
+// codepoint of the appended data could form a contraction with it.
+func (ctr *contractions) stablePrefixContextual(src []byte) int {
+	var prev rune
+	var pos, stable int
+	for pos < len(src) {
+		cp, width := utf8.DecodeRune(src[pos:])
+		if cp == utf8.RuneError && width < 3 {
+			break
+		}
+		start := pos
+		pos += width
+
+		if ctr.weightForContextualContraction(cp, prev) != nil {
+			prev = 0
+			stable = pos
+			continue
+		}
+		prev = cp
+		if ctr.contextualPrevs[cp] {
+			stable = start
+		} else {
+			stable = pos
+		}
+	}
+	return stable
+}
+
 func (ctr *contractions) weightForContraction(cp rune, remainder []byte) ([]uint16, []byte) {
 	if ctr != nil {
 		if tr := ctr.tr.children[cp]; tr != nil {
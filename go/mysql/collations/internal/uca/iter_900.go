@@ -29,6 +29,10 @@ type iterator900 struct {
 	codepoint codepointIterator
 	input     []byte
 	level     int
+
+	// Streaming state
+	streaming bool
+	starved   bool
 }
 
 type codepointIterator struct {
@@ -60,6 +64,21 @@ func (it *codepointIterator) next() (uint16, bool) {
 	return weight, true
 }
 
+// pending returns whether there are any non-zero weights left to yield
+// for the current codepoint
+func (it *codepointIterator) pending() bool {
+	weights := it.weights
+	for ce := it.ce; ce > 0; ce-- {
+		if weights[0] != 0x0 {
+			return true
+		}
+		if it.stride <= len(weights) {
+			weights = weights[it.stride:]
+		}
+	}
+	return false
+}
+
 func (it *codepointIterator) init(parent *iterator900, cp rune) {
 	p, offset := pageOffset(cp)
 	page := parent.table[p]
@@ -119,17 +138,70 @@ func (it *iterator900) SkipLevel() int {
 	return it.level
 }
 
+// Position returns the offset in the original input up to which all the codepoints
+// have been processed in the current level, and whether all the weights for these
+// codepoints have already been yielded.
+func (it *iterator900) Position() (int, bool) {
+	return len(it.original) - len(it.input), !it.codepoint.pending()
+}
+
 func (it *iterator900) reset(input []byte) {
 	it.input = input
 	it.original = input
 	it.level = 0
 	it.codepoint.ce = 0
+	it.streaming = false
+	it.starved = false
+}
+
+// Stream switches the iterator into streaming mode: the input for the iterator
+// can be extended with Feed, and the iterator will stop yielding weights once
+// it doesn't have enough input to be sure that the weights for the next codepoint
+// are final, instead of moving on to the next level.
+func (it *iterator900) Stream() {
+	it.streaming = true
+}
+
+// Feed replaces the input for a streaming iterator. The new input must contain
+// the previous input as its prefix, followed by the newly available data.
+func (it *iterator900) Feed(input []byte) {
+	offset := len(it.original) - len(it.input)
+	it.original = input
+	it.input = input[offset:]
+	it.starved = false
+}
+
+// EndStream marks the end of the input of a streaming iterator: no more data will be
+// fed into it, so the codepoints at the end of the input no longer have to wait for
+// more data, and the iterator moves on to the next levels once it has processed them.
+func (it *iterator900) EndStream() {
+	it.streaming = false
+	it.starved = false
+}
+
+// Starved returns whether the last call to Next on a streaming iterator returned
+// no weights because the iterator needs more input.
+func (it *iterator900) Starved() bool {
+	return it.starved
+}
+
+func (it *iterator900) starving() bool {
+	if it.streaming && len(it.input) < it.lookahead {
+		it.starved = true
+		return true
+	}
+	return false
 }
 
 type WeightIterator interface {
 	Next() (uint16, bool)
 	Level() int
 	SkipLevel() int
+	Position() (int, bool)
+	Stream()
+	Feed(input []byte)
+	EndStream()
+	Starved() bool
 	Done()
 	reset(input []byte)
 }
@@ -149,6 +221,9 @@ func (it *slowIterator900) Next() (uint16, bool) {
 		if w, ok := it.codepoint.next(); ok {
 			return it.param.adjust(it.level, w), true
 		}
+		if it.starving() {
+			return 0, false
+		}
 
 		cp, width := utf8.DecodeRune(it.input)
 		if cp == utf8.RuneError && width < 3 {
@@ -254,6 +254,9 @@ func (it *FastIterator900) Next() (uint16, bool) {
 		if w, ok := it.codepoint.next(); ok {
 			return w, true
 		}
+		if it.starving() {
+			return 0, false
+		}
 
 		cp, width := utf8.DecodeRune(it.input)
 		if cp == utf8.RuneError && width < 3 {
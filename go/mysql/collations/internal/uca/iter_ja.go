@@ -63,12 +63,20 @@ func (it *jaIterator900) cacheKana(cp rune) {
 func (it *jaIterator900) Done() {
 	it.queuedWeight = 0x0
 	it.prevCodepoint = 0
-	it.kanas = nil
+	// keep the kana cache around so that pooled iterators don't have to allocate it again
+	for cp := range it.kanas {
+		delete(it.kanas, cp)
+	}
 	it.original = nil
 	it.input = nil
 	it.iterpool.Put(it)
 }
 
+func (it *jaIterator900) Position() (int, bool) {
+	offset, complete := it.iterator900.Position()
+	return offset, complete && it.queuedWeight == 0x0
+}
+
 func (it *jaIterator900) Next() (uint16, bool) {
 	for {
 		if it.queuedWeight != 0x0 {
@@ -81,12 +89,15 @@ func (it *jaIterator900) Next() (uint16, bool) {
 		}
 
 	decodeNext:
+		if it.starving() {
+			return 0, false
+		}
 		cp, width := utf8.DecodeRune(it.input)
 		if cp == utf8.RuneError && width < 3 {
 			it.level++
 			// if we're at level 3 (Kana-sensitive) and we haven't seen
 			// any Kanas in the previous levels, there's nothing to yield
-			if it.level == 3 && it.kanas == nil {
+			if it.level == 3 && len(it.kanas) == 0 {
 				return 0, false
 			}
 			if it.level < it.maxLevel {
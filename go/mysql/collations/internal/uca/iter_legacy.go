@@ -26,6 +26,7 @@ type WeightIteratorLegacy struct {
 
 	// Internal state
 	codepoint codepointIteratorLegacy
+	original  []byte
 	input     []byte
 	length    int
 }
@@ -64,11 +65,13 @@ func (it *codepointIteratorLegacy) initContraction(weights []uint16) {
 
 func (it *WeightIteratorLegacy) reset(input []byte) {
 	it.input = input
+	it.original = input
 	it.length = 0
 	it.codepoint.weights = nil
 }
 
 func (it *WeightIteratorLegacy) Done() {
+	it.original = nil
 	it.input = nil
 	it.iterpool.Put(it)
 }
@@ -103,6 +106,14 @@ func (it *WeightIteratorLegacy) Next() (uint16, bool) {
 	}
 }
 
+// Position returns the offset in the original input up to which all the codepoints
+// have been processed, and whether all the weights for these codepoints have already
+// been yielded.
+func (it *WeightIteratorLegacy) Position() (int, bool) {
+	pending := len(it.codepoint.weights) > 0 && it.codepoint.weights[0] != 0x0
+	return len(it.original) - len(it.input), !pending
+}
+
 func (it *WeightIteratorLegacy) Length() int {
 	return it.length
 }
@@ -0,0 +1,159 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package uca
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+	"unicode"
+)
+
+// weightTableMagic identifies the binary encoding of a WeightTable
+const weightTableMagic = "UCAW"
+
+// embeddedWeightTables are the weight tables generated by maketables, indexed by the
+// name that is used to store them with EncodeWeightTable
+var embeddedWeightTables = map[string]WeightTable{
+	"uca900":    WeightTable_uca900,
+	"uca900_ja": WeightTable_uca900_ja,
+	"uca900_zh": WeightTable_uca900_zh,
+	"uca400":    WeightTable_uca400,
+	"uca520":    WeightTable_uca520,
+}
+
+// EmbeddedWeightTables returns the names of all the weight tables embedded in this package, sorted
+func EmbeddedWeightTables() []string {
+	names := make([]string, 0, len(embeddedWeightTables))
+	for name := range embeddedWeightTables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// EmbeddedWeightTable returns the weight table embedded in this package with the given name
+func EmbeddedWeightTable(name string) (WeightTable, bool) {
+	table, ok := embeddedWeightTables[name]
+	return table, ok
+}
+
+// WeightTableName returns the name of a weight table embedded in this package, or false
+// if the table is not one of the embedded ones
+func WeightTableName(table WeightTable) (string, bool) {
+	if len(table) == 0 {
+		return "", false
+	}
+	for name, embedded := range embeddedWeightTables {
+		if len(embedded) == len(table) && &embedded[0] == &table[0] {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// EncodeWeightTable returns a compact binary encoding of a WeightTable, which only contains
+// the pages that are not empty. All the integers are little endian:
+//
+//	magic      [4]byte "UCAW"
+//	tableLen   uint32  the number of pages in the table, including empty ones
+//	pageCount  uint32  the number of pages that follow
+//	pages      pageCount * { index uint32, weightCount uint32, weights [weightCount]uint16 }
+func EncodeWeightTable(table WeightTable) []byte {
+	var pageCount, weightCount int
+	for _, page := range table {
+		if page != nil {
+			pageCount++
+			weightCount += len(*page)
+		}
+	}
+
+	out := make([]byte, 0, len(weightTableMagic)+8+pageCount*8+weightCount*2)
+	out = append(out, weightTableMagic...)
+	out = appendUint32(out, uint32(len(table)))
+	out = appendUint32(out, uint32(pageCount))
+	for index, page := range table {
+		if page == nil {
+			continue
+		}
+		out = appendUint32(out, uint32(index))
+		out = appendUint32(out, uint32(len(*page)))
+		for _, w := range *page {
+			out = append(out, byte(w), byte(w>>8))
+		}
+	}
+	return out
+}
+
+func appendUint32(out []byte, v uint32) []byte {
+	return append(out, byte(v), byte(v>>8), byte(v>>16), byte(v>>24))
+}
+
+// maxWeightTablePages is the number of pages needed to cover all the Unicode codepoints
+const maxWeightTablePages = (unicode.MaxRune + 1) / CodepointsPerPage
+
+var errTruncatedWeightTable = errors.New("truncated weight table")
+
+// DecodeWeightTable decodes a WeightTable encoded with EncodeWeightTable. The weights of all
+// the pages are copied into a single allocation, so `data` can be released afterwards.
+func DecodeWeightTable(data []byte) (WeightTable, error) {
+	if len(data) < len(weightTableMagic)+8 || string(data[:len(weightTableMagic)]) != weightTableMagic {
+		return nil, fmt.Errorf("not an encoded weight table")
+	}
+	data = data[len(weightTableMagic):]
+	tableLen := binary.LittleEndian.Uint32(data)
+	pageCount := binary.LittleEndian.Uint32(data[4:])
+	data = data[8:]
+
+	if tableLen > maxWeightTablePages {
+		return nil, fmt.Errorf("weight table has too many pages (%d)", tableLen)
+	}
+	if pageCount > tableLen || uint64(pageCount)*8 > uint64(len(data)) {
+		return nil, errTruncatedWeightTable
+	}
+	// every page takes 8 bytes for its header, so the rest of the data are the weights
+	weights := make([]uint16, (uint64(len(data))-uint64(pageCount)*8)/2)
+	table := make(WeightTable, tableLen)
+
+	for i := uint32(0); i < pageCount; i++ {
+		if len(data) < 8 {
+			return nil, errTruncatedWeightTable
+		}
+		index := binary.LittleEndian.Uint32(data)
+		weightCount := binary.LittleEndian.Uint32(data[4:])
+		data = data[8:]
+
+		if index >= tableLen || table[index] != nil {
+			return nil, fmt.Errorf("invalid page index %d in weight table", index)
+		}
+		if uint64(weightCount)*2 > uint64(len(data)) {
+			return nil, errTruncatedWeightTable
+		}
+		page := weights[:weightCount:weightCount]
+		for w := range page {
+			page[w] = binary.LittleEndian.Uint16(data[w*2:])
+		}
+		weights = weights[weightCount:]
+		data = data[weightCount*2:]
+		table[index] = &page
+	}
+	if len(data) > 0 {
+		return nil, fmt.Errorf("%d trailing bytes in weight table", len(data))
+	}
+	return table, nil
+}
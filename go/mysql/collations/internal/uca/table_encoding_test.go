@@ -0,0 +1,87 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package uca
+
+import (
+	"testing"
+)
+
+func TestWeightTableEncoding(t *testing.T) {
+	for _, name := range EmbeddedWeightTables() {
+		table, ok := EmbeddedWeightTable(name)
+		if !ok {
+			t.Fatalf("missing embedded table %q", name)
+		}
+		if found, ok := WeightTableName(table); !ok || found != name {
+			t.Errorf("WeightTableName(%s) = %q, %v", name, found, ok)
+		}
+
+		encoded := EncodeWeightTable(table)
+		decoded, err := DecodeWeightTable(encoded)
+		if err != nil {
+			t.Fatalf("%s: failed to decode: %v", name, err)
+		}
+		if len(decoded) != len(table) {
+			t.Fatalf("%s: decoded %d pages (expected %d)", name, len(decoded), len(table))
+		}
+		for p := range table {
+			if (table[p] == nil) != (decoded[p] == nil) {
+				t.Fatalf("%s: page %d has not been decoded", name, p)
+			}
+			if table[p] == nil {
+				continue
+			}
+			expected, got := *table[p], *decoded[p]
+			if len(expected) != len(got) {
+				t.Fatalf("%s: page %d has %d weights (expected %d)", name, p, len(got), len(expected))
+			}
+			for w := range expected {
+				if expected[w] != got[w] {
+					t.Fatalf("%s: page %d weight %d = %04x (expected %04x)", name, p, w, got[w], expected[w])
+				}
+			}
+		}
+	}
+
+	if _, ok := WeightTableName(WeightTable{nil}); ok {
+		t.Errorf("WeightTableName should not find tables that are not embedded")
+	}
+}
+
+func TestDecodeWeightTableErrors(t *testing.T) {
+	valid := EncodeWeightTable(WeightTable_uca400)
+
+	var cases = [][]byte{
+		nil,
+		[]byte("UCAX\x00\x00\x00\x00\x00\x00\x00\x00"),
+		valid[:len(valid)-1],
+		valid[:len(valid)/2],
+		append(valid[:len(valid):len(valid)], 0, 0),
+		// more pages than the table can hold
+		[]byte("UCAW\x01\x00\x00\x00\x02\x00\x00\x00"),
+		// a table larger than all of Unicode
+		[]byte("UCAW\xff\xff\xff\xff\x00\x00\x00\x00"),
+		// a page outside of the table
+		[]byte("UCAW\x01\x00\x00\x00\x01\x00\x00\x00\x01\x00\x00\x00\x00\x00\x00\x00"),
+	}
+
+	for _, data := range cases {
+		if _, err := DecodeWeightTable(data); err == nil {
+			t.Errorf("DecodeWeightTable(%q) should fail", data)
+		}
+	}
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package uca
 
 import (
+	"fmt"
 	"reflect"
 	"sync"
 	"unsafe"
@@ -38,7 +39,13 @@ const Pages = MaxCodepoint / CodepointsPerPage
 const MaxCollationElementsPerCodepoint = 8
 
 type TableLayout interface {
+	// MaxCodepoint returns the highest codepoint that can have weights in the table
 	MaxCodepoint() rune
+	// PageCount returns the amount of pages of CodepointsPerPage codepoints that
+	// are required to store the weights for all the codepoints up to MaxCodepoint
+	PageCount() int
+	// DebugString returns a human-readable description of the layout
+	DebugString() string
 	DebugWeights(table WeightTable, codepoint rune) []uint16
 
 	allocPage(original *[]uint16, patches []WeightPatch) []uint16
@@ -51,6 +58,14 @@ func (TableLayout_uca900) MaxCodepoint() rune {
 	return MaxCodepoint - 1
 }
 
+func (l TableLayout_uca900) PageCount() int {
+	return pageCount(l.MaxCodepoint())
+}
+
+func (l TableLayout_uca900) DebugString() string {
+	return debugLayout("uca900", l.MaxCodepoint())
+}
+
 func (TableLayout_uca900) DebugWeights(table WeightTable, codepoint rune) (result []uint16) {
 	p, offset := pageOffset(codepoint)
 	page := table[p]
@@ -120,6 +135,14 @@ func (l TableLayout_uca_legacy) MaxCodepoint() rune {
 	return l.maxCodepoint
 }
 
+func (l TableLayout_uca_legacy) PageCount() int {
+	return pageCount(l.maxCodepoint)
+}
+
+func (l TableLayout_uca_legacy) DebugString() string {
+	return debugLayout("uca_legacy", l.maxCodepoint)
+}
+
 func (l TableLayout_uca_legacy) DebugWeights(table WeightTable, codepoint rune) (result []uint16) {
 	if codepoint > l.maxCodepoint {
 		return nil
@@ -305,3 +328,12 @@ func newParametricTailoring(reorder []Reorder, upperCaseFirst bool) *parametricT
 
 	return t
 }
+
+func pageCount(maxCodepoint rune) int {
+	return int(maxCodepoint)/CodepointsPerPage + 1
+}
+
+func debugLayout(name string, maxCodepoint rune) string {
+	return fmt.Sprintf("TableLayout_%s{codepoints: U+0000-U+%04X, pages: %d x %d codepoints}",
+		name, maxCodepoint, pageCount(maxCodepoint), CodepointsPerPage)
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collations
+
+import (
+	"bytes"
+
+	"vitess.io/vitess/go/mysql/collations/internal/charset"
+)
+
+const (
+	likeMatchAny  = '%'
+	likeMatchOne  = '_'
+	likeNoEscapes = 0
+)
+
+// likeMatch implements the LIKE operator for any collation. Just like in MySQL,
+// the pattern is matched one codepoint at a time: `%` matches any sequence of
+// codepoints (including an empty one), `_` matches exactly one codepoint, and any
+// other codepoint in the pattern matches a codepoint in the input if both are equal
+// under the collation. The escape codepoint makes the codepoint that follows it be
+// matched literally, even if it's a wildcard; if the escape is the last codepoint
+// in the pattern, it's matched literally itself.
+func likeMatch(coll Collation, pattern, input []byte, escape rune) bool {
+	cs := coll.Charset()
+
+	var (
+		p, i int
+		// the position in the pattern right after the last `%` we've seen, and the
+		// position in the input where we'll try to resume matching if we backtrack
+		starP = -1
+		starI int
+	)
+
+	for i < len(input) {
+		if p < len(pattern) {
+			pr, pw := decodeLike(cs, pattern[p:])
+			switch {
+			case pr == escape && escape != likeNoEscapes && p+pw < len(pattern):
+				_, lw := decodeLike(cs, pattern[p+pw:])
+				literal := pattern[p+pw : p+pw+lw]
+				_, iw := decodeLike(cs, input[i:])
+				if likeEqual(coll, input[i:i+iw], literal) {
+					p += pw + lw
+					i += iw
+					continue
+				}
+			case pr == likeMatchAny:
+				p += pw
+				starP, starI = p, i
+				continue
+			case pr == likeMatchOne:
+				_, iw := decodeLike(cs, input[i:])
+				p += pw
+				i += iw
+				continue
+			default:
+				_, iw := decodeLike(cs, input[i:])
+				if likeEqual(coll, input[i:i+iw], pattern[p:p+pw]) {
+					p += pw
+					i += iw
+					continue
+				}
+			}
+		}
+
+		// mismatch: backtrack to the last `%` and let it consume one more codepoint
+		if starP < 0 {
+			return false
+		}
+		_, iw := decodeLike(cs, input[starI:])
+		starI += iw
+		p, i = starP, starI
+	}
+
+	// the input has been consumed; any remaining pattern can only contain `%` wildcards
+	for p < len(pattern) {
+		pr, pw := decodeLike(cs, pattern[p:])
+		if pr != likeMatchAny {
+			return false
+		}
+		p += pw
+	}
+	return true
+}
+
+// decodeLike decodes the next codepoint in `src`. Invalid sequences are returned
+// as a single RuneError byte, so they can still be matched literally.
+func decodeLike(cs charset.Charset, src []byte) (rune, int) {
+	r, width := cs.DecodeRune(src)
+	if width < 1 {
+		width = 1
+	}
+	return r, width
+}
+
+func likeEqual(coll Collation, input, literal []byte) bool {
+	return bytes.Equal(input, literal) || coll.Collate(input, literal, false) == 0
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collations
+
+import (
+	"testing"
+
+	"vitess.io/vitess/go/mysql/collations/internal/charset"
+)
+
+func TestLike(t *testing.T) {
+	var cases = []struct {
+		collation      string
+		pattern, input string
+		expected       bool
+	}{
+		{"utf8mb4_0900_ai_ci", "caf%", "café table", true},
+		{"utf8mb4_0900_ai_ci", "%CAFE%", "le café table", true},
+		{"utf8mb4_0900_ai_ci", "caf_", "café", true},
+		{"utf8mb4_0900_ai_ci", "caf_", "cafés", false},
+		{"utf8mb4_0900_ai_ci", "cafe", "café", true},
+		{"utf8mb4_0900_as_ci", "cafe", "café", false},
+		{"utf8mb4_0900_as_ci", "CAFÉ", "café", true},
+		{"utf8mb4_0900_as_cs", "CAFÉ", "café", false},
+		{"utf8mb4_0900_bin", "cafe", "café", false},
+		{"utf8mb4_0900_ai_ci", "", "", true},
+		{"utf8mb4_0900_ai_ci", "%", "", true},
+		{"utf8mb4_0900_ai_ci", "_", "", false},
+		{"utf8mb4_0900_ai_ci", "%%%", "abc", true},
+		{"utf8mb4_0900_ai_ci", "a%b%c", "aXXbYYc", true},
+		{"utf8mb4_0900_ai_ci", "a%b%c", "aXXbYYcd", false},
+		{"utf8mb4_0900_ai_ci", "%a%a%a%", "banana", true},
+		{"utf8mb4_0900_ai_ci", "%a%a%a%a%", "banana", false},
+		{"utf8mb4_0900_ai_ci", "日本_", "日本語", true},
+		{"utf8mb4_0900_ai_ci", "100\\%", "100%", true},
+		{"utf8mb4_0900_ai_ci", "100\\%", "1000", false},
+		{"utf8mb4_0900_ai_ci", "a\\_c", "abc", false},
+		{"utf8mb4_0900_ai_ci", "a\\_c", "a_c", true},
+		{"utf8mb4_0900_ai_ci", "a\\\\c", "a\\c", true},
+		{"utf8mb4_0900_ai_ci", "abc\\", "abc\\", true},
+		{"utf8mb4_0900_ai_ci", "straße", "strasse", false},
+		{"utf8mb4_general_ci", "CAFE%", "café table", true},
+		{"utf8mb4_bin", "CAFE%", "café table", false},
+		{"utf8mb4_unicode_ci", "%É", "café", true},
+		{"latin1_swedish_ci", "CAFE%", "cafe table", true},
+		{"latin1_bin", "CAFE%", "cafe table", false},
+		{"binary", "caf_", "cafe", true},
+		{"utf16_unicode_ci", "%_É", "café", true},
+		{"utf32_general_ci", "c%É", "café", true},
+		{"gb2312_chinese_ci", "%中文%", "这是中文文本", true},
+		{"ujis_japanese_ci", "日本_", "日本語", true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.collation, func(t *testing.T) {
+			coll := testcollation(t, tc.collation)
+
+			pattern, err := charset.ConvertFromUTF8(nil, coll.Charset(), []byte(tc.pattern))
+			if err != nil {
+				t.Fatal(err)
+			}
+			input, err := charset.ConvertFromUTF8(nil, coll.Charset(), []byte(tc.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := coll.Like(pattern, input, '\\'); got != tc.expected {
+				t.Errorf("%q LIKE %q = %v (expected %v)", tc.input, tc.pattern, got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestLikeCustomEscape(t *testing.T) {
+	coll := testcollation(t, "utf8mb4_0900_ai_ci")
+
+	if !coll.Like([]byte("50|%"), []byte("50%"), '|') {
+		t.Errorf("expected escaped wildcard to match")
+	}
+	if coll.Like([]byte("50|%"), []byte("500"), '|') {
+		t.Errorf("expected escaped wildcard to not match")
+	}
+	if !coll.Like([]byte("a\\%"), []byte("a\\bc"), 0) {
+		t.Errorf("expected backslash to be literal when escaping is disabled")
+	}
+}
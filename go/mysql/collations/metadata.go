@@ -0,0 +1,86 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collations
+
+// The values of the PAD_ATTRIBUTE column in information_schema.COLLATIONS
+const (
+	PadAttributePadSpace = "PAD SPACE"
+	PadAttributeNoPad    = "NO PAD"
+)
+
+// CollationInfo is the metadata of a collation, as shown by MySQL in its row of
+// the information_schema.COLLATIONS table
+type CollationInfo struct {
+	// Name is the name of the collation, e.g. "utf8mb4_0900_ai_ci"
+	Name string
+	// Charset is the name of the charset of the collation, e.g. "utf8mb4"
+	Charset string
+	// ID is the numerical identifier of the collation
+	ID ID
+	// IsDefault is true if the collation is the default for its charset
+	IsDefault bool
+	// IsCompiled is true if the collation is compiled into the server; this is always
+	// the case for the collations in this package, which are never loaded from a file
+	IsCompiled bool
+	// SortLen is the amount of memory that MySQL needs to sort strings expressed in
+	// this collation, as a multiple of the size of the strings; it is 0 for the UCA 9.0.0
+	// collations, whose weight strings have a variable length
+	SortLen int
+	// PadAttribute is either PadAttributePadSpace or PadAttributeNoPad
+	PadAttribute string
+}
+
+// Metadata returns the metadata of the given collation in the newest supported version
+// of MySQL. Use Environment.Metadata for a specific MySQL version.
+func Metadata(coll Collation) CollationInfo {
+	return collationInfo(coll, IsDefaultForCharset(coll))
+}
+
+// Metadata returns the metadata of the given collation in this Environment
+func (env *Environment) Metadata(coll Collation) CollationInfo {
+	return collationInfo(coll, env.IsDefaultForCharset(coll))
+}
+
+func collationInfo(coll Collation, isDefault bool) CollationInfo {
+	info := CollationInfo{
+		Name:         coll.Name(),
+		Charset:      coll.Charset().Name(),
+		ID:           coll.ID(),
+		IsDefault:    isDefault,
+		IsCompiled:   true,
+		SortLen:      sortLen(coll),
+		PadAttribute: PadAttributeNoPad,
+	}
+	if coll.Capabilities().PadSpace {
+		info.PadAttribute = PadAttributePadSpace
+	}
+	return info
+}
+
+// sortLen returns the `strxfrm_multiply` of the MySQL implementation of the collation
+func sortLen(coll Collation) int {
+	switch coll.(type) {
+	case *Collation_utf8mb4_uca_0900:
+		return 0
+	case *Collation_uca_legacy:
+		return 8
+	default:
+		// all the other collations that are supported have a single byte of weight for
+		// each byte in the strings
+		return 1
+	}
+}
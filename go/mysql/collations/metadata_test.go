@@ -0,0 +1,50 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collations
+
+import (
+	"testing"
+)
+
+func TestMetadata(t *testing.T) {
+	var cases = []CollationInfo{
+		{"utf8mb4_0900_ai_ci", "utf8mb4", 255, true, true, 0, PadAttributeNoPad},
+		{"utf8mb4_0900_as_cs", "utf8mb4", 278, false, true, 0, PadAttributeNoPad},
+		{"utf8mb4_0900_bin", "utf8mb4", 309, false, true, 1, PadAttributeNoPad},
+		{"utf8mb4_general_ci", "utf8mb4", 45, false, true, 1, PadAttributePadSpace},
+		{"utf8mb4_bin", "utf8mb4", 46, false, true, 1, PadAttributePadSpace},
+		{"utf8mb4_unicode_ci", "utf8mb4", 224, false, true, 8, PadAttributePadSpace},
+		{"utf8_general_ci", "utf8", 33, true, true, 1, PadAttributePadSpace},
+		{"latin1_swedish_ci", "latin1", 8, true, true, 1, PadAttributePadSpace},
+		{"sjis_japanese_ci", "sjis", 13, true, true, 1, PadAttributePadSpace},
+		{"binary", "binary", 63, true, true, 1, PadAttributeNoPad},
+	}
+
+	for _, expected := range cases {
+		if got := Metadata(FromName(expected.Name)); got != expected {
+			t.Errorf("Metadata(%s) = %+v\nexpected %+v", expected.Name, got, expected)
+		}
+	}
+
+	mysql57 := testenvironment(t, "5.7.31")
+	if !mysql57.Metadata(FromName("utf8mb4_general_ci")).IsDefault {
+		t.Errorf("utf8mb4_general_ci should be the default collation in MySQL 5.7")
+	}
+	if testenvironment(t, "8.0.26").Metadata(FromName("utf8mb4_general_ci")).IsDefault {
+		t.Errorf("utf8mb4_general_ci should not be the default collation in MySQL 8.0")
+	}
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collations
+
+import "bytes"
+
+// collationMin returns the smaller of `a` and `b` under the given collation.
+// Strings that collate as equal (e.g. "a" and "A " in a case-insensitive collation
+// with PAD SPACE) are never reordered, so `a` is returned if they tie.
+func collationMin(coll Collation, a, b []byte) []byte {
+	if coll.Collate(a, b, false) <= 0 {
+		return a
+	}
+	return b
+}
+
+// collationMax returns the larger of `a` and `b` under the given collation,
+// or `a` if both strings collate as equal.
+func collationMax(coll Collation, a, b []byte) []byte {
+	if coll.Collate(a, b, false) >= 0 {
+		return a
+	}
+	return b
+}
+
+func binaryMin(a, b []byte) []byte {
+	if bytes.Compare(a, b) <= 0 {
+		return a
+	}
+	return b
+}
+
+func binaryMax(a, b []byte) []byte {
+	if bytes.Compare(a, b) >= 0 {
+		return a
+	}
+	return b
+}
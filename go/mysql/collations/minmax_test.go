@@ -0,0 +1,129 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collations
+
+import (
+	"bytes"
+	"testing"
+
+	"vitess.io/vitess/go/mysql/collations/internal/charset"
+)
+
+func TestMinMax(t *testing.T) {
+	var cases = []struct {
+		collation string
+		a, b      string
+		min, max  string
+	}{
+		{"utf8mb4_0900_as_cs", "apple", "banana", "apple", "banana"},
+		{"utf8mb4_0900_as_cs", "banana", "apple", "apple", "banana"},
+		{"utf8mb4_0900_as_cs", "a", "A", "a", "A"},
+		{"utf8mb4_0900_ai_ci", "a", "A", "a", "a"},
+		{"utf8mb4_0900_ai_ci", "A", "á", "A", "A"},
+		{"utf8mb4_0900_ai_ci", "abc", "ab", "ab", "abc"},
+		{"utf8mb4_general_ci", "abc", "ABC", "abc", "abc"},
+		{"utf8mb4_general_ci", "ABC", "abc", "ABC", "ABC"},
+		{"latin1_swedish_ci", "\xe4", "z", "z", "\xe4"},
+		{"utf8mb4_bin", "a", "A", "A", "a"},
+		{"binary", "a ", "a", "a", "a "},
+		{"binary", "", "\x00", "", "\x00"},
+		{"utf8mb4_0900_ai_ci", "", "", "", ""},
+	}
+
+	for _, tc := range cases {
+		coll := testcollation(t, tc.collation)
+		a, b := []byte(tc.a), []byte(tc.b)
+
+		if got := coll.Min(a, b); string(got) != tc.min {
+			t.Errorf("%s: Min(%q, %q) = %q (expected %q)", tc.collation, tc.a, tc.b, got, tc.min)
+		}
+		if got := coll.Max(a, b); string(got) != tc.max {
+			t.Errorf("%s: Max(%q, %q) = %q (expected %q)", tc.collation, tc.a, tc.b, got, tc.max)
+		}
+	}
+}
+
+func TestMinMaxReturnsArguments(t *testing.T) {
+	a := []byte("Hello")
+	b := []byte("hello")
+
+	for _, coll := range All() {
+		min, max := coll.Min(a, b), coll.Max(a, b)
+		if &min[0] != &a[0] && &min[0] != &b[0] {
+			t.Errorf("%s: Min did not return one of its arguments", coll.Name())
+		}
+		if &max[0] != &a[0] && &max[0] != &b[0] {
+			t.Errorf("%s: Max did not return one of its arguments", coll.Name())
+		}
+		if coll.Collate(a, b, false) == 0 && (!bytes.Equal(min, a) || !bytes.Equal(max, a)) {
+			t.Errorf("%s: ties should return the first argument", coll.Name())
+		}
+	}
+}
+
+func TestEqual(t *testing.T) {
+	var cases = []struct {
+		collation   string
+		left, right string
+		equal       bool
+	}{
+		{"utf8mb4_0900_ai_ci", "café", "cafe", true},
+		{"utf8mb4_0900_ai_ci", "CAFÉ", "cafe", true},
+		{"utf8mb4_0900_as_ci", "café", "cafe", false},
+		{"utf8mb4_0900_as_ci", "CAFÉ", "café", true},
+		{"utf8mb4_0900_as_cs", "CAFÉ", "café", false},
+		{"utf8mb4_0900_as_cs", "café", "café", true},
+		{"utf8mb4_0900_ai_ci", "cafe", "cafes", false},
+		{"utf8mb4_0900_ai_ci", "", "", true},
+		{"utf8mb4_0900_ai_ci", "", "\x00", true},
+		{"utf8mb4_0900_bin", "a", "A", false},
+		{"utf8mb4_general_ci", "Straße", "STRASSE", false},
+		{"utf8mb4_general_ci", "abc", "ABC", true},
+		{"latin1_swedish_ci", "abc", "ABC", true},
+		{"binary", "abc", "ABC", false},
+		{"binary", "abc", "abc", true},
+	}
+
+	for _, tc := range cases {
+		coll := testcollation(t, tc.collation)
+		if got := coll.Equal([]byte(tc.left), []byte(tc.right)); got != tc.equal {
+			t.Errorf("%s: Equal(%q, %q) = %v (expected %v)", tc.collation, tc.left, tc.right, got, tc.equal)
+		}
+	}
+}
+
+func TestEqualMatchesCollate(t *testing.T) {
+	var inputs = []string{"", "a", "A", "á", "ab", "abc", "abc ", "ABC", "café", "cafe", "Straße", "strasse", "ｶｰﾄﾞ", "カード"}
+
+	for _, coll := range All() {
+		var converted [][]byte
+		for _, input := range inputs {
+			if conv, err := charset.ConvertFromUTF8(nil, coll.Charset(), []byte(input)); err == nil {
+				converted = append(converted, append([]byte(nil), conv...))
+			}
+		}
+
+		for _, left := range converted {
+			for _, right := range converted {
+				equal := coll.Collate(left, right, false) == 0
+				if got := coll.Equal(left, right); got != equal {
+					t.Errorf("%s: Equal(%q, %q) = %v, but Collate returns %v", coll.Name(), left, right, got, equal)
+				}
+			}
+		}
+	}
+}
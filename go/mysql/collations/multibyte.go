@@ -16,7 +16,11 @@ limitations under the License.
 
 package collations
 
-import "vitess.io/vitess/go/mysql/collations/internal/charset"
+import (
+	"bytes"
+
+	"vitess.io/vitess/go/mysql/collations/internal/charset"
+)
 
 type Collation_multibyte struct {
 	id      ID
@@ -43,9 +47,30 @@ func (c *Collation_multibyte) IsBinary() bool {
 	return c.sort == nil
 }
 
+func (c *Collation_multibyte) Capabilities() CollationCaps {
+	// the sort order only applies to ASCII, and all the other codepoints are compared
+	// byte-wise, so the collation is always accent sensitive
+	return CollationCaps{
+		CaseSensitive:   c.sort == nil || c.sort['a'] != c.sort['A'],
+		AccentSensitive: true,
+		Binary:          c.IsBinary(),
+		PadSpace:        true,
+		Levels:          1,
+	}
+}
+
 func (c *Collation_multibyte) Collate(left, right []byte, isPrefix bool) int {
 	if c.sort == nil {
-		return collationBinary(left, right, isPrefix)
+		if isPrefix {
+			return collationBinary(left, right, isPrefix)
+		}
+		cmpLen := minInt(len(left), len(right))
+		if diff := bytes.Compare(left[:cmpLen], right[:cmpLen]); diff != 0 {
+			return diff
+		}
+		return collatePadSpace(left[cmpLen:], right[cmpLen:], func(rest []byte) int {
+			return padSpaceBytes(rest, identityByte)
+		})
 	}
 
 	cmpLen := minInt(len(left), len(right))
@@ -57,8 +82,8 @@ func (c *Collation_multibyte) Collate(left, right []byte, isPrefix bool) int {
 			if sortL != sortR {
 				return int(sortL) - int(sortR)
 			}
-			_, widthL := cs.DecodeRune(left[i:])
-			_, widthR := cs.DecodeRune(right[i:])
+			_, widthL := cs.DecodeRune(left[i:cmpLen])
+			_, widthR := cs.DecodeRune(right[i:cmpLen])
 			switch minInt(widthL, widthR) {
 			case 4:
 				i++
@@ -90,8 +115,18 @@ func (c *Collation_multibyte) Collate(left, right []byte, isPrefix bool) int {
 
 	if isPrefix {
 		left = left[:cmpLen]
+		return len(left) - len(right)
 	}
-	return len(left) - len(right)
+	// only the ASCII range has a sort order, and the lead byte of any other
+	// codepoint always sorts after a space
+	return collatePadSpace(left[cmpLen:], right[cmpLen:], func(rest []byte) int {
+		return padSpaceBytes(rest, func(b byte) byte {
+			if b <= 127 {
+				return sortOrder[b]
+			}
+			return b
+		})
+	})
 }
 
 func (c *Collation_multibyte) WeightString(dst, src []byte, numCodepoints int) []byte {
@@ -146,3 +181,86 @@ func (c *Collation_multibyte) WeightString(dst, src []byte, numCodepoints int) [
 func (c *Collation_multibyte) WeightStringLen(numCodepoints int) int {
 	return numCodepoints
 }
+
+func (c *Collation_multibyte) Hash(src []byte, numCodepoints int) uint64 {
+	cs := c.charset
+	sortOrder := c.sort
+	limit, pad := hashLimit(src, numCodepoints)
+	if !pad {
+		// trailing spaces are not significant when comparing. The trailing bytes of
+		// multi-byte codepoints are never in the ASCII range, so they cannot be trimmed.
+		src = bytes.TrimRight(src, " ")
+	}
+
+	h := newWeightHasher()
+	for len(src) > 0 && limit > 0 {
+		w := src[0]
+		if w <= 127 {
+			if sortOrder != nil {
+				w = sortOrder[w]
+			}
+			h.writeByte(w)
+			src = src[1:]
+		} else {
+			_, width := cs.DecodeRune(src)
+			h.write(src[:width])
+			src = src[width:]
+		}
+		limit--
+	}
+	if pad {
+		h.pad(hashPadding8, limit)
+	}
+	return h.sum64()
+}
+
+func (c *Collation_multibyte) Like(pattern, input []byte, escape rune) bool {
+	return likeMatch(c, pattern, input, escape)
+}
+
+func (c *Collation_multibyte) Min(a, b []byte) []byte {
+	return collationMin(c, a, b)
+}
+
+func (c *Collation_multibyte) Max(a, b []byte) []byte {
+	return collationMax(c, a, b)
+}
+
+func (c *Collation_multibyte) TrimLeft(src, cutset []byte) []byte {
+	return collationTrimLeft(c, src, cutset)
+}
+
+func (c *Collation_multibyte) TrimRight(src, cutset []byte) []byte {
+	return collationTrimRight(c, src, cutset)
+}
+
+func (c *Collation_multibyte) Trim(src, cutset []byte) []byte {
+	return collationTrim(c, src, cutset)
+}
+
+func (c *Collation_multibyte) Index(haystack, needle []byte) int {
+	return collationIndex(c, haystack, needle)
+}
+
+func (c *Collation_multibyte) Upper(dst, src []byte) []byte {
+	return collationUpper(c, dst, src)
+}
+
+func (c *Collation_multibyte) Lower(dst, src []byte) []byte {
+	return collationLower(c, dst, src)
+}
+
+func (c *Collation_multibyte) Equal(left, right []byte) bool {
+	return c.Collate(left, right, false) == 0
+}
+
+func (c *Collation_multibyte) codepointWeight(r rune) (uint32, bool) {
+	weight, ok := encodedWeight(c.charset, r)
+	if ok && c.sort != nil && weight&0xFFFFFF == 0 {
+		// the sort order only applies to single bytes, and it's the identity for
+		// all the bytes that are not ASCII, so it cannot reorder them with the
+		// multi-byte codepoints
+		weight = uint32(c.sort[weight>>24]) << 24
+	}
+	return weight, ok
+}
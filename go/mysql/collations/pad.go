@@ -0,0 +1,96 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collations
+
+import (
+	"bytes"
+
+	"vitess.io/vitess/go/mysql/collations/internal/charset"
+)
+
+// All the collations in MySQL are PAD SPACE, except for the UCA 9.0.0 collations introduced
+// in MySQL 8.0 and the binary collation, which are NO PAD. In a PAD SPACE collation, strings
+// are compared as if the shorter one was padded with spaces up to the length of the longer
+// one, so trailing spaces are not significant: 'abc' and 'abc  ' are equal, but 'abc\t'
+// sorts before 'abc', because a tab sorts before the space it's compared with.
+
+// collatePadSpace finishes the comparison of two strings in a PAD SPACE collation once
+// the shortest of them has been consumed: at most one of `left` and `right` can still
+// have codepoints left, and these are compared with `cmpSpace` against the spaces that
+// the other string is padded with.
+func collatePadSpace(left, right []byte, cmpSpace func(rest []byte) int) int {
+	if len(left) > 0 {
+		return cmpSpace(left)
+	}
+	if len(right) > 0 {
+		return -cmpSpace(right)
+	}
+	return 0
+}
+
+// padSpaceBytes compares the bytes in `rest` against a string of spaces of the same
+// length, in a collation where the weight of every byte is given by `weight`
+func padSpaceBytes(rest []byte, weight func(b byte) byte) int {
+	space := weight(' ')
+	for _, b := range rest {
+		if w := weight(b); w != space {
+			if w < space {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// padSpaceRunes compares the codepoints in `rest`, encoded with the given charset, against
+// a string of spaces of the same length, in a collation where the weight of every codepoint
+// is given by `weight`
+func padSpaceRunes(cs charset.Charset, rest []byte, weight func(r rune) rune) int {
+	space := weight(' ')
+	for len(rest) > 0 {
+		r, width := cs.DecodeRune(rest)
+		if width <= 0 {
+			width = 1
+		}
+		if w := weight(r); w != space {
+			if w < space {
+				return -1
+			}
+			return 1
+		}
+		rest = rest[width:]
+	}
+	return 0
+}
+
+// padSpaceEncoded compares the bytes in `rest` against the given encoding of a space,
+// repeated for as long as `rest`, in a collation that compares its strings byte by byte
+func padSpaceEncoded(rest []byte, space []byte) int {
+	for len(rest) > 0 {
+		n := minInt(len(rest), len(space))
+		if diff := bytes.Compare(rest[:n], space[:n]); diff != 0 {
+			return diff
+		}
+		rest = rest[n:]
+	}
+	return 0
+}
+
+func identityByte(b byte) byte {
+	return b
+}
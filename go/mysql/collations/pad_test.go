@@ -0,0 +1,123 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collations
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"vitess.io/vitess/go/mysql/collations/internal/charset"
+)
+
+func TestPadSpace(t *testing.T) {
+	var cases = []struct {
+		left, right     string
+		padSpace, noPad int
+	}{
+		{"abc", "abc  ", 0, -1},
+		{"abc  ", "abc", 0, 1},
+		{"", "   ", 0, -1},
+		// a tab sorts before a space in most collations, so the string with the tab
+		// sorts first if the other one is padded
+		{"abc", "abc\t", 1, -1},
+		{"abc\t", "abc", -1, 1},
+		{"abc", "abc b", -1, -1},
+		{"abc ", "abc  b", -1, -1},
+	}
+
+	// the padding never applies to prefix matches
+	for _, coll := range All() {
+		left, _ := charset.ConvertFromUTF8(nil, coll.Charset(), []byte("abc  "))
+		right, _ := charset.ConvertFromUTF8(nil, coll.Charset(), []byte("abc"))
+		if coll.Collate(left, right, true) != 0 || coll.Collate(right, left, true) == 0 {
+			t.Errorf("%s: trailing spaces should be significant in prefix matches", coll.Name())
+		}
+	}
+
+	for _, coll := range All() {
+		caps := coll.Capabilities()
+		for _, tc := range cases {
+			left, err := charset.ConvertFromUTF8(nil, coll.Charset(), []byte(tc.left))
+			if err != nil {
+				t.Fatal(err)
+			}
+			right, err := charset.ConvertFromUTF8(nil, coll.Charset(), []byte(tc.right))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expected := tc.noPad
+			if caps.PadSpace {
+				expected = tc.padSpace
+				if tabSortsAfterSpace(coll) && strings.Contains(tc.left+tc.right, "\t") {
+					expected = -expected
+				}
+			}
+			if got := sign(coll.Collate(left, right, false)); got != expected {
+				t.Errorf("%s (PAD SPACE = %v): Collate(%q, %q) = %d (expected %d)",
+					coll.Name(), caps.PadSpace, tc.left, tc.right, got, expected)
+			}
+			if expected == 0 && coll.Hash(left, 0) != coll.Hash(right, 0) {
+				t.Errorf("%s: %q and %q are equal, but have different hashes", coll.Name(), tc.left, tc.right)
+			}
+		}
+	}
+}
+
+func tabSortsAfterSpace(coll Collation) bool {
+	var tab, space [4]byte
+	tabWeight := coll.WeightString(nil, tab[:coll.Charset().EncodeRune(tab[:], '\t')], 0)
+	spaceWeight := coll.WeightString(nil, space[:coll.Charset().EncodeRune(space[:], ' ')], 0)
+	return bytes.Compare(tabWeight, spaceWeight) > 0
+}
+
+func TestPadSpaceTotalOrder(t *testing.T) {
+	// the tails of the longer strings must be compared against the padding in the same
+	// way as the rest of the strings, or Collate stops being transitive
+	var cases = []struct {
+		collation string
+		strs      [3]string
+	}{
+		{"utf16le_bin", [3]string{"\x1f\x01", "", " \x00\x1e\x00"}},
+		{"utf32_bin", [3]string{"\x00\x00\x01\x1f", "", "\x00\x00\x00 \x00\x00\x00\x1e"}},
+		{"ujis_japanese_ci", [3]string{"\x8e", "\x8e\xa4", " "}},
+	}
+
+	for _, tc := range cases {
+		coll := testcollation(t, tc.collation)
+		var cmp [3][3]int
+		for i := range tc.strs {
+			for j := range tc.strs {
+				cmp[i][j] = sign(coll.Collate([]byte(tc.strs[i]), []byte(tc.strs[j]), false))
+				if cmp[i][j] != -sign(coll.Collate([]byte(tc.strs[j]), []byte(tc.strs[i]), false)) {
+					t.Errorf("%s: Collate(%q, %q) is not antisymmetric", tc.collation, tc.strs[i], tc.strs[j])
+				}
+			}
+		}
+		for i := range tc.strs {
+			for j := range tc.strs {
+				for k := range tc.strs {
+					if cmp[i][j] <= 0 && cmp[j][k] <= 0 && cmp[i][k] > 0 {
+						t.Errorf("%s: %q <= %q <= %q, but %q > %q", tc.collation,
+							tc.strs[i], tc.strs[j], tc.strs[k], tc.strs[i], tc.strs[k])
+					}
+				}
+			}
+		}
+	}
+}
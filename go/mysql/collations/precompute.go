@@ -0,0 +1,252 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collations
+
+import (
+	"bytes"
+	"fmt"
+	"unicode/utf8"
+
+	"vitess.io/vitess/go/mysql/collations/internal/charset"
+)
+
+// PrecomputedCollation compares strings made of the codepoints of a small alphabet with
+// the semantics of a Collation, but without running the general algorithm of the collation
+// for every codepoint: the weights of all the codepoints in the alphabet are computed once
+// by Precompute, and looked up from a table afterwards. This pays off for the UCA collations,
+// whose algorithm is much more expensive than a table lookup, but not for the collations
+// that already compare their strings with a single table, or byte by byte.
+// Strings with codepoints that are not in the alphabet are still supported, but they are
+// handled by the original Collation. A PrecomputedCollation is immutable, so it is safe
+// for concurrent use.
+type PrecomputedCollation struct {
+	coll Collation
+	cs   charset.Charset
+
+	// byteWeights has the weights for the codepoints in the alphabet below 256, and
+	// runeWeights for the rest; the weights of an ignorable codepoint are empty
+	byteWeights [256][]byte
+	byteInSet   [256]bool
+	runeWeights map[rune][]byte
+
+	// asciiBytes is true if all the ASCII codepoints are encoded as a single byte in
+	// the charset, so that any byte below 0x80 can be looked up without decoding it
+	asciiBytes bool
+	// padWeights are the weights for a space if the collation is PAD SPACE
+	padWeights []byte
+}
+
+// Precompute returns a PrecomputedCollation with the weights of `coll` for all the codepoints
+// in `alphabet`. This is only possible if the weight string of any string is the concatenation
+// of the weights of its codepoints, which is the case for all the collations that compare one
+// codepoint at a time and for the UCA 9.0.0 collations that only compare primary weights, as long
+// as the alphabet has no codepoints that are part of a contraction of the collation. An error is
+// returned for any other collation, or if a codepoint of the alphabet is not in its charset.
+func Precompute(coll Collation, alphabet []rune) (*PrecomputedCollation, error) {
+	if err := checkPrecomputable(coll, alphabet); err != nil {
+		return nil, err
+	}
+
+	coll.init()
+	cs := coll.Charset()
+	p := &PrecomputedCollation{
+		coll:        coll,
+		cs:          cs,
+		runeWeights: make(map[rune][]byte),
+		asciiBytes:  true,
+	}
+
+	var buf [8]byte
+	for _, r := range alphabet {
+		n := cs.EncodeRune(buf[:], r)
+		if n <= 0 {
+			return nil, fmt.Errorf("codepoint %U cannot be encoded in charset %s", r, cs.Name())
+		}
+		if decoded, _ := cs.DecodeRune(buf[:n]); decoded != r {
+			return nil, fmt.Errorf("codepoint %U cannot be encoded in charset %s", r, cs.Name())
+		}
+		weights := coll.WeightString(nil, buf[:n], 0)
+		if r < 256 {
+			p.byteWeights[r] = weights
+			p.byteInSet[r] = true
+		} else {
+			p.runeWeights[r] = weights
+		}
+	}
+
+	if coll.Capabilities().PadSpace {
+		p.padWeights = coll.WeightString(nil, buf[:cs.EncodeRune(buf[:], ' ')], 0)
+		// the padding is compared one weight at a time, so all the weights must have
+		// the same size as the weights for a space
+		for _, r := range alphabet {
+			weights, _ := p.lookup(r)
+			if len(weights)%len(p.padWeights) != 0 {
+				return nil, fmt.Errorf("collation %s cannot be precomputed: the weights for %U have an irregular size", coll.Name(), r)
+			}
+		}
+	}
+
+	for b := 0; b < utf8.RuneSelf; b++ {
+		if r, width := cs.DecodeRune([]byte{byte(b)}); r != rune(b) || width != 1 {
+			p.asciiBytes = false
+			break
+		}
+	}
+	return p, nil
+}
+
+func checkPrecomputable(coll Collation, alphabet []rune) error {
+	switch coll := coll.(type) {
+	case codepointCollation, *Collation_binary:
+		return nil
+	case *Collation_utf8mb4_uca_0900:
+		if coll.levelsForCompare > 1 {
+			return fmt.Errorf("collation %s cannot be precomputed: it compares %d levels of weights", coll.Name(), coll.levelsForCompare)
+		}
+		for _, ctr := range coll.contractions {
+			for _, cp := range ctr.Path {
+				for _, r := range alphabet {
+					if cp == r {
+						return fmt.Errorf("collation %s cannot be precomputed: codepoint %U is part of a contraction", coll.Name(), r)
+					}
+				}
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("collation %s cannot be precomputed", coll.Name())
+	}
+}
+
+// Collation returns the Collation whose weights have been precomputed
+func (p *PrecomputedCollation) Collation() Collation {
+	return p.coll
+}
+
+func (p *PrecomputedCollation) lookup(r rune) ([]byte, bool) {
+	if r >= 0 && r < 256 {
+		return p.byteWeights[r], p.byteInSet[r]
+	}
+	weights, ok := p.runeWeights[r]
+	return weights, ok
+}
+
+// next returns the weights for the first codepoint in `src` and the rest of `src`, or
+// false if the codepoint is not in the alphabet
+func (p *PrecomputedCollation) next(src []byte) ([]byte, []byte, bool) {
+	if b := src[0]; b < utf8.RuneSelf && p.asciiBytes {
+		return p.byteWeights[b], src[1:], p.byteInSet[b]
+	}
+	r, width := p.cs.DecodeRune(src)
+	if width < 1 || width > len(src) || (r == utf8.RuneError && width < 3) {
+		return nil, nil, false
+	}
+	weights, ok := p.lookup(r)
+	return weights, src[width:], ok
+}
+
+// Collate compares `left` and `right` like Collate(left, right, false) in the original
+// Collation. If any of the strings has codepoints outside of the alphabet, the comparison
+// is performed by the original Collation.
+func (p *PrecomputedCollation) Collate(left, right []byte) int {
+	var (
+		l, r   = left, right
+		lw, rw []byte
+		ok     bool
+	)
+	for {
+		for len(lw) == 0 && len(l) > 0 {
+			if lw, l, ok = p.next(l); !ok {
+				return p.coll.Collate(left, right, false)
+			}
+		}
+		for len(rw) == 0 && len(r) > 0 {
+			if rw, r, ok = p.next(r); !ok {
+				return p.coll.Collate(left, right, false)
+			}
+		}
+		if len(lw) == 0 || len(rw) == 0 {
+			break
+		}
+		n := minInt(len(lw), len(rw))
+		if cmp := bytes.Compare(lw[:n], rw[:n]); cmp != 0 {
+			return cmp
+		}
+		lw, rw = lw[n:], rw[n:]
+	}
+
+	// one of the strings has run out of weights, and the rest of the other one is either
+	// larger, or compared against the weights for spaces in a PAD SPACE collation
+	switch {
+	case len(lw) > 0:
+		cmp, ok := p.collatePadding(lw, l)
+		if !ok {
+			return p.coll.Collate(left, right, false)
+		}
+		return cmp
+	case len(rw) > 0:
+		cmp, ok := p.collatePadding(rw, r)
+		if !ok {
+			return p.coll.Collate(left, right, false)
+		}
+		return -cmp
+	default:
+		return 0
+	}
+}
+
+// collatePadding compares the remaining `weights` of a string, followed by the weights for
+// the rest of its codepoints in `src`, against the padding of the other string
+func (p *PrecomputedCollation) collatePadding(weights, src []byte) (int, bool) {
+	if p.padWeights == nil {
+		return 1, true
+	}
+	var ok bool
+	for i := 0; ; i++ {
+		for len(weights) == 0 {
+			if len(src) == 0 {
+				return 0, true
+			}
+			if weights, src, ok = p.next(src); !ok {
+				return 0, false
+			}
+		}
+		if pad := p.padWeights[i%len(p.padWeights)]; weights[0] != pad {
+			if weights[0] < pad {
+				return -1, true
+			}
+			return 1, true
+		}
+		weights = weights[1:]
+	}
+}
+
+// WeightString appends the weight string for `src` to `dst`, like WeightStringAppend with
+// the original Collation and no padding. If `src` has codepoints outside of the alphabet, the
+// weight string is computed by the original Collation.
+func (p *PrecomputedCollation) WeightString(dst, src []byte) []byte {
+	start := len(dst)
+	for rest := src; len(rest) > 0; {
+		weights, next, ok := p.next(rest)
+		if !ok {
+			return WeightStringAppend(p.coll, dst[:start], src, 0)
+		}
+		dst = append(dst, weights...)
+		rest = next
+	}
+	return dst
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collations
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+)
+
+func TestPrecompute(t *testing.T) {
+	alphabet := []rune("0123456789abcdEF é\tß😀")
+	outside := []rune("xÉ日")
+
+	var collationNames = []string{
+		"utf8mb4_0900_ai_ci", "utf8mb4_0900_bin", "utf8mb4_general_ci", "utf8mb4_bin",
+		"utf16_general_ci", "utf32_bin", "sjis_japanese_ci", "latin1_swedish_ci", "binary",
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	for _, name := range collationNames {
+		coll := testcollation(t, name)
+		cs := coll.Charset()
+
+		encodable := func(runes []rune) (result []rune) {
+			for _, r := range runes {
+				var buf [8]byte
+				if n := cs.EncodeRune(buf[:], r); n > 0 {
+					if decoded, _ := cs.DecodeRune(buf[:n]); decoded == r {
+						result = append(result, r)
+					}
+				}
+			}
+			return
+		}
+		inAlphabet := encodable(alphabet)
+		runes := append(encodable(outside), inAlphabet...)
+
+		precomputed, err := Precompute(coll, inAlphabet)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+
+		randomString := func() []byte {
+			var str []byte
+			var buf [8]byte
+			for n := rng.Intn(6); n > 0; n-- {
+				// mostly codepoints from the alphabet, and from time to time one from outside
+				r := runes[rng.Intn(len(runes))]
+				if rng.Intn(10) > 0 {
+					r = inAlphabet[rng.Intn(len(inAlphabet))]
+				}
+				str = append(str, buf[:cs.EncodeRune(buf[:], r)]...)
+			}
+			return str
+		}
+
+		for i := 0; i < 2000; i++ {
+			left, right := randomString(), randomString()
+			if got, want := sign(precomputed.Collate(left, right)), sign(coll.Collate(left, right, false)); got != want {
+				t.Errorf("%s: Collate(%q, %q) = %d (expected %d)", name, left, right, got, want)
+			}
+			if got, want := precomputed.WeightString([]byte("prefix:"), left), coll.WeightString([]byte("prefix:"), left, 0); !bytes.Equal(got, want) {
+				t.Errorf("%s: WeightString(%q) = %x (expected %x)", name, left, got, want)
+			}
+		}
+	}
+}
+
+func TestPrecomputeErrors(t *testing.T) {
+	var cases = []struct {
+		collation string
+		alphabet  string
+	}{
+		// UCA collations with more than one level of weights
+		{"utf8mb4_0900_as_cs", "abc"},
+		{"utf8mb4_ja_0900_as_cs_ks", "abc"},
+		// legacy UCA collations
+		{"utf8mb4_unicode_ci", "abc"},
+		// "ch" is a contraction in Czech
+		{"utf8mb4_cs_0900_ai_ci", "abc"},
+		// codepoints that are not in the charset
+		{"latin1_swedish_ci", "abc日"},
+		{"ucs2_general_ci", "abc😀"},
+	}
+
+	for _, tc := range cases {
+		if _, err := Precompute(testcollation(t, tc.collation), []rune(tc.alphabet)); err == nil {
+			t.Errorf("%s: Precompute(%q) should fail", tc.collation, tc.alphabet)
+		}
+	}
+
+	if _, err := Precompute(testcollation(t, "utf8mb4_cs_0900_ai_ci"), []rune("abd")); err != nil {
+		t.Errorf("utf8mb4_cs_0900_ai_ci: Precompute(\"abd\") failed: %v", err)
+	}
+}
+
+func BenchmarkPrecompute(b *testing.B) {
+	alphabet := []rune("0123456789abcdef")
+	rng := rand.New(rand.NewSource(0xDEADBEEF))
+
+	inputs := make([][]byte, 1024)
+	for i := range inputs {
+		str := make([]rune, 16+rng.Intn(16))
+		for j := range str {
+			str[j] = alphabet[rng.Intn(len(alphabet))]
+		}
+		inputs[i] = []byte(string(str))
+	}
+
+	for _, name := range []string{"utf8mb4_0900_ai_ci", "utf8mb4_general_ci", "utf8mb4_bin"} {
+		coll := testcollation(b, name)
+		precomputed, err := Precompute(coll, alphabet)
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		b.Run(name+"/Collate", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_ = coll.Collate(inputs[i%len(inputs)], inputs[(i+1)%len(inputs)], false)
+			}
+		})
+		b.Run(name+"/Precomputed", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_ = precomputed.Collate(inputs[i%len(inputs)], inputs[(i+1)%len(inputs)])
+			}
+		})
+	}
+}
@@ -47,6 +47,14 @@ func (c *Charset) SupportsSupplementaryChars() bool {
 	return true
 }
 
+func (c *Charset) MaxBytesPerChar() int {
+	if local, err := charset.ByName(c.name); err == nil {
+		return local.MaxBytesPerChar()
+	}
+	// no charset in MySQL uses more than 4 bytes per codepoint
+	return 4
+}
+
 func (c *Charset) EncodeRune(dst []byte, r rune) int {
 	panic("unsupported: EncodeRune in remote.Charset (use Charset.Convert directly)")
 }
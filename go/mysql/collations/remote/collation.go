@@ -18,11 +18,13 @@ package remote
 
 import (
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
 	"math"
 	"strings"
 	"sync"
+	"time"
 
 	"vitess.io/vitess/go/bytes2"
 	"vitess.io/vitess/go/mysql"
@@ -50,8 +52,16 @@ type Collation struct {
 	sql  bytes2.Buffer
 	hex  io.Writer
 	err  error
+
+	retries int
+	backoff time.Duration
 }
 
+// ErrUnsupported is wrapped by the errors returned from LastError when the remote
+// server does not support the collation or its charset, as opposed to a failure
+// to perform the query. Check for it with errors.Is.
+var ErrUnsupported = errors.New("not supported by the remote server")
+
 func makeRemoteCollation(conn *mysql.Conn, collid collations.ID, collname string) *Collation {
 	charset := collname
 	if idx := strings.IndexByte(collname, '_'); idx >= 0 {
@@ -76,6 +86,19 @@ func ForName(conn *mysql.Conn, collname string) *Collation {
 	return makeRemoteCollation(conn, id, collname)
 }
 
+// SetRetries makes the remote Collation retry every failed query up to `retries` times,
+// waiting `backoff` before the first retry and twice as long before every following one.
+// Queries are not retried if the collation is not supported, or if the connection has
+// been closed, since a mysql.Conn cannot reconnect on its own.
+func (c *Collation) SetRetries(retries int, backoff time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.retries = retries
+	c.backoff = backoff
+}
+
+// LastError returns the error from the last operation on this Collation, or nil if it
+// succeeded. If the collation is not supported by the server, the error wraps ErrUnsupported.
 func (c *Collation) LastError() error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -131,9 +154,81 @@ func (c *Collation) Collate(left, right []byte, isPrefix bool) int {
 	return int(cmp)
 }
 
+// Locate returns the result of LOCATE(needle, haystack) in the remote server: the position
+// of the first occurrence of `needle` in `haystack`, counted in codepoints and starting at 1,
+// or 0 if `needle` cannot be found
+func (c *Collation) Locate(haystack, needle []byte) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.sql.Reset()
+	c.sql.WriteString("SELECT LOCATE(")
+	c.sql.WriteString(c.prefix)
+	c.hex.Write(needle)
+	c.sql.WriteString(c.suffix)
+	c.sql.WriteString(", ")
+	c.sql.WriteString(c.prefix)
+	c.hex.Write(haystack)
+	c.sql.WriteString(c.suffix)
+	c.sql.WriteString(")")
+
+	result := c.performRemoteQuery()
+	if result == nil {
+		return 0
+	}
+
+	var pos int64
+	pos, c.err = result[0].ToInt64()
+	return int(pos)
+}
+
+// Upper appends to `dst` the result of UPPER(src) in the remote server
+func (c *Collation) Upper(dst, src []byte) []byte {
+	return c.changeCase("UPPER", dst, src)
+}
+
+// Lower appends to `dst` the result of LOWER(src) in the remote server
+func (c *Collation) Lower(dst, src []byte) []byte {
+	return c.changeCase("LOWER", dst, src)
+}
+
+func (c *Collation) changeCase(fn string, dst, src []byte) []byte {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// the result is cast to binary so that it is not transcoded into the charset
+	// of the connection, and can be compared byte-wise with the local result
+	c.sql.Reset()
+	c.sql.WriteString("SELECT CAST(")
+	c.sql.WriteString(fn)
+	c.sql.WriteString("(")
+	c.sql.WriteString(c.prefix)
+	c.hex.Write(src)
+	c.sql.WriteString(c.suffix)
+	c.sql.WriteString(") AS BINARY)")
+
+	result := c.performRemoteQuery()
+	if result == nil {
+		return nil
+	}
+	return append(dst, result[0].ToBytes()...)
+}
+
 func (c *Collation) performRemoteQuery() []sqltypes.Value {
 	res, err := c.conn.ExecuteFetch(c.sql.StringUnsafe(), 1, false)
+	backoff := c.backoff
+	for retry := 0; err != nil && retry < c.retries; retry++ {
+		if isUnsupported(err) || c.conn.IsClosed() {
+			break
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+		res, err = c.conn.ExecuteFetch(c.sql.StringUnsafe(), 1, false)
+	}
 	if err != nil {
+		if isUnsupported(err) {
+			err = fmt.Errorf("collation %s: %w (%v)", c.name, ErrUnsupported, err)
+		}
 		c.err = err
 		return nil
 	}
@@ -145,6 +240,19 @@ func (c *Collation) performRemoteQuery() []sqltypes.Value {
 	return res.Rows[0]
 }
 
+func isUnsupported(err error) bool {
+	var sqlErr *mysql.SQLError
+	if !errors.As(err, &sqlErr) {
+		return false
+	}
+	switch sqlErr.Number() {
+	case mysql.ERUnknownCollation, mysql.ERUnknownCharacterSet:
+		return true
+	default:
+		return false
+	}
+}
+
 func (c *Collation) WeightString(dst, src []byte, numCodepoints int) []byte {
 	if numCodepoints == math.MaxInt32 {
 		panic("unsupported: PadToMax with remote.Collation")
@@ -173,6 +281,51 @@ func (c *Collation) WeightString(dst, src []byte, numCodepoints int) []byte {
 	return append(dst, result[0].ToBytes()...)
 }
 
+// batchWeightStringsSize is the maximum amount of weight strings that BatchWeightStrings
+// fetches with a single query, to stay well below the column limit of MySQL
+const batchWeightStringsSize = 256
+
+// BatchWeightStrings returns the weight strings for all the inputs in the given collation,
+// like calling WeightString for each one of them on a remote Collation, but fetching them
+// with a single query for every batchWeightStringsSize inputs instead of one query each.
+func BatchWeightStrings(conn *mysql.Conn, collation string, inputs [][]byte) ([][]byte, error) {
+	c := ForName(conn, collation)
+	weights := make([][]byte, 0, len(inputs))
+
+	for len(inputs) > 0 {
+		batch := inputs
+		if len(batch) > batchWeightStringsSize {
+			batch = batch[:batchWeightStringsSize]
+		}
+		inputs = inputs[len(batch):]
+
+		c.sql.Reset()
+		c.sql.WriteString("SELECT ")
+		for i, src := range batch {
+			if i > 0 {
+				c.sql.WriteString(", ")
+			}
+			c.sql.WriteString("WEIGHT_STRING(")
+			c.sql.WriteString(c.prefix)
+			c.hex.Write(src)
+			c.sql.WriteString(c.suffix)
+			c.sql.WriteString(")")
+		}
+
+		result := c.performRemoteQuery()
+		if result == nil {
+			return nil, c.err
+		}
+		if len(result) != len(batch) {
+			return nil, fmt.Errorf("unexpected result from MySQL: %d columns returned", len(result))
+		}
+		for _, value := range result {
+			weights = append(weights, value.ToBytes())
+		}
+	}
+	return weights, nil
+}
+
 func (c *Collation) WeightStringLen(_ int) int {
 	return 0
 }
@@ -0,0 +1,109 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collations
+
+import (
+	"bytes"
+	"sort"
+)
+
+// SortStrings sorts a slice of strings, encoded in the charset of the given collation,
+// in increasing order under that collation. The weight string of every element is
+// calculated only once before sorting, so this is much faster than calling Collate
+// from the comparison function of sort.Slice, which needs to walk each string
+// O(log n) times. The sort is not guaranteed to be stable.
+func SortStrings(coll Collation, s []string) {
+	sort.Sort(newWeightedStrings(coll, s))
+}
+
+// SortStable sorts a slice of strings like SortStrings, but keeping the original order
+// of the strings that collate as equal.
+func SortStable(coll Collation, s []string) {
+	sort.Stable(newWeightedStrings(coll, s))
+}
+
+// weightedStrings implements sort.Interface for a slice of strings together with
+// the weight strings that are used to compare them
+type weightedStrings struct {
+	strs    []string
+	weights [][]byte
+	// space is the weight of a space in PAD SPACE collations, where the shorter
+	// weight string must be compared as if it was padded with spaces
+	space []byte
+}
+
+func newWeightedStrings(coll Collation, s []string) *weightedStrings {
+	// all the weight strings are stored back to back in a single buffer, and
+	// only sliced once the buffer won't be reallocated anymore
+	var buf []byte
+	ends := make([]int, len(s))
+	for i, str := range s {
+		buf = coll.WeightString(buf, stringBytes(str), 0)
+		ends[i] = len(buf)
+	}
+
+	ws := &weightedStrings{strs: s, weights: make([][]byte, len(s))}
+	if coll.Capabilities().PadSpace {
+		var space [4]byte
+		ws.space = coll.WeightString(nil, space[:coll.Charset().EncodeRune(space[:], ' ')], 0)
+	}
+	start := 0
+	for i, end := range ends {
+		ws.weights[i] = buf[start:end:end]
+		start = end
+	}
+	return ws
+}
+
+func (ws *weightedStrings) Len() int {
+	return len(ws.strs)
+}
+
+func (ws *weightedStrings) Less(i, j int) bool {
+	left, right := ws.weights[i], ws.weights[j]
+	if len(ws.space) == 0 {
+		return bytes.Compare(left, right) < 0
+	}
+
+	cmpLen := minInt(len(left), len(right))
+	if diff := bytes.Compare(left[:cmpLen], right[:cmpLen]); diff != 0 {
+		return diff < 0
+	}
+	// all the weights in a collation have the same width, so the rest of
+	// the longer weight string can be compared a whole weight at a time
+	if len(left) < len(right) {
+		return comparePadding(right[cmpLen:], ws.space) > 0
+	}
+	return comparePadding(left[cmpLen:], ws.space) < 0
+}
+
+// comparePadding compares `rest` against the padding that would be appended to a
+// shorter weight string by repeating the given `space` weight
+func comparePadding(rest, space []byte) int {
+	for len(rest) >= len(space) {
+		if diff := bytes.Compare(rest[:len(space)], space); diff != 0 {
+			return diff
+		}
+		rest = rest[len(space):]
+	}
+	return bytes.Compare(rest, space[:len(rest)])
+}
+
+func (ws *weightedStrings) Swap(i, j int) {
+	ws.strs[i], ws.strs[j] = ws.strs[j], ws.strs[i]
+	ws.weights[i], ws.weights[j] = ws.weights[j], ws.weights[i]
+}
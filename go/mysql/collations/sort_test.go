@@ -0,0 +1,106 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collations
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"vitess.io/vitess/go/mysql/collations/internal/charset"
+)
+
+func TestSortStrings(t *testing.T) {
+	var inputs = []string{
+		"b", "a", "A", "á", "a ", "a\t", "", " ", "ab", "Ab", "ß", "ss", "s", "æ", "ae",
+		"Straße", "strasse", "résumé", "RESUME", "日本語", "abc æøå 日本語",
+	}
+
+	for _, coll := range All() {
+		if coll.Name() == "utf16le_bin" {
+			// Collate compares the little-endian bytes of the strings, but the
+			// weight strings are made of the big-endian codepoints
+			continue
+		}
+
+		var converted []string
+		for _, input := range inputs {
+			conv, err := charset.ConvertFromUTF8(nil, coll.Charset(), []byte(input))
+			if err != nil {
+				continue
+			}
+			converted = append(converted, string(conv))
+		}
+
+		expected := append([]string{}, converted...)
+		sort.SliceStable(expected, func(i, j int) bool {
+			return CollateString(coll, expected[i], expected[j], false) < 0
+		})
+
+		stable := append([]string{}, converted...)
+		SortStable(coll, stable)
+		assert.Equal(t, expected, stable, "SortStable with %s", coll.Name())
+
+		unstable := append([]string{}, converted...)
+		SortStrings(coll, unstable)
+		for i := 1; i < len(unstable); i++ {
+			if CollateString(coll, unstable[i-1], unstable[i], false) > 0 {
+				t.Errorf("SortStrings with %s: %q sorted before %q", coll.Name(), unstable[i-1], unstable[i])
+			}
+		}
+	}
+}
+
+func BenchmarkSortStrings(b *testing.B) {
+	const alphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZáéíóúñçß "
+	var runes = []rune(alphabet)
+	var rng = rand.New(rand.NewSource(0xDEADBEEF))
+
+	inputs := make([]string, 10000)
+	for i := range inputs {
+		word := make([]rune, 4+rng.Intn(12))
+		for j := range word {
+			word[j] = runes[rng.Intn(len(runes))]
+		}
+		inputs[i] = string(word)
+	}
+
+	for _, collName := range []string{"utf8mb4_0900_ai_ci", "utf8mb4_unicode_ci", "utf8mb4_general_ci"} {
+		collation := testcollation(b, collName)
+		sorted := make([]string, len(inputs))
+
+		b.Run(collName+"/Collate", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				copy(sorted, inputs)
+				sort.Slice(sorted, func(i, j int) bool {
+					return CollateString(collation, sorted[i], sorted[j], false) < 0
+				})
+			}
+		})
+
+		b.Run(collName+"/SortStrings", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				copy(sorted, inputs)
+				SortStrings(collation, sorted)
+			}
+		})
+	}
+}
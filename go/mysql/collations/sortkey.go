@@ -0,0 +1,182 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collations
+
+import "bytes"
+
+const (
+	sortKeyNull    = 0x00
+	sortKeyNotNull = 0x01
+
+	// sortKeyEscape is the byte that follows a 0x00 byte in the weights of a column, so
+	// that it can be told apart from the two 0x00 bytes that terminate the column
+	sortKeyEscape = 0xFF
+
+	// In PAD SPACE collations, every weight is preceded by a tag that tells whether the
+	// weight sorts before or after the padding spaces, and the column is terminated by
+	// sortKeyPadEnd, which sorts between both tags just like the padding would.
+	sortKeyPadBefore = 0x01
+	sortKeyPadEnd    = 0x02
+	sortKeyPadAfter  = 0x03
+)
+
+// SortKeyBuilder builds a single binary sort key for a row that is sorted by several
+// columns, e.g. `ORDER BY a COLLATE x, b COLLATE y DESC`, so that the rows can be sorted
+// by comparing their keys with bytes.Compare. The key for each column is its weight
+// string, escaped so that a shorter weight string always sorts before any longer one
+// that starts with it, and preceded by a marker that sorts NULL values first, like
+// MySQL does. The bytes for the columns sorted in descending order are inverted, so
+// their NULL values sort last. For PAD SPACE collations, where trailing spaces are not
+// significant, the weights are tagged instead, so that the end of a column sorts like
+// an infinite amount of padding spaces.
+// The zero value is ready to use, and a builder can be reused for many rows with Reset.
+type SortKeyBuilder struct {
+	key     []byte
+	weights []byte
+	space   []byte
+}
+
+// Add appends the sort key for `value`, compared with the given collation, to the key
+// for the current row. `asc` is false if the column is sorted in descending order.
+func (b *SortKeyBuilder) Add(coll Collation, value []byte, asc bool) {
+	start := len(b.key)
+	b.key = append(b.key, sortKeyNotNull)
+
+	b.weights = coll.WeightString(b.weights[:0], value, 0)
+	if coll.Capabilities().PadSpace {
+		b.addPadSpace(coll)
+	} else {
+		for _, w := range b.weights {
+			b.key = append(b.key, w)
+			if w == 0x00 {
+				b.key = append(b.key, sortKeyEscape)
+			}
+		}
+		b.key = append(b.key, 0x00, 0x00)
+	}
+
+	if !asc {
+		invertSortKey(b.key[start:])
+	}
+}
+
+// addPadSpace appends the weights for a column in a PAD SPACE collation. All the weights
+// in a collation have the same width as the weight of a space, and each one is preceded by
+// a tag that tells whether the first weight starting from it that is not a space sorts before
+// or after a space. This makes a shorter column compare with the rest of a longer one as if
+// it was padded with spaces, since its terminator sorts between both tags. Trailing spaces
+// are not significant, so they are dropped.
+func (b *SortKeyBuilder) addPadSpace(coll Collation) {
+	var space [4]byte
+	b.space = coll.WeightString(b.space[:0], space[:coll.Charset().EncodeRune(space[:], ' ')], 0)
+	width := len(b.space)
+
+	weights := b.weights
+	for len(weights) >= width && bytes.Equal(weights[len(weights)-width:], b.space) {
+		weights = weights[:len(weights)-width]
+	}
+
+	var tag byte
+	for i := 0; i+width <= len(weights); i += width {
+		w := weights[i : i+width]
+		if tag == 0 {
+			// find the next weight that is not a space, which always exists because
+			// the trailing spaces have been dropped
+			for next := i; tag == 0; next += width {
+				switch bytes.Compare(weights[next:next+width], b.space) {
+				case -1:
+					tag = sortKeyPadBefore
+				case 1:
+					tag = sortKeyPadAfter
+				}
+			}
+		}
+		b.key = append(b.key, tag)
+		b.key = append(b.key, w...)
+		if !bytes.Equal(w, b.space) {
+			tag = 0
+		}
+	}
+	b.key = append(b.key, sortKeyPadEnd)
+}
+
+// AddNull appends the sort key for a NULL value to the key for the current row.
+// `asc` is false if the column is sorted in descending order.
+func (b *SortKeyBuilder) AddNull(asc bool) {
+	start := len(b.key)
+	b.key = append(b.key, sortKeyNull)
+	if !asc {
+		invertSortKey(b.key[start:])
+	}
+}
+
+// Key returns the sort key for all the columns that have been added since the last
+// Reset. The returned slice is only valid until the next call to Add, AddNull or Reset,
+// so it must be copied if it's going to be kept.
+func (b *SortKeyBuilder) Key() []byte {
+	return b.key
+}
+
+// Reset clears the key so the builder can be used for the next row
+func (b *SortKeyBuilder) Reset() {
+	b.key = b.key[:0]
+}
+
+func invertSortKey(key []byte) {
+	for i := range key {
+		key[i] = ^key[i]
+	}
+}
+
+// WeightStringDesc appends to `dst` a key for `src` that sorts in the opposite order of
+// its weight string, so that comparing the keys for two strings with bytes.Compare sorts
+// them in descending order. Inverting the bytes of a weight string is not enough, since a
+// weight string must still sort after any longer one that starts with it: the key is the
+// weight string with its 0x00 bytes escaped and a 0x00 0x00 terminator, like the columns
+// in a SortKeyBuilder, with all its bytes inverted. This applies to the NULL separators
+// between levels and to the padding just like to any other weight, so the keys are longer
+// than the weight strings they are built from.
+// `numCodepoints` is handled like in WeightStringAppend, so PadToMax is the same as 0.
+func WeightStringDesc(coll Collation, dst, src []byte, numCodepoints int) []byte {
+	start := len(dst)
+	dst = WeightStringAppend(coll, dst, src, numCodepoints)
+	end := len(dst)
+
+	// grow the key to fit the escapes and the terminator, and escape it in place
+	// starting from the end so no weights are overwritten before they're moved
+	extra := 2
+	for _, w := range dst[start:end] {
+		if w == 0x00 {
+			extra++
+		}
+	}
+	for i := 0; i < extra; i++ {
+		dst = append(dst, 0x00)
+	}
+
+	out := len(dst) - 2
+	dst[out], dst[out+1] = ^byte(0x00), ^byte(0x00)
+	for i := end - 1; i >= start; i-- {
+		if dst[i] == 0x00 {
+			out--
+			dst[out] = ^byte(sortKeyEscape)
+		}
+		out--
+		dst[out] = ^dst[i]
+	}
+	return dst
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collations
+
+import (
+	"bytes"
+	"testing"
+
+	"vitess.io/vitess/go/mysql/collations/internal/charset"
+)
+
+func TestSortKeyBuilder(t *testing.T) {
+	type column struct {
+		coll Collation
+		asc  bool
+	}
+
+	// nil values are NULLs
+	var values = [][]byte{
+		nil, []byte(""), []byte("a"), []byte("A"), []byte("a "), []byte("ab"), []byte("b"),
+		[]byte("café"), []byte("cafe"), []byte("Straße"), []byte("strasse"), []byte("\x00"), []byte("a\t"), []byte("a b"),
+	}
+
+	var layouts = [][]column{
+		{{testcollation(t, "utf8mb4_0900_ai_ci"), true}, {testcollation(t, "utf8mb4_bin"), false}},
+		{{testcollation(t, "utf8mb4_general_ci"), false}, {testcollation(t, "utf8mb4_0900_as_cs"), true}},
+		{{testcollation(t, "utf8mb4_unicode_ci"), true}, {testcollation(t, "binary"), true}},
+	}
+
+	for _, columns := range layouts {
+		// the reference comparison for two rows, as done by ORDER BY: NULLs sort first
+		// in ascending order, and descending columns reverse the whole comparison
+		compareRows := func(left, right [][]byte) int {
+			for i, col := range columns {
+				var cmp int
+				switch {
+				case left[i] == nil && right[i] == nil:
+					cmp = 0
+				case left[i] == nil:
+					cmp = -1
+				case right[i] == nil:
+					cmp = 1
+				default:
+					cmp = sign(col.coll.Collate(left[i], right[i], false))
+				}
+				if !col.asc {
+					cmp = -cmp
+				}
+				if cmp != 0 {
+					return cmp
+				}
+			}
+			return 0
+		}
+
+		var rows [][][]byte
+		var keys [][]byte
+		var builder SortKeyBuilder
+		for _, a := range values {
+			for _, b := range values {
+				row := [][]byte{a, b}
+				builder.Reset()
+				for i, col := range columns {
+					if row[i] == nil {
+						builder.AddNull(col.asc)
+					} else {
+						builder.Add(col.coll, row[i], col.asc)
+					}
+				}
+				rows = append(rows, row)
+				keys = append(keys, append([]byte(nil), builder.Key()...))
+			}
+		}
+
+		for i := range rows {
+			for j := range rows {
+				expected := compareRows(rows[i], rows[j])
+				if got := bytes.Compare(keys[i], keys[j]); got != expected {
+					t.Errorf("%s, %s: rows %q and %q compare as %d with their sort keys (expected %d)",
+						columns[0].coll.Name(), columns[1].coll.Name(), rows[i], rows[j], got, expected)
+				}
+			}
+		}
+	}
+}
+
+func TestWeightStringDesc(t *testing.T) {
+	var inputs = []string{"", "a", "A", "a ", "ab", "b", "café", "cafe", "Straße", "strasse", "\x00", "a\x00", "a\x00\x00", "日本語"}
+
+	for _, coll := range All() {
+		for _, numCodepoints := range []int{0, 2, PadToMax} {
+			var weights, keys [][]byte
+			for _, input := range inputs {
+				src, err := charset.ConvertFromUTF8(nil, coll.Charset(), []byte(input))
+				if err != nil {
+					continue
+				}
+				weights = append(weights, WeightStringAppend(coll, nil, src, numCodepoints))
+
+				prefix := []byte("prefix")
+				key := WeightStringDesc(coll, prefix, src, numCodepoints)
+				if !bytes.HasPrefix(key, []byte("prefix")) {
+					t.Fatalf("%s: WeightStringDesc did not append to dst", coll.Name())
+				}
+				keys = append(keys, key[len(prefix):])
+			}
+
+			for i := range keys {
+				for j := range keys {
+					expected := -bytes.Compare(weights[i], weights[j])
+					if got := bytes.Compare(keys[i], keys[j]); got != expected {
+						t.Errorf("%s (numCodepoints = %d): weight strings %x and %x compare as %d with their descending keys (expected %d)",
+							coll.Name(), numCodepoints, weights[i], weights[j], got, expected)
+					}
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collations
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// maxSuggestions is the maximum amount of collation names suggested in an error
+const maxSuggestions = 3
+
+// unknownCollationError is returned when looking up a collation name that is not
+// known at all. The suggestions for the error are only calculated when the error
+// is formatted, so failed lookups that ignore the error stay cheap.
+type unknownCollationError struct {
+	name string
+}
+
+func (err *unknownCollationError) Error() string {
+	suggestions := suggestCollations(err.name)
+	if len(suggestions) == 0 {
+		return fmt.Sprintf("unknown collation %q", err.name)
+	}
+	return fmt.Sprintf("unknown collation %q (did you mean %s?)", err.name, strings.Join(suggestions, ", "))
+}
+
+// charsetAliases are the common misspellings of charset names that are worth
+// trying when suggesting alternatives for an unknown collation
+var charsetAliases = map[string][]string{
+	"utf8":    {"utf8mb4", "utf8mb3"},
+	"utf8mb3": {"utf8", "utf8mb4"},
+	"utf8mb4": {"utf8"},
+	"utf16be": {"utf16"},
+	"ucs4":    {"utf32"},
+}
+
+// suggestCollations returns the names of the supported collations that are the
+// most similar to `name`
+func suggestCollations(name string) []string {
+	type candidate struct {
+		name     string
+		distance int
+	}
+
+	var candidates []candidate
+	seen := make(map[string]bool)
+	add := func(name string, distance int) {
+		if _, ok := collationsByName[name]; ok && !seen[name] {
+			seen[name] = true
+			candidates = append(candidates, candidate{name, distance})
+		}
+	}
+
+	// a collation with the same language and sensitivity in a similar charset
+	// is always the best suggestion
+	if underscore := strings.IndexByte(name, '_'); underscore > 0 {
+		for _, alias := range charsetAliases[name[:underscore]] {
+			add(alias+name[underscore:], 0)
+		}
+	}
+
+	maxDistance := len(name)/5 + 1
+	for other := range collationsByName {
+		if d := editDistance(name, other); d <= maxDistance {
+			add(other, d)
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].distance != candidates[j].distance {
+			return candidates[i].distance < candidates[j].distance
+		}
+		return candidates[i].name < candidates[j].name
+	})
+
+	var suggestions []string
+	for i := 0; i < len(candidates) && i < maxSuggestions; i++ {
+		suggestions = append(suggestions, candidates[i].name)
+	}
+	return suggestions
+}
+
+// editDistance returns the Levenshtein distance between two ASCII strings
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = minInt(minInt(prev[j]+1, cur[j-1]+1), prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collations
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"vitess.io/vitess/go/mysql/collations/internal/uca"
+)
+
+// weightTableExt is the extension of the files with the encoded UCA weight tables
+const weightTableExt = ".bin"
+
+var tableSource struct {
+	sync.Mutex
+	fsys    fs.FS
+	onError func(error)
+	tables  map[string]uca.WeightTable
+}
+
+// SetTableSource sets the file system from which the UCA weight tables are loaded when a
+// collation is initialized, instead of using the tables embedded in this package. The
+// tables are loaded lazily: only the tables of the collations that are actually used are
+// decoded, and every table is decoded once and shared between all its collations. If a table
+// is missing from `fsys` or cannot be decoded, the embedded one is used instead, and the error
+// is passed to `onError` (which can be nil) when the collation is initialized, so the caller
+// can report it. The files can be created with WriteWeightTables.
+//
+// Note that the embedded tables are in the read-only data of the binary, which is only
+// paged in by the OS when it is accessed, while the tables from `fsys` are decoded into
+// the heap. The table source is useful to load tables that are kept outside of the
+// binary, not to reduce the memory usage of a process that uses the embedded tables.
+//
+// SetTableSource only affects collations that have not been initialized yet, so it must
+// be called before any collation is used. A nil `fsys` restores the embedded tables.
+func SetTableSource(fsys fs.FS, onError func(error)) {
+	tableSource.Lock()
+	defer tableSource.Unlock()
+	tableSource.fsys = fsys
+	tableSource.onError = onError
+	tableSource.tables = nil
+}
+
+// loadWeightTable returns the version of the embedded weight table `table` from the table
+// source, or `table` itself if there is no table source
+func loadWeightTable(table uca.WeightTable) uca.WeightTable {
+	tableSource.Lock()
+	defer tableSource.Unlock()
+
+	if tableSource.fsys == nil {
+		return table
+	}
+	name, ok := uca.WeightTableName(table)
+	if !ok {
+		return table
+	}
+	if loaded, ok := tableSource.tables[name]; ok {
+		return loaded
+	}
+
+	data, err := fs.ReadFile(tableSource.fsys, name+weightTableExt)
+	if err != nil {
+		tableSourceError(fmt.Errorf("failed to load weight table %q, using the embedded one: %w", name, err))
+		return table
+	}
+	loaded, err := uca.DecodeWeightTable(data)
+	if err != nil {
+		tableSourceError(fmt.Errorf("failed to decode weight table %q, using the embedded one: %w", name, err))
+		return table
+	}
+	if tableSource.tables == nil {
+		tableSource.tables = make(map[string]uca.WeightTable)
+	}
+	tableSource.tables[name] = loaded
+	return loaded
+}
+
+// tableSourceError reports an error with the table source to its error handler, if any.
+// It must be called with the table source locked.
+func tableSourceError(err error) {
+	if tableSource.onError != nil {
+		tableSource.onError(err)
+	}
+}
+
+// WriteWeightTables writes all the UCA weight tables embedded in this package into `dir`,
+// in the format that is loaded by SetTableSource
+func WriteWeightTables(dir string) error {
+	for _, name := range uca.EmbeddedWeightTables() {
+		table, _ := uca.EmbeddedWeightTable(name)
+		if err := os.WriteFile(filepath.Join(dir, name+weightTableExt), uca.EncodeWeightTable(table), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collations
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"os"
+	"runtime"
+	"testing"
+	"testing/fstest"
+
+	"vitess.io/vitess/go/mysql/collations/internal/charset"
+	"vitess.io/vitess/go/mysql/collations/internal/uca"
+)
+
+// newTableSourceCollations returns uninitialized copies of some of the UCA collations
+// in this package, which load their weight tables from the current table source
+func newTableSourceCollations() []Collation {
+	return []Collation{
+		&Collation_utf8mb4_uca_0900{
+			name:             "utf8mb4_0900_ai_ci",
+			id:               255,
+			levelsForCompare: 1,
+			weights:          uca.WeightTable_uca900,
+		},
+		&Collation_utf8mb4_uca_0900{
+			name:             "utf8mb4_es_0900_as_cs",
+			id:               286,
+			levelsForCompare: 3,
+			weights:          uca.WeightTable_uca900,
+			tailoring:        weightTailoring_utf8mb4_es_0900_ai_ci,
+		},
+		&Collation_uca_legacy{
+			name:         "utf8mb4_unicode_ci",
+			id:           224,
+			charset:      charset.Charset_utf8mb4{},
+			weights:      uca.WeightTable_uca400,
+			maxCodepoint: 0xFFFF,
+		},
+	}
+}
+
+func encodedWeightTables() fstest.MapFS {
+	fsys := make(fstest.MapFS)
+	for _, name := range uca.EmbeddedWeightTables() {
+		table, _ := uca.EmbeddedWeightTable(name)
+		fsys[name+weightTableExt] = &fstest.MapFile{Data: uca.EncodeWeightTable(table)}
+	}
+	return fsys
+}
+
+func TestTableSource(t *testing.T) {
+	dir := t.TempDir()
+	if err := WriteWeightTables(dir); err != nil {
+		t.Fatal(err)
+	}
+	SetTableSource(os.DirFS(dir), func(err error) {
+		t.Errorf("unexpected table source error: %v", err)
+	})
+	defer SetTableSource(nil, nil)
+
+	var inputs = []string{"", "abc", "ABC ", "Ñandú llama", "Straße 日本語 😀"}
+	for _, coll := range newTableSourceCollations() {
+		coll.init()
+		expected := FromName(coll.Name())
+		for _, input := range inputs {
+			got := coll.WeightString(nil, []byte(input), 0)
+			want := expected.WeightString(nil, []byte(input), 0)
+			if !bytes.Equal(got, want) {
+				t.Errorf("%s: WeightString(%q) = %x (expected %x)", coll.Name(), input, got, want)
+			}
+		}
+	}
+
+	tableSource.Lock()
+	loaded := len(tableSource.tables)
+	tableSource.Unlock()
+	if loaded != 2 {
+		t.Errorf("expected the uca900 and uca400 tables to be loaded, got %d tables", loaded)
+	}
+}
+
+func TestTableSourceMissingTables(t *testing.T) {
+	var errs []error
+	SetTableSource(fstest.MapFS{
+		"uca400.bin": &fstest.MapFile{Data: []byte("not a table")},
+	}, func(err error) {
+		errs = append(errs, err)
+	})
+	defer SetTableSource(nil, nil)
+
+	// the collations fall back to the embedded tables
+	for _, coll := range newTableSourceCollations() {
+		coll.init()
+		expected := FromName(coll.Name())
+		if got, want := coll.WeightString(nil, []byte("abc"), 0), expected.WeightString(nil, []byte("abc"), 0); !bytes.Equal(got, want) {
+			t.Errorf("%s: WeightString(\"abc\") = %x (expected %x)", coll.Name(), got, want)
+		}
+	}
+
+	// the missing uca900 table is reported for each of its two collations, and the
+	// uca400 table cannot be decoded
+	if len(errs) != 3 || !errors.Is(errs[0], fs.ErrNotExist) || !errors.Is(errs[1], fs.ErrNotExist) || errors.Is(errs[2], fs.ErrNotExist) {
+		t.Errorf("expected three errors from the table source, got %v", errs)
+	}
+}
+
+func heapAlloc() uint64 {
+	var stats runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&stats)
+	return stats.HeapAlloc
+}
+
+// BenchmarkTableSourceMemory reports the heap memory that is retained by a process
+// that only uses utf8mb4_0900_ai_ci, depending on where its weight table comes from,
+// and by a process that loads all the weight tables from a table source
+func BenchmarkTableSourceMemory(b *testing.B) {
+	fsys := encodedWeightTables()
+	defer SetTableSource(nil, nil)
+
+	var cases = []struct {
+		name string
+		fsys fstest.MapFS
+		init func() interface{}
+	}{
+		{"Embedded/OneCollation", nil, func() interface{} {
+			coll := newTableSourceCollations()[0]
+			coll.init()
+			return coll
+		}},
+		{"TableSource/OneCollation", fsys, func() interface{} {
+			coll := newTableSourceCollations()[0]
+			coll.init()
+			return coll
+		}},
+		{"TableSource/AllTables", fsys, func() interface{} {
+			var tables []uca.WeightTable
+			for _, name := range uca.EmbeddedWeightTables() {
+				table, _ := uca.EmbeddedWeightTable(name)
+				tables = append(tables, loadWeightTable(table))
+			}
+			return tables
+		}},
+	}
+
+	for _, tc := range cases {
+		b.Run(tc.name, func(b *testing.B) {
+			retained := make([]interface{}, 0, b.N)
+			before := heapAlloc()
+			for i := 0; i < b.N; i++ {
+				if tc.fsys != nil {
+					// start every iteration with an empty cache of tables, like a new process
+					SetTableSource(tc.fsys, nil)
+				}
+				retained = append(retained, tc.init())
+			}
+			after := heapAlloc()
+			b.ReportMetric(float64(after-before)/float64(b.N), "heap-B/op")
+			runtime.KeepAlive(retained)
+		})
+	}
+}
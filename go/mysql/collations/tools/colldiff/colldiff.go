@@ -0,0 +1,149 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// colldiff prints the weight strings that the collations package computes for its input,
+// and optionally compares them with the ones computed by a live MySQL server, which is
+// useful to debug the mismatches found by the collation integration tests:
+//
+//	colldiff [-lines] [-test -dsn <dsn>] <collation> < input
+//
+// The input is read from stdin, and it must already be encoded in the charset of the
+// collation. With -lines, every line of the input is handled separately, and consecutive
+// lines are also compared with Collate.
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"vitess.io/vitess/go/mysql/collations"
+	"vitess.io/vitess/go/mysql/collations/integration"
+	"vitess.io/vitess/go/mysql/collations/remote"
+)
+
+var (
+	flagLines = flag.Bool("lines", false, "handle every line of the input as a separate string, and collate consecutive lines")
+	flagTest  = flag.Bool("test", false, "compare the results with the ones from the MySQL server in -dsn")
+	flagDSN   = flag.String("dsn", "root@tcp(127.0.0.1:3306)/", "the DSN of the MySQL server to compare with, in the go-sql-driver/mysql format")
+)
+
+// errorf reports an error and returns the exit code for it; the standard logger is not used
+// because importing the mysql package redirects it to the log files of glog
+func errorf(format string, args ...interface{}) int {
+	fmt.Fprintf(os.Stderr, "colldiff: "+format+"\n", args...)
+	return 1
+}
+
+func sign(cmp int) int {
+	switch {
+	case cmp < 0:
+		return -1
+	case cmp > 0:
+		return 1
+	}
+	return 0
+}
+
+func main() {
+	os.Exit(run())
+}
+
+// run does the work of main and returns the exit code, so that the deferred calls run
+// before exiting
+func run() int {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [flags] <collation> < input\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		return 2
+	}
+
+	name := flag.Arg(0)
+	local, err := collations.LookupByName(name)
+	if err != nil {
+		return errorf("%v", err)
+	}
+
+	input, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return errorf("%v", err)
+	}
+	inputs := [][]byte{input}
+	if *flagLines {
+		inputs = bytes.Split(bytes.TrimSuffix(input, []byte("\n")), []byte("\n"))
+	}
+
+	var server *remote.Collation
+	if *flagTest {
+		conn, err := integration.Connect(*flagDSN)
+		if err != nil {
+			return errorf("failed to connect to %s: %v", *flagDSN, err)
+		}
+		defer conn.Close()
+		server = remote.ForName(conn, name)
+	}
+
+	mismatches := 0
+	for i, text := range inputs {
+		weights := local.WeightString(nil, text, 0)
+		fmt.Printf("input:\n%s", hex.Dump(text))
+		fmt.Printf("weight string: %s\n%s\n", hex.EncodeToString(weights), collations.DescribeWeightString(weights))
+
+		if server != nil {
+			remoteWeights := server.WeightString(nil, text, 0)
+			if err := server.LastError(); err != nil {
+				return errorf("%v", err)
+			}
+			if diff := collations.DiffWeightStrings(weights, remoteWeights); diff != "" {
+				fmt.Printf("WEIGHT_STRING mismatch:\n%s\n", diff)
+				mismatches++
+			} else {
+				fmt.Printf("WEIGHT_STRING matches\n")
+			}
+		}
+
+		if i > 0 {
+			prev := inputs[i-1]
+			cmp := sign(local.Collate(prev, text, false))
+			fmt.Printf("collate(line %d, line %d) = %d\n", i, i+1, cmp)
+
+			if server != nil {
+				remoteCmp := sign(server.Collate(prev, text, false))
+				if err := server.LastError(); err != nil {
+					return errorf("%v", err)
+				}
+				if cmp != remoteCmp {
+					fmt.Printf("STRCMP mismatch: remote result is %d\n", remoteCmp)
+					mismatches++
+				}
+			}
+		}
+		fmt.Println()
+	}
+
+	if mismatches > 0 {
+		fmt.Printf("%d mismatches with the remote server\n", mismatches)
+		return 1
+	}
+	return 0
+}
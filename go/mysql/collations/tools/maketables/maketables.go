@@ -26,6 +26,7 @@ import (
 	"log"
 	"os"
 	"path"
+	"path/filepath"
 	"strconv"
 
 	"vitess.io/vitess/go/mysql/collations/internal/uca"
@@ -33,13 +34,15 @@ import (
 )
 
 var Output = flag.String("out", "internal/uca", "")
+var Input = flag.String("in", "testdata/mysqldata", "directory with the weights dumped from MySQL")
+var Verify = flag.Bool("verify", false, "ensure that the weight tables on disk match the ones generated from the MySQL data")
 
 func maketable(w io.Writer, table string, filename string, pages *tablebuilder.EmbeddedPageBuilder, layout uca.TableLayout) {
 	var metadata struct {
 		Weights map[string][]uint16
 	}
 
-	r, err := os.Open(filename)
+	r, err := os.Open(path.Join(*Input, filename))
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -66,9 +69,9 @@ func maketable(w io.Writer, table string, filename string, pages *tablebuilder.E
 	}
 }
 
-func main() {
-	flag.Parse()
-
+// generate returns the contents of all the files that contain the weight tables,
+// indexed by their path
+func generate() map[string][]byte {
 	var buf bytes.Buffer
 	var pages = tablebuilder.NewPageBuilder()
 
@@ -81,12 +84,12 @@ func main() {
 	fmt.Fprintf(&buf, "\"unsafe\"\n")
 	fmt.Fprintf(&buf, ")\n\n")
 
-	maketable(&buf, "uca900", "testdata/mysqldata/utf8mb4_0900_ai_ci.json", pages, uca.TableLayout_uca900{})
-	maketable(&buf, "uca900_ja", "testdata/mysqldata/utf8mb4_ja_0900_as_cs.json", pages, uca.TableLayout_uca900{})
-	maketable(&buf, "uca900_zh", "testdata/mysqldata/utf8mb4_zh_0900_as_cs.json", pages, uca.TableLayout_uca900{})
+	maketable(&buf, "uca900", "utf8mb4_0900_ai_ci.json", pages, uca.TableLayout_uca900{})
+	maketable(&buf, "uca900_ja", "utf8mb4_ja_0900_as_cs.json", pages, uca.TableLayout_uca900{})
+	maketable(&buf, "uca900_zh", "utf8mb4_zh_0900_as_cs.json", pages, uca.TableLayout_uca900{})
 
-	maketable(&buf, "uca400", "testdata/mysqldata/utf8mb4_unicode_ci.json", pages, uca.TableLayout_uca_legacy{})
-	maketable(&buf, "uca520", "testdata/mysqldata/utf8mb4_unicode_520_ci.json", pages, uca.TableLayout_uca_legacy{})
+	maketable(&buf, "uca400", "utf8mb4_unicode_ci.json", pages, uca.TableLayout_uca_legacy{})
+	maketable(&buf, "uca520", "utf8mb4_unicode_520_ci.json", pages, uca.TableLayout_uca_legacy{})
 
 	pages.WriteTrailer(&buf, "tables_uca.bin")
 
@@ -96,20 +99,63 @@ func main() {
 		log.Fatalf("failed to format generated code: %v", err)
 	}
 
-	output := path.Join(*Output, "tables_uca.go")
-	err = os.WriteFile(output, formatted, 0644)
-	if err != nil {
-		log.Fatalf("failed to generate %q: %v", output, err)
+	return map[string][]byte{
+		path.Join(*Output, "tables_uca.go"):  formatted,
+		path.Join(*Output, "tables_uca.bin"): pages.EmbedData(),
 	}
+}
 
-	log.Printf("written %s (%.02fkb)", output, float64(len(formatted))/1024.0)
+// verifyFilesOnDisk compares the generated tables against the files that currently
+// exist on disk and returns any mismatches
+func verifyFilesOnDisk(result map[string][]byte) (errors []error) {
+	for fullPath, generated := range result {
+		existing, err := os.ReadFile(fullPath)
+		if err != nil {
+			errors = append(errors, fmt.Errorf("missing file on disk: %s (%w)", fullPath, err))
+			continue
+		}
+		if !bytes.Equal(existing, generated) {
+			errors = append(errors, fmt.Errorf("'%s' has changed", fullPath))
+		}
+	}
+	return errors
+}
 
-	output = path.Join(*Output, "tables_uca.bin")
-	embed := pages.EmbedData()
-	err = os.WriteFile(output, embed, 0644)
+func main() {
+	flag.Parse()
+
+	// the JSON files are dumped from a MySQL server with colldump and they are not checked in,
+	// so they may be missing; there's nothing to verify against in that case
+	dumps, err := filepath.Glob(path.Join(*Input, "*.json"))
 	if err != nil {
-		log.Fatalf("failed to generate %q: %v", output, err)
+		log.Fatal(err)
+	}
+	if len(dumps) == 0 {
+		if *Verify {
+			log.Printf("no files under '%s', skipping verification (did you run colldump locally?)", *Input)
+			return
+		}
+		log.Fatalf("no files under '%s' (did you run colldump locally?)", *Input)
 	}
 
-	log.Printf("written %s (%.02fkb)", output, float64(len(embed))/1024.0)
+	result := generate()
+
+	if *Verify {
+		errors := verifyFilesOnDisk(result)
+		for _, err := range errors {
+			log.Print(err)
+		}
+		if len(errors) > 0 {
+			log.Fatalf("the weight tables are out of date: run `go run ./tools/maketables/` to regenerate them")
+		}
+		log.Printf("%d files OK", len(result))
+		return
+	}
+
+	for output, contents := range result {
+		if err := os.WriteFile(output, contents, 0644); err != nil {
+			log.Fatalf("failed to generate %q: %v", output, err)
+		}
+		log.Printf("written %s (%.02fkb)", output, float64(len(contents))/1024.0)
+	}
 }
@@ -0,0 +1,101 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collations
+
+import (
+	"bytes"
+
+	"vitess.io/vitess/go/mysql/collations/internal/charset"
+)
+
+// collationTrimLeft implements TrimLeft for any collation. Just like in likeMatch,
+// the strings are compared one codepoint at a time, so contractions are never matched.
+func collationTrimLeft(coll Collation, src, cutset []byte) []byte {
+	cs := coll.Charset()
+	cutset = trimCutset(cs, cutset)
+
+	for len(src) > 0 {
+		width := nextCodepointWidth(cs, src)
+		if !trimContains(coll, cutset, src[:width]) {
+			break
+		}
+		src = src[width:]
+	}
+	return src
+}
+
+// collationTrimRight implements TrimRight for any collation. Since not all charsets can
+// be decoded backwards, `src` is decoded from its start, keeping track of where its
+// trailing run of trimmed codepoints begins.
+func collationTrimRight(coll Collation, src, cutset []byte) []byte {
+	cs := coll.Charset()
+	cutset = trimCutset(cs, cutset)
+
+	end := len(src)
+	for pos := 0; pos < len(src); {
+		width := nextCodepointWidth(cs, src[pos:])
+		if !trimContains(coll, cutset, src[pos:pos+width]) {
+			end = len(src)
+		} else if end == len(src) {
+			end = pos
+		}
+		pos += width
+	}
+	return src[:end]
+}
+
+func collationTrim(coll Collation, src, cutset []byte) []byte {
+	return collationTrimRight(coll, collationTrimLeft(coll, src, cutset), cutset)
+}
+
+// trimCutset returns the codepoints to trim: `cutset` itself, or the collation's padding
+// character encoded in `cs` if `cutset` is empty
+func trimCutset(cs charset.Charset, cutset []byte) []byte {
+	if len(cutset) > 0 {
+		return cutset
+	}
+	var space [4]byte
+	return space[:cs.EncodeRune(space[:], ' ')]
+}
+
+// nextCodepointWidth returns the width of the next codepoint in `src`, which is always at
+// least one byte and never goes past the end of `src`, so that invalid or truncated
+// sequences can be skipped whole without ever splitting a codepoint
+func nextCodepointWidth(cs charset.Charset, src []byte) int {
+	_, width := cs.DecodeRune(src)
+	if width < 1 {
+		width = 1
+	}
+	if width > len(src) {
+		width = len(src)
+	}
+	return width
+}
+
+// trimContains returns whether the single codepoint in `cp` is equal under the collation
+// to any of the codepoints in `cutset`
+func trimContains(coll Collation, cutset, cp []byte) bool {
+	cs := coll.Charset()
+	for len(cutset) > 0 {
+		width := nextCodepointWidth(cs, cutset)
+		if bytes.Equal(cp, cutset[:width]) || coll.Equal(cp, cutset[:width]) {
+			return true
+		}
+		cutset = cutset[width:]
+	}
+	return false
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collations
+
+import (
+	"testing"
+)
+
+func TestTrim(t *testing.T) {
+	var cases = []struct {
+		collation         string
+		src, cutset       string
+		left, right, both string
+	}{
+		{"utf8mb4_0900_ai_ci", "  hello  ", "", "hello  ", "  hello", "hello"},
+		{"utf8mb4_0900_ai_ci", "éeÉhelloEé", "e", "helloEé", "éeÉhello", "hello"},
+		{"utf8mb4_0900_as_cs", "éeÉhelloEé", "e", "éeÉhelloEé", "éeÉhelloEé", "éeÉhelloEé"},
+		{"utf8mb4_0900_as_cs", "eehelloe", "e", "helloe", "eehello", "hello"},
+		{"utf8mb4_general_ci", "xXhelloXx", "x", "helloXx", "xXhello", "hello"},
+		{"utf8mb4_bin", "xXhelloXx", "x", "XhelloXx", "xXhelloX", "XhelloX"},
+		{"utf8mb4_0900_ai_ci", "abchelloba", "ab", "chelloba", "abchello", "chello"},
+		{"utf8mb4_0900_ai_ci", "😀hello😀", "😀", "hello😀", "😀hello", "hello"},
+		{"utf8mb4_0900_ai_ci", "eeee", "E", "", "", ""},
+		{"utf8mb4_0900_ai_ci", "", "e", "", "", ""},
+		{"latin1_swedish_ci", "\xe9e\xc9hello", "e", "hello", "\xe9e\xc9hello", "hello"},
+		{"latin1_swedish_ci", "\xe4ahello\xe4", "a", "\xe4ahello\xe4", "\xe4ahello\xe4", "\xe4ahello\xe4"},
+		{"latin1_general_ci", "aAhello", "a", "hello", "aAhello", "hello"},
+		{"binary", "  hello\x00 ", "", "hello\x00 ", "  hello\x00", "hello\x00"},
+		{"binary", "aAhelloa", "a", "Ahelloa", "aAhello", "Ahello"},
+		// the second byte of "ア" in Shift-JIS is 'A', but codepoints must never be split
+		{"sjis_japanese_ci", "\x83\x41hello\x83\x41", "A", "\x83\x41hello\x83\x41", "\x83\x41hello\x83\x41", "\x83\x41hello\x83\x41"},
+		{"sjis_japanese_ci", "aA\x83\x41Aa", "a", "\x83\x41Aa", "aA\x83\x41", "\x83\x41"},
+		{"utf16_general_ci", "\x00 \x00a\x00 ", "", "\x00a\x00 ", "\x00 \x00a", "\x00a"},
+	}
+
+	for _, tc := range cases {
+		coll := testcollation(t, tc.collation)
+		src, cutset := []byte(tc.src), []byte(tc.cutset)
+
+		if got := coll.TrimLeft(src, cutset); string(got) != tc.left {
+			t.Errorf("%s: TrimLeft(%q, %q) = %q (expected %q)", tc.collation, tc.src, tc.cutset, got, tc.left)
+		}
+		if got := coll.TrimRight(src, cutset); string(got) != tc.right {
+			t.Errorf("%s: TrimRight(%q, %q) = %q (expected %q)", tc.collation, tc.src, tc.cutset, got, tc.right)
+		}
+		if got := coll.Trim(src, cutset); string(got) != tc.both {
+			t.Errorf("%s: Trim(%q, %q) = %q (expected %q)", tc.collation, tc.src, tc.cutset, got, tc.both)
+		}
+	}
+}
+
+func TestTrimAllCollations(t *testing.T) {
+	for _, coll := range All() {
+		cs := coll.Charset()
+		var src, space []byte
+		var buf [4]byte
+		space = append(space, buf[:cs.EncodeRune(buf[:], ' ')]...)
+		src = append(src, space...)
+		src = append(src, buf[:cs.EncodeRune(buf[:], 'a')]...)
+		src = append(src, space...)
+
+		if got := coll.Trim(src, nil); string(got) != string(src[len(space):len(src)-len(space)]) {
+			t.Errorf("%s: Trim(%q) = %q", coll.Name(), src, got)
+		}
+	}
+}
@@ -17,7 +17,15 @@ limitations under the License.
 package collations
 
 import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
 	"sync"
+	"unicode/utf8"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/transform"
 
 	"vitess.io/vitess/go/mysql/collations/internal/charset"
 	"vitess.io/vitess/go/mysql/collations/internal/uca"
@@ -31,6 +39,140 @@ type CollationUCA interface {
 	Collation
 	Charset() charset.Charset
 	UnicodeWeightsTable() (uca.WeightTable, uca.TableLayout)
+
+	// HasPrefix returns whether `haystack` starts with a sequence of codepoints that
+	// is equal to `needle` in all the levels this collation compares. This means that
+	// an accent insensitive collation will find "cafe" to be a prefix of "café table",
+	// but an accent sensitive one won't.
+	// Contractions and expansions are resolved in each string independently, so the
+	// needle must end on a boundary between the collation elements of the haystack:
+	// in a collation where "ch" is a contraction, "c" is not a prefix of "chleba",
+	// and in any collation, "s" is not a prefix of "ß", which expands to "ss".
+	HasPrefix(haystack, needle []byte) bool
+
+	// HasSuffix returns whether `haystack` ends with a sequence of codepoints that is
+	// equal to `needle` in all the levels this collation compares. Like in HasPrefix,
+	// the needle must start on a boundary between the collation elements of the haystack,
+	// so "h" is not a suffix of "ch" in a collation where "ch" is a contraction.
+	// The primary weights for both strings are computed and their tails are compared,
+	// so this takes O(len(haystack) + len(needle)) time and allocates memory proportional
+	// to the length of both inputs; if the tails match, the needle is compared against
+	// the haystack at every codepoint boundary where the match may start, which is
+	// usually only one. For ASCII strings in collations without contractions, only
+	// the last len(needle) bytes of the haystack are compared, without allocating.
+	HasSuffix(haystack, needle []byte) bool
+
+	// PadWeight returns the weight that WeightString uses to pad the weight strings of
+	// this collation: the primary weight for the SPACE character in the legacy collations,
+	// and 0 in the UCA 9.0.0 collations, which pad with 0x00 bytes. Padding a weight string
+	// with the big-endian bytes of this weight yields the same result as WeightString.
+	PadWeight() uint16
+
+	// CollateCtx works like Collate, but it checks `ctx` periodically while comparing the
+	// two strings, and returns its error if it has been cancelled, so that comparing very
+	// large inputs can be interrupted. The comparison result is only valid if the error is nil.
+	CollateCtx(ctx context.Context, left, right []byte, rightIsPrefix bool) (int, error)
+
+	// CollateDetailed compares `left` and `right` like Collate, and also returns the amount
+	// of weights that were equal in both strings before the comparison was decided, across
+	// all the levels this collation compares. When the strings are equal, this is the total
+	// amount of weights in either of them. Since it is tracked while comparing, it can be
+	// used to break ties between strings that diverge at different points without having
+	// to compare them again.
+	CollateDetailed(left, right []byte) (cmp int, matchedWeights int)
+
+	// CollateWeightString compares a string whose weight string has already been computed
+	// with WeightString (with `numCodepoints` set to 0) against the string `right`, and
+	// returns the same result as calling Collate with the original string: the weights for
+	// `right` are computed on the fly and compared with `weights`, so storing the weight
+	// strings of e.g. the keys of an index saves weighting them again on every comparison.
+	CollateWeightString(weights, right []byte, rightIsPrefix bool) int
+
+	// Weights returns a function that yields the collation weights for `src` one at a time,
+	// together with the level they belong to, starting at level 0 for the primary weights.
+	// All the weights for a level are yielded before moving on to the next one, and only the
+	// levels this collation compares are included; the NULL weights that separate the levels
+	// in a weight string are not yielded. Contractions and expansions are already resolved
+	// in the stream, so a contraction yields the weights for all its codepoints at once, and
+	// an expanded codepoint yields several weights. Once the returned function has reported
+	// `ok = false`, the stream is exhausted and must not be used again.
+	Weights(src []byte) func() (weight uint16, level int, ok bool)
+
+	// Explain compares `left` and `right` like Collate, and returns a human-readable trace
+	// of the comparison: the codepoints of both strings, their weights in every level this
+	// collation compares, the weight where the strings diverged, and the final result.
+	// The comparison is performed again to build the trace, so this is only meant to
+	// debug why two strings sort the way they do, and must not be used in hot paths.
+	Explain(left, right []byte) string
+}
+
+// collateCtxInterval is the amount of weights compared between checks of the context in
+// CollateCtx; checking the context is relatively expensive compared to comparing weights
+const collateCtxInterval = 4096
+
+// weightOffset is a primary weight, together with the offset in its input where the
+// codepoint that yielded it ends, and whether it was the last weight for that codepoint
+type weightOffset struct {
+	weight   uint16
+	end      int
+	complete bool
+}
+
+// suffixRange returns whether the primary weights of `needle` match the tail of the
+// primary weights of a haystack with length `haylen`, and if so, the range of offsets
+// (both inclusive) in the haystack where the codepoints that match the needle may start.
+func suffixRange(haystack, needle []weightOffset, haylen int) (int, int, bool) {
+	m := len(haystack) - len(needle)
+	if m < 0 {
+		return 0, 0, false
+	}
+	for i := range needle {
+		if haystack[m+i].weight != needle[i].weight {
+			return 0, 0, false
+		}
+	}
+
+	start, limit := 0, haylen
+	if m > 0 {
+		if !haystack[m-1].complete {
+			return 0, 0, false
+		}
+		start = haystack[m-1].end
+	}
+	if m < len(haystack) {
+		// the match must start before the end of the codepoint that yields the first
+		// matching weight; any codepoints in between have no primary weights
+		limit = haystack[m].end - 1
+	}
+	return start, limit, true
+}
+
+// hasSuffixInRange calls `match` with the suffixes of `haystack` starting at every
+// codepoint boundary between `start` and `limit`, and returns whether any of them matched
+func hasSuffixInRange(cs charset.Charset, haystack []byte, start, limit int, match func(suffix []byte) bool) bool {
+	for off := start; off <= limit; {
+		if match(haystack[off:]) {
+			return true
+		}
+		if off >= len(haystack) {
+			break
+		}
+		_, width := cs.DecodeRune(haystack[off:])
+		if width < 1 {
+			width = 1
+		}
+		off += width
+	}
+	return false
+}
+
+func isPrintableASCII(str []byte) bool {
+	for _, ch := range str {
+		if ch < 0x20 || ch > 0x7E {
+			return false
+		}
+	}
+	return true
 }
 
 type Collation_utf8mb4_uca_0900 struct {
@@ -44,26 +186,56 @@ type Collation_utf8mb4_uca_0900 struct {
 	upperCaseFirst   bool
 	levelsForCompare int
 
-	uca     *uca.Collation900
-	ucainit sync.Once
+	uca          *uca.Collation900
+	ucainit      sync.Once
+	asciiPrimary *[128]uint16
 }
 
 func (c *Collation_utf8mb4_uca_0900) init() {
 	c.ucainit.Do(func() {
-		c.uca = uca.NewCollation(c.name, c.weights, c.tailoring, c.reorder, c.contractions, c.upperCaseFirst, c.levelsForCompare)
+		c.uca = uca.NewCollation(c.name, loadWeightTable(c.weights), c.tailoring, c.reorder, c.contractions, c.upperCaseFirst, c.levelsForCompare)
+		c.asciiPrimary = asciiPrimaryWeights(c.uca)
 
-		// Clear the external metadata for this collation, so it can be picked up by the GC
+		// Clear the external metadata for this collation, so it can be picked up by the GC.
+		// The contractions and reorderings are kept because they can be inspected through
+		// Contractions() and Reorders().
 		c.weights = nil
-		c.contractions = nil
 		c.tailoring = nil
-		c.reorder = nil
 	})
 }
 
+// Contractions returns the contractions defined by this collation, i.e. the sequences
+// of codepoints that are sorted as a single unit, such as "ch" in Czech. The weights for
+// each contraction are the raw weights from the collation's tables, before any reordering
+// or case tailoring is applied. This method can be called whether or not the collation
+// has been initialized, and it returns an empty slice for collations without contractions.
+// The returned slice is a copy, but the paths and weights of each contraction are shared
+// with the collation and must not be modified.
+func (c *Collation_utf8mb4_uca_0900) Contractions() []uca.Contraction {
+	contractions := make([]uca.Contraction, len(c.contractions))
+	copy(contractions, c.contractions)
+	return contractions
+}
+
+// Reorders returns the script reorderings applied by this collation, i.e. the ranges of
+// primary weights that are moved so that some scripts sort before others, such as Cyrillic
+// sorting before Latin in Russian. Like Contractions, this method can be called whether or
+// not the collation has been initialized, and it returns an empty slice for collations
+// that don't reorder any scripts.
+func (c *Collation_utf8mb4_uca_0900) Reorders() []uca.Reorder {
+	reorders := make([]uca.Reorder, len(c.reorder))
+	copy(reorders, c.reorder)
+	return reorders
+}
+
 func (c *Collation_utf8mb4_uca_0900) UnicodeWeightsTable() (uca.WeightTable, uca.TableLayout) {
 	return c.uca.Weights()
 }
 
+func (c *Collation_utf8mb4_uca_0900) PadWeight() uint16 {
+	return 0
+}
+
 func (c *Collation_utf8mb4_uca_0900) Name() string {
 	return c.name
 }
@@ -80,7 +252,158 @@ func (c *Collation_utf8mb4_uca_0900) IsBinary() bool {
 	return false
 }
 
+func (c *Collation_utf8mb4_uca_0900) Capabilities() CollationCaps {
+	// the secondary level holds the accents, and the tertiary level holds the case
+	return CollationCaps{
+		CaseSensitive:   c.levelsForCompare >= 3,
+		AccentSensitive: c.levelsForCompare >= 2,
+		Levels:          c.levelsForCompare,
+	}
+}
+
+// asciiPrimaryWeights returns the primary weight for each ASCII character in the
+// given collation, or 0 if the character is ignorable. If the weights for ASCII
+// characters are not simple (i.e. the collation has tailorings or contractions,
+// or any ASCII character has more than one primary weight), it returns nil.
+func asciiPrimaryWeights(coll *uca.Collation900) *[128]uint16 {
+	it := coll.Iterator(nil)
+	_, fast := it.(*uca.FastIterator900)
+	it.Done()
+	if !fast {
+		return nil
+	}
+
+	var weights [128]uint16
+	for ch := range weights {
+		it := coll.Iterator([]byte{byte(ch)})
+		w, ok := it.Next()
+		if ok && it.Level() == 0 {
+			weights[ch] = w
+			if w2, ok := it.Next(); ok && it.Level() == 0 && w2 != 0 {
+				it.Done()
+				return nil
+			}
+		}
+		it.Done()
+	}
+	return &weights
+}
+
+// collateASCII compares two strings using the primary weights of their ASCII characters,
+// and returns whether the comparison could be resolved without looking at the other levels.
+// The comparison gives up as soon as it reaches a non-ASCII byte in either string, but the
+// strings can contain non-ASCII characters after the first difference in their primary weights.
+func (c *Collation_utf8mb4_uca_0900) collateASCII(left, right []byte, rightIsPrefix bool) (int, bool) {
+	weights := c.asciiPrimary
+	var l, r int
+
+	for {
+		for l < len(left) && left[l] < utf8.RuneSelf && weights[left[l]] == 0 {
+			l++
+		}
+		for r < len(right) && right[r] < utf8.RuneSelf && weights[right[r]] == 0 {
+			r++
+		}
+		if (l < len(left) && left[l] >= utf8.RuneSelf) || (r < len(right) && right[r] >= utf8.RuneSelf) {
+			return 0, false
+		}
+
+		switch {
+		case l == len(left) && r == len(right):
+			return 0, c.levelsForCompare == 1
+		case l == len(left):
+			return -1, true
+		case r == len(right):
+			// if `right` is a prefix, the result depends on the remaining levels
+			return 1, !rightIsPrefix
+		}
+
+		if lw, rw := weights[left[l]], weights[right[r]]; lw != rw {
+			return int(lw) - int(rw), true
+		}
+		l++
+		r++
+	}
+}
+
 func (c *Collation_utf8mb4_uca_0900) Collate(left, right []byte, rightIsPrefix bool) int {
+	cmp, _ := c.CollateCtx(context.Background(), left, right, rightIsPrefix)
+	return cmp
+}
+
+func (c *Collation_utf8mb4_uca_0900) CollateCtx(ctx context.Context, left, right []byte, rightIsPrefix bool) (int, error) {
+	// Two empty strings are equal, and an empty string is a prefix of any string. An empty
+	// string compared with one that is not empty must still go through the iterators, since
+	// the other string could be made only of ignorable codepoints that yield no weights.
+	if bytes.Equal(left, right) || (rightIsPrefix && len(right) == 0) {
+		return 0, nil
+	}
+	if c.asciiPrimary != nil {
+		if cmp, ok := c.collateASCII(left, right, rightIsPrefix); ok {
+			return cmp, nil
+		}
+	}
+	cmp, _, err := c.collate(ctx, left, right, rightIsPrefix)
+	return cmp, err
+}
+
+func (c *Collation_utf8mb4_uca_0900) CollateDetailed(left, right []byte) (int, int) {
+	cmp, matched, _ := c.collate(context.Background(), left, right, false)
+	return cmp, matched
+}
+
+func (c *Collation_utf8mb4_uca_0900) Explain(left, right []byte) string {
+	return explainCollation(c, c.levelsForCompare, left, right)
+}
+
+func (c *Collation_utf8mb4_uca_0900) CollateWeightString(weights, right []byte, rightIsPrefix bool) int {
+	it := c.uca.Iterator(right)
+	defer it.Done()
+
+	// the weight string has the weights for every level this collation compares, separated
+	// by NULL weights, which is exactly what the iterator yields until it reaches the levels
+	// that are not compared
+	for {
+		r, rok := it.Next()
+		if rok && it.Level() >= c.levelsForCompare {
+			rok = false
+		}
+		if len(weights) < 2 {
+			if !rok {
+				return 0
+			}
+			return -1
+		}
+		if !rok {
+			if rightIsPrefix {
+				return 0
+			}
+			return 1
+		}
+
+		l := uint16(weights[0])<<8 | uint16(weights[1])
+		if l != r {
+			if r == 0 && rightIsPrefix {
+				// `right` has moved on to the next level, so the rest of this level in
+				// the weight string is skipped, up to and including the separator
+				for len(weights) >= 2 && (weights[0] != 0 || weights[1] != 0) {
+					weights = weights[2:]
+				}
+				if len(weights) >= 2 {
+					weights = weights[2:]
+					continue
+				}
+				return 0
+			}
+			return int(l) - int(r)
+		}
+		weights = weights[2:]
+	}
+}
+
+// collate compares `left` and `right` one weight at a time, and returns the result of the
+// comparison together with the amount of weights that were equal in both strings
+func (c *Collation_utf8mb4_uca_0900) collate(ctx context.Context, left, right []byte, rightIsPrefix bool) (int, int, error) {
 	var (
 		l, r            uint16
 		lok, rok        bool
@@ -88,6 +411,8 @@ func (c *Collation_utf8mb4_uca_0900) Collate(left, right []byte, rightIsPrefix b
 		levelsToCompare = c.levelsForCompare
 		itleft          = c.uca.Iterator(left)
 		itright         = c.uca.Iterator(right)
+		weights         int
+		matched         int
 	)
 
 	defer itleft.Done()
@@ -98,43 +423,161 @@ nextLevel:
 		l, lok = itleft.Next()
 		r, rok = itright.Next()
 
+		if weights++; weights%collateCtxInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return 0, matched, err
+			}
+		}
+
 		if l != r || !lok || !rok {
 			break
 		}
 		if itleft.Level() != level || itright.Level() != level {
 			break
 		}
+		matched++
 	}
 
 	switch {
 	case itleft.Level() == itright.Level():
 		if l == r && lok && rok {
+			// both iterators yielded the NULL weight that separates the levels, which
+			// is not counted as a matched weight
 			level++
 			if level < levelsToCompare {
 				goto nextLevel
 			}
 		}
 	case itleft.Level() > level:
-		return -1
+		return -1, matched, nil
 	case itright.Level() > level:
 		if rightIsPrefix {
 			level = itleft.SkipLevel()
 			if level < levelsToCompare {
 				goto nextLevel
 			}
-			return -int(r)
+			return -int(r), matched, nil
 		}
-		return 1
+		return 1, matched, nil
 	}
 
-	return int(l) - int(r)
+	return int(l) - int(r), matched, nil
+}
+
+func (c *Collation_utf8mb4_uca_0900) HasPrefix(haystack, needle []byte) bool {
+	var (
+		ithay    = c.uca.Iterator(haystack)
+		itneedle = c.uca.Iterator(needle)
+	)
+
+	defer ithay.Done()
+	defer itneedle.Done()
+
+	// Match all the primary weights in `needle` against the start of `haystack`;
+	// the haystack iterator is only advanced while the needle has weights left, so
+	// once the needle is exhausted, the haystack iterator points right after the
+	// codepoints that matched.
+	for {
+		r, rok := itneedle.Next()
+		if !rok || itneedle.Level() > 0 {
+			break
+		}
+		l, lok := ithay.Next()
+		if !lok || ithay.Level() > 0 || l != r {
+			return false
+		}
+	}
+
+	// If the match ended halfway through the weights of an expansion or a contraction
+	// in `haystack`, there's no codepoint boundary where the needle ends.
+	end, complete := ithay.Position()
+	if !complete {
+		return false
+	}
+	if c.levelsForCompare == 1 {
+		return true
+	}
+
+	// All the remaining levels must be compared exclusively against the part of the
+	// haystack that matched the needle in the primary level.
+	return c.Collate(haystack[:end], needle, false) == 0
+}
+
+func (c *Collation_utf8mb4_uca_0900) HasSuffix(haystack, needle []byte) bool {
+	// Without contractions, every printable ASCII character yields exactly one weight
+	// per level on its own, so an ASCII needle can only match the same amount of bytes
+	// at the end of the haystack.
+	if len(c.contractions) == 0 && len(needle) <= len(haystack) {
+		tail := haystack[len(haystack)-len(needle):]
+		if isPrintableASCII(needle) && isPrintableASCII(tail) {
+			return c.Collate(tail, needle, false) == 0
+		}
+	}
+
+	hayWeights := c.primaryWeights(haystack)
+	needleWeights := c.primaryWeights(needle)
+
+	start, limit, ok := suffixRange(hayWeights, needleWeights, len(haystack))
+	if !ok {
+		return false
+	}
+	return hasSuffixInRange(c.Charset(), haystack, start, limit, func(suffix []byte) bool {
+		return c.Collate(suffix, needle, false) == 0
+	})
+}
+
+func (c *Collation_utf8mb4_uca_0900) primaryWeights(src []byte) []weightOffset {
+	it := c.uca.Iterator(src)
+	defer it.Done()
+
+	var weights []weightOffset
+	for {
+		w, ok := it.Next()
+		if !ok || it.Level() > 0 {
+			return weights
+		}
+		end, complete := it.Position()
+		weights = append(weights, weightOffset{w, end, complete})
+	}
+}
+
+func (c *Collation_utf8mb4_uca_0900) Weights(src []byte) func() (uint16, int, bool) {
+	it := c.uca.Iterator(src)
+	level := 0
+
+	return func() (uint16, int, bool) {
+		if it == nil {
+			return 0, 0, false
+		}
+		w, ok := it.Next()
+		// the iterator yields a NULL weight every time it moves on to the next level
+		for ok && it.Level() != level {
+			level = it.Level()
+			if level >= c.levelsForCompare {
+				ok = false
+				break
+			}
+			w, ok = it.Next()
+		}
+		if !ok {
+			it.Done()
+			it = nil
+			return 0, 0, false
+		}
+		return w, level, true
+	}
 }
 
 func (c *Collation_utf8mb4_uca_0900) WeightString(dst, src []byte, numCodepoints int) []byte {
+	if numCodepoints > 0 && numCodepoints != PadToMax {
+		// NO PAD collations never pad their weight strings, but they are still truncated
+		src = charset.Truncate(c.Charset(), src, numCodepoints)
+	}
+
 	it := c.uca.Iterator(src)
 	defer it.Done()
 
-	if fast, ok := it.(*uca.FastIterator900); ok {
+	if fast, ok := it.(*uca.FastIterator900); ok && c.levelsForCompare == c.uca.Levels() {
 		var chunk [16]byte
 		for {
 			for cap(dst)-len(dst) >= 16 {
@@ -153,7 +596,7 @@ func (c *Collation_utf8mb4_uca_0900) WeightString(dst, src []byte, numCodepoints
 	} else {
 		for {
 			w, ok := it.Next()
-			if !ok {
+			if !ok || it.Level() >= c.levelsForCompare {
 				break
 			}
 			dst = append(dst, byte(w>>8), byte(w))
@@ -170,16 +613,449 @@ performPadding:
 	return dst
 }
 
+// WeightStringLevels returns a weight string for `src` that only contains the weights
+// for the first `maxLevel` levels of this collation, e.g. the weight string for
+// `maxLevel = 1` only contains primary weights, which is enough to bucket strings that
+// are equal when ignoring accents and case. The levels in the weight string are
+// separated by NULL weights, just like in WeightString.
+// If `maxLevel` is larger than the amount of levels compared by this collation, the
+// result is the same as the full weight string. `maxLevel` must be at least 1.
+func (c *Collation_utf8mb4_uca_0900) WeightStringLevels(dst, src []byte, numCodepoints, maxLevel int) []byte {
+	if maxLevel < 1 {
+		panic(fmt.Sprintf("WeightStringLevels: invalid level %d", maxLevel))
+	}
+	if maxLevel >= c.levelsForCompare {
+		return c.WeightString(dst, src, numCodepoints)
+	}
+
+	it := c.uca.Iterator(src)
+	defer it.Done()
+
+	for {
+		w, ok := it.Next()
+		if !ok || it.Level() >= maxLevel {
+			break
+		}
+		dst = append(dst, byte(w>>8), byte(w))
+	}
+
+	if numCodepoints == PadToMax {
+		for len(dst) < cap(dst) {
+			dst = append(dst, 0x00)
+		}
+	}
+	return dst
+}
+
+// WeightStringTo writes the weight string for `src` into `w` instead of appending it to
+// a byte slice, so that the weights for very large inputs never need to be kept in
+// memory at once. The weights are written in chunks of up to 16 bytes, and the output
+// is identical to the result of WeightString. Since there is no destination slice whose
+// capacity can be filled, PadToMax pads the weight string with 0x00 bytes up to the
+// WeightStringLen for `src`, rounded up to whole 4-byte characters, just like WeightString
+// does when `dst` has been pre-allocated to that size.
+// Returns the number of bytes written to `w`.
+func (c *Collation_utf8mb4_uca_0900) WeightStringTo(w io.Writer, src []byte, numCodepoints int) (int, error) {
+	var padTo int
+	if numCodepoints == PadToMax {
+		padTo = c.WeightStringLen((len(src) + 3) &^ 3)
+	} else if numCodepoints > 0 {
+		src = charset.Truncate(c.Charset(), src, numCodepoints)
+	}
+
+	it := c.uca.Iterator(src)
+	defer it.Done()
+
+	var chunk [16]byte
+	var total int
+	for {
+		n := c.nextWeightChunk(it, &chunk)
+		if n <= 0 {
+			break
+		}
+		written, err := w.Write(chunk[:n])
+		total += written
+		if err != nil {
+			return total, err
+		}
+	}
+
+	chunk = [16]byte{}
+	for total < padTo {
+		written, err := w.Write(chunk[:minInt(padTo-total, len(chunk))])
+		total += written
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+// WeightStringStrict is equivalent to WeightString, but it returns an error if `src`
+// is not a valid utf8mb4 string instead of silently ignoring the invalid input.
+// Just like MySQL's WEIGHT_STRING, WeightString stops processing its input at the first
+// invalid byte, so invalid strings will have the same weights as their longest valid prefix.
+func (c *Collation_utf8mb4_uca_0900) WeightStringStrict(dst, src []byte, numCodepoints int) ([]byte, error) {
+	if offset := charset.ValidateOffset(c.Charset(), src); offset >= 0 {
+		return dst, invalidCharacterString(c.Charset(), src, offset)
+	}
+	return c.WeightString(dst, src, numCodepoints), nil
+}
+
+// invalidCharacterString returns the same error that MySQL returns when it finds an
+// invalid sequence at `src[offset:]`. Like MySQL, only the first 6 invalid bytes are
+// displayed in the error message.
+func invalidCharacterString(cs charset.Charset, src []byte, offset int) error {
+	invalid := src[offset:]
+	if len(invalid) > 6 {
+		invalid = invalid[:6]
+	}
+	return fmt.Errorf("Invalid %s character string: '%X' (at offset %d)", cs.Name(), invalid, offset)
+}
+
+// nextWeightChunk fills `chunk` with the next weights yielded by the iterator, using
+// the fast path for ASCII strings if it's available, and returns the number of bytes
+// that were written. If 0, the iterator has been fully consumed.
+func (c *Collation_utf8mb4_uca_0900) nextWeightChunk(it uca.WeightIterator, chunk *[16]byte) int {
+	if fast, ok := it.(*uca.FastIterator900); ok && c.levelsForCompare == c.uca.Levels() {
+		return fast.NextChunk(chunk[:])
+	}
+	for i := 0; i < 8; i++ {
+		w, ok := it.Next()
+		if !ok || it.Level() >= c.levelsForCompare {
+			return i * 2
+		}
+		chunk[i*2] = byte(w >> 8)
+		chunk[i*2+1] = byte(w)
+	}
+	return 16
+}
+
+// weightStringLenExact counts the weights that WeightString would append for `src`,
+// including the NULL weights that separate the levels
+func (c *Collation_utf8mb4_uca_0900) weightStringLenExact(src []byte, numCodepoints int) int {
+	if numCodepoints > 0 {
+		src = charset.Truncate(c.Charset(), src, numCodepoints)
+	}
+
+	it := c.uca.Iterator(src)
+	defer it.Done()
+
+	var n int
+	for {
+		_, ok := it.Next()
+		if !ok || it.Level() >= c.levelsForCompare {
+			return n
+		}
+		n += 2
+	}
+}
+
+func (c *Collation_utf8mb4_uca_0900) Hash(src []byte, numCodepoints int) uint64 {
+	if numCodepoints > 0 && numCodepoints != PadToMax {
+		src = charset.Truncate(c.Charset(), src, numCodepoints)
+	}
+
+	it := c.uca.Iterator(src)
+	defer it.Done()
+
+	var chunk [16]byte
+	h := newWeightHasher()
+	for {
+		n := c.nextWeightChunk(it, &chunk)
+		if n <= 0 {
+			break
+		}
+		h.write(chunk[:n])
+	}
+	return h.sum64()
+}
+
+func (c *Collation_utf8mb4_uca_0900) Like(pattern, input []byte, escape rune) bool {
+	return likeMatch(c, pattern, input, escape)
+}
+
+func (c *Collation_utf8mb4_uca_0900) Min(a, b []byte) []byte {
+	return collationMin(c, a, b)
+}
+
+func (c *Collation_utf8mb4_uca_0900) Max(a, b []byte) []byte {
+	return collationMax(c, a, b)
+}
+
+func (c *Collation_utf8mb4_uca_0900) TrimLeft(src, cutset []byte) []byte {
+	return collationTrimLeft(c, src, cutset)
+}
+
+func (c *Collation_utf8mb4_uca_0900) TrimRight(src, cutset []byte) []byte {
+	return collationTrimRight(c, src, cutset)
+}
+
+func (c *Collation_utf8mb4_uca_0900) Trim(src, cutset []byte) []byte {
+	return collationTrim(c, src, cutset)
+}
+
+func (c *Collation_utf8mb4_uca_0900) Index(haystack, needle []byte) int {
+	return collationIndex(c, haystack, needle)
+}
+
+func (c *Collation_utf8mb4_uca_0900) Upper(dst, src []byte) []byte {
+	return collationUpper(c, dst, src)
+}
+
+func (c *Collation_utf8mb4_uca_0900) Lower(dst, src []byte) []byte {
+	return collationLower(c, dst, src)
+}
+
+func (c *Collation_utf8mb4_uca_0900) Equal(left, right []byte) bool {
+	if bytes.Equal(left, right) {
+		return true
+	}
+
+	itleft := c.uca.Iterator(left)
+	itright := c.uca.Iterator(right)
+	defer itleft.Done()
+	defer itright.Done()
+
+	// the strings are only equal if they yield the same weights for all the levels
+	// this collation compares, so we can stop as soon as any weight is different
+	for {
+		l, lok := itleft.Next()
+		r, rok := itright.Next()
+		if lok != rok || l != r || itleft.Level() != itright.Level() {
+			return false
+		}
+		if !lok || itleft.Level() >= c.levelsForCompare {
+			return true
+		}
+	}
+}
+
+// WithLevels returns a view of this collation that compares strings (and generates
+// weight strings) using only the first `n` levels of its weights: a view with 1 level
+// is accent and case insensitive, a view with 2 levels is accent sensitive but case
+// insensitive, and so on. The returned collation shares the weight tables with the
+// original one and has the same name and ID, so it should not be used where the
+// collation needs to be looked up again by either of them.
+// The weight tables for a collation only contain as many levels as the collation
+// compares, so `n` cannot be larger than the amount of levels in the original
+// collation: e.g. to switch between accent sensitive and insensitive comparisons,
+// use a view on utf8mb4_0900_as_ci instead of utf8mb4_0900_ai_ci.
+func (c *Collation_utf8mb4_uca_0900) WithLevels(n int) Collation {
+	c.init()
+	if n < 1 || n > c.uca.Levels() {
+		panic(fmt.Sprintf("WithLevels: collation %s supports between 1 and %d levels (got %d)", c.name, c.uca.Levels(), n))
+	}
+
+	view := &Collation_utf8mb4_uca_0900{
+		name:             c.name,
+		id:               c.id,
+		contractions:     c.contractions,
+		reorder:          c.reorder,
+		upperCaseFirst:   c.upperCaseFirst,
+		levelsForCompare: n,
+		uca:              c.uca,
+		asciiPrimary:     c.asciiPrimary,
+	}
+	// the view shares the already initialized tables, so it must never initialize its own
+	view.ucainit.Do(func() {})
+	return view
+}
+
+// NewComparator returns a Comparator that compares two strings using this collation
+// while their contents are being received in chunks.
+func (c *Collation_utf8mb4_uca_0900) NewComparator() *Comparator {
+	cmp := &Comparator{coll: c}
+	cmp.left.init(c.uca)
+	cmp.right.init(c.uca)
+	return cmp
+}
+
+// Comparator compares two strings incrementally. The contents of each string can be
+// pushed in chunks of any size, which don't need to be split on codepoint boundaries,
+// and the comparison advances as far as the data that is available allows, keeping
+// the state of the weight iterators for both strings between chunks.
+// All the pushed data is buffered inside the Comparator, because the secondary and
+// tertiary levels of a collation can only be compared once the primary level has been
+// fully processed, which cannot happen until the whole strings are known.
+type Comparator struct {
+	coll        *Collation_utf8mb4_uca_0900
+	left, right comparatorInput
+	level       int
+	result      int
+	done        bool
+}
+
+type comparatorInput struct {
+	data    []byte
+	it      uca.WeightIterator
+	weight  uint16
+	ok      bool
+	pending bool
+}
+
+func (in *comparatorInput) init(coll *uca.Collation900) {
+	in.it = coll.Iterator(nil)
+	in.it.Stream()
+}
+
+func (in *comparatorInput) push(chunk []byte) {
+	in.data = append(in.data, chunk...)
+	in.it.Feed(in.data)
+}
+
+// next loads the next weight from this input, unless the last weight that was loaded
+// has not been compared yet. Returns false if there's not enough input to continue.
+func (in *comparatorInput) next() bool {
+	if !in.pending {
+		in.weight, in.ok = in.it.Next()
+		if !in.ok && in.it.Starved() {
+			return false
+		}
+		in.pending = true
+	}
+	return true
+}
+
+// PushLeft appends a chunk of data to the left string of the comparison
+func (cmp *Comparator) PushLeft(chunk []byte) {
+	cmp.left.push(chunk)
+	cmp.advance()
+}
+
+// PushRight appends a chunk of data to the right string of the comparison
+func (cmp *Comparator) PushRight(chunk []byte) {
+	cmp.right.push(chunk)
+	cmp.advance()
+}
+
+// Result returns the result of comparing all the data that has been pushed so far,
+// with the same semantics as Collate, and whether this result is definitive, i.e.
+// whether it will stay the same regardless of any data pushed afterwards. Once a
+// result is definitive, there's no need to push any more data into the Comparator.
+// Results that are not definitive yet are computed by comparing all the buffered data,
+// so they're as expensive as calling Collate on both strings; call Finish instead once
+// both strings are complete.
+func (cmp *Comparator) Result() (int, bool) {
+	if cmp.done {
+		return cmp.result, true
+	}
+	return cmp.coll.Collate(cmp.left.data, cmp.right.data, false), false
+}
+
+// Finish marks the end of both strings and returns the definitive result of the
+// comparison. The last bytes of each string are held back while the strings are being
+// pushed, because they could be the start of a contraction that continues in the next
+// chunk, so strings shorter than that are only compared here. No more data can be
+// pushed into the Comparator after calling Finish.
+func (cmp *Comparator) Finish() int {
+	if !cmp.done {
+		cmp.left.it.EndStream()
+		cmp.right.it.EndStream()
+		cmp.advance()
+	}
+	return cmp.result
+}
+
+// advance compares the weights from both inputs for as long as the inputs have
+// enough data to do so, following the same algorithm as Collate.
+func (cmp *Comparator) advance() {
+	left, right := &cmp.left, &cmp.right
+
+	for !cmp.done {
+		if !left.next() || !right.next() {
+			return
+		}
+		left.pending, right.pending = false, false
+
+		l, r := left.weight, right.weight
+		lok, rok := left.ok, right.ok
+		llevel, rlevel := left.it.Level(), right.it.Level()
+
+		if l == r && lok && rok && llevel == cmp.level && rlevel == cmp.level {
+			continue
+		}
+
+		switch {
+		case llevel == rlevel:
+			if l == r && lok && rok {
+				cmp.level++
+				if cmp.level < cmp.coll.levelsForCompare {
+					continue
+				}
+			}
+			cmp.result = int(l) - int(r)
+		case llevel > cmp.level:
+			cmp.result = -1
+		default:
+			cmp.result = 1
+		}
+		cmp.done = true
+	}
+}
+
 func (c *Collation_utf8mb4_uca_0900) WeightStringLen(numBytes int) int {
+	return c.WeightStringLenLevels(numBytes, c.levelsForCompare)
+}
+
+// WeightStringLenLevels returns a size (in bytes) that would fit any weight string
+// returned by WeightStringLevels for a string of `numBytes` with the given `maxLevel`.
+func (c *Collation_utf8mb4_uca_0900) WeightStringLenLevels(numBytes, maxLevel int) int {
 	if numBytes%4 != 0 {
 		panic("WeightStringLen called with non-MOD4 length")
 	}
-	levels := int(c.levelsForCompare)
+	levels := minInt(maxLevel, c.levelsForCompare)
 	weights := (numBytes / 4) * uca.MaxCollationElementsPerCodepoint * levels
 	weights += levels - 1 // one NULL byte as a separator between levels
 	return weights * 2    // two bytes per weight
 }
 
+// Fold appends to `dst` the case-folded representation of `src`, using the full
+// Unicode case folding rules, and returns the extended slice. Unlike a weight
+// string, the result is valid UTF-8 that can be displayed or stored. Multi-codepoint
+// foldings are expanded (e.g. "ß" folds into "ss"), and the Turkic collations fold
+// the dotted and dotless I following their language-specific casing.
+//
+// Folding is lossless with respect to this collation only for accent-insensitive,
+// case-insensitive collations (_ai_ci): for these, Collate(Fold(s), s) is always 0.
+// For all other collations, the folded string may compare as different from the
+// original. The `upperCaseFirst` setting of a collation only changes the relative
+// order of upper and lowercase characters, so it has no effect on the folded output.
+func (c *Collation_utf8mb4_uca_0900) Fold(dst, src []byte) []byte {
+	if c.isTurkic() {
+		src = foldTurkic(src)
+	}
+	dst, _, _ = transform.Append(cases.Fold(), dst, src)
+	return dst
+}
+
+func (c *Collation_utf8mb4_uca_0900) isTurkic() bool {
+	return isTurkicCollation(c.name)
+}
+
+// foldTurkic applies the Turkic-specific foldings from Unicode's CaseFolding.txt,
+// which map 'I' to the dotless 'ı' and the dotted 'İ' to 'i'
+func foldTurkic(src []byte) []byte {
+	if bytes.IndexByte(src, 'I') < 0 && !bytes.ContainsRune(src, 'İ') {
+		return src
+	}
+
+	folded := make([]byte, 0, len(src)+8)
+	for len(src) > 0 {
+		r, width := utf8.DecodeRune(src)
+		switch r {
+		case 'I':
+			folded = append(folded, "ı"...)
+		case 'İ':
+			folded = append(folded, 'i')
+		default:
+			folded = append(folded, src[:width]...)
+		}
+		src = src[width:]
+	}
+	return folded
+}
+
 type Collation_utf8mb4_0900_bin struct{}
 
 func (c *Collation_utf8mb4_0900_bin) init() {}
@@ -200,11 +1076,18 @@ func (c *Collation_utf8mb4_0900_bin) IsBinary() bool {
 	return true
 }
 
+func (c *Collation_utf8mb4_0900_bin) Capabilities() CollationCaps {
+	return CollationCaps{CaseSensitive: true, AccentSensitive: true, Binary: true, Levels: 1}
+}
+
 func (c *Collation_utf8mb4_0900_bin) Collate(left, right []byte, isPrefix bool) int {
 	return collationBinary(left, right, isPrefix)
 }
 
 func (c *Collation_utf8mb4_0900_bin) WeightString(dst, src []byte, numCodepoints int) []byte {
+	if numCodepoints > 0 && numCodepoints != PadToMax {
+		src = charset.Truncate(c.Charset(), src, numCodepoints)
+	}
 	dst = append(dst, src...)
 	if numCodepoints == PadToMax {
 		for len(dst) < cap(dst) {
@@ -218,6 +1101,59 @@ func (c *Collation_utf8mb4_0900_bin) WeightStringLen(numBytes int) int {
 	return numBytes
 }
 
+func (c *Collation_utf8mb4_0900_bin) Hash(src []byte, numCodepoints int) uint64 {
+	if numCodepoints > 0 && numCodepoints != PadToMax {
+		src = charset.Truncate(c.Charset(), src, numCodepoints)
+	}
+	h := newWeightHasher()
+	h.write(src)
+	return h.sum64()
+}
+
+func (c *Collation_utf8mb4_0900_bin) Like(pattern, input []byte, escape rune) bool {
+	return likeMatch(c, pattern, input, escape)
+}
+
+func (c *Collation_utf8mb4_0900_bin) Min(a, b []byte) []byte {
+	return collationMin(c, a, b)
+}
+
+func (c *Collation_utf8mb4_0900_bin) Max(a, b []byte) []byte {
+	return collationMax(c, a, b)
+}
+
+func (c *Collation_utf8mb4_0900_bin) TrimLeft(src, cutset []byte) []byte {
+	return collationTrimLeft(c, src, cutset)
+}
+
+func (c *Collation_utf8mb4_0900_bin) TrimRight(src, cutset []byte) []byte {
+	return collationTrimRight(c, src, cutset)
+}
+
+func (c *Collation_utf8mb4_0900_bin) Trim(src, cutset []byte) []byte {
+	return collationTrim(c, src, cutset)
+}
+
+func (c *Collation_utf8mb4_0900_bin) Index(haystack, needle []byte) int {
+	return collationIndex(c, haystack, needle)
+}
+
+func (c *Collation_utf8mb4_0900_bin) Upper(dst, src []byte) []byte {
+	return collationUpper(c, dst, src)
+}
+
+func (c *Collation_utf8mb4_0900_bin) Lower(dst, src []byte) []byte {
+	return collationLower(c, dst, src)
+}
+
+func (c *Collation_utf8mb4_0900_bin) Equal(left, right []byte) bool {
+	return bytes.Equal(left, right)
+}
+
+func (c *Collation_utf8mb4_0900_bin) codepointWeight(r rune) (uint32, bool) {
+	return encodedWeight(c.Charset(), r)
+}
+
 type Collation_uca_legacy struct {
 	name string
 	id   ID
@@ -234,7 +1170,7 @@ type Collation_uca_legacy struct {
 
 func (c *Collation_uca_legacy) init() {
 	c.ucainit.Do(func() {
-		c.uca = uca.NewCollationLegacy(c.charset, c.weights, c.tailoring, c.contractions, c.maxCodepoint)
+		c.uca = uca.NewCollationLegacy(c.charset, loadWeightTable(c.weights), c.tailoring, c.contractions, c.maxCodepoint)
 		c.weights = nil
 		c.tailoring = nil
 		c.contractions = nil
@@ -245,6 +1181,10 @@ func (c *Collation_uca_legacy) UnicodeWeightsTable() (uca.WeightTable, uca.Table
 	return c.uca.Weights()
 }
 
+func (c *Collation_uca_legacy) PadWeight() uint16 {
+	return c.uca.WeightForSpace()
+}
+
 func (c *Collation_uca_legacy) ID() ID {
 	return c.id
 }
@@ -261,12 +1201,89 @@ func (c *Collation_uca_legacy) IsBinary() bool {
 	return false
 }
 
+func (c *Collation_uca_legacy) Capabilities() CollationCaps {
+	// the legacy UCA collations only compare the primary weights
+	return CollationCaps{PadSpace: true, Levels: 1}
+}
+
 func (c *Collation_uca_legacy) Collate(left, right []byte, isPrefix bool) int {
+	cmp, _ := c.CollateCtx(context.Background(), left, right, isPrefix)
+	return cmp
+}
+
+func (c *Collation_uca_legacy) CollateCtx(ctx context.Context, left, right []byte, isPrefix bool) (int, error) {
+	if isPrefix && len(right) == 0 {
+		return 0, nil
+	}
+	cmp, _, err := c.collate(ctx, left, right, isPrefix)
+	return cmp, err
+}
+
+func (c *Collation_uca_legacy) CollateDetailed(left, right []byte) (int, int) {
+	cmp, matched, _ := c.collate(context.Background(), left, right, false)
+	return cmp, matched
+}
+
+func (c *Collation_uca_legacy) Explain(left, right []byte) string {
+	// legacy collations only have primary weights
+	return explainCollation(c, 1, left, right)
+}
+
+func (c *Collation_uca_legacy) CollateWeightString(weights, right []byte, isPrefix bool) int {
+	if isPrefix && len(right) == 0 {
+		return 0
+	}
+
+	var (
+		l, r     uint16
+		lok, rok bool
+		it       = c.uca.Iterator(right)
+
+		weightForSpace = c.uca.WeightForSpace()
+	)
+
+	defer it.Done()
+
+	for {
+		l, lok = 0, len(weights) >= 2
+		if lok {
+			l = uint16(weights[0])<<8 | uint16(weights[1])
+			weights = weights[2:]
+		}
+		r, rok = it.Next()
+
+		if lok != rok && !isPrefix {
+			// the collation is PAD SPACE, so the string that ran out is padded with spaces
+			if !lok {
+				l, lok = weightForSpace, true
+			} else {
+				r, rok = weightForSpace, true
+			}
+		}
+
+		if l == r && lok && rok {
+			continue
+		}
+		if !rok && isPrefix {
+			return 0
+		}
+		return int(l) - int(r)
+	}
+}
+
+// collate compares `left` and `right` one weight at a time, and returns the result of the
+// comparison together with the amount of weights that were equal in both strings, including
+// the weights for the spaces that pad the shortest string
+func (c *Collation_uca_legacy) collate(ctx context.Context, left, right []byte, isPrefix bool) (int, int, error) {
+
 	var (
 		l, r     uint16
 		lok, rok bool
 		itleft   = c.uca.Iterator(left)
 		itright  = c.uca.Iterator(right)
+		weights  int
+
+		weightForSpace = c.uca.WeightForSpace()
 	)
 
 	defer itleft.Done()
@@ -276,17 +1293,105 @@ func (c *Collation_uca_legacy) Collate(left, right []byte, isPrefix bool) int {
 		l, lok = itleft.Next()
 		r, rok = itright.Next()
 
+		if lok != rok && !isPrefix {
+			// the collation is PAD SPACE, so the string that ran out is padded with spaces
+			if !lok {
+				l, lok = weightForSpace, true
+			} else {
+				r, rok = weightForSpace, true
+			}
+		}
+
 		if l == r && lok && rok {
+			if weights++; weights%collateCtxInterval == 0 {
+				if err := ctx.Err(); err != nil {
+					return 0, weights, err
+				}
+			}
 			continue
 		}
 		if !rok && isPrefix {
-			return 0
+			return 0, weights, nil
 		}
-		return int(l) - int(r)
+		return int(l) - int(r), weights, nil
+	}
+}
+
+func (c *Collation_uca_legacy) HasPrefix(haystack, needle []byte) bool {
+	var (
+		ithay    = c.uca.Iterator(haystack)
+		itneedle = c.uca.Iterator(needle)
+	)
+
+	defer ithay.Done()
+	defer itneedle.Done()
+
+	for {
+		r, rok := itneedle.Next()
+		if !rok {
+			break
+		}
+		l, lok := ithay.Next()
+		if !lok || l != r {
+			return false
+		}
+	}
+
+	_, complete := ithay.Position()
+	return complete
+}
+
+func (c *Collation_uca_legacy) HasSuffix(haystack, needle []byte) bool {
+	hayWeights := c.primaryWeights(haystack)
+	needleWeights := c.primaryWeights(needle)
+
+	start, limit, ok := suffixRange(hayWeights, needleWeights, len(haystack))
+	if !ok {
+		return false
+	}
+	return hasSuffixInRange(c.charset, haystack, start, limit, func(suffix []byte) bool {
+		return c.Collate(suffix, needle, false) == 0
+	})
+}
+
+func (c *Collation_uca_legacy) primaryWeights(src []byte) []weightOffset {
+	it := c.uca.Iterator(src)
+	defer it.Done()
+
+	var weights []weightOffset
+	for {
+		w, ok := it.Next()
+		if !ok {
+			return weights
+		}
+		end, complete := it.Position()
+		weights = append(weights, weightOffset{w, end, complete})
+	}
+}
+
+func (c *Collation_uca_legacy) Weights(src []byte) func() (uint16, int, bool) {
+	it := c.uca.Iterator(src)
+
+	return func() (uint16, int, bool) {
+		if it == nil {
+			return 0, 0, false
+		}
+		w, ok := it.Next()
+		if !ok {
+			it.Done()
+			it = nil
+			return 0, 0, false
+		}
+		// legacy collations only have primary weights
+		return w, 0, true
 	}
 }
 
 func (c *Collation_uca_legacy) WeightString(dst, src []byte, numCodepoints int) []byte {
+	if numCodepoints > 0 && numCodepoints != PadToMax {
+		src = charset.Truncate(c.charset, src, numCodepoints)
+	}
+
 	it := c.uca.Iterator(src)
 	defer it.Done()
 
@@ -322,6 +1427,111 @@ func (c *Collation_uca_legacy) WeightString(dst, src []byte, numCodepoints int)
 }
 
 func (c *Collation_uca_legacy) WeightStringLen(numBytes int) int {
-	// TODO: This is literally the worst case scenario. Improve on this.
-	return numBytes * 8
+	// The legacy weight tables never store more than MaxCollationElementsPerCodepoint
+	// weights for a single codepoint, and contractions always consume at least two
+	// codepoints, so the worst case is bounded by the amount of codepoints that can
+	// fit in `numBytes` for this charset.
+	mbmaxlen := c.charset.MaxBytesPerChar()
+	codepoints := (numBytes + mbmaxlen - 1) / mbmaxlen
+	return codepoints * uca.MaxCollationElementsPerCodepoint * 2 // two bytes per weight
+}
+
+// weightStringLenExact counts the weights that WeightString would append for `src`,
+// including the weights for the spaces that pad it to `numCodepoints`
+func (c *Collation_uca_legacy) weightStringLenExact(src []byte, numCodepoints int) int {
+	if numCodepoints > 0 {
+		src = charset.Truncate(c.charset, src, numCodepoints)
+	}
+
+	it := c.uca.Iterator(src)
+	defer it.Done()
+
+	var n int
+	for {
+		if _, ok := it.Next(); !ok {
+			break
+		}
+		n++
+	}
+	if numCodepoints > 0 && numCodepoints > it.Length() {
+		n += numCodepoints - it.Length()
+	}
+	return n * 2 // two bytes per weight
+}
+
+func (c *Collation_uca_legacy) Hash(src []byte, numCodepoints int) uint64 {
+	if numCodepoints > 0 && numCodepoints != PadToMax {
+		src = charset.Truncate(c.charset, src, numCodepoints)
+	}
+
+	it := c.uca.Iterator(src)
+	defer it.Done()
+
+	weightForSpace := c.uca.WeightForSpace()
+
+	// spaces are only hashed once something else follows them, or if the string is
+	// padded, because trailing spaces are not significant when comparing
+	var spaces int
+	h := newWeightHasher()
+	for {
+		w, ok := it.Next()
+		if !ok {
+			break
+		}
+		if w == weightForSpace {
+			spaces++
+			continue
+		}
+		for ; spaces > 0; spaces-- {
+			h.writeUint16(weightForSpace)
+		}
+		h.writeUint16(w)
+	}
+
+	if numCodepoints > 0 && numCodepoints != PadToMax {
+		for numCodepoints += spaces - it.Length(); numCodepoints > 0; numCodepoints-- {
+			h.writeUint16(weightForSpace)
+		}
+	}
+	return h.sum64()
+}
+
+func (c *Collation_uca_legacy) Like(pattern, input []byte, escape rune) bool {
+	return likeMatch(c, pattern, input, escape)
+}
+
+func (c *Collation_uca_legacy) Min(a, b []byte) []byte {
+	return collationMin(c, a, b)
+}
+
+func (c *Collation_uca_legacy) Max(a, b []byte) []byte {
+	return collationMax(c, a, b)
+}
+
+func (c *Collation_uca_legacy) TrimLeft(src, cutset []byte) []byte {
+	return collationTrimLeft(c, src, cutset)
+}
+
+func (c *Collation_uca_legacy) TrimRight(src, cutset []byte) []byte {
+	return collationTrimRight(c, src, cutset)
+}
+
+func (c *Collation_uca_legacy) Trim(src, cutset []byte) []byte {
+	return collationTrim(c, src, cutset)
+}
+
+func (c *Collation_uca_legacy) Index(haystack, needle []byte) int {
+	return collationIndex(c, haystack, needle)
+}
+
+func (c *Collation_uca_legacy) Upper(dst, src []byte) []byte {
+	return collationUpper(c, dst, src)
+}
+
+func (c *Collation_uca_legacy) Lower(dst, src []byte) []byte {
+	return collationLower(c, dst, src)
+}
+
+func (c *Collation_uca_legacy) Equal(left, right []byte) bool {
+	return c.Collate(left, right, false) == 0
 }
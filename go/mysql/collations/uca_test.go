@@ -18,9 +18,16 @@ package collations
 
 import (
 	"bytes"
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
 	"strings"
 	"testing"
 	"unicode/utf8"
+
+	"vitess.io/vitess/go/mysql/collations/internal/charset"
+	"vitess.io/vitess/go/mysql/collations/internal/uca"
 )
 
 func testcollation(t testing.TB, name string) Collation {
@@ -32,6 +39,17 @@ func testcollation(t testing.TB, name string) Collation {
 	return coll
 }
 
+// sign normalizes the result of a comparison to -1, 0 or 1
+func sign(cmp int) int {
+	switch {
+	case cmp < 0:
+		return -1
+	case cmp > 0:
+		return 1
+	}
+	return 0
+}
+
 func TestWeightsForSpace(t *testing.T) {
 	for _, coll := range All() {
 		var actual, expected uint16
@@ -79,6 +97,20 @@ func TestKanaSensitivity(t *testing.T) {
 	}
 }
 
+func TestKanaSensitivityWeightString(t *testing.T) {
+	collation := testcollation(t, "utf8mb4_ja_0900_as_cs_ks")
+
+	// the Kana-sensitive level is only present if the input contains a Kana, regardless
+	// of the inputs that were weighted before by the same (pooled) iterator
+	expected := collation.WeightString(nil, []byte("abc"), 0)
+	for i := 0; i < 4; i++ {
+		_ = collation.WeightString(nil, []byte("の東京ノ"), 0)
+		if got := collation.WeightString(nil, []byte("abc"), 0); !bytes.Equal(got, expected) {
+			t.Fatalf("WeightString(\"abc\") = %x after weighting a Kana, expected %x", got, expected)
+		}
+	}
+}
+
 func TestContractions(t *testing.T) {
 	var cases = []struct {
 		collation string
@@ -111,6 +143,71 @@ func TestContractions(t *testing.T) {
 	}
 }
 
+func TestContractionsAccessor(t *testing.T) {
+	hasContraction := func(contractions []uca.Contraction, path string) bool {
+		for _, c := range contractions {
+			if string(c.Path) == path {
+				return true
+			}
+		}
+		return false
+	}
+
+	// a collation that hasn't been initialized yet
+	uninitialized := &Collation_utf8mb4_uca_0900{
+		name:         "utf8mb4_cs_0900_ai_ci",
+		contractions: contractions_utf8mb4_cs_0900_ai_ci,
+	}
+	if !hasContraction(uninitialized.Contractions(), "ch") {
+		t.Errorf("missing contraction 'ch' in uninitialized collation")
+	}
+
+	czech := testcollation(t, "utf8mb4_cs_0900_ai_ci").(*Collation_utf8mb4_uca_0900)
+	if !hasContraction(czech.Contractions(), "ch") {
+		t.Errorf("missing contraction 'ch' in %s", czech.Name())
+	}
+	if !hasContraction(czech.WithLevels(1).(*Collation_utf8mb4_uca_0900).Contractions(), "ch") {
+		t.Errorf("missing contraction 'ch' in WithLevels view of %s", czech.Name())
+	}
+
+	root := testcollation(t, "utf8mb4_0900_ai_ci").(*Collation_utf8mb4_uca_0900)
+	if contractions := root.Contractions(); contractions == nil || len(contractions) != 0 {
+		t.Errorf("expected no contractions in %s, got %v", root.Name(), contractions)
+	}
+}
+
+func TestReordersAccessor(t *testing.T) {
+	// Russian moves the primary weights for Cyrillic before the ones for Latin
+	russian := testcollation(t, "utf8mb4_ru_0900_ai_ci").(*Collation_utf8mb4_uca_0900)
+	reorders := russian.Reorders()
+	if len(reorders) != len(reorder_utf8mb4_ru_0900_ai_ci) {
+		t.Fatalf("expected %d reorders in %s, got %v", len(reorder_utf8mb4_ru_0900_ai_ci), russian.Name(), reorders)
+	}
+	for i, r := range reorders {
+		if r != reorder_utf8mb4_ru_0900_ai_ci[i] {
+			t.Errorf("reorder %d in %s: expected %+v, got %+v", i, russian.Name(), reorder_utf8mb4_ru_0900_ai_ci[i], r)
+		}
+	}
+	if russian.Collate([]byte("б"), []byte("a"), false) >= 0 {
+		t.Errorf("expected Cyrillic to sort before Latin in %s", russian.Name())
+	}
+
+	// the accessor returns a copy
+	reorders[0].ToMin = 0
+	if russian.Reorders()[0].ToMin == 0 {
+		t.Errorf("modifying the result of Reorders() modified the collation")
+	}
+
+	if len(russian.WithLevels(1).(*Collation_utf8mb4_uca_0900).Reorders()) != len(reorder_utf8mb4_ru_0900_ai_ci) {
+		t.Errorf("missing reorders in WithLevels view of %s", russian.Name())
+	}
+
+	root := testcollation(t, "utf8mb4_0900_ai_ci").(*Collation_utf8mb4_uca_0900)
+	if reorders := root.Reorders(); reorders == nil || len(reorders) != 0 {
+		t.Errorf("expected no reorders in %s, got %v", root.Name(), reorders)
+	}
+}
+
 func TestReplacementCharacter(t *testing.T) {
 	var cases = []struct {
 		collation string
@@ -157,6 +254,119 @@ func TestIsPrefix(t *testing.T) {
 	}
 }
 
+func TestFold(t *testing.T) {
+	var cases = []struct {
+		collation string
+		input     string
+		expected  string
+	}{
+		{"utf8mb4_0900_ai_ci", "Hello World", "hello world"},
+		{"utf8mb4_0900_ai_ci", "Straße", "strasse"},
+		{"utf8mb4_0900_as_cs", "ÀÉÎÕÜ", "àéîõü"},
+		{"utf8mb4_0900_ai_ci", "İstanbul", "i̇stanbul"},
+		{"utf8mb4_tr_0900_ai_ci", "İstanbul", "istanbul"},
+		{"utf8mb4_tr_0900_ai_ci", "ISPARTA", "ısparta"},
+		{"utf8mb4_0900_ai_ci", "日本語", "日本語"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.collation, func(t *testing.T) {
+			coll := testcollation(t, tc.collation).(*Collation_utf8mb4_uca_0900)
+			folded := coll.Fold(nil, []byte(tc.input))
+			if string(folded) != tc.expected {
+				t.Errorf("Fold(%q) = %q (expected %q)", tc.input, folded, tc.expected)
+			}
+		})
+	}
+
+	for _, coll := range All() {
+		coll, ok := coll.(*Collation_utf8mb4_uca_0900)
+		if !ok || !strings.HasSuffix(coll.name, "_ai_ci") {
+			continue
+		}
+		input := []byte(ExampleStringLong + " Straße")
+		folded := coll.Fold(nil, input)
+		if coll.Collate(folded, input, false) != 0 {
+			t.Errorf("%s: Fold(%q) = %q is not lossless", coll.name, input, folded)
+		}
+	}
+}
+
+func TestHasPrefix(t *testing.T) {
+	var cases = []struct {
+		collation        string
+		haystack, needle string
+		expected         bool
+	}{
+		{"utf8mb4_0900_ai_ci", "café table", "cafe", true},
+		{"utf8mb4_0900_ai_ci", "café table", "CAFÉ T", true},
+		{"utf8mb4_0900_as_ci", "café table", "cafe", false},
+		{"utf8mb4_0900_as_ci", "café table", "CAFÉ", true},
+		{"utf8mb4_0900_as_cs", "café table", "CAFÉ", false},
+		{"utf8mb4_0900_as_cs", "café table", "café", true},
+		{"utf8mb4_0900_ai_ci", "café", "café table", false},
+		{"utf8mb4_0900_ai_ci", "café", "", true},
+		{"utf8mb4_cs_0900_ai_ci", "chleba", "c", false},
+		{"utf8mb4_cs_0900_ai_ci", "chleba", "ch", true},
+		{"utf8mb4_cs_0900_ai_ci", "cibule", "c", true},
+		{"utf8mb4_unicode_ci", "café table", "CAFE", true},
+		{"utf8mb4_unicode_ci", "café", "cafés", false},
+		{"utf8mb4_0900_ai_ci", "straße", "stras", false},
+		{"utf8mb4_0900_ai_ci", "straße", "strass", true},
+		{"utf8mb4_unicode_ci", "straße", "stras", false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.collation, func(t *testing.T) {
+			coll := testcollation(t, tc.collation).(CollationUCA)
+			if got := coll.HasPrefix([]byte(tc.haystack), []byte(tc.needle)); got != tc.expected {
+				t.Errorf("HasPrefix(%q, %q) = %v (expected %v)", tc.haystack, tc.needle, got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestHasSuffix(t *testing.T) {
+	var cases = []struct {
+		collation        string
+		haystack, needle string
+		expected         bool
+	}{
+		{"utf8mb4_0900_ai_ci", "table café", "cafe", true},
+		{"utf8mb4_0900_ai_ci", "table café", "E CAFÉ", true},
+		{"utf8mb4_0900_as_ci", "table café", "cafe", false},
+		{"utf8mb4_0900_as_ci", "table café", "CAFÉ", true},
+		{"utf8mb4_0900_as_cs", "table café", "CAFÉ", false},
+		{"utf8mb4_0900_as_cs", "table café", "café", true},
+		{"utf8mb4_0900_as_cs", "table cafe\u0301", "café", true},
+		{"utf8mb4_0900_as_cs", "table cafe\u0301", "e", false},
+		{"utf8mb4_0900_ai_ci", "café", "table café", false},
+		{"utf8mb4_0900_ai_ci", "café", "", true},
+		{"utf8mb4_0900_ai_ci", "hello world", "WORLD", true},
+		{"utf8mb4_0900_ai_ci", "hello world", "worl", false},
+		{"utf8mb4_0900_ai_ci", "hello\x00 world", "o\x00 world", true},
+		{"utf8mb4_0900_ai_ci", "日本語 world", "語 WORLD", true},
+		{"utf8mb4_cs_0900_ai_ci", "bach", "h", false},
+		{"utf8mb4_cs_0900_ai_ci", "bach", "ch", true},
+		{"utf8mb4_cs_0900_ai_ci", "bach", "ach", true},
+		{"utf8mb4_unicode_ci", "table café", "CAFE", true},
+		{"utf8mb4_unicode_ci", "café", "scafé", false},
+		{"utf8mb4_0900_ai_ci", "straße", "se", false},
+		{"utf8mb4_0900_ai_ci", "straße", "sse", true},
+		{"utf8mb4_unicode_ci", "straße", "se", false},
+		{"utf8mb4_unicode_ci", "straße", "SSE", true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.collation, func(t *testing.T) {
+			coll := testcollation(t, tc.collation).(CollationUCA)
+			if got := coll.HasSuffix([]byte(tc.haystack), []byte(tc.needle)); got != tc.expected {
+				t.Errorf("HasSuffix(%q, %q) = %v (expected %v)", tc.haystack, tc.needle, got, tc.expected)
+			}
+		})
+	}
+}
+
 func DebugUcaLegacyWeightString(t *testing.T, collname string, input, expected []byte) {
 	coll := testcollation(t, collname).(*Collation_uca_legacy)
 	iter := coll.uca.Iterator(input)
@@ -743,6 +953,623 @@ func BenchmarkAllUCAWeightStrings(b *testing.B) {
 	}
 }
 
+func TestUCACollateAllocs(t *testing.T) {
+	var collations = []string{"utf8mb4_0900_ai_ci", "utf8mb4_0900_as_cs", "utf8mb4_cs_0900_as_cs", "utf8mb4_ja_0900_as_cs_ks"}
+	left := []byte("Chleba カード")
+	right := []byte("chleba かーど")
+
+	for _, collName := range collations {
+		coll := testcollation(t, collName)
+		coll.Collate(left, right, false)
+
+		allocs := testing.AllocsPerRun(100, func() {
+			coll.Collate(left, right, false)
+		})
+		if allocs > 0 {
+			t.Errorf("%s: Collate allocated %.1f times per run", collName, allocs)
+		}
+	}
+}
+
+func BenchmarkUCASort(b *testing.B) {
+	const alphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZáéíóúñçß "
+	var runes = []rune(alphabet)
+	var rng = rand.New(rand.NewSource(0xDEADBEEF))
+
+	inputs := make([][]byte, 100000)
+	for i := range inputs {
+		word := make([]rune, 4+rng.Intn(12))
+		for j := range word {
+			word[j] = runes[rng.Intn(len(runes))]
+		}
+		inputs[i] = []byte(string(word))
+	}
+
+	for _, collName := range []string{"utf8mb4_0900_ai_ci", "utf8mb4_0900_as_cs", "utf8mb4_es_0900_ai_ci"} {
+		b.Run(collName, func(b *testing.B) {
+			collation := testcollation(b, collName)
+			sorted := make([][]byte, len(inputs))
+
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				copy(sorted, inputs)
+				sort.Slice(sorted, func(i, j int) bool {
+					return collation.Collate(sorted[i], sorted[j], false) < 0
+				})
+			}
+		})
+	}
+}
+
+func TestDescribeWeightString(t *testing.T) {
+	var cases = []struct {
+		collation string
+		input     string
+		expected  string
+	}{
+		{"utf8mb4_0900_as_cs", "aB", "level 1: [1C47 1C60]\nlevel 2: [0020 0020]\nlevel 3: [0002 0008]"},
+		{"utf8mb4_0900_as_ci", "aB", "level 1: [1C47 1C60]\nlevel 2: [0020 0020]"},
+		{"utf8mb4_0900_ai_ci", "aB", "level 1: [1C47 1C60]"},
+		{"utf8mb4_0900_as_cs", "", "level 1: []\nlevel 2: []\nlevel 3: []"},
+		{"utf8mb4_unicode_ci", "aB", "level 1: [0E33 0E4A]"},
+	}
+
+	for _, tc := range cases {
+		coll := testcollation(t, tc.collation)
+		ws := coll.WeightString(nil, []byte(tc.input), 0)
+		if got := DescribeWeightString(ws); got != tc.expected {
+			t.Errorf("%s: DescribeWeightString(%q) =\n%s\nexpected:\n%s", tc.collation, tc.input, got, tc.expected)
+		}
+	}
+
+	if got := DescribeWeightString([]byte{0x1C, 0x47, 0x00, 0x00, 0x00}); got != "level 1: [1C47]\nlevel 2: []\ntrailing: [00]" {
+		t.Errorf("unexpected description for odd-length weight string:\n%s", got)
+	}
+}
+
+func TestTableLayout(t *testing.T) {
+	var cases = []struct {
+		collation    string
+		maxCodepoint rune
+		pages        int
+	}{
+		{"utf8mb4_0900_ai_ci", 0x10FFFF, 4352},
+		{"utf8mb4_ja_0900_as_cs", 0x10FFFF, 4352},
+		{"utf8mb4_unicode_ci", 0xFFFF, 256},
+		{"utf8mb4_unicode_520_ci", 0x10FFFF, 4352},
+		{"ucs2_unicode_ci", 0xFFFF, 256},
+	}
+
+	for _, tc := range cases {
+		coll := testcollation(t, tc.collation).(CollationUCA)
+		table, layout := coll.UnicodeWeightsTable()
+
+		if layout.MaxCodepoint() != tc.maxCodepoint {
+			t.Errorf("%s: MaxCodepoint() = U+%04X (expected U+%04X)", tc.collation, layout.MaxCodepoint(), tc.maxCodepoint)
+		}
+		if layout.PageCount() != tc.pages {
+			t.Errorf("%s: PageCount() = %d (expected %d)", tc.collation, layout.PageCount(), tc.pages)
+		}
+		if len(table) < layout.PageCount() {
+			t.Errorf("%s: weight table has %d pages, layout expects %d", tc.collation, len(table), layout.PageCount())
+		}
+		if !strings.Contains(layout.DebugString(), fmt.Sprintf("U+%04X", tc.maxCodepoint)) {
+			t.Errorf("%s: DebugString() = %q", tc.collation, layout.DebugString())
+		}
+	}
+}
+
+func TestWeightStringStrict(t *testing.T) {
+	var cases = []struct {
+		input  string
+		offset int
+	}{
+		{"", -1},
+		{ExampleString, -1},
+		{"\uFFFD", -1},
+		{"abc\xff", 3},
+		{"abc\xe6\x97", 3},
+		{"\xed\xa0\x80", 0},
+		{"日本\xf0\x9f\x98語", 6},
+	}
+
+	coll := testcollation(t, "utf8mb4_0900_as_cs").(*Collation_utf8mb4_uca_0900)
+	for _, tc := range cases {
+		input := []byte(tc.input)
+		lenient := coll.WeightString(nil, input, 0)
+
+		strict, err := coll.WeightStringStrict(nil, input, 0)
+		if tc.offset < 0 {
+			if err != nil {
+				t.Errorf("WeightStringStrict(%q) failed: %v", tc.input, err)
+			} else if !bytes.Equal(strict, lenient) {
+				t.Errorf("WeightStringStrict(%q) = %v (expected %v)", tc.input, strict, lenient)
+			}
+			continue
+		}
+
+		if err == nil {
+			t.Errorf("WeightStringStrict(%q) should fail", tc.input)
+		} else if !strings.Contains(err.Error(), fmt.Sprintf("at offset %d", tc.offset)) {
+			t.Errorf("WeightStringStrict(%q): unexpected error %v", tc.input, err)
+		}
+
+		// the lenient version ignores everything after the first invalid byte
+		prefix := coll.WeightString(nil, input[:tc.offset], 0)
+		if !bytes.Equal(lenient, prefix) {
+			t.Errorf("WeightString(%q) = %v (expected the weights for %q: %v)", tc.input, lenient, input[:tc.offset], prefix)
+		}
+	}
+}
+
+func TestWeightStringLevels(t *testing.T) {
+	var inputs = []string{"", "a", "Résumé", ExampleString, JapaneseString, "Chleba"}
+	var views = []struct {
+		collation string
+		level     int
+		expected  string
+	}{
+		{"utf8mb4_0900_as_cs", 1, "utf8mb4_0900_ai_ci"},
+		{"utf8mb4_0900_as_cs", 2, "utf8mb4_0900_as_ci"},
+		{"utf8mb4_0900_as_cs", 3, "utf8mb4_0900_as_cs"},
+		{"utf8mb4_0900_as_cs", 4, "utf8mb4_0900_as_cs"},
+		{"utf8mb4_0900_as_ci", 1, "utf8mb4_0900_ai_ci"},
+		{"utf8mb4_cs_0900_as_cs", 1, "utf8mb4_cs_0900_ai_ci"},
+	}
+
+	for _, view := range views {
+		coll := testcollation(t, view.collation).(*Collation_utf8mb4_uca_0900)
+		expected := testcollation(t, view.expected)
+
+		for _, input := range inputs {
+			got := coll.WeightStringLevels(nil, []byte(input), 0, view.level)
+			want := expected.WeightString(nil, []byte(input), 0)
+			if !bytes.Equal(got, want) {
+				t.Errorf("%s: WeightStringLevels(%q, %d) = %v\nexpected (%s): %v",
+					view.collation, input, view.level, got, view.expected, want)
+			}
+
+			maxLen := coll.WeightStringLenLevels(len(input)+(4-len(input)%4)%4, view.level)
+			if len(got) > maxLen {
+				t.Errorf("%s: WeightStringLevels(%q, %d) is %d bytes long, larger than WeightStringLenLevels (%d)",
+					view.collation, input, view.level, len(got), maxLen)
+			}
+		}
+	}
+
+	coll := testcollation(t, "utf8mb4_0900_as_cs").(*Collation_utf8mb4_uca_0900)
+	if coll.WeightStringLenLevels(4, 3) != coll.WeightStringLen(4) {
+		t.Errorf("WeightStringLenLevels with all the levels should match WeightStringLen")
+	}
+	if l1, l3 := coll.WeightStringLenLevels(64, 1), coll.WeightStringLenLevels(64, 3); l1*3+4 != l3 {
+		t.Errorf("WeightStringLenLevels(64, 1) = %d, WeightStringLenLevels(64, 3) = %d", l1, l3)
+	}
+}
+
+func TestCollateASCII(t *testing.T) {
+	const alphabet = "aAbBcC zZ01-_.\x00\x01\t"
+	var rng = rand.New(rand.NewSource(0xA5C11))
+
+	var nonASCII = []string{"é", "ß", "\u0301", "😀", "\xff"}
+
+	randomASCII := func() []byte {
+		str := make([]byte, rng.Intn(8))
+		for i := range str {
+			str[i] = alphabet[rng.Intn(len(alphabet))]
+		}
+		// the fast path must give up once it reaches a non-ASCII character
+		if rng.Intn(4) == 0 {
+			pos := rng.Intn(len(str) + 1)
+			str = append(str[:pos:pos], append([]byte(nonASCII[rng.Intn(len(nonASCII))]), str[pos:]...)...)
+		}
+		return str
+	}
+
+	for _, collName := range []string{"utf8mb4_0900_ai_ci", "utf8mb4_0900_as_ci", "utf8mb4_0900_as_cs"} {
+		coll := testcollation(t, collName).(*Collation_utf8mb4_uca_0900)
+		if coll.asciiPrimary == nil {
+			t.Fatalf("%s should have an ASCII fast path", collName)
+		}
+		slow := coll.WithLevels(coll.levelsForCompare).(*Collation_utf8mb4_uca_0900)
+		slow.asciiPrimary = nil
+
+		for i := 0; i < 10000; i++ {
+			left, right := randomASCII(), randomASCII()
+			for _, prefix := range []bool{false, true} {
+				expected := sign(slow.Collate(left, right, prefix))
+				if got := sign(coll.Collate(left, right, prefix)); got != expected {
+					t.Errorf("%s: Collate(%q, %q, %v) = %d (expected %d)", collName, left, right, prefix, got, expected)
+				}
+			}
+		}
+	}
+
+	for _, collName := range []string{"utf8mb4_es_0900_ai_ci", "utf8mb4_cs_0900_as_cs", "utf8mb4_ja_0900_as_cs"} {
+		if testcollation(t, collName).(*Collation_utf8mb4_uca_0900).asciiPrimary != nil {
+			t.Errorf("%s has tailorings and should not have an ASCII fast path", collName)
+		}
+	}
+}
+
+func BenchmarkUCACollate(b *testing.B) {
+	var inputs = []struct {
+		name        string
+		left, right string
+	}{
+		{"ascii", "The quick brown fox jumps over the lazy dog", "The quick brown fox jumps over the lazy cat"},
+		{"ascii-equal", "The quick brown fox jumps over the lazy dog", "the quick brown fox jumps over the lazy dog"},
+		{"identical", "The quick brown fox jumps over the lazy dog", "The quick brown fox jumps over the lazy dog"},
+		{"ascii-prefix", "The quick brown fox jumps over the lazy dog, Příliš žluťoučký kůň", "The quick brown fox jumps over the lazy cat, Příliš žluťoučký kůň"},
+		{"non-ascii", "Příliš žluťoučký kůň úpěl ďábelské ódy", "Příliš žluťoučký kůň úpěl ďábelské kódy"},
+	}
+
+	for _, collName := range []string{"utf8mb4_0900_ai_ci", "utf8mb4_0900_as_cs"} {
+		coll := testcollation(b, collName)
+		for _, input := range inputs {
+			left, right := []byte(input.left), []byte(input.right)
+			if input.name == "identical" {
+				right = append([]byte(nil), left...)
+			}
+
+			b.Run(collName+"/"+input.name, func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					_ = coll.Collate(left, right, false)
+				}
+			})
+		}
+	}
+}
+
+func TestWeightStringTo(t *testing.T) {
+	var inputs = []string{"", ExampleString, ExampleStringLong, JapaneseString, WhitespaceString, strings.Repeat(HungarianString, 32)}
+	var collations = []string{"utf8mb4_0900_ai_ci", "utf8mb4_0900_as_cs", "utf8mb4_hu_0900_ai_ci", "utf8mb4_ja_0900_as_cs_ks"}
+
+	for _, collName := range collations {
+		t.Run(collName, func(t *testing.T) {
+			coll := testcollation(t, collName).(*Collation_utf8mb4_uca_0900)
+
+			for _, input := range inputs {
+				for _, numCodepoints := range []int{0, 2, utf8.RuneCountInString(input) + 1} {
+					var buf bytes.Buffer
+					n, err := coll.WeightStringTo(&buf, []byte(input), numCodepoints)
+					if err != nil {
+						t.Fatal(err)
+					}
+					expected := coll.WeightString(nil, []byte(input), numCodepoints)
+					if n != len(expected) || !bytes.Equal(buf.Bytes(), expected) {
+						t.Errorf("WeightStringTo(%q, %d) = %v (%d bytes)\nexpected: %v", input, numCodepoints, buf.Bytes(), n, expected)
+					}
+				}
+			}
+
+			for _, input := range inputs {
+				var buf bytes.Buffer
+				n, err := coll.WeightStringTo(&buf, []byte(input), PadToMax)
+				if err != nil {
+					t.Fatal(err)
+				}
+				expected := coll.WeightString(make([]byte, 0, coll.WeightStringLen((len(input)+3)&^3)), []byte(input), PadToMax)
+				if n != len(expected) || !bytes.Equal(buf.Bytes(), expected) {
+					t.Errorf("WeightStringTo(%q, PadToMax) = %v (%d bytes)\nexpected: %v", input, buf.Bytes(), n, expected)
+				}
+			}
+		})
+	}
+}
+
+func TestComparator(t *testing.T) {
+	var collations = []string{
+		"utf8mb4_0900_ai_ci",
+		"utf8mb4_0900_as_cs",
+		"utf8mb4_cs_0900_as_cs",
+		"utf8mb4_es_trad_0900_ai_ci",
+		"utf8mb4_ja_0900_as_cs_ks",
+		"utf8mb4_zh_0900_as_cs",
+	}
+	var inputs = []string{
+		"", "c", "ch", "cz", "h", "Chleba", "chleba", "hlava", "café", "cafe", "CAFÉ",
+		"Straße", "strasse", "ｶｰﾄﾞ", "カード", "かーど", ExampleString, JapaneseString, ChineseString2,
+	}
+	var rng = rand.New(rand.NewSource(0xC0FFEE))
+
+	chunks := func(input string) (chunks [][]byte) {
+		for len(input) > 0 {
+			n := 1 + rng.Intn(minInt(len(input), 5))
+			chunks = append(chunks, []byte(input[:n]))
+			input = input[n:]
+		}
+		return
+	}
+	for _, collname := range collations {
+		coll := testcollation(t, collname).(*Collation_utf8mb4_uca_0900)
+
+		for _, left := range inputs {
+			for _, right := range inputs {
+				expected := sign(coll.Collate([]byte(left), []byte(right), false))
+
+				cmp := coll.NewComparator()
+				lchunks, rchunks := chunks(left), chunks(right)
+				for len(lchunks) > 0 || len(rchunks) > 0 {
+					if len(lchunks) > 0 && (len(rchunks) == 0 || rng.Intn(2) == 0) {
+						cmp.PushLeft(lchunks[0])
+						lchunks = lchunks[1:]
+					} else {
+						cmp.PushRight(rchunks[0])
+						rchunks = rchunks[1:]
+					}
+					if result, definitive := cmp.Result(); definitive && sign(result) != expected {
+						t.Fatalf("%s: definitive result for %q vs %q is %d, expected %d", collname, left, right, result, expected)
+					}
+				}
+				if result, _ := cmp.Result(); sign(result) != expected {
+					t.Errorf("%s: result for %q vs %q is %d, expected %d", collname, left, right, result, expected)
+				}
+				if result := cmp.Finish(); sign(result) != expected {
+					t.Errorf("%s: final result for %q vs %q is %d, expected %d", collname, left, right, result, expected)
+				}
+				if result, definitive := cmp.Result(); !definitive || sign(result) != expected {
+					t.Errorf("%s: result for %q vs %q after Finish is %d (definitive=%v), expected %d", collname, left, right, result, definitive, expected)
+				}
+			}
+		}
+	}
+}
+
+func TestComparatorStopsEarly(t *testing.T) {
+	coll := testcollation(t, "utf8mb4_0900_ai_ci").(*Collation_utf8mb4_uca_0900)
+	cmp := coll.NewComparator()
+
+	cmp.PushLeft([]byte("apple pie, "))
+	cmp.PushRight([]byte("banana split, "))
+
+	result, definitive := cmp.Result()
+	if !definitive || result >= 0 {
+		t.Fatalf("expected a definitive negative result, got %d (definitive=%v)", result, definitive)
+	}
+
+	cmp = coll.NewComparator()
+	cmp.PushLeft([]byte("apple"))
+	cmp.PushRight([]byte("APPLE"))
+	if result, definitive := cmp.Result(); definitive || result != 0 {
+		t.Fatalf("expected a provisional equal result, got %d (definitive=%v)", result, definitive)
+	}
+}
+
+func TestComparatorFinish(t *testing.T) {
+	coll := testcollation(t, "utf8mb4_0900_as_cs").(*Collation_utf8mb4_uca_0900)
+
+	var cases = []struct {
+		left, right string
+		expected    int
+	}{
+		{"", "", 0},
+		{"a", "", 1},
+		{"", "a", -1},
+		{"a", "b", -1},
+		{"ab", "ab", 0},
+		{"ab", "aB", -1},
+		{"é", "e", 1},
+		{"ｶ", "カ", 1},
+	}
+	for _, tc := range cases {
+		// the inputs are shorter than the lookahead of the iterators, so no result can
+		// be definitive until the end of both strings is known
+		cmp := coll.NewComparator()
+		cmp.PushLeft([]byte(tc.left))
+		cmp.PushRight([]byte(tc.right))
+		if _, definitive := cmp.Result(); definitive {
+			t.Errorf("%q vs %q: got a definitive result before Finish", tc.left, tc.right)
+		}
+
+		if result := cmp.Finish(); sign(result) != tc.expected {
+			t.Errorf("%q vs %q: Finish() = %d, expected %d", tc.left, tc.right, result, tc.expected)
+		}
+		if want := sign(coll.Collate([]byte(tc.left), []byte(tc.right), false)); want != tc.expected {
+			t.Errorf("%q vs %q: Collate() = %d, expected %d", tc.left, tc.right, want, tc.expected)
+		}
+	}
+}
+
+func TestWithLevels(t *testing.T) {
+	ascs := testcollation(t, "utf8mb4_0900_as_cs").(*Collation_utf8mb4_uca_0900)
+
+	var cases = []struct {
+		levels   int
+		expected string
+	}{
+		{1, "utf8mb4_0900_ai_ci"},
+		{2, "utf8mb4_0900_as_ci"},
+		{3, "utf8mb4_0900_as_cs"},
+	}
+
+	var inputs = append([]string{"café", "CAFE", "cafe", "Straße"}, AllTestStrings...)
+
+	for _, tc := range cases {
+		view := ascs.WithLevels(tc.levels)
+		expected := testcollation(t, tc.expected)
+
+		if view.(*Collation_utf8mb4_uca_0900).uca != ascs.uca {
+			t.Errorf("WithLevels(%d) does not share the weight tables with the original collation", tc.levels)
+		}
+
+		for _, left := range inputs {
+			want := expected.WeightString(nil, []byte(left), 0)
+			got := view.WeightString(nil, []byte(left), 0)
+			if !bytes.Equal(want, got) {
+				t.Errorf("WithLevels(%d).WeightString(%q) = %v, %s returned %v", tc.levels, left, got, tc.expected, want)
+			}
+
+			var buf bytes.Buffer
+			if _, err := view.(*Collation_utf8mb4_uca_0900).WeightStringTo(&buf, []byte(left), 0); err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(want, buf.Bytes()) {
+				t.Errorf("WithLevels(%d).WeightStringTo(%q) = %v, %s returned %v", tc.levels, left, buf.Bytes(), tc.expected, want)
+			}
+
+			for _, right := range inputs {
+				want := expected.Collate([]byte(left), []byte(right), false)
+				got := view.Collate([]byte(left), []byte(right), false)
+				if (want == 0) != (got == 0) || (want < 0) != (got < 0) {
+					t.Errorf("WithLevels(%d).Collate(%q, %q) = %d, %s returned %d", tc.levels, left, right, got, tc.expected, want)
+				}
+			}
+		}
+	}
+
+	for _, n := range []int{0, 4} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("WithLevels(%d) should panic", n)
+				}
+			}()
+			ascs.WithLevels(n)
+		}()
+	}
+}
+
+func TestLegacyWeightStringLen(t *testing.T) {
+	// these codepoints expand to the maximum amount of weights in the legacy tables
+	var inputs = append([]string{"\uFDFA\uFDFB\u3300\u33FF"}, AllTestStrings...)
+
+	for _, coll := range All() {
+		legacy, ok := coll.(*Collation_uca_legacy)
+		if !ok {
+			continue
+		}
+		mbmaxlen := legacy.Charset().MaxBytesPerChar()
+
+		for _, input := range inputs {
+			converted, err := charset.ConvertFromUTF8(nil, legacy.Charset(), []byte(input))
+			if err != nil {
+				continue
+			}
+			columnLen := utf8.RuneCountInString(input) * mbmaxlen
+			predicted := legacy.WeightStringLen(columnLen)
+			actual := len(legacy.WeightString(nil, converted, 0))
+
+			if actual > predicted {
+				t.Errorf("%s: WeightStringLen(%d) = %d, but WeightString(%q) is %d bytes long",
+					legacy.Name(), columnLen, predicted, input, actual)
+			}
+			if predicted > columnLen*8 {
+				t.Errorf("%s: WeightStringLen(%d) = %d is larger than the naive bound", legacy.Name(), columnLen, predicted)
+			}
+		}
+	}
+}
+
+func TestPadWeight(t *testing.T) {
+	var cases = []struct {
+		collation string
+		weight    uint16
+	}{
+		{"utf8mb4_unicode_ci", 0x0209},
+		{"utf8mb4_unicode_520_ci", 0x020A},
+		{"ucs2_unicode_ci", 0x0209},
+		{"utf8mb4_0900_ai_ci", 0},
+		{"utf8mb4_ja_0900_as_cs", 0},
+	}
+
+	for _, tc := range cases {
+		coll := testcollation(t, tc.collation).(CollationUCA)
+		if got := coll.PadWeight(); got != tc.weight {
+			t.Errorf("%s: PadWeight() = 0x%04x (expected 0x%04x)", tc.collation, got, tc.weight)
+		}
+	}
+
+	// padding a weight string by hand must be equivalent to letting WeightString do it
+	for _, coll := range All() {
+		ucaColl, ok := coll.(CollationUCA)
+		if !ok {
+			continue
+		}
+		pad := ucaColl.PadWeight()
+		unpadded := coll.WeightString(nil, nil, 0)
+		padded := coll.WeightString(make([]byte, 0, len(unpadded)+4), nil, PadToMax)
+		expected := append(unpadded, byte(pad>>8), byte(pad), byte(pad>>8), byte(pad))
+		if !bytes.Equal(padded, expected) {
+			t.Errorf("%s: WeightString with PadToMax = %x, padding with PadWeight = %x", coll.Name(), padded, expected)
+		}
+	}
+}
+
+func TestWeights(t *testing.T) {
+	var inputs = []string{"", "abc", "Ch", "Straße", "résumé RÉSUMÉ", "ｶｰﾄﾞ カード かーど", ExampleString}
+
+	for _, coll := range All() {
+		ucaColl, ok := coll.(CollationUCA)
+		if !ok {
+			continue
+		}
+		for _, input := range inputs {
+			src, err := charset.ConvertFromUTF8(nil, coll.Charset(), []byte(input))
+			if err != nil {
+				continue
+			}
+
+			// rebuilding the weight string from the stream of weights, with a NULL
+			// weight between every level, must yield the original weight string
+			var ws []byte
+			var level int
+			next := ucaColl.Weights(src)
+			for {
+				w, l, ok := next()
+				if !ok {
+					break
+				}
+				if l < level || l >= coll.Capabilities().Levels {
+					t.Fatalf("%s: Weights(%q) yielded a weight for level %d after level %d", coll.Name(), input, l, level)
+				}
+				for ; level < l; level++ {
+					ws = append(ws, 0x00, 0x00)
+				}
+				ws = append(ws, byte(w>>8), byte(w))
+			}
+			if _, _, ok := next(); ok {
+				t.Errorf("%s: Weights(%q) yielded a weight after being exhausted", coll.Name(), input)
+			}
+
+			// the separators for trailing levels without weights cannot be rebuilt
+			expected := coll.WeightString(nil, src, 0)
+			for bytes.HasSuffix(expected, []byte{0x00, 0x00}) {
+				expected = expected[:len(expected)-2]
+			}
+			if !bytes.Equal(ws, expected) {
+				t.Errorf("%s: Weights(%q) = %x (expected %x)", coll.Name(), input, ws, expected)
+			}
+		}
+	}
+}
+
+func TestCollateCtx(t *testing.T) {
+	large := []byte(strings.Repeat("El veloz murciélago hindú comía feliz cardillo y kiwi. ", 10000))
+	larger := append(append([]byte(nil), large...), 'x')
+
+	for _, name := range []string{"utf8mb4_0900_ai_ci", "utf8mb4_0900_as_cs", "utf8mb4_unicode_ci", "utf8mb4_unicode_520_ci"} {
+		coll := testcollation(t, name).(CollationUCA)
+
+		cmp, err := coll.CollateCtx(context.Background(), large, larger, false)
+		if err != nil || cmp != coll.Collate(large, larger, false) {
+			t.Errorf("%s: CollateCtx = %d, %v (expected %d)", name, cmp, err, coll.Collate(large, larger, false))
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if _, err := coll.CollateCtx(ctx, large, larger, false); err != context.Canceled {
+			t.Errorf("%s: CollateCtx with a cancelled context returned %v", name, err)
+		}
+		// short inputs are compared before the context is checked
+		if cmp, err := coll.CollateCtx(ctx, []byte("a"), []byte("b"), false); err != nil || cmp >= 0 {
+			t.Errorf("%s: CollateCtx on short inputs = %d, %v", name, cmp, err)
+		}
+	}
+}
+
 func TestCompareWithWeightString(t *testing.T) {
 	var cases = []struct {
 		left, right string
@@ -801,3 +1628,86 @@ func TestFastIterators(t *testing.T) {
 		})
 	}
 }
+
+func TestCollateDetailed(t *testing.T) {
+	var cases = []struct {
+		collation   string
+		left, right string
+		cmp         int
+		matched     int
+	}{
+		{"utf8mb4_0900_ai_ci", "", "", 0, 0},
+		{"utf8mb4_0900_ai_ci", "abc", "abc", 0, 3},
+		{"utf8mb4_0900_ai_ci", "abc", "ABC", 0, 3},
+		{"utf8mb4_0900_ai_ci", "abc", "abd", -1, 2},
+		{"utf8mb4_0900_ai_ci", "abd", "abc", 1, 2},
+		{"utf8mb4_0900_ai_ci", "ab", "abc", -1, 2},
+		{"utf8mb4_0900_ai_ci", "a", "b", -1, 0},
+		// ß expands to the primary weights of "ss"
+		{"utf8mb4_0900_ai_ci", "ß", "ss", 0, 2},
+		{"utf8mb4_0900_ai_ci", "ßa", "ssb", -1, 2},
+		// 3 primary, 3 secondary and 3 tertiary weights
+		{"utf8mb4_0900_as_cs", "abc", "abc", 0, 9},
+		// the case is only compared in the tertiary weights
+		{"utf8mb4_0900_as_cs", "abc", "abC", -1, 8},
+		// the cedilla has no primary weight, and an extra secondary weight after the one for "c"
+		{"utf8mb4_0900_as_cs", "abc", "abç", -1, 6},
+		// the shortest string is padded with spaces
+		{"utf8mb4_unicode_ci", "abc", "abc  ", 0, 5},
+		{"utf8mb4_unicode_ci", "abc", "abd", -1, 2},
+		{"utf8mb4_unicode_ci", "abc", "ABC", 0, 3},
+	}
+
+	for _, tc := range cases {
+		coll := testcollation(t, tc.collation).(CollationUCA)
+		cmp, matched := coll.CollateDetailed([]byte(tc.left), []byte(tc.right))
+		if sign(cmp) != tc.cmp || matched != tc.matched {
+			t.Errorf("%s: CollateDetailed(%q, %q) = (%d, %d) (expected (%d, %d))",
+				tc.collation, tc.left, tc.right, sign(cmp), matched, tc.cmp, tc.matched)
+		}
+		if expected := sign(coll.Collate([]byte(tc.left), []byte(tc.right), false)); sign(cmp) != expected {
+			t.Errorf("%s: CollateDetailed(%q, %q) = %d, but Collate returned %d", tc.collation, tc.left, tc.right, sign(cmp), expected)
+		}
+	}
+}
+
+// TestCollateWeightStringFuzz compares random pairs of strings made of fragments that are
+// often equal to each other, and verifies that CollateWeightString always agrees with Collate
+func TestCollateWeightStringFuzz(t *testing.T) {
+	var fragments = []string{"a", "A", "á", "b", "ss", "ß", "SS", "ae", "æ", " ", "  ", "ch", "­", "日", "😀"}
+	var rng = rand.New(rand.NewSource(0x5EED))
+
+	randomString := func() []byte {
+		var b []byte
+		for n := rng.Intn(5); n > 0; n-- {
+			b = append(b, fragments[rng.Intn(len(fragments))]...)
+		}
+		return b
+	}
+
+	var colls []CollationUCA
+	for _, coll := range All() {
+		if uca, ok := coll.(CollationUCA); ok && coll.Charset().Name() == "utf8mb4" {
+			colls = append(colls, uca)
+		}
+	}
+	colls = append(colls, testcollation(t, "utf8mb4_0900_as_cs").(*Collation_utf8mb4_uca_0900).WithLevels(2).(CollationUCA))
+
+	for _, coll := range colls {
+		for i := 0; i < 200; i++ {
+			left, right := randomString(), randomString()
+			if i%4 == 0 {
+				// make `right` a prefix of `left` more often
+				left = append(append([]byte(nil), right...), left...)
+			}
+			weights := coll.WeightString(nil, left, 0)
+
+			for _, prefix := range []bool{false, true} {
+				expected := sign(coll.Collate(left, right, prefix))
+				if got := sign(coll.CollateWeightString(weights, right, prefix)); got != expected {
+					t.Errorf("%s: CollateWeightString(%q, %q, %v) = %d (expected %d)", coll.Name(), left, right, prefix, got, expected)
+				}
+			}
+		}
+	}
+}
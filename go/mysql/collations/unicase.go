@@ -31,7 +31,7 @@ type UnicaseInfo struct {
 }
 
 func (info *UnicaseInfo) unicodeSort(codepoint rune) rune {
-	if codepoint > info.MaxChar {
+	if codepoint < 0 || codepoint > info.MaxChar {
 		return charset.RuneError
 	}
 	if page := info.Page[int(codepoint)>>8]; page != nil {
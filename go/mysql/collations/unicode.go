@@ -18,6 +18,7 @@ package collations
 
 import (
 	"bytes"
+	"strings"
 
 	"vitess.io/vitess/go/mysql/collations/internal/charset"
 )
@@ -47,6 +48,10 @@ func (c *Collation_unicode_general_ci) IsBinary() bool {
 	return false
 }
 
+func (c *Collation_unicode_general_ci) Capabilities() CollationCaps {
+	return CollationCaps{PadSpace: true, Levels: 1}
+}
+
 func (c *Collation_unicode_general_ci) Collate(left, right []byte, isPrefix bool) int {
 	unicaseInfo := c.unicase
 	cs := c.charset
@@ -72,9 +77,12 @@ func (c *Collation_unicode_general_ci) Collate(left, right []byte, isPrefix bool
 		right = right[rWidth:]
 	}
 	if isPrefix {
-		return len(right)
+		// if `right` has codepoints left, `left` is shorter than the prefix
+		return -len(right)
 	}
-	return len(left) - len(right)
+	return collatePadSpace(left, right, func(rest []byte) int {
+		return padSpaceRunes(cs, rest, unicaseInfo.unicodeSort)
+	})
 }
 
 func (c *Collation_unicode_general_ci) WeightString(dst, src []byte, numCodepoints int) []byte {
@@ -126,6 +134,86 @@ func (c *Collation_unicode_general_ci) WeightStringLen(numBytes int) int {
 	return ((numBytes + 3) / 4) * 2
 }
 
+func (c *Collation_unicode_general_ci) Hash(src []byte, numCodepoints int) uint64 {
+	unicaseInfo := c.unicase
+	cs := c.charset
+	limit, pad := hashLimit(src, numCodepoints)
+	space := unicaseInfo.unicodeSort(' ')
+
+	// spaces are only hashed once something else follows them, or if the string is
+	// padded, because trailing spaces are not significant when comparing
+	var spaces int
+	h := newWeightHasher()
+	for limit > 0 {
+		r, width := cs.DecodeRune(src)
+		if r == charset.RuneError && width < 3 {
+			break
+		}
+
+		src = src[width:]
+		limit--
+		sorted := unicaseInfo.unicodeSort(r)
+		if sorted == space {
+			spaces++
+			continue
+		}
+		h.pad(hashPadding16, spaces)
+		spaces = 0
+		h.writeUint16(uint16(sorted))
+	}
+	if pad {
+		h.pad(hashPadding16, spaces+limit)
+	}
+	return h.sum64()
+}
+
+func (c *Collation_unicode_general_ci) Like(pattern, input []byte, escape rune) bool {
+	return likeMatch(c, pattern, input, escape)
+}
+
+func (c *Collation_unicode_general_ci) Min(a, b []byte) []byte {
+	return collationMin(c, a, b)
+}
+
+func (c *Collation_unicode_general_ci) Max(a, b []byte) []byte {
+	return collationMax(c, a, b)
+}
+
+func (c *Collation_unicode_general_ci) TrimLeft(src, cutset []byte) []byte {
+	return collationTrimLeft(c, src, cutset)
+}
+
+func (c *Collation_unicode_general_ci) TrimRight(src, cutset []byte) []byte {
+	return collationTrimRight(c, src, cutset)
+}
+
+func (c *Collation_unicode_general_ci) Trim(src, cutset []byte) []byte {
+	return collationTrim(c, src, cutset)
+}
+
+func (c *Collation_unicode_general_ci) Index(haystack, needle []byte) int {
+	return collationIndex(c, haystack, needle)
+}
+
+func (c *Collation_unicode_general_ci) Upper(dst, src []byte) []byte {
+	return collationUpper(c, dst, src)
+}
+
+func (c *Collation_unicode_general_ci) Lower(dst, src []byte) []byte {
+	return collationLower(c, dst, src)
+}
+
+func (c *Collation_unicode_general_ci) Equal(left, right []byte) bool {
+	return c.Collate(left, right, false) == 0
+}
+
+func (c *Collation_unicode_general_ci) codepointWeight(r rune) (uint32, bool) {
+	if _, ok := encodedWeight(c.charset, r); !ok {
+		return 0, false
+	}
+	return uint32(c.unicase.unicodeSort(r)), true
+}
+
 type Collation_unicode_bin struct {
 	id      ID
 	name    string
@@ -150,8 +238,25 @@ func (c *Collation_unicode_bin) IsBinary() bool {
 	return true
 }
 
+func (c *Collation_unicode_bin) Capabilities() CollationCaps {
+	return CollationCaps{CaseSensitive: true, AccentSensitive: true, Binary: true, PadSpace: true, Levels: 1}
+}
+
 func (c *Collation_unicode_bin) Collate(left, right []byte, isPrefix bool) int {
-	return collationBinary(left, right, isPrefix)
+	if isPrefix {
+		return collationBinary(left, right, isPrefix)
+	}
+	cmpLen := minInt(len(left), len(right))
+	if diff := bytes.Compare(left[:cmpLen], right[:cmpLen]); diff != 0 {
+		return diff
+	}
+	// the strings are compared byte by byte, so the padding must be too: comparing the
+	// codepoints against a space would not agree with the byte order in all charsets
+	var space [4]byte
+	spaceWidth := c.charset.EncodeRune(space[:], ' ')
+	return collatePadSpace(left[cmpLen:], right[cmpLen:], func(rest []byte) int {
+		return padSpaceEncoded(rest, space[:spaceWidth])
+	})
 }
 
 func (c *Collation_unicode_bin) WeightString(dst, src []byte, numCodepoints int) []byte {
@@ -251,6 +356,88 @@ func (c *Collation_unicode_bin) WeightStringLen(numBytes int) int {
 	return ((numBytes + 3) / 4) * 3
 }
 
+func (c *Collation_unicode_bin) Hash(src []byte, numCodepoints int) uint64 {
+	cs := c.charset
+	supplementary := cs.SupportsSupplementaryChars()
+	limit, pad := hashLimit(src, numCodepoints)
+	padding := hashPadding16
+	if supplementary {
+		padding = hashPadding24
+	}
+
+	// spaces are only hashed once something else follows them, or if the string is
+	// padded, because trailing spaces are not significant when comparing
+	var spaces int
+	h := newWeightHasher()
+	for limit > 0 {
+		r, width := cs.DecodeRune(src)
+		if r == charset.RuneError && width < 3 {
+			break
+		}
+
+		src = src[width:]
+		limit--
+		if r == ' ' {
+			spaces++
+			continue
+		}
+		h.pad(padding, spaces)
+		spaces = 0
+		if supplementary {
+			h.writeByte(byte((r >> 16) & 0xFF))
+		}
+		h.writeUint16(uint16(r))
+	}
+	if pad {
+		h.pad(padding, spaces+limit)
+	}
+	return h.sum64()
+}
+
+func (c *Collation_unicode_bin) Like(pattern, input []byte, escape rune) bool {
+	return likeMatch(c, pattern, input, escape)
+}
+
+func (c *Collation_unicode_bin) Min(a, b []byte) []byte {
+	return collationMin(c, a, b)
+}
+
+func (c *Collation_unicode_bin) Max(a, b []byte) []byte {
+	return collationMax(c, a, b)
+}
+
+func (c *Collation_unicode_bin) TrimLeft(src, cutset []byte) []byte {
+	return collationTrimLeft(c, src, cutset)
+}
+
+func (c *Collation_unicode_bin) TrimRight(src, cutset []byte) []byte {
+	return collationTrimRight(c, src, cutset)
+}
+
+func (c *Collation_unicode_bin) Trim(src, cutset []byte) []byte {
+	return collationTrim(c, src, cutset)
+}
+
+func (c *Collation_unicode_bin) Index(haystack, needle []byte) int {
+	return collationIndex(c, haystack, needle)
+}
+
+func (c *Collation_unicode_bin) Upper(dst, src []byte) []byte {
+	return collationUpper(c, dst, src)
+}
+
+func (c *Collation_unicode_bin) Lower(dst, src []byte) []byte {
+	return collationLower(c, dst, src)
+}
+
+func (c *Collation_unicode_bin) Equal(left, right []byte) bool {
+	return c.Collate(left, right, false) == 0
+}
+
+func (c *Collation_unicode_bin) codepointWeight(r rune) (uint32, bool) {
+	return encodedWeight(c.charset, r)
+}
+
 func collationBinary(left, right []byte, rightPrefix bool) int {
 	minLen := minInt(len(left), len(right))
 	if diff := bytes.Compare(left[:minLen], right[:minLen]); diff != 0 {
@@ -261,3 +448,14 @@ func collationBinary(left, right []byte, rightPrefix bool) int {
 	}
 	return len(left) - len(right)
 }
+
+func collationBinaryString(left, right string, rightPrefix bool) int {
+	minLen := minInt(len(left), len(right))
+	if diff := strings.Compare(left[:minLen], right[:minLen]); diff != 0 {
+		return diff
+	}
+	if rightPrefix {
+		left = left[:minLen]
+	}
+	return len(left) - len(right)
+}
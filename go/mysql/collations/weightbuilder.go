@@ -0,0 +1,151 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collations
+
+import "vitess.io/vitess/go/mysql/collations/internal/charset"
+
+// WeightStringBuilder builds the weight string for a string that is received in segments,
+// e.g. the arguments of CONCAT, without weighting the segments that were already appended
+// again every time a new segment is appended. The result is identical to the weight string
+// for the concatenation of all the segments, as returned by WeightString with `numCodepoints`
+// set to 0.
+//
+// The weights for the end of a segment can depend on the beginning of the next one. A
+// contraction is weighted as a single unit, e.g. "ch" in Czech, so the weights for "c" + "h"
+// are not the weights for "c" followed by the weights for "h". In the Japanese collations,
+// the weights for some codepoints depend on the codepoint before them. A segment may also
+// end in the middle of a multi-byte codepoint. Because of this, the builder only weights
+// the part of its input whose weights are final, and buffers the shortest suffix that is
+// not: the contraction that could continue in the next segment, the codepoint that would
+// change the weights of the first codepoint of the next segment, or the incomplete codepoint.
+// Like WeightString, the builder stops weighting at the first invalid codepoint, so all the
+// input that follows an invalid codepoint is buffered.
+//
+// The weight strings of the UCA 0900 collations contain the weights for every level one after
+// the other, so the builder keeps the weights for each level separately until they're joined
+// by WeightString.
+type WeightStringBuilder struct {
+	coll    Collation
+	levels  [][]byte
+	pending []byte
+	scratch []byte
+}
+
+// NewWeightStringBuilder returns an empty WeightStringBuilder for strings in the given collation
+func NewWeightStringBuilder(coll Collation) *WeightStringBuilder {
+	return &WeightStringBuilder{coll: coll}
+}
+
+// Append appends `segment` to the string whose weight string is being built. The segment
+// doesn't need to start or end at a codepoint boundary.
+func (b *WeightStringBuilder) Append(segment []byte) {
+	b.pending = append(b.pending, segment...)
+
+	stable := stableWeightPrefix(b.coll, b.pending)
+	if stable == 0 {
+		return
+	}
+	b.scratch = b.coll.WeightString(b.scratch[:0], b.pending[:stable], 0)
+	b.levels = b.appendLevels(b.levels, b.scratch)
+	b.pending = append(b.pending[:0], b.pending[stable:]...)
+}
+
+// WeightString appends the weight string for the concatenation of all the segments that
+// have been appended so far to `dst`. More segments can be appended afterwards.
+func (b *WeightStringBuilder) WeightString(dst []byte) []byte {
+	levels := b.levels
+	if len(b.pending) > 0 || len(levels) == 0 {
+		// the weights for the buffered input are only needed for this result, so the levels
+		// are copied before appending to them, and the builder itself is not modified
+		levels = make([][]byte, len(b.levels))
+		for i, weights := range b.levels {
+			levels[i] = weights[:len(weights):len(weights)]
+		}
+		levels = b.appendLevels(levels, b.coll.WeightString(nil, b.pending, 0))
+	}
+
+	for i, weights := range levels {
+		if i > 0 {
+			dst = append(dst, 0x00, 0x00)
+		}
+		dst = append(dst, weights...)
+	}
+	return dst
+}
+
+// Reset clears the builder, so it can be reused to build the weight string of another string
+func (b *WeightStringBuilder) Reset() {
+	b.levels = b.levels[:0]
+	b.pending = b.pending[:0]
+}
+
+// appendLevels appends the weights of each level in the weight string `ws` to `levels`
+func (b *WeightStringBuilder) appendLevels(levels [][]byte, ws []byte) [][]byte {
+	if !b.multilevel() {
+		if len(levels) == 0 {
+			levels = append(levels, nil)
+		}
+		levels[0] = append(levels[0], ws...)
+		return levels
+	}
+
+	// the levels are separated by a NULL weight, and no other weights are NULL. The weight
+	// strings for different inputs may not have the same amount of levels: the Kana-sensitive
+	// level of Japanese collations is only there when the input contains a Kana
+	level, start := 0, 0
+	for i := 0; i <= len(ws); i += 2 {
+		if i < len(ws) && (ws[i] != 0x00 || ws[i+1] != 0x00) {
+			continue
+		}
+		if level == len(levels) {
+			levels = append(levels, nil)
+		}
+		levels[level] = append(levels[level], ws[start:i]...)
+		level++
+		start = i + 2
+	}
+	return levels
+}
+
+// multilevel returns whether the weight strings of the collation contain more than one level
+func (b *WeightStringBuilder) multilevel() bool {
+	coll, ok := b.coll.(*Collation_utf8mb4_uca_0900)
+	return ok && coll.levelsForCompare > 1
+}
+
+// stableWeightPrefix returns the length of the longest prefix of `src` whose weights in the
+// given collation cannot change when more data is appended to `src`
+func stableWeightPrefix(coll Collation, src []byte) int {
+	switch coll := coll.(type) {
+	case *Collation_utf8mb4_uca_0900:
+		return coll.uca.StablePrefix(src)
+	case *Collation_uca_legacy:
+		return coll.uca.StablePrefix(src)
+	}
+
+	// the rest of the collations weight every codepoint on its own
+	cs := coll.Charset()
+	pos := 0
+	for pos < len(src) {
+		cp, width := cs.DecodeRune(src[pos:])
+		if cp == charset.RuneError && width < 3 {
+			break
+		}
+		pos += width
+	}
+	return pos
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collations
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+
+	"vitess.io/vitess/go/mysql/collations/internal/charset"
+)
+
+func TestWeightStringBuilder(t *testing.T) {
+	var inputs = []string{
+		"", ExampleString, JapaneseString, WhitespaceString, HungarianString,
+		"chocholoušek", "dzsungel ccs", "かゝきゞカヽキヾカーき", "ǅ́\x00", "ab\xffch", "\xe6\x97",
+	}
+	var rng = rand.New(rand.NewSource(0xB111D))
+
+	for _, coll := range All() {
+		for _, input := range inputs {
+			src, err := charset.ConvertFromUTF8(nil, coll.Charset(), []byte(input))
+			if err != nil {
+				src = []byte(input)
+			}
+			expected := coll.WeightString(nil, src, 0)
+
+			// split the input at every byte, and then into random segments
+			for split := 0; split <= len(src) && split < 32; split++ {
+				b := NewWeightStringBuilder(coll)
+				b.Append(src[:split])
+				b.Append(src[split:])
+				if got := b.WeightString(nil); !bytes.Equal(got, expected) {
+					t.Fatalf("%s: WeightString(%q + %q) = %x, expected %x", coll.Name(), src[:split], src[split:], got, expected)
+				}
+			}
+
+			b := NewWeightStringBuilder(coll)
+			var appended []byte
+			for rest := src; len(rest) > 0; {
+				n := rng.Intn(minInt(len(rest), 6) + 1)
+				b.Append(rest[:n])
+				appended, rest = append(appended, rest[:n]...), rest[n:]
+
+				if got, expected := b.WeightString(nil), coll.WeightString(nil, appended, 0); !bytes.Equal(got, expected) {
+					t.Fatalf("%s: WeightString(%q) = %x, expected %x", coll.Name(), appended, got, expected)
+				}
+			}
+
+			b.Reset()
+			b.Append(src)
+			if got := b.WeightString(nil); !bytes.Equal(got, expected) {
+				t.Fatalf("%s: WeightString(%q) after Reset = %x, expected %x", coll.Name(), src, got, expected)
+			}
+		}
+	}
+}
+
+func TestWeightStringBuilderBoundary(t *testing.T) {
+	var cases = []struct {
+		collation string
+		segment   string
+		pending   string
+		next      string
+	}{
+		// a contraction that could continue in the next segment is buffered
+		{"utf8mb4_cs_0900_as_cs", "chocholou", "", "c"},
+		{"utf8mb4_cs_0900_as_cs", "chocholouc", "c", "h"},
+		{"utf8mb4_czech_ci", "abc", "c", "h"},
+		// "dz" is a contraction, but it can also be the beginning of "dzs"
+		{"utf8mb4_hu_0900_ai_ci", "madz", "dz", "s"},
+		// a complete contraction is buffered too, because it doesn't match if it's followed by an invalid codepoint
+		{"utf8mb4_hu_0900_ai_ci", "madzs", "dzs", "ong"},
+		{"utf8mb4_hu_0900_ai_ci", "madzs", "dzs", "\xff"},
+		{"utf8mb4_hu_0900_ai_ci", "madzso", "", "ng"},
+		// the iteration mark ゝ repeats the kana before it
+		{"utf8mb4_ja_0900_as_cs", "かき", "き", "ゝ"},
+		{"utf8mb4_ja_0900_as_cs", "かゝ", "", "ゝ"},
+		{"utf8mb4_ja_0900_as_cs", "abc", "", "ゝ"},
+		{"utf8mb4_ja_0900_as_cs_ks", "カキ", "キ", "ヽ"},
+		// an incomplete codepoint is buffered until it's completed
+		{"utf8mb4_0900_ai_ci", "caf\xc3", "\xc3", "\xa9"},
+		{"utf8mb4_general_ci", "caf\xc3", "\xc3", "\xa9"},
+		// and everything after an invalid codepoint is buffered
+		{"utf8mb4_0900_ai_ci", "ab\xffcd", "\xffcd", "ef"},
+		{"latin1_swedish_ci", "caf\xe9", "", "s"},
+	}
+
+	for _, tc := range cases {
+		coll := testcollation(t, tc.collation)
+		b := NewWeightStringBuilder(coll)
+		b.Append([]byte(tc.segment))
+		if string(b.pending) != tc.pending {
+			t.Errorf("%s: Append(%q) buffered %q, expected %q", tc.collation, tc.segment, b.pending, tc.pending)
+		}
+
+		b.Append([]byte(tc.next))
+		expected := coll.WeightString(nil, []byte(tc.segment+tc.next), 0)
+		if got := b.WeightString(nil); !bytes.Equal(got, expected) {
+			t.Errorf("%s: WeightString(%q + %q) = %x, expected %x", tc.collation, tc.segment, tc.next, got, expected)
+		}
+	}
+
+	// the contraction spans both segments, so the result is not the concatenation of the levels
+	// of the weight strings for each segment
+	czech := testcollation(t, "utf8mb4_cs_0900_ai_ci")
+	b := NewWeightStringBuilder(czech)
+	b.Append([]byte("c"))
+	b.Append([]byte("h"))
+	separate := append(czech.WeightString(nil, []byte("c"), 0), czech.WeightString(nil, []byte("h"), 0)...)
+	if got := b.WeightString(nil); bytes.Equal(got, separate) || !bytes.Equal(got, czech.WeightString(nil, []byte("ch"), 0)) {
+		t.Errorf("WeightString(\"c\" + \"h\") = %x, expected the weights for the contraction \"ch\"", got)
+	}
+}
+
+func BenchmarkWeightStringBuilder(b *testing.B) {
+	coll := testcollation(b, "utf8mb4_0900_as_cs")
+	segments := [][]byte{[]byte(ExampleStringLong), []byte(HungarianString), []byte(ExampleString)}
+
+	b.Run("builder", func(b *testing.B) {
+		b.ReportAllocs()
+		builder := NewWeightStringBuilder(coll)
+		var dst []byte
+		for i := 0; i < b.N; i++ {
+			builder.Reset()
+			for _, segment := range segments {
+				builder.Append(segment)
+				dst = builder.WeightString(dst[:0])
+			}
+		}
+	})
+
+	b.Run("concat", func(b *testing.B) {
+		b.ReportAllocs()
+		var concat, dst []byte
+		for i := 0; i < b.N; i++ {
+			concat = concat[:0]
+			for _, segment := range segments {
+				concat = append(concat, segment...)
+				dst = coll.WeightString(dst[:0], concat, 0)
+			}
+		}
+	})
+}
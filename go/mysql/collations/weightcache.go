@@ -0,0 +1,63 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collations
+
+import "vitess.io/vitess/go/cache"
+
+// WeightCache memoizes the weight strings computed by a collation, keyed by their input,
+// so that the weight strings for the same inputs are only computed once when they are
+// compared over and over, e.g. for the build-side keys of a hash join. The cache is
+// bounded: once the weight strings it holds add up to more than its capacity in bytes,
+// the least recently used ones are evicted. A WeightCache is safe for concurrent use.
+type WeightCache struct {
+	coll  Collation
+	cache *cache.LRUCache
+}
+
+// NewWeightCache returns a WeightCache for the given collation that holds up to
+// `capacity` bytes of weight strings.
+func NewWeightCache(coll Collation, capacity int64) *WeightCache {
+	return &WeightCache{
+		coll: coll,
+		cache: cache.NewLRUCache(capacity, func(v interface{}) int64 {
+			return int64(len(v.([]byte)))
+		}),
+	}
+}
+
+// Collation returns the collation whose weight strings are cached
+func (wc *WeightCache) Collation() Collation {
+	return wc.coll
+}
+
+// WeightString returns the weight string for `src`, like WeightString with `numCodepoints`
+// set to 0 would, either from the cache or by computing it and adding it to the cache.
+// The returned weight string is shared with other callers, so it must not be modified,
+// and it must be copied if it is going to be appended to.
+func (wc *WeightCache) WeightString(src []byte) []byte {
+	if weights, ok := wc.cache.Get(bytesString(src)); ok {
+		return weights.([]byte)
+	}
+	weights := wc.coll.WeightString(nil, src, 0)
+	wc.cache.Set(string(src), weights)
+	return weights
+}
+
+// Len returns the amount of weight strings in the cache
+func (wc *WeightCache) Len() int {
+	return wc.cache.Len()
+}
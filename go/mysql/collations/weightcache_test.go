@@ -0,0 +1,127 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collations
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"sync"
+	"testing"
+)
+
+func TestWeightCache(t *testing.T) {
+	coll := testcollation(t, "utf8mb4_0900_ai_ci")
+	wc := NewWeightCache(coll, 1024)
+
+	inputs := []string{"abc", "ABC", "Straße", "日本語", ""}
+	for round := 0; round < 2; round++ {
+		for _, input := range inputs {
+			expected := coll.WeightString(nil, []byte(input), 0)
+			if got := wc.WeightString([]byte(input)); !bytes.Equal(got, expected) {
+				t.Errorf("WeightString(%q) = %x (expected %x)", input, got, expected)
+			}
+		}
+	}
+	if wc.Len() != len(inputs) {
+		t.Errorf("the cache holds %d weight strings (expected %d)", wc.Len(), len(inputs))
+	}
+
+	// the input can be reused after it has been cached
+	src := []byte("reused")
+	expected := wc.WeightString(src)
+	copy(src, "REUSED")
+	if got := wc.WeightString([]byte("reused")); !bytes.Equal(got, expected) {
+		t.Errorf("the cached weight string changed after its input was modified: %x", got)
+	}
+}
+
+func TestWeightCacheEviction(t *testing.T) {
+	coll := testcollation(t, "binary")
+	// the weight strings in the binary collation are their inputs
+	wc := NewWeightCache(coll, 32)
+
+	for i := 0; i < 100; i++ {
+		input := []byte(fmt.Sprintf("input-%03d", i))
+		if got := wc.WeightString(input); !bytes.Equal(got, coll.WeightString(nil, input, 0)) {
+			t.Fatalf("WeightString(%q) = %x", input, got)
+		}
+	}
+	if wc.Len() != 3 {
+		t.Errorf("the cache holds %d weight strings (expected 3)", wc.Len())
+	}
+}
+
+func TestWeightCacheConcurrent(t *testing.T) {
+	coll := testcollation(t, "utf8mb4_0900_as_cs")
+	wc := NewWeightCache(coll, 256)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+			rng := rand.New(rand.NewSource(seed))
+			for i := 0; i < 1000; i++ {
+				input := []byte(fmt.Sprintf("key %d", rng.Intn(50)))
+				if got := wc.WeightString(input); !bytes.Equal(got, coll.WeightString(nil, input, 0)) {
+					t.Errorf("WeightString(%q) = %x", input, got)
+					return
+				}
+			}
+		}(int64(g))
+	}
+	wg.Wait()
+}
+
+// BenchmarkWeightCache simulates the probe phase of a hash join, where every probe-side key
+// is compared with the weight strings of a small set of build-side keys that repeat
+func BenchmarkWeightCache(b *testing.B) {
+	coll := testcollation(b, "utf8mb4_0900_ai_ci")
+	rng := rand.New(rand.NewSource(1))
+
+	var build [][]byte
+	for i := 0; i < 64; i++ {
+		build = append(build, []byte(fmt.Sprintf("Café número %d, Straße %d", i, rng.Intn(1000))))
+	}
+	var probe [][]byte
+	for i := 0; i < 1024; i++ {
+		key := build[rng.Intn(len(build))]
+		probe = append(probe, coll.WeightString(nil, key, 0))
+	}
+
+	b.Run("Recompute", func(b *testing.B) {
+		b.ReportAllocs()
+		var buf []byte
+		for n := 0; n < b.N; n++ {
+			for i, weights := range probe {
+				buf = coll.WeightString(buf[:0], build[i%len(build)], 0)
+				_ = bytes.Equal(buf, weights)
+			}
+		}
+	})
+
+	b.Run("Cached", func(b *testing.B) {
+		b.ReportAllocs()
+		wc := NewWeightCache(coll, 64*1024)
+		for n := 0; n < b.N; n++ {
+			for i, weights := range probe {
+				_ = bytes.Equal(wc.WeightString(build[i%len(build)]), weights)
+			}
+		}
+	})
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"vitess.io/vitess/go/mysql/collations"
 	"vitess.io/vitess/go/sqltypes"
 
 	querypb "vitess.io/vitess/go/vt/proto/query"
@@ -588,6 +589,51 @@ func TestQueries(t *testing.T) {
 	})
 }
 
+func TestQueryCollations(t *testing.T) {
+	listener, sConn, cConn := createSocketPair(t)
+	defer func() {
+		listener.Close()
+		sConn.Close()
+		cConn.Close()
+	}()
+
+	result := &sqltypes.Result{
+		Fields: []*querypb.Field{
+			{Name: "id", Type: querypb.Type_INT64, Charset: 63},
+			{Name: "name", Type: querypb.Type_VARCHAR, Charset: 255},
+		},
+		Rows: [][]sqltypes.Value{
+			{sqltypes.NewInt64(10), sqltypes.NewVarChar("nice name")},
+		},
+	}
+
+	// the collations of the columns are only known if the fields are wanted
+	for _, wantfields := range []bool{true, false} {
+		var got *sqltypes.Result
+		var err error
+
+		wg := sync.WaitGroup{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got, err = cConn.ExecuteFetch("select collations", 10, wantfields)
+		}()
+
+		handler := testHandler{result: result}
+		if !sConn.handleNextCommand(&handler) {
+			t.Fatal("error handling command")
+		}
+		wg.Wait()
+		require.NoError(t, err)
+
+		expected := collations.ID(255)
+		if !wantfields {
+			expected = collations.Unknown
+		}
+		assert.Equal(t, expected, got.ColumnCollation(1), "collation for column 1 (wantfields = %v)", wantfields)
+	}
+}
+
 func checkQuery(t *testing.T, query string, sConn, cConn *Conn, result *sqltypes.Result) {
 	// The protocol depends on the CapabilityClientDeprecateEOF flag.
 	// So we want to test both cases.
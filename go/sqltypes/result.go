@@ -17,12 +17,16 @@ limitations under the License.
 package sqltypes
 
 import (
+	"bytes"
 	"crypto/sha256"
 	"fmt"
 	"reflect"
+	"sort"
 
 	"google.golang.org/protobuf/proto"
 
+	"vitess.io/vitess/go/mysql/collations"
+
 	querypb "vitess.io/vitess/go/vt/proto/query"
 )
 
@@ -139,6 +143,67 @@ func (result *Result) Truncate(l int) *Result {
 	return out
 }
 
+// ColumnCollation returns the ID of the collation of the column at `colIndex`, as reported by
+// MySQL in its column definition, so that the values of the column can be compared with the
+// right collation. It returns collations.Unknown if the result has no fields, e.g. because
+// they were not requested when executing the query.
+func (result *Result) ColumnCollation(colIndex int) collations.ID {
+	if colIndex < 0 || colIndex >= len(result.Fields) {
+		return collations.Unknown
+	}
+	return collations.ID(result.Fields[colIndex].Charset)
+}
+
+// SortBy sorts the rows of the result by the values in the column at `colIndex`, compared
+// with the given collation, in descending order if `desc` is true. Like in MySQL, NULL values
+// sort before any other value in ascending order, and after them in descending order. The
+// sort key of every row is built only once with a collations.SortKeyBuilder, and the rows
+// whose values are equal under the collation keep their original order.
+func (result *Result) SortBy(colIndex int, coll collations.Collation, desc bool) {
+	// all the keys are stored back to back in a single buffer, and only sliced
+	// once the buffer won't be reallocated anymore
+	var builder collations.SortKeyBuilder
+	var buf []byte
+	ends := make([]int, len(result.Rows))
+	for i, row := range result.Rows {
+		builder.Reset()
+		if value := row[colIndex]; value.IsNull() {
+			builder.AddNull(!desc)
+		} else {
+			builder.Add(coll, value.Raw(), !desc)
+		}
+		buf = append(buf, builder.Key()...)
+		ends[i] = len(buf)
+	}
+
+	sorted := &keyedRows{rows: result.Rows, keys: make([][]byte, len(result.Rows))}
+	start := 0
+	for i, end := range ends {
+		sorted.keys[i] = buf[start:end:end]
+		start = end
+	}
+	sort.Stable(sorted)
+}
+
+// keyedRows implements sort.Interface for the rows of a Result together with their sort keys
+type keyedRows struct {
+	rows [][]Value
+	keys [][]byte
+}
+
+func (kr *keyedRows) Len() int {
+	return len(kr.rows)
+}
+
+func (kr *keyedRows) Less(i, j int) bool {
+	return bytes.Compare(kr.keys[i], kr.keys[j]) < 0
+}
+
+func (kr *keyedRows) Swap(i, j int) {
+	kr.rows[i], kr.rows[j] = kr.rows[j], kr.rows[i]
+	kr.keys[i], kr.keys[j] = kr.keys[j], kr.keys[i]
+}
+
 // FieldsEqual compares two arrays of fields.
 // reflect.DeepEqual shouldn't be used because of the protos.
 func FieldsEqual(f1, f2 []*querypb.Field) bool {
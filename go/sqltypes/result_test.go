@@ -20,6 +20,7 @@ import (
 	"reflect"
 	"testing"
 
+	"vitess.io/vitess/go/mysql/collations"
 	"vitess.io/vitess/go/test/utils"
 
 	querypb "vitess.io/vitess/go/vt/proto/query"
@@ -107,6 +108,56 @@ func TestTruncate(t *testing.T) {
 	}
 }
 
+func TestColumnCollation(t *testing.T) {
+	result := &Result{
+		Fields: []*querypb.Field{
+			{Name: "id", Type: Int64, Charset: 63},
+			{Name: "name", Type: VarChar, Charset: 255},
+		},
+	}
+	if got := result.ColumnCollation(1); got != 255 {
+		t.Errorf("ColumnCollation(1) = %d, want 255", got)
+	}
+	if got := result.ColumnCollation(2); got != collations.Unknown {
+		t.Errorf("ColumnCollation(2) = %d, want unknown", got)
+	}
+	if got := (&Result{}).ColumnCollation(0); got != collations.Unknown {
+		t.Errorf("ColumnCollation(0) without fields = %d, want unknown", got)
+	}
+}
+
+func TestSortBy(t *testing.T) {
+	coll := collations.FromName("utf8mb4_0900_ai_ci")
+
+	names := []string{"zoë", "Émile", "eve", "", "adam", "emile", "Zoe", "ÉVE", "Adam"}
+	result := &Result{Fields: []*querypb.Field{{Name: "id", Type: Int64}, {Name: "name", Type: VarChar}}}
+	for i, name := range names {
+		result.Rows = append(result.Rows, []Value{NewInt64(int64(i)), NewVarChar(name)})
+	}
+	result.Rows = append(result.Rows, []Value{NewInt64(int64(len(names))), NULL})
+
+	ids := func() (ids []int64) {
+		for _, row := range result.Rows {
+			id, _ := row[0].ToInt64()
+			ids = append(ids, id)
+		}
+		return ids
+	}
+
+	// the names that only differ in case and accents are equal, so they keep their original order
+	result.SortBy(1, coll, false)
+	want := []int64{9, 3, 4, 8, 1, 5, 2, 7, 0, 6}
+	if got := ids(); !reflect.DeepEqual(got, want) {
+		t.Errorf("SortBy(ASC) = %v, want %v", got, want)
+	}
+
+	result.SortBy(1, coll, true)
+	want = []int64{0, 6, 2, 7, 1, 5, 4, 8, 3, 9}
+	if got := ids(); !reflect.DeepEqual(got, want) {
+		t.Errorf("SortBy(DESC) = %v, want %v", got, want)
+	}
+}
+
 func TestStripMetaData(t *testing.T) {
 	testcases := []struct {
 		name           string
@@ -27,6 +27,7 @@ import (
 
 	"vitess.io/vitess/go/bytes2"
 	"vitess.io/vitess/go/hack"
+	"vitess.io/vitess/go/mysql/collations"
 
 	querypb "vitess.io/vitess/go/vt/proto/query"
 )
@@ -186,6 +187,23 @@ func (v Value) Type() querypb.Type {
 	return v.typ
 }
 
+// CollateNullable compares two values with the given collation, following the semantics
+// of SQL for NULL: if either value is NULL, the result of the comparison is unknown, so
+// `isNull` is true. In that case `cmp` still orders the values like MySQL does when sorting,
+// where NULL is smaller than any other value, including the empty string, and equal to
+// NULL; otherwise, `cmp` is the result of `coll.Collate` on the contents of the values.
+func CollateNullable(coll collations.Collation, left, right Value, rightIsPrefix bool) (cmp int, isNull bool) {
+	switch leftNull, rightNull := left.IsNull(), right.IsNull(); {
+	case leftNull && rightNull:
+		return 0, true
+	case leftNull:
+		return -1, true
+	case rightNull:
+		return 1, true
+	}
+	return coll.Collate(left.Raw(), right.Raw(), rightIsPrefix), false
+}
+
 // Raw returns the internal representation of the value. For newer types,
 // this may not match MySQL's representation.
 func (v Value) Raw() []byte {
@@ -19,9 +19,11 @@ package sqltypes
 import (
 	"bytes"
 	"reflect"
+	"sort"
 	"strings"
 	"testing"
 
+	"vitess.io/vitess/go/mysql/collations"
 	querypb "vitess.io/vitess/go/vt/proto/query"
 )
 
@@ -469,3 +471,71 @@ func TestEncodeMap(t *testing.T) {
 		t.Errorf("SQLDecodeMap[DontEscape] = %v, want %v", SQLEncodeMap[DontEscape], DontEscape)
 	}
 }
+
+func TestCollateNullable(t *testing.T) {
+	coll := collations.FromName("utf8mb4_0900_ai_ci")
+	empty := NewVarChar("")
+
+	var cases = []struct {
+		left, right Value
+		prefix      bool
+		cmp         int
+		isNull      bool
+	}{
+		{NULL, NULL, false, 0, true},
+		{NULL, empty, false, -1, true},
+		{empty, NULL, false, 1, true},
+		{NULL, NewVarChar("a"), false, -1, true},
+		{NewVarChar("a"), NULL, true, 1, true},
+		{empty, empty, false, 0, false},
+		{empty, NewVarChar("a"), false, -1, false},
+		{NewVarChar("a"), NewVarChar("A"), false, 0, false},
+		{NewVarChar("b"), NewVarChar("A"), false, 1, false},
+		{NewVarChar("abc"), NewVarChar("AB"), true, 0, false},
+	}
+
+	for _, tc := range cases {
+		cmp, isNull := CollateNullable(coll, tc.left, tc.right, tc.prefix)
+		switch {
+		case cmp < 0:
+			cmp = -1
+		case cmp > 0:
+			cmp = 1
+		}
+		if cmp != tc.cmp || isNull != tc.isNull {
+			t.Errorf("CollateNullable(%v, %v, %v) = %d, %v (expected %d, %v)",
+				tc.left, tc.right, tc.prefix, cmp, isNull, tc.cmp, tc.isNull)
+		}
+	}
+}
+
+func TestCollateNullableOrdering(t *testing.T) {
+	coll := collations.FromName("utf8mb4_0900_ai_ci")
+	values := []Value{NewVarChar("b"), NULL, NewVarChar(""), NewVarChar("A"), NULL, NewVarChar("c")}
+
+	sortValues := func(desc bool) []string {
+		sorted := append([]Value(nil), values...)
+		sort.SliceStable(sorted, func(i, j int) bool {
+			cmp, _ := CollateNullable(coll, sorted[i], sorted[j], false)
+			if desc {
+				return cmp > 0
+			}
+			return cmp < 0
+		})
+		var out []string
+		for _, v := range sorted {
+			out = append(out, v.String())
+		}
+		return out
+	}
+
+	// like in MySQL, NULLs come first in ascending order and last in descending order
+	asc := []string{"NULL", "NULL", `VARCHAR("")`, `VARCHAR("A")`, `VARCHAR("b")`, `VARCHAR("c")`}
+	if got := sortValues(false); !reflect.DeepEqual(got, asc) {
+		t.Errorf("ASC: %v (expected %v)", got, asc)
+	}
+	desc := []string{`VARCHAR("c")`, `VARCHAR("b")`, `VARCHAR("A")`, `VARCHAR("")`, "NULL", "NULL"}
+	if got := sortValues(true); !reflect.DeepEqual(got, desc) {
+		t.Errorf("DESC: %v (expected %v)", got, desc)
+	}
+}
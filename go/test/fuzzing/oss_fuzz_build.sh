@@ -23,6 +23,9 @@ compile_go_fuzzer vitess.io/vitess/go/mysql FuzzWritePacket write_packet_fuzzer
 compile_go_fuzzer vitess.io/vitess/go/mysql FuzzHandleNextCommand handle_next_command_fuzzer
 compile_go_fuzzer vitess.io/vitess/go/mysql FuzzReadQueryResults read_query_results_fuzzer
 compile_go_fuzzer vitess.io/vitess/go/mysql FuzzTLSServer fuzz_tls
+compile_go_fuzzer vitess.io/vitess/go/mysql/collations FuzzCollateTransitivity collate_transitivity_fuzzer gofuzz
+compile_go_fuzzer vitess.io/vitess/go/mysql/collations FuzzCollateWeightString collate_weight_string_fuzzer gofuzz
+compile_go_fuzzer vitess.io/vitess/go/mysql/collations FuzzCollateASCII collate_ascii_fuzzer gofuzz
 compile_go_fuzzer vitess.io/vitess/go/vt/vtgate/grpcvtgateconn Fuzz grpc_vtgate_fuzzer
 compile_go_fuzzer vitess.io/vitess/go/vt/vtgate/planbuilder/abstract FuzzAnalyse planbuilder_fuzzer gofuzz
 
@@ -19,10 +19,14 @@ package asthelpergen
 import (
 	"bytes"
 	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
 	"go/types"
 	"log"
 	"os"
 	"path"
+	"sort"
 	"strings"
 
 	"vitess.io/vitess/go/tools/goimports"
@@ -191,16 +195,97 @@ func VerifyFilesOnDisk(result map[string]*jen.File) (errors []error) {
 		}
 
 		if !bytes.Equal(existing, genFile) {
-			errors = append(errors, fmt.Errorf("'%s' has changed", fullPath))
+			if missing := missingTypes(existing, genFile); len(missing) > 0 {
+				errors = append(errors, fmt.Errorf("'%s' has changed: missing support for the types %s", fullPath, strings.Join(missing, ", ")))
+			} else {
+				errors = append(errors, fmt.Errorf("'%s' has changed", fullPath))
+			}
 			continue
 		}
 	}
 	return errors
 }
 
+// missingTypes returns the types that are handled in the type switches of the generated
+// code but not in the existing code, which is what happens when a type is added to the
+// AST without re-generating the helpers
+func missingTypes(existing, generated []byte) []string {
+	existingTypes, err := typeSwitchCases(existing)
+	if err != nil {
+		return nil
+	}
+	generatedTypes, err := typeSwitchCases(generated)
+	if err != nil {
+		return nil
+	}
+
+	var missing []string
+	for t := range generatedTypes {
+		if !existingTypes[t] {
+			missing = append(missing, t)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+// typeSwitchCases returns all the types that appear in the cases of the type switches of a Go file
+func typeSwitchCases(src []byte) (map[string]bool, error) {
+	file, err := parser.ParseFile(token.NewFileSet(), "", src, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	cases := map[string]bool{}
+	ast.Inspect(file, func(node ast.Node) bool {
+		if sw, ok := node.(*ast.TypeSwitchStmt); ok {
+			for _, stmt := range sw.Body.List {
+				for _, expr := range stmt.(*ast.CaseClause).List {
+					cases[types.ExprString(expr)] = true
+				}
+			}
+		}
+		return true
+	})
+	return cases, nil
+}
+
+// Options configures the code generated by GenerateASTHelpers
+type Options struct {
+	// Packages are the patterns of the packages to load, e.g. `./go/vt/sqlparser`. Packages
+	// are loaded as a whole, so the AST nodes can be declared in any of their files, and
+	// splitting them into several files does not change the generated code.
+	Packages []string
+	// RootInterface is the fully qualified name of the interface that all the AST nodes implement
+	RootInterface string
+	// ExceptCloneType is a type that is not deep cloned, e.g. `*ColName`
+	ExceptCloneType string
+	// TypedWalkers are the types that get a narrow Walk function that only visits them
+	TypedWalkers []string
+	// ParentAccessors are the types that get a typed accessor for the parent in the Cursor,
+	// e.g. `Cursor.ParentIfSelect() (*Select, bool)` for `*Select`
+	ParentAccessors []string
+	// EnclosingInterface is the name of an interface in the package of the root interface, e.g.
+	// `Statement`, whose nearest implementation among the ancestors of the current node is
+	// returned by a method of the Cursor, e.g. `Cursor.EnclosingStatement() Statement`
+	EnclosingInterface string
+	// IterativeRewriter generates the code needed by RewriteIterative, which walks the AST
+	// with an explicit stack instead of recursion
+	IterativeRewriter bool
+	// JSON generates MarshalJSON functions that serialize the AST into JSON, with the type of every
+	// node in a "_type" field
+	JSON bool
+	// FileHeader is the comment at the top of every generated file, before the notice that
+	// the code is generated; the Vitess license header is used if it is empty
+	FileHeader string
+	// FileFooter is a comment at the end of every generated file, if it is not empty
+	FileFooter string
+}
+
 // GenerateASTHelpers loads the input code, constructs the necessary generators,
 // and generates the rewriter and clone methods for the AST
-func GenerateASTHelpers(packagePatterns []string, rootIface, exceptCloneType string) (map[string]*jen.File, error) {
+func GenerateASTHelpers(options *Options) (map[string]*jen.File, error) {
+	packagePatterns, rootIface := options.Packages, options.RootInterface
 	loaded, err := packages.Load(&packages.Config{
 		Mode: packages.NeedName | packages.NeedTypes | packages.NeedTypesSizes | packages.NeedTypesInfo | packages.NeedDeps | packages.NeedImports | packages.NeedModule,
 	}, packagePatterns...)
@@ -235,18 +320,40 @@ func GenerateASTHelpers(packagePatterns []string, rootIface, exceptCloneType str
 
 	nt := tt.Type().(*types.Named)
 	pName := nt.Obj().Pkg().Name()
-	generator := newGenerator(loaded[0].Module, loaded[0].TypesSizes, nt,
+	generators := []generator{
 		newEqualsGen(pName),
-		newCloneGen(pName, exceptCloneType),
+		newCloneGen(pName, options.ExceptCloneType),
 		newVisitGen(pName),
-		newRewriterGen(pName, types.TypeString(nt, noQualifier)),
-	)
+		newRewriterGen(pName, types.TypeString(nt, noQualifier), options.ParentAccessors, options.EnclosingInterface),
+	}
+	if len(options.TypedWalkers) > 0 {
+		generators = append(generators, newTypedWalkGen(pName, types.TypeString(nt, noQualifier), options.TypedWalkers))
+	}
+	if options.IterativeRewriter {
+		generators = append(generators, newIterativeRewriteGen(pName, types.TypeString(nt, noQualifier)))
+	}
+	if options.JSON {
+		generators = append(generators, newJSONGen(pName, types.TypeString(nt, noQualifier)))
+	}
+	generator := newGenerator(loaded[0].Module, loaded[0].TypesSizes, nt, generators...)
 
 	it, err := generator.GenerateCode()
 	if err != nil {
 		return nil, err
 	}
 
+	header := options.FileHeader
+	if header == "" {
+		header = licenseFileHeader
+	}
+	for _, file := range it {
+		file.HeaderComment(header)
+		file.HeaderComment("Code generated by ASTHelperGen. DO NOT EDIT.")
+		if options.FileFooter != "" {
+			file.Comment(options.FileFooter)
+		}
+	}
+
 	return it, nil
 }
 
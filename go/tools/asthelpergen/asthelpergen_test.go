@@ -25,7 +25,16 @@ import (
 )
 
 func TestFullGeneration(t *testing.T) {
-	result, err := GenerateASTHelpers([]string{"./integration/..."}, "vitess.io/vitess/go/tools/asthelpergen/integration.AST", "*NoCloneType")
+	result, err := GenerateASTHelpers(&Options{
+		Packages:           []string{"./integration/..."},
+		RootInterface:      "vitess.io/vitess/go/tools/asthelpergen/integration.AST",
+		ExceptCloneType:    "*NoCloneType",
+		TypedWalkers:       []string{"*Leaf", "InterfaceSlice"},
+		ParentAccessors:    []string{"*RefContainer", "InterfaceSlice"},
+		EnclosingInterface: "SubIface",
+		IterativeRewriter:  true,
+		JSON:               true,
+	})
 	require.NoError(t, err)
 
 	verifyErrors := VerifyFilesOnDisk(result)
@@ -41,3 +50,57 @@ func TestFullGeneration(t *testing.T) {
 		}
 	}
 }
+
+func TestFileHeaderAndFooter(t *testing.T) {
+	result, err := GenerateASTHelpers(&Options{
+		Packages:        []string{"./integration/..."},
+		RootInterface:   "vitess.io/vitess/go/tools/asthelpergen/integration.AST",
+		ExceptCloneType: "*NoCloneType",
+		FileHeader:      "Copyright 2021 Example Authors.",
+		FileFooter:      "end of generated code",
+	})
+	require.NoError(t, err)
+
+	for _, file := range result {
+		contents := fmt.Sprintf("%#v", file)
+		require.True(t, strings.HasPrefix(contents, "// Copyright 2021 Example Authors.\n"), contents)
+		require.Contains(t, contents, "// Code generated by ASTHelperGen. DO NOT EDIT.")
+		require.NotContains(t, contents, "http://www.apache.org/licenses/LICENSE-2.0")
+		require.True(t, strings.HasSuffix(contents, "// end of generated code\n"), contents)
+	}
+}
+
+func TestMissingTypes(t *testing.T) {
+	existing := []byte(`package integration
+
+func VisitAST(in AST) error {
+	switch in := in.(type) {
+	case *Leaf:
+		return VisitRefOfLeaf(in)
+	case LeafSlice:
+		return VisitLeafSlice(in)
+	}
+	return nil
+}
+`)
+	generated := []byte(`package integration
+
+func VisitAST(in AST) error {
+	switch in := in.(type) {
+	case *Leaf:
+		return VisitRefOfLeaf(in)
+	case LeafSlice:
+		return VisitLeafSlice(in)
+	case *NewType:
+		return VisitRefOfNewType(in)
+	case AnotherType:
+		return VisitAnotherType(in)
+	}
+	return nil
+}
+`)
+
+	require.Equal(t, []string{"*NewType", "AnotherType"}, missingTypes(existing, generated))
+	require.Empty(t, missingTypes(generated, existing))
+	require.Empty(t, missingTypes([]byte("not go code"), generated))
+}
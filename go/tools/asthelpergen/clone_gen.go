@@ -36,8 +36,6 @@ var _ generator = (*cloneGen)(nil)
 
 func newCloneGen(pkgname string, exceptType string) *cloneGen {
 	file := jen.NewFile(pkgname)
-	file.HeaderComment(licenseFileHeader)
-	file.HeaderComment("Code generated by ASTHelperGen. DO NOT EDIT.")
 
 	return &cloneGen{
 		exceptType: exceptType,
@@ -33,8 +33,6 @@ var _ generator = (*equalsGen)(nil)
 
 func newEqualsGen(pkgname string) *equalsGen {
 	file := jen.NewFile(pkgname)
-	file.HeaderComment(licenseFileHeader)
-	file.HeaderComment("Code generated by ASTHelperGen. DO NOT EDIT.")
 
 	return &equalsGen{
 		file: file,
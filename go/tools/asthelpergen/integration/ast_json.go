@@ -0,0 +1,397 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by ASTHelperGen. DO NOT EDIT.
+
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// MarshalASTJSON returns the JSON representation of an AST. Every node is an object with
+// its type in the "_type" field followed by its struct fields, with their Go names.
+func MarshalASTJSON(node AST) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := jsonAST(&buf, node); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+func jsonValue(buf *bytes.Buffer, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	buf.Write(data)
+	return nil
+}
+func jsonAST(buf *bytes.Buffer, in AST) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	switch in := in.(type) {
+	case BasicType:
+		return jsonBasicType(buf, in)
+	case Bytes:
+		return jsonBytes(buf, in)
+	case InterfaceContainer:
+		return jsonInterfaceContainer(buf, in)
+	case InterfaceSlice:
+		return jsonInterfaceSlice(buf, in)
+	case *Leaf:
+		return jsonRefOfLeaf(buf, in)
+	case LeafSlice:
+		return jsonLeafSlice(buf, in)
+	case *NoCloneType:
+		return jsonRefOfNoCloneType(buf, in)
+	case *RefContainer:
+		return jsonRefOfRefContainer(buf, in)
+	case *RefSliceContainer:
+		return jsonRefOfRefSliceContainer(buf, in)
+	case *SubImpl:
+		return jsonRefOfSubImpl(buf, in)
+	case ValueContainer:
+		return jsonValueContainer(buf, in)
+	case ValueSliceContainer:
+		return jsonValueSliceContainer(buf, in)
+	case *BasicType:
+		if in == nil {
+			buf.WriteString("null")
+			return nil
+		}
+		return jsonBasicType(buf, *in)
+	case *Bytes:
+		if in == nil {
+			buf.WriteString("null")
+			return nil
+		}
+		return jsonBytes(buf, *in)
+	case *InterfaceContainer:
+		if in == nil {
+			buf.WriteString("null")
+			return nil
+		}
+		return jsonInterfaceContainer(buf, *in)
+	case *InterfaceSlice:
+		if in == nil {
+			buf.WriteString("null")
+			return nil
+		}
+		return jsonInterfaceSlice(buf, *in)
+	case *LeafSlice:
+		if in == nil {
+			buf.WriteString("null")
+			return nil
+		}
+		return jsonLeafSlice(buf, *in)
+	case *ValueContainer:
+		if in == nil {
+			buf.WriteString("null")
+			return nil
+		}
+		return jsonValueContainer(buf, *in)
+	case *ValueSliceContainer:
+		if in == nil {
+			buf.WriteString("null")
+			return nil
+		}
+		return jsonValueSliceContainer(buf, *in)
+	default:
+		return fmt.Errorf("unknown AST node type %T", in)
+	}
+}
+func jsonBytes(buf *bytes.Buffer, in Bytes) error {
+	buf.WriteString("{\"_type\":\"Bytes\",\"Value\":")
+	if err := jsonValue(buf, in); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonInterfaceContainer(buf *bytes.Buffer, in InterfaceContainer) error {
+	buf.WriteString("{\"_type\":\"InterfaceContainer\"")
+	buf.WriteString(",\"v\":")
+	if err := jsonValue(buf, in.v); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonInterfaceSlice(buf *bytes.Buffer, in InterfaceSlice) error {
+	buf.WriteByte('[')
+	for i, el := range in {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := jsonAST(buf, el); err != nil {
+			return err
+		}
+	}
+	buf.WriteByte(']')
+	return nil
+}
+func jsonRefOfLeaf(buf *bytes.Buffer, in *Leaf) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"Leaf\"")
+	buf.WriteString(",\"v\":")
+	if err := jsonValue(buf, in.v); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonLeafSlice(buf *bytes.Buffer, in LeafSlice) error {
+	buf.WriteByte('[')
+	for i, el := range in {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := jsonRefOfLeaf(buf, el); err != nil {
+			return err
+		}
+	}
+	buf.WriteByte(']')
+	return nil
+}
+func jsonRefOfNoCloneType(buf *bytes.Buffer, in *NoCloneType) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"NoCloneType\"")
+	buf.WriteString(",\"v\":")
+	if err := jsonValue(buf, in.v); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfRefContainer(buf *bytes.Buffer, in *RefContainer) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"RefContainer\"")
+	buf.WriteString(",\"ASTType\":")
+	if err := jsonAST(buf, in.ASTType); err != nil {
+		return err
+	}
+	buf.WriteString(",\"NotASTType\":")
+	if err := jsonValue(buf, in.NotASTType); err != nil {
+		return err
+	}
+	buf.WriteString(",\"ASTImplementationType\":")
+	if err := jsonRefOfLeaf(buf, in.ASTImplementationType); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfRefSliceContainer(buf *bytes.Buffer, in *RefSliceContainer) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"RefSliceContainer\"")
+	buf.WriteString(",\"ASTElements\":")
+	buf.WriteByte('[')
+	for i, el := range in.ASTElements {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := jsonAST(buf, el); err != nil {
+			return err
+		}
+	}
+	buf.WriteByte(']')
+	buf.WriteString(",\"NotASTElements\":")
+	if err := jsonValue(buf, in.NotASTElements); err != nil {
+		return err
+	}
+	buf.WriteString(",\"ASTImplementationElements\":")
+	buf.WriteByte('[')
+	for i, el := range in.ASTImplementationElements {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := jsonRefOfLeaf(buf, el); err != nil {
+			return err
+		}
+	}
+	buf.WriteByte(']')
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfSubImpl(buf *bytes.Buffer, in *SubImpl) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"SubImpl\"")
+	buf.WriteString(",\"inner\":")
+	if err := jsonSubIface(buf, in.inner); err != nil {
+		return err
+	}
+	buf.WriteString(",\"field\":")
+	if err := jsonValue(buf, in.field); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonValueContainer(buf *bytes.Buffer, in ValueContainer) error {
+	buf.WriteString("{\"_type\":\"ValueContainer\"")
+	buf.WriteString(",\"ASTType\":")
+	if err := jsonAST(buf, in.ASTType); err != nil {
+		return err
+	}
+	buf.WriteString(",\"NotASTType\":")
+	if err := jsonValue(buf, in.NotASTType); err != nil {
+		return err
+	}
+	buf.WriteString(",\"ASTImplementationType\":")
+	if err := jsonRefOfLeaf(buf, in.ASTImplementationType); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonValueSliceContainer(buf *bytes.Buffer, in ValueSliceContainer) error {
+	buf.WriteString("{\"_type\":\"ValueSliceContainer\"")
+	buf.WriteString(",\"ASTElements\":")
+	buf.WriteByte('[')
+	for i, el := range in.ASTElements {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := jsonAST(buf, el); err != nil {
+			return err
+		}
+	}
+	buf.WriteByte(']')
+	buf.WriteString(",\"NotASTElements\":")
+	if err := jsonValue(buf, in.NotASTElements); err != nil {
+		return err
+	}
+	buf.WriteString(",\"ASTImplementationElements\":")
+	buf.WriteByte('[')
+	for i, el := range in.ASTImplementationElements {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := jsonRefOfLeaf(buf, el); err != nil {
+			return err
+		}
+	}
+	buf.WriteByte(']')
+	buf.WriteByte('}')
+	return nil
+}
+func jsonSubIface(buf *bytes.Buffer, in SubIface) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	switch in := in.(type) {
+	case *SubImpl:
+		return jsonRefOfSubImpl(buf, in)
+	default:
+		return fmt.Errorf("unknown AST node type %T", in)
+	}
+}
+func jsonBasicType(buf *bytes.Buffer, in BasicType) error {
+	buf.WriteString("{\"_type\":\"BasicType\",\"Value\":")
+	if err := jsonValue(buf, in); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfInterfaceContainer(buf *bytes.Buffer, in *InterfaceContainer) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"InterfaceContainer\"")
+	buf.WriteString(",\"v\":")
+	if err := jsonValue(buf, in.v); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfValueContainer(buf *bytes.Buffer, in *ValueContainer) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"ValueContainer\"")
+	buf.WriteString(",\"ASTType\":")
+	if err := jsonAST(buf, in.ASTType); err != nil {
+		return err
+	}
+	buf.WriteString(",\"NotASTType\":")
+	if err := jsonValue(buf, in.NotASTType); err != nil {
+		return err
+	}
+	buf.WriteString(",\"ASTImplementationType\":")
+	if err := jsonRefOfLeaf(buf, in.ASTImplementationType); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfValueSliceContainer(buf *bytes.Buffer, in *ValueSliceContainer) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"ValueSliceContainer\"")
+	buf.WriteString(",\"ASTElements\":")
+	buf.WriteByte('[')
+	for i, el := range in.ASTElements {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := jsonAST(buf, el); err != nil {
+			return err
+		}
+	}
+	buf.WriteByte(']')
+	buf.WriteString(",\"NotASTElements\":")
+	if err := jsonValue(buf, in.NotASTElements); err != nil {
+		return err
+	}
+	buf.WriteString(",\"ASTImplementationElements\":")
+	buf.WriteByte('[')
+	for i, el := range in.ASTImplementationElements {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := jsonRefOfLeaf(buf, el); err != nil {
+			return err
+		}
+	}
+	buf.WriteByte(']')
+	buf.WriteByte('}')
+	return nil
+}
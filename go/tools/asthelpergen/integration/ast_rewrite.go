@@ -17,6 +17,8 @@ limitations under the License.
 
 package integration
 
+import "reflect"
+
 func (a *application) rewriteAST(parent AST, node AST, replacer replacerFunc) bool {
 	if node == nil {
 		return true
@@ -46,8 +48,12 @@ func (a *application) rewriteAST(parent AST, node AST, replacer replacerFunc) bo
 		return a.rewriteValueContainer(parent, node, replacer)
 	case ValueSliceContainer:
 		return a.rewriteValueSliceContainer(parent, node, replacer)
+	case *BasicType, *Bytes, *InterfaceContainer, *InterfaceSlice, *LeafSlice, *ValueContainer, *ValueSliceContainer:
+		// these nodes are only visited by value
+		return true
 	default:
-		// this should never happen
+		// this should never happen, unless the AST helpers are out of date
+		a.unknownNode(node)
 		return true
 	}
 }
@@ -55,48 +61,62 @@ func (a *application) rewriteBytes(parent AST, node Bytes, replacer replacerFunc
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*Bytes)(nil)).Elem()]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		kontinue := !a.pre(&a.cur)
+		kontinue := !pre(&a.cur)
 		if a.cur.revisit {
-			node = a.cur.node.(Bytes)
 			a.cur.revisit = false
-			return a.rewriteBytes(parent, node, replacer)
+			return a.rewriteAST(parent, a.cur.node, replacer)
 		}
 		if kontinue {
 			return true
 		}
 	}
-	if a.post != nil {
-		if a.pre == nil {
+	if post != nil {
+		if pre == nil {
 			a.cur.replacer = replacer
 			a.cur.parent = parent
 			a.cur.node = node
 		}
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
 	return true
 }
 func (a *application) rewriteInterfaceContainer(parent AST, node InterfaceContainer, replacer replacerFunc) bool {
-	if a.pre != nil {
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*InterfaceContainer)(nil)).Elem()]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteAST(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
-	if a.post != nil {
-		if a.pre == nil {
+	if post != nil {
+		if pre == nil {
 			a.cur.replacer = replacer
 			a.cur.parent = parent
 			a.cur.node = node
 		}
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -106,21 +126,29 @@ func (a *application) rewriteInterfaceSlice(parent AST, node InterfaceSlice, rep
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*InterfaceSlice)(nil)).Elem()]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		kontinue := !a.pre(&a.cur)
+		kontinue := !pre(&a.cur)
 		if a.cur.revisit {
-			node = a.cur.node.(InterfaceSlice)
 			a.cur.revisit = false
-			return a.rewriteInterfaceSlice(parent, node, replacer)
+			return a.rewriteAST(parent, a.cur.node, replacer)
 		}
 		if kontinue {
 			return true
 		}
 	}
 	for x, el := range node {
+		a.cur.enterField("", x)
 		if !a.rewriteAST(node, el, func(idx int) replacerFunc {
 			return func(newNode, parent AST) {
 				parent.(InterfaceSlice)[idx] = newNode.(AST)
@@ -128,12 +156,25 @@ func (a *application) rewriteInterfaceSlice(parent AST, node InterfaceSlice, rep
 		}(x)) {
 			return false
 		}
+		a.cur.leaveField()
+	}
+	if edits := a.cur.takeEdits(); edits != nil {
+		var result InterfaceSlice
+		rebuildSlice(edits, len(node), func(idx int, inserted AST) {
+			if idx < 0 {
+				result = append(result, inserted.(AST))
+			} else {
+				result = append(result, node[idx])
+			}
+		})
+		replacer(result, parent)
+		node = result
 	}
-	if a.post != nil {
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -143,21 +184,31 @@ func (a *application) rewriteRefOfLeaf(parent AST, node *Leaf, replacer replacer
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*Leaf)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteAST(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
-	if a.post != nil {
-		if a.pre == nil {
+	if post != nil {
+		if pre == nil {
 			a.cur.replacer = replacer
 			a.cur.parent = parent
 			a.cur.node = node
 		}
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -167,21 +218,29 @@ func (a *application) rewriteLeafSlice(parent AST, node LeafSlice, replacer repl
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*LeafSlice)(nil)).Elem()]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		kontinue := !a.pre(&a.cur)
+		kontinue := !pre(&a.cur)
 		if a.cur.revisit {
-			node = a.cur.node.(LeafSlice)
 			a.cur.revisit = false
-			return a.rewriteLeafSlice(parent, node, replacer)
+			return a.rewriteAST(parent, a.cur.node, replacer)
 		}
 		if kontinue {
 			return true
 		}
 	}
 	for x, el := range node {
+		a.cur.enterField("", x)
 		if !a.rewriteRefOfLeaf(node, el, func(idx int) replacerFunc {
 			return func(newNode, parent AST) {
 				parent.(LeafSlice)[idx] = newNode.(*Leaf)
@@ -189,12 +248,25 @@ func (a *application) rewriteLeafSlice(parent AST, node LeafSlice, replacer repl
 		}(x)) {
 			return false
 		}
+		a.cur.leaveField()
+	}
+	if edits := a.cur.takeEdits(); edits != nil {
+		var result LeafSlice
+		rebuildSlice(edits, len(node), func(idx int, inserted AST) {
+			if idx < 0 {
+				result = append(result, inserted.(*Leaf))
+			} else {
+				result = append(result, node[idx])
+			}
+		})
+		replacer(result, parent)
+		node = result
 	}
-	if a.post != nil {
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -204,21 +276,31 @@ func (a *application) rewriteRefOfNoCloneType(parent AST, node *NoCloneType, rep
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*NoCloneType)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteAST(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
-	if a.post != nil {
-		if a.pre == nil {
+	if post != nil {
+		if pre == nil {
 			a.cur.replacer = replacer
 			a.cur.parent = parent
 			a.cur.node = node
 		}
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -228,29 +310,46 @@ func (a *application) rewriteRefOfRefContainer(parent AST, node *RefContainer, r
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*RefContainer)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteAST(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	a.cur.enterField("ASTType", -1)
 	if !a.rewriteAST(node, node.ASTType, func(newNode, parent AST) {
 		parent.(*RefContainer).ASTType = newNode.(AST)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("ASTImplementationType", -1)
 	if !a.rewriteRefOfLeaf(node, node.ASTImplementationType, func(newNode, parent AST) {
 		parent.(*RefContainer).ASTImplementationType = newNode.(*Leaf)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -260,15 +359,29 @@ func (a *application) rewriteRefOfRefSliceContainer(parent AST, node *RefSliceCo
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*RefSliceContainer)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteAST(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
 	for x, el := range node.ASTElements {
+		a.cur.enterField("ASTElements", x)
 		if !a.rewriteAST(node, el, func(idx int) replacerFunc {
 			return func(newNode, parent AST) {
 				parent.(*RefSliceContainer).ASTElements[idx] = newNode.(AST)
@@ -276,8 +389,21 @@ func (a *application) rewriteRefOfRefSliceContainer(parent AST, node *RefSliceCo
 		}(x)) {
 			return false
 		}
+		a.cur.leaveField()
+	}
+	if edits := a.cur.takeEdits(); edits != nil {
+		var result []AST
+		rebuildSlice(edits, len(node.ASTElements), func(idx int, inserted AST) {
+			if idx < 0 {
+				result = append(result, inserted.(AST))
+			} else {
+				result = append(result, node.ASTElements[idx])
+			}
+		})
+		node.ASTElements = result
 	}
 	for x, el := range node.ASTImplementationElements {
+		a.cur.enterField("ASTImplementationElements", x)
 		if !a.rewriteRefOfLeaf(node, el, func(idx int) replacerFunc {
 			return func(newNode, parent AST) {
 				parent.(*RefSliceContainer).ASTImplementationElements[idx] = newNode.(*Leaf)
@@ -285,12 +411,24 @@ func (a *application) rewriteRefOfRefSliceContainer(parent AST, node *RefSliceCo
 		}(x)) {
 			return false
 		}
+		a.cur.leaveField()
+	}
+	if edits := a.cur.takeEdits(); edits != nil {
+		var result []*Leaf
+		rebuildSlice(edits, len(node.ASTImplementationElements), func(idx int, inserted AST) {
+			if idx < 0 {
+				result = append(result, inserted.(*Leaf))
+			} else {
+				result = append(result, node.ASTImplementationElements[idx])
+			}
+		})
+		node.ASTImplementationElements = result
 	}
-	if a.post != nil {
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -300,86 +438,144 @@ func (a *application) rewriteRefOfSubImpl(parent AST, node *SubImpl, replacer re
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*SubImpl)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteAST(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	outer := a.cur.enclosing
+	a.cur.enclosing = node
+	a.cur.enterField("inner", -1)
 	if !a.rewriteSubIface(node, node.inner, func(newNode, parent AST) {
 		parent.(*SubImpl).inner = newNode.(SubIface)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	a.cur.enclosing = outer
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
 	return true
 }
 func (a *application) rewriteValueContainer(parent AST, node ValueContainer, replacer replacerFunc) bool {
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*ValueContainer)(nil)).Elem()]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteAST(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	a.cur.enterField("ASTType", -1)
 	if !a.rewriteAST(node, node.ASTType, func(newNode, parent AST) {
 		panic("[BUG] tried to replace 'ASTType' on 'ValueContainer'")
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("ASTImplementationType", -1)
 	if !a.rewriteRefOfLeaf(node, node.ASTImplementationType, func(newNode, parent AST) {
 		panic("[BUG] tried to replace 'ASTImplementationType' on 'ValueContainer'")
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
 	return true
 }
 func (a *application) rewriteValueSliceContainer(parent AST, node ValueSliceContainer, replacer replacerFunc) bool {
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*ValueSliceContainer)(nil)).Elem()]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteAST(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
-	for _, el := range node.ASTElements {
+	for x, el := range node.ASTElements {
+		a.cur.enterField("ASTElements", x)
 		if !a.rewriteAST(node, el, func(newNode, parent AST) {
 			panic("[BUG] tried to replace 'ASTElements' on 'ValueSliceContainer'")
 		}) {
 			return false
 		}
+		a.cur.leaveField()
 	}
-	for _, el := range node.ASTImplementationElements {
+	if a.cur.takeEdits() != nil {
+		panic("[BUG] tried to insert or remove in 'ASTElements' on 'ValueSliceContainer'")
+	}
+	for x, el := range node.ASTImplementationElements {
+		a.cur.enterField("ASTImplementationElements", x)
 		if !a.rewriteRefOfLeaf(node, el, func(newNode, parent AST) {
 			panic("[BUG] tried to replace 'ASTImplementationElements' on 'ValueSliceContainer'")
 		}) {
 			return false
 		}
+		a.cur.leaveField()
 	}
-	if a.post != nil {
+	if a.cur.takeEdits() != nil {
+		panic("[BUG] tried to insert or remove in 'ASTImplementationElements' on 'ValueSliceContainer'")
+	}
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -393,26 +589,37 @@ func (a *application) rewriteSubIface(parent AST, node SubIface, replacer replac
 	case *SubImpl:
 		return a.rewriteRefOfSubImpl(parent, node, replacer)
 	default:
-		// this should never happen
+		// this should never happen, unless the AST helpers are out of date
+		a.unknownNode(node)
 		return true
 	}
 }
 func (a *application) rewriteBasicType(parent AST, node BasicType, replacer replacerFunc) bool {
-	if a.pre != nil {
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*BasicType)(nil)).Elem()]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteAST(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
-	if a.post != nil {
-		if a.pre == nil {
+	if post != nil {
+		if pre == nil {
 			a.cur.replacer = replacer
 			a.cur.parent = parent
 			a.cur.node = node
 		}
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -422,21 +629,31 @@ func (a *application) rewriteRefOfInterfaceContainer(parent AST, node *Interface
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*InterfaceContainer)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteAST(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
-	if a.post != nil {
-		if a.pre == nil {
+	if post != nil {
+		if pre == nil {
 			a.cur.replacer = replacer
 			a.cur.parent = parent
 			a.cur.node = node
 		}
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -446,29 +663,46 @@ func (a *application) rewriteRefOfValueContainer(parent AST, node *ValueContaine
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*ValueContainer)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteAST(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	a.cur.enterField("ASTType", -1)
 	if !a.rewriteAST(node, node.ASTType, func(newNode, parent AST) {
 		parent.(*ValueContainer).ASTType = newNode.(AST)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("ASTImplementationType", -1)
 	if !a.rewriteRefOfLeaf(node, node.ASTImplementationType, func(newNode, parent AST) {
 		parent.(*ValueContainer).ASTImplementationType = newNode.(*Leaf)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -478,15 +712,29 @@ func (a *application) rewriteRefOfValueSliceContainer(parent AST, node *ValueSli
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*ValueSliceContainer)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteAST(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
 	for x, el := range node.ASTElements {
+		a.cur.enterField("ASTElements", x)
 		if !a.rewriteAST(node, el, func(idx int) replacerFunc {
 			return func(newNode, parent AST) {
 				parent.(*ValueSliceContainer).ASTElements[idx] = newNode.(AST)
@@ -494,8 +742,21 @@ func (a *application) rewriteRefOfValueSliceContainer(parent AST, node *ValueSli
 		}(x)) {
 			return false
 		}
+		a.cur.leaveField()
+	}
+	if edits := a.cur.takeEdits(); edits != nil {
+		var result []AST
+		rebuildSlice(edits, len(node.ASTElements), func(idx int, inserted AST) {
+			if idx < 0 {
+				result = append(result, inserted.(AST))
+			} else {
+				result = append(result, node.ASTElements[idx])
+			}
+		})
+		node.ASTElements = result
 	}
 	for x, el := range node.ASTImplementationElements {
+		a.cur.enterField("ASTImplementationElements", x)
 		if !a.rewriteRefOfLeaf(node, el, func(idx int) replacerFunc {
 			return func(newNode, parent AST) {
 				parent.(*ValueSliceContainer).ASTImplementationElements[idx] = newNode.(*Leaf)
@@ -503,14 +764,107 @@ func (a *application) rewriteRefOfValueSliceContainer(parent AST, node *ValueSli
 		}(x)) {
 			return false
 		}
+		a.cur.leaveField()
 	}
-	if a.post != nil {
+	if edits := a.cur.takeEdits(); edits != nil {
+		var result []*Leaf
+		rebuildSlice(edits, len(node.ASTImplementationElements), func(idx int, inserted AST) {
+			if idx < 0 {
+				result = append(result, inserted.(*Leaf))
+			} else {
+				result = append(result, node.ASTImplementationElements[idx])
+			}
+		})
+		node.ASTImplementationElements = result
+	}
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
 	return true
 }
+
+// IsKnownType returns whether node has one of the types that the rewriter knows how to visit.
+// The rewriter panics when it finds a node of any other type, unless it has been configured
+// to skip it.
+func IsKnownType(node AST) bool {
+	switch node.(type) {
+	case BasicType, Bytes, InterfaceContainer, InterfaceSlice, *Leaf, LeafSlice, *NoCloneType, *RefContainer, *RefSliceContainer, *SubImpl, ValueContainer, ValueSliceContainer, *BasicType, *Bytes, *InterfaceContainer, *InterfaceSlice, *LeafSlice, *ValueContainer, *ValueSliceContainer:
+		return true
+	default:
+		return false
+	}
+}
+
+// TypedRewriter rewrites the AST like Rewrite, but instead of calling the same pre and post
+// functions for every node, it calls the handlers that were registered for the concrete type
+// of the node. The generated code already knows the type of every node it visits, so the
+// handlers don't need to switch on it again. The nodes without handlers are still traversed.
+type TypedRewriter struct {
+	handlers map[reflect.Type]typedHandlers
+}
+
+// typedHandlers are the handlers registered in a TypedRewriter for one type
+type typedHandlers struct {
+	pre, post ApplyFunc
+}
+
+// NewTypedRewriter returns a TypedRewriter without any handlers
+func NewTypedRewriter() *TypedRewriter {
+	return &TypedRewriter{handlers: map[reflect.Type]typedHandlers{}}
+}
+
+// Pre registers f to be called before the children of every node with the same concrete type as node,
+// replacing any handler that was registered for that type before. Only the type of node is used,
+// so it can be a nil pointer. f follows the same rules as the pre function of Rewrite.
+// It returns the TypedRewriter, so that the calls can be chained.
+func (r *TypedRewriter) Pre(node AST, f ApplyFunc) *TypedRewriter {
+	t := reflect.TypeOf(node)
+	h := r.handlers[t]
+	h.pre = f
+	r.handlers[t] = h
+	return r
+}
+
+// Post registers f to be called after the children of every node with the same concrete type as node,
+// replacing any handler that was registered for that type before. Only the type of node is used,
+// so it can be a nil pointer. f follows the same rules as the post function of Rewrite.
+// It returns the TypedRewriter, so that the calls can be chained.
+func (r *TypedRewriter) Post(node AST, f ApplyFunc) *TypedRewriter {
+	t := reflect.TypeOf(node)
+	h := r.handlers[t]
+	h.post = f
+	r.handlers[t] = h
+	return r
+}
+
+// ParentIfRefContainer returns the parent of the current node if it is a *RefContainer
+func (c *Cursor) ParentIfRefContainer() (*RefContainer, bool) {
+	parent, ok := c.parent.(*RefContainer)
+	return parent, ok
+}
+
+// ParentIfInterfaceSlice returns the parent of the current node if it is a InterfaceSlice
+func (c *Cursor) ParentIfInterfaceSlice() (InterfaceSlice, bool) {
+	parent, ok := c.parent.(InterfaceSlice)
+	return parent, ok
+}
+
+// EnclosingSubIface returns the nearest ancestor of the current node that is a SubIface,
+// or nil if there is none. A SubIface does not enclose itself, so when the current node
+// is a SubIface, this returns the SubIface that contains it, if any.
+func (c *Cursor) EnclosingSubIface() SubIface {
+	enclosing, _ := c.enclosing.(SubIface)
+	return enclosing
+}
+
+// isEnclosing returns whether node is a SubIface, which the Cursor tracks as the enclosing
+// node of its children
+func isEnclosing(node AST) bool {
+	_, ok := node.(SubIface)
+	return ok
+}
@@ -0,0 +1,162 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package integration
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// cursorTrace returns pre and post functions that record every call with the position of the node
+// and its enclosing node
+func cursorTrace(trace *[]string) (pre, post ApplyFunc) {
+	record := func(prefix string, cursor *Cursor) {
+		*trace = append(*trace, fmt.Sprintf("%s%v:%s[%d] in %p", prefix, cursor.Node(), cursor.FieldName(), cursor.FieldIndex(), cursor.EnclosingSubIface()))
+	}
+	pre = func(cursor *Cursor) bool {
+		record("", cursor)
+		return true
+	}
+	post = func(cursor *Cursor) bool {
+		record("/", cursor)
+		return true
+	}
+	return
+}
+
+func TestRewriteIterativeSameOrder(t *testing.T) {
+	asts := []AST{
+		typedRewriteTestAST(),
+		ValueContainer{ASTType: &RefSliceContainer{ASTElements: []AST{&Leaf{1}, InterfaceSlice{&Leaf{3}}}}},
+		&ValueSliceContainer{ASTElements: []AST{&SubImpl{inner: &SubImpl{}}, nil}, ASTImplementationElements: LeafSlice{nil}},
+		&RefContainer{ASTType: (*Leaf)(nil)},
+	}
+
+	for _, ast := range asts {
+		var expected, got []string
+		pre, post := cursorTrace(&expected)
+		Rewrite(ast, pre, post)
+		pre, post = cursorTrace(&got)
+		RewriteIterative(ast, pre, post)
+		assert.Equal(t, expected, got)
+
+		expected, got = nil, nil
+		pre, _ = cursorTrace(&expected)
+		Rewrite(ast, pre, nil)
+		pre, _ = cursorTrace(&got)
+		RewriteIterative(ast, pre, nil)
+		assert.Equal(t, expected, got)
+	}
+}
+
+func TestRewriteIterativeReplace(t *testing.T) {
+	ast := &RefSliceContainer{
+		ASTElements:               []AST{&Leaf{1}, &RefContainer{ASTType: &Leaf{2}}},
+		ASTImplementationElements: []*Leaf{{3}, {4}},
+	}
+
+	result := RewriteIterative(ast, rewriteLeaf(2, 42), nil)
+	result = RewriteIterative(result, rewriteLeaf(3, 88), nil)
+	result = RewriteIterative(result, func(cursor *Cursor) bool {
+		if _, isRoot := cursor.Node().(*RefSliceContainer); isRoot {
+			cursor.Replace(InterfaceSlice{cursor.Node()})
+		}
+		return true
+	}, nil)
+
+	assert.Equal(t, InterfaceSlice{&RefSliceContainer{
+		ASTElements:               []AST{&Leaf{1}, &RefContainer{ASTType: &Leaf{42}}},
+		ASTImplementationElements: []*Leaf{{88}, {4}},
+	}}, result)
+
+	require.PanicsWithValue(t, "[BUG] tried to replace 'ASTImplementationType' on 'ValueContainer'", func() {
+		RewriteIterative(ValueContainer{ASTImplementationType: &Leaf{2}}, rewriteLeaf(2, 10), nil)
+	})
+}
+
+func TestRewriteIterativeRevisit(t *testing.T) {
+	leaf1 := &Leaf{1}
+	leaf2 := &Leaf{2}
+	replacement := &RefContainer{ASTType: leaf2}
+	container := &RefContainer{ASTType: leaf1}
+
+	tv := &rewriteTestVisitor{}
+	result := RewriteIterative(container, func(cursor *Cursor) bool {
+		tv.pre(cursor)
+		if cursor.Node() == leaf1 {
+			cursor.ReplaceAndRevisit(replacement)
+		}
+		return true
+	}, tv.post)
+
+	assert.Equal(t, replacement, result.(*RefContainer).ASTType)
+	tv.assertEquals(t, []step{
+		Pre{container},
+		Pre{leaf1},
+		Pre{replacement},
+		Pre{leaf2},
+		Post{leaf2},
+		Post{replacement},
+		Post{container},
+	})
+}
+
+func TestRewriteIterativeAbort(t *testing.T) {
+	var seen []int
+	RewriteIterative(typedRewriteTestAST(), nil, func(cursor *Cursor) bool {
+		leaf, ok := cursor.Node().(*Leaf)
+		if !ok {
+			return true
+		}
+		seen = append(seen, leaf.v)
+		return leaf.v != 3
+	})
+
+	assert.Equal(t, []int{1, 2, 3}, seen)
+}
+
+func TestRewriteIterativeSliceEditsPanic(t *testing.T) {
+	require.PanicsWithValue(t, "InsertBefore, InsertAfter and Remove are not supported by RewriteIterative", func() {
+		RewriteIterative(InterfaceSlice{&Leaf{1}}, func(cursor *Cursor) bool {
+			if _, ok := cursor.Node().(*Leaf); ok {
+				cursor.Remove()
+			}
+			return true
+		}, nil)
+	})
+}
+
+func TestRewriteIterativeDeepAST(t *testing.T) {
+	var ast AST = &Leaf{0}
+	for i := 0; i < 1000000; i++ {
+		ast = &RefContainer{ASTType: ast}
+	}
+
+	result := RewriteIterative(ast, rewriteLeaf(0, 1), nil)
+
+	for node := result; ; {
+		container, ok := node.(*RefContainer)
+		if !ok {
+			assert.Equal(t, &Leaf{1}, node)
+			break
+		}
+		node = container.ASTType
+	}
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package integration
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshalJSON(t *testing.T) {
+	var nilLeaf *Leaf
+	tcases := []struct {
+		name     string
+		in       AST
+		expected string
+	}{{
+		name:     "nil",
+		in:       nil,
+		expected: `null`,
+	}, {
+		name:     "leaf",
+		in:       &Leaf{1},
+		expected: `{"_type":"Leaf","v":1}`,
+	}, {
+		name:     "nil leaf",
+		in:       nilLeaf,
+		expected: `null`,
+	}, {
+		name:     "ref container",
+		in:       &RefContainer{ASTType: &Leaf{1}, NotASTType: 2},
+		expected: `{"_type":"RefContainer","ASTType":{"_type":"Leaf","v":1},"NotASTType":2,"ASTImplementationType":null}`,
+	}, {
+		name:     "value container",
+		in:       ValueContainer{ASTType: BasicType(3), ASTImplementationType: &Leaf{4}},
+		expected: `{"_type":"ValueContainer","ASTType":{"_type":"BasicType","Value":3},"NotASTType":0,"ASTImplementationType":{"_type":"Leaf","v":4}}`,
+	}, {
+		name:     "pointer to value container",
+		in:       &ValueContainer{NotASTType: 1},
+		expected: `{"_type":"ValueContainer","ASTType":null,"NotASTType":1,"ASTImplementationType":null}`,
+	}, {
+		name: "slice containers",
+		in: &RefSliceContainer{
+			ASTElements:               []AST{InterfaceSlice{&Leaf{1}}, Bytes("a")},
+			NotASTElements:            []int{1, 2},
+			ASTImplementationElements: nil,
+		},
+		expected: `{"_type":"RefSliceContainer","ASTElements":[[{"_type":"Leaf","v":1}],{"_type":"Bytes","Value":"YQ=="}],"NotASTElements":[1,2],"ASTImplementationElements":[]}`,
+	}, {
+		name:     "leaf slice",
+		in:       LeafSlice{&Leaf{1}, nil},
+		expected: `[{"_type":"Leaf","v":1},null]`,
+	}, {
+		name:     "sub interface",
+		in:       &SubImpl{inner: &SubImpl{}},
+		expected: `{"_type":"SubImpl","inner":{"_type":"SubImpl","inner":null,"field":null},"field":null}`,
+	}}
+
+	for _, tcase := range tcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			out, err := MarshalASTJSON(tcase.in)
+			require.NoError(t, err)
+			assert.Equal(t, tcase.expected, string(out))
+			assert.True(t, json.Valid(out))
+		})
+	}
+}
+
+func TestMarshalJSONUnsupportedValue(t *testing.T) {
+	_, err := MarshalASTJSON(InterfaceContainer{v: func() {}})
+	require.Error(t, err)
+}
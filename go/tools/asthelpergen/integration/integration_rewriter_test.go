@@ -204,6 +204,34 @@ func TestRewriteAndRevisitInterfaceSlice(t *testing.T) {
 	})
 }
 
+func TestRewriteAndRevisitRefContainer(t *testing.T) {
+	leaf1 := &Leaf{1}
+	leaf2 := &Leaf{2}
+	replacement := &RefContainer{ASTType: leaf2}
+	container := &RefContainer{ASTType: leaf1}
+
+	tv := &rewriteTestVisitor{}
+
+	result := Rewrite(container, func(cursor *Cursor) bool {
+		tv.pre(cursor)
+		if cursor.Node() == leaf1 {
+			cursor.ReplaceAndRevisit(replacement)
+		}
+		return true
+	}, tv.post)
+
+	assert.Equal(t, replacement, result.(*RefContainer).ASTType)
+	tv.assertEquals(t, []step{
+		Pre{container},
+		Pre{leaf1},
+		Pre{replacement},
+		Pre{leaf2},
+		Post{leaf2},
+		Post{replacement},
+		Post{container},
+	})
+}
+
 func TestRewriteVisitRefContainerReplace(t *testing.T) {
 	ast := &RefContainer{
 		ASTType:               &RefContainer{NotASTType: 12},
@@ -391,3 +419,124 @@ func (tv *rewriteTestVisitor) assertEquals(t *testing.T, expected []step) {
 	}
 
 }
+
+func TestRewriteFieldName(t *testing.T) {
+	leaf1 := &Leaf{1}
+	leaf2 := &Leaf{2}
+	leaf3 := &Leaf{3}
+	leaf4 := &Leaf{4}
+	slice := InterfaceSlice{leaf3, leaf4}
+	container := &RefSliceContainer{ASTElements: []AST{leaf1, slice}, ASTImplementationElements: []*Leaf{leaf2}}
+	ast := ValueContainer{ASTType: container}
+
+	var fields []string
+	Rewrite(ast, func(cursor *Cursor) bool {
+		fields = append(fields, fmt.Sprintf("%s:%s[%d]", cursor.Node().String(), cursor.FieldName(), cursor.FieldIndex()))
+		return true
+	}, func(cursor *Cursor) bool {
+		fields = append(fields, fmt.Sprintf("/%s:%s[%d]", cursor.Node().String(), cursor.FieldName(), cursor.FieldIndex()))
+		return true
+	})
+
+	assert.Equal(t, []string{
+		ast.String() + ":[-1]",
+		container.String() + ":ASTType[-1]",
+		"Leaf(1):ASTElements[0]",
+		"/Leaf(1):ASTElements[0]",
+		slice.String() + ":ASTElements[1]",
+		"Leaf(3):[0]",
+		"/Leaf(3):[0]",
+		"Leaf(4):[1]",
+		"/Leaf(4):[1]",
+		"/" + slice.String() + ":ASTElements[1]",
+		"Leaf(2):ASTImplementationElements[0]",
+		"/Leaf(2):ASTImplementationElements[0]",
+		"/" + container.String() + ":ASTType[-1]",
+		"/" + ast.String() + ":[-1]",
+	}, fields)
+}
+
+func TestRewriteSliceEdits(t *testing.T) {
+	leaf1 := &Leaf{1}
+	leaf2 := &Leaf{2}
+	leaf3 := &Leaf{3}
+	inner := InterfaceSlice{leaf2, leaf3}
+	container := &RefSliceContainer{
+		ASTElements:               []AST{leaf1, inner},
+		ASTImplementationElements: []*Leaf{leaf1, leaf2, leaf3},
+	}
+
+	result := Rewrite(container, func(cursor *Cursor) bool {
+		leaf, ok := cursor.Node().(*Leaf)
+		if !ok {
+			return true
+		}
+		switch leaf.v {
+		case 1:
+			cursor.InsertBefore(&Leaf{0})
+		case 2:
+			cursor.Remove()
+		case 3:
+			cursor.InsertAfter(&Leaf{4})
+		}
+		return true
+	}, nil)
+
+	assert.Equal(t, &RefSliceContainer{
+		ASTElements:               []AST{&Leaf{0}, leaf1, InterfaceSlice{leaf3, &Leaf{4}}},
+		ASTImplementationElements: []*Leaf{{0}, leaf1, leaf3, {4}},
+	}, result)
+}
+
+func TestRewriteSliceEditsOnValueType(t *testing.T) {
+	container := ValueSliceContainer{ASTImplementationElements: []*Leaf{{1}}}
+
+	defer func() {
+		require.Equal(t, "[BUG] tried to insert or remove in 'ASTImplementationElements' on 'ValueSliceContainer'", recover())
+	}()
+	Rewrite(container, func(cursor *Cursor) bool {
+		if _, ok := cursor.Node().(*Leaf); ok {
+			cursor.Remove()
+		}
+		return true
+	}, nil)
+}
+
+func TestRewriteParentAccessors(t *testing.T) {
+	leaf1 := &Leaf{1}
+	leaf2 := &Leaf{2}
+	container := &RefContainer{ASTType: InterfaceSlice{leaf1}, ASTImplementationType: leaf2}
+
+	var parents []string
+	Rewrite(container, func(cursor *Cursor) bool {
+		if _, ok := cursor.ParentIfRefContainer(); ok {
+			parents = append(parents, "RefContainer:"+cursor.Node().String())
+		}
+		if _, ok := cursor.ParentIfInterfaceSlice(); ok {
+			parents = append(parents, "InterfaceSlice:"+cursor.Node().String())
+		}
+		return true
+	}, nil)
+
+	assert.Equal(t, []string{"RefContainer:[Leaf(1)]", "InterfaceSlice:Leaf(1)", "RefContainer:Leaf(2)"}, parents)
+}
+
+func TestRewriteEnclosing(t *testing.T) {
+	inner := &SubImpl{}
+	outer := &SubImpl{inner: inner}
+	container := &RefContainer{ASTType: outer}
+
+	enclosing := map[AST]SubIface{}
+	Rewrite(container, func(cursor *Cursor) bool {
+		enclosing[cursor.Node()] = cursor.EnclosingSubIface()
+		return true
+	}, func(cursor *Cursor) bool {
+		assert.Equal(t, enclosing[cursor.Node()], cursor.EnclosingSubIface(), "pre and post must see the same enclosing node")
+		return true
+	})
+
+	require.Len(t, enclosing, 3)
+	assert.Nil(t, enclosing[container])
+	assert.Nil(t, enclosing[outer])
+	assert.Same(t, outer, enclosing[inner])
+}
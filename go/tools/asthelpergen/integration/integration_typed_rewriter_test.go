@@ -0,0 +1,170 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package integration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func typedRewriteTestAST() AST {
+	return &RefSliceContainer{
+		ASTElements: []AST{
+			&Leaf{1},
+			InterfaceSlice{&Leaf{2}, &RefContainer{ASTType: &Leaf{3}, ASTImplementationType: &Leaf{4}}},
+			ValueContainer{ASTType: InterfaceSlice{&Leaf{5}}, ASTImplementationType: &Leaf{6}},
+			LeafSlice{&Leaf{7}, nil, &Leaf{8}},
+			BasicType(9),
+			Bytes("not a leaf"),
+			nil,
+		},
+		ASTImplementationElements: []*Leaf{{10}, {11}},
+	}
+}
+
+// filteredRewrite returns the steps of the generic Rewrite for the nodes that match the filter
+func filteredRewrite(node AST, filter func(AST) bool) []step {
+	tv := &rewriteTestVisitor{}
+	_ = Rewrite(node, func(cursor *Cursor) bool {
+		if filter(cursor.Node()) {
+			return tv.pre(cursor)
+		}
+		return true
+	}, func(cursor *Cursor) bool {
+		if filter(cursor.Node()) {
+			return tv.post(cursor)
+		}
+		return true
+	})
+	return tv.walk
+}
+
+func TestWalkLeaf(t *testing.T) {
+	ast := typedRewriteTestAST()
+	expected := filteredRewrite(ast, func(node AST) bool {
+		leaf, ok := node.(*Leaf)
+		return ok && leaf != nil
+	})
+
+	var walk []step
+	WalkLeaf(ast, func(leaf *Leaf) bool {
+		walk = append(walk, Pre{leaf})
+		return true
+	}, func(leaf *Leaf) bool {
+		walk = append(walk, Post{leaf})
+		return true
+	})
+
+	assert.Len(t, walk, 20)
+	assert.Equal(t, expected, walk)
+}
+
+func TestWalkInterfaceSlice(t *testing.T) {
+	ast := typedRewriteTestAST()
+	expected := filteredRewrite(ast, func(node AST) bool {
+		_, ok := node.(InterfaceSlice)
+		return ok
+	})
+
+	var walk []step
+	WalkInterfaceSlice(ast, func(slice InterfaceSlice) bool {
+		walk = append(walk, Pre{slice})
+		return true
+	}, func(slice InterfaceSlice) bool {
+		walk = append(walk, Post{slice})
+		return true
+	})
+
+	assert.Len(t, walk, 4)
+	assert.Equal(t, expected, walk)
+}
+
+func TestWalkSkipChildren(t *testing.T) {
+	inner := InterfaceSlice{&Leaf{2}}
+	ast := InterfaceSlice{&Leaf{1}, inner}
+
+	var walk []step
+	WalkInterfaceSlice(ast, func(slice InterfaceSlice) bool {
+		walk = append(walk, Pre{slice})
+		return len(slice) != 1
+	}, func(slice InterfaceSlice) bool {
+		walk = append(walk, Post{slice})
+		return true
+	})
+
+	assert.Equal(t, []step{Pre{ast}, Pre{inner}, Post{ast}}, walk)
+}
+
+func TestWalkAbort(t *testing.T) {
+	var seen []int
+	WalkLeaf(typedRewriteTestAST(), nil, func(leaf *Leaf) bool {
+		seen = append(seen, leaf.v)
+		return leaf.v != 3
+	})
+
+	assert.Equal(t, []int{1, 2, 3}, seen)
+}
+
+func TestTypedRewriter(t *testing.T) {
+	ast := typedRewriteTestAST()
+	expected := filteredRewrite(ast, func(node AST) bool {
+		switch node := node.(type) {
+		case *Leaf:
+			return node != nil
+		case InterfaceSlice, BasicType:
+			return true
+		}
+		return false
+	})
+
+	tv := &rewriteTestVisitor{}
+	NewTypedRewriter().
+		Pre((*Leaf)(nil), tv.pre).
+		Post((*Leaf)(nil), tv.post).
+		Pre(InterfaceSlice(nil), tv.pre).
+		Post(InterfaceSlice(nil), tv.post).
+		Pre(BasicType(0), tv.pre).
+		Post(BasicType(0), tv.post).
+		Rewrite(ast)
+
+	assert.Len(t, tv.walk, 26)
+	assert.Equal(t, expected, tv.walk)
+}
+
+func TestTypedRewriterReplace(t *testing.T) {
+	ast := InterfaceSlice{&Leaf{1}, BasicType(2), &Leaf{3}}
+
+	var posts []AST
+	result := NewTypedRewriter().
+		Pre((*Leaf)(nil), func(cursor *Cursor) bool {
+			leaf := cursor.Node().(*Leaf)
+			if leaf.v == 3 {
+				cursor.Replace(BasicType(30))
+			}
+			return true
+		}).
+		Post(InterfaceSlice(nil), func(cursor *Cursor) bool {
+			posts = append(posts, cursor.Node())
+			return true
+		}).
+		Rewrite(ast)
+
+	expected := InterfaceSlice{&Leaf{1}, BasicType(2), BasicType(30)}
+	assert.Equal(t, expected, result)
+	assert.Equal(t, []AST{expected}, posts)
+}
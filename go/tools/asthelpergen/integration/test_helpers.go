@@ -17,6 +17,7 @@ limitations under the License.
 package integration
 
 import (
+	"fmt"
 	"strings"
 )
 
@@ -49,6 +50,21 @@ type Cursor struct {
 	node     AST
 	// marks that the node has been replaced, and the new node should be visited
 	revisit bool
+
+	// fields is the stack of the fields that lead from the root to the current node
+	fields []cursorField
+
+	// edits are the insertions and removals in slices that haven't been applied yet
+	edits []sliceEdit
+
+	// enclosing is the nearest ancestor of the current node that implements the
+	// interface tracked by the generated rewriter
+	enclosing AST
+}
+
+type cursorField struct {
+	name  string
+	index int
 }
 
 // Node returns the current Node.
@@ -57,6 +73,132 @@ func (c *Cursor) Node() AST { return c.node }
 // Parent returns the parent of the current Node.
 func (c *Cursor) Parent() AST { return c.parent }
 
+// FieldName returns the name of the field of the parent that contains the current Node.
+func (c *Cursor) FieldName() string {
+	if len(c.fields) == 0 {
+		return ""
+	}
+	return c.fields[len(c.fields)-1].name
+}
+
+// FieldIndex returns the index of the current Node in its parent's slice, or -1 if
+// the current Node is not an element of a slice.
+func (c *Cursor) FieldIndex() int {
+	if len(c.fields) == 0 {
+		return -1
+	}
+	return c.fields[len(c.fields)-1].index
+}
+
+// InsertBefore inserts newNode before the current Node in the slice that contains it.
+// If the current Node is not an element of a slice, InsertBefore panics. The new node
+// is not visited by Rewrite, and the slice is only modified after all its elements
+// have been visited, so FieldIndex keeps returning the original indexes.
+func (c *Cursor) InsertBefore(newNode AST) {
+	c.edit(editInsertBefore, newNode)
+}
+
+// InsertAfter inserts newNode after the current Node in the slice that contains it.
+// The same rules as for InsertBefore apply.
+func (c *Cursor) InsertAfter(newNode AST) {
+	c.edit(editInsertAfter, newNode)
+}
+
+// Remove removes the current Node from the slice that contains it. The children of the
+// Node are still visited unless pre returns false. The same rules as for InsertBefore apply.
+func (c *Cursor) Remove() {
+	c.edit(editRemove, nil)
+}
+
+type editOp int
+
+const (
+	editInsertBefore editOp = iota
+	editInsertAfter
+	editRemove
+)
+
+type sliceEdit struct {
+	depth int
+	index int
+	op    editOp
+	node  AST
+}
+
+func (c *Cursor) edit(op editOp, node AST) {
+	index := c.FieldIndex()
+	if index < 0 {
+		panic("the current node is not an element of a slice")
+	}
+	c.edits = append(c.edits, sliceEdit{depth: len(c.fields), index: index, op: op, node: node})
+}
+
+// takeEdits is called by the generated code after visiting all the elements of a slice,
+// and returns the edits that must be applied to that slice. The edits of any slices
+// deeper in the AST have already been taken, so they're always at the end of the list.
+// The returned slice is only valid until the next edit.
+func (c *Cursor) takeEdits() []sliceEdit {
+	if len(c.edits) == 0 {
+		return nil
+	}
+	i := len(c.edits)
+	for i > 0 && c.edits[i-1].depth > len(c.fields) {
+		i--
+	}
+	if i == len(c.edits) {
+		return nil
+	}
+	edits := c.edits[i:]
+	c.edits = c.edits[:i]
+	return edits
+}
+
+// rebuildSlice is called by the generated code to apply edits to a slice of length n.
+// It calls emit for every element of the resulting slice, in order: with the index of
+// the element in the original slice, or with -1 and the node that was inserted.
+func rebuildSlice(edits []sliceEdit, n int, emit func(idx int, inserted AST)) {
+	for idx := 0; idx < n; idx++ {
+		removed := false
+		for _, e := range edits {
+			if e.index == idx && e.op == editInsertBefore {
+				emit(-1, e.node)
+			}
+			if e.index == idx && e.op == editRemove {
+				removed = true
+			}
+		}
+		if !removed {
+			emit(idx, nil)
+		}
+		for _, e := range edits {
+			if e.index == idx && e.op == editInsertAfter {
+				emit(-1, e.node)
+			}
+		}
+	}
+}
+
+// enterField is called by the generated code before visiting a child of the current node
+func (c *Cursor) enterField(name string, index int) {
+	c.fields = append(c.fields, cursorField{name, index})
+}
+
+// leaveField is called by the generated code after visiting a child of the current node
+func (c *Cursor) leaveField() {
+	c.fields = c.fields[:len(c.fields)-1]
+}
+
+// tooDeep is called by the generated code before visiting the children of a node, and
+// returns whether the rewrite must be aborted because the node is deeper than maxDepth
+func (a *application) tooDeep() bool {
+	return a.maxDepth > 0 && len(a.cur.fields) > a.maxDepth
+}
+
+// unknownNode is called by the generated code when it finds a node whose type it doesn't know
+func (a *application) unknownNode(node AST) {
+	panic(fmt.Sprintf("unknown AST node type %T", node))
+}
+
 // Replace replaces the current node in the parent field with this new object. The user needs to make sure to not
 // replace the object with something of the wrong type, or the visitor will panic.
 func (c *Cursor) Replace(newNode AST) {
@@ -66,16 +208,11 @@ func (c *Cursor) Replace(newNode AST) {
 
 // ReplaceAndRevisit replaces the current node in the parent field with this new object.
 // When used, this will abort the visitation of the current node - no post or children visited,
-// and the new node visited.
+// and the new node visited, starting with a new call to pre.
+// It can only be called from pre. The new node is visited as if it had been there all along, so
+// if pre keeps replacing the nodes it returns with new ones, the rewrite never terminates:
+// pre must eventually stop producing nodes that it wants to replace.
 func (c *Cursor) ReplaceAndRevisit(newNode AST) {
-	switch newNode.(type) {
-	case InterfaceSlice:
-	default:
-		// We need to add support to the generated code for when to look at the revisit flag. At the moment it is only
-		// there for slices of AST implementations
-		panic("no support added for this type yet")
-	}
-
 	c.replacer(newNode, c.parent)
 	c.node = newNode
 	c.revisit = true
@@ -98,3 +235,133 @@ func Rewrite(node AST, pre, post ApplyFunc) AST {
 
 	return outer.AST
 }
+
+// Rewrite traverses the AST like the Rewrite function, calling the handlers registered for the type of every node
+func (r *TypedRewriter) Rewrite(node AST) AST {
+	outer := &struct{ AST }{node}
+
+	a := &application{
+		typed: r,
+	}
+
+	a.rewriteAST(outer, node, func(newNode, parent AST) {
+		outer.AST = newNode
+	})
+
+	return outer.AST
+}
+
+// RewriteIterative works like Rewrite, but it walks the AST with an explicit stack instead
+// of recursing once for every level of the AST, so it can rewrite ASTs of any depth without
+// risking a stack overflow. pre and post are called in the same order as in Rewrite, and
+// Replace and ReplaceAndRevisit work the same way. InsertBefore, InsertAfter and Remove
+// are not supported, and panic when the rewrite continues after them.
+// Since the children of a node are collected right after pre is called for it, changes
+// to the fields of a node made while visiting its children are not seen by the walk.
+func RewriteIterative(node AST, pre, post ApplyFunc) AST {
+	outer := &struct{ AST }{node}
+
+	a := &application{
+		pre:  pre,
+		post: post,
+	}
+
+	a.iterate(outer, node, func(newNode, parent AST) {
+		outer.AST = newNode
+	})
+
+	return outer.AST
+}
+
+// iterFrame is an entry in the stack of RewriteIterative: a node that has to be visited,
+// or one whose children have been visited and that is waiting for post to be called
+type iterFrame struct {
+	parent   AST
+	node     AST
+	replacer replacerFunc
+	field    cursorField
+	// depth is the number of fields that lead from the root to the node
+	depth int
+	post  bool
+	// enclosing is the enclosing node of the node, as returned by the Cursor
+	enclosing AST
+}
+
+func (a *application) iterate(parent, node AST, replacer replacerFunc) {
+	stack := []iterFrame{{parent: parent, node: node, replacer: replacer}}
+	var children []iterFrame
+
+	for len(stack) > 0 {
+		f := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		// the frames are visited depth-first, so the fields of the ancestors of the node
+		// are already in the Cursor, and only the innermost one needs to be set
+		if f.depth == 0 {
+			a.cur.fields = a.cur.fields[:0]
+		} else {
+			a.cur.fields = append(a.cur.fields[:f.depth-1], f.field)
+		}
+		a.cur.parent = f.parent
+		a.cur.node = f.node
+		a.cur.replacer = f.replacer
+		a.cur.enclosing = f.enclosing
+
+		if f.post {
+			if !a.post(&a.cur) {
+				return
+			}
+			a.checkNoEdits()
+			continue
+		}
+
+		if a.skipNode(f.node) {
+			continue
+		}
+		if a.pre != nil {
+			kontinue := !a.pre(&a.cur)
+			a.checkNoEdits()
+			if a.cur.revisit {
+				a.cur.revisit = false
+				f.node = a.cur.node
+				stack = append(stack, f)
+				continue
+			}
+			if kontinue {
+				continue
+			}
+		}
+
+		if a.post != nil {
+			f.post = true
+			stack = append(stack, f)
+		}
+
+		enclosing := f.enclosing
+		if isEnclosing(f.node) {
+			enclosing = f.node
+		}
+
+		// the children are pushed in reverse, so they're popped in order
+		children = children[:0]
+		a.iterChildren(f.node, func(child AST, name string, index int, replacer replacerFunc) {
+			children = append(children, iterFrame{
+				parent:    f.node,
+				node:      child,
+				replacer:  replacer,
+				field:     cursorField{name, index},
+				depth:     f.depth + 1,
+				enclosing: enclosing,
+			})
+		})
+		for i := len(children) - 1; i >= 0; i-- {
+			stack = append(stack, children[i])
+		}
+	}
+}
+
+func (a *application) checkNoEdits() {
+	if len(a.cur.edits) > 0 {
+		panic("InsertBefore, InsertAfter and Remove are not supported by RewriteIterative")
+	}
+}
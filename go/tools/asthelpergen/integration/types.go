@@ -26,7 +26,7 @@ import (
 These types are used to test the rewriter generator against these types.
 To recreate them, just run:
 
-go run go/tools/asthelpergen -in ./go/tools/asthelpergen/integration -iface vitess.io/vitess/go/tools/asthelpergen/integration.AST -except "*NoCloneType"
+go run go/tools/asthelpergen/main -in ./go/tools/asthelpergen/integration -iface vitess.io/vitess/go/tools/asthelpergen/integration.AST -except "*NoCloneType" -typed "*Leaf" -typed InterfaceSlice -parent "*RefContainer" -parent InterfaceSlice -enclosing SubIface -iterative -json
 */
 // AST is the interface all interface types implement
 type AST interface {
@@ -177,4 +177,6 @@ type Visit func(node AST) (bool, error)
 type application struct {
 	pre, post ApplyFunc
 	cur       Cursor
+	maxDepth  int
+	typed     *TypedRewriter
 }
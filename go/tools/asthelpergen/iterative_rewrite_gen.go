@@ -0,0 +1,257 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package asthelpergen
+
+import (
+	"go/types"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// iterativeRewriteGen generates the type-specific parts of the iterative rewriter:
+// a method that lists the children of a node together with their replacers, and a
+// method that tells which nodes are skipped. The loop that walks the AST with an
+// explicit stack is not generated, and lives next to the Cursor.
+type iterativeRewriteGen struct {
+	ifaceName string
+	file      *jen.File
+
+	// childCases are the cases of the type switch in iterChildren
+	childCases []jen.Code
+	// skipCases are the cases of the type switch in skipNode
+	skipCases []jen.Code
+	// byValue are the pointers to the nodes that are only visited by value, which are skipped
+	byValue []jen.Code
+}
+
+var _ generator = (*iterativeRewriteGen)(nil)
+
+func newIterativeRewriteGen(pkgname string, ifaceName string) *iterativeRewriteGen {
+	file := jen.NewFile(pkgname)
+
+	return &iterativeRewriteGen{
+		ifaceName: ifaceName,
+		file:      file,
+	}
+}
+
+func (r *iterativeRewriteGen) genFile() (string, *jen.File) {
+	/*
+		// iterChildren calls emit for every child of node that the recursive rewriter
+		// visits, in the same order, together with the replacer for the child
+		func (a *application) iterChildren(node AST, emit func(child AST, name string, index int, replacer replacerFunc)) {
+			switch node := node.(type) {
+			case *RefContainer:
+				emit(node.ASTType, "ASTType", -1, func(newNode, parent AST) {
+					parent.(*RefContainer).ASTType = newNode.(AST)
+				})
+			}
+		}
+	*/
+	r.file.Comment("iterChildren calls emit for every child of node that the recursive rewriter")
+	r.file.Comment("visits, in the same order, together with the replacer for the child")
+	r.file.Func().Params(jen.Id("a").Op("*").Id("application")).Id("iterChildren").Params(
+		jen.Id("node").Id(r.ifaceName),
+		jen.Id("emit").Func().Params(
+			jen.Id("child").Id(r.ifaceName),
+			jen.Id("name").String(),
+			jen.Id("index").Int(),
+			jen.Id("replacer").Id("replacerFunc"),
+		),
+	).Block(
+		jen.Switch(jen.Id("node := node.(type)")).Block(r.childCases...),
+	)
+
+	/*
+		// skipNode returns whether the recursive rewriter skips node without calling pre or post
+		func (a *application) skipNode(node AST) bool {
+			switch node := node.(type) {
+			case nil:
+				return true
+			case *RefContainer:
+				return node == nil
+			case ValueContainer:
+				return false
+			default:
+				a.unknownNode(node)
+				return true
+			}
+		}
+	*/
+	skipCases := append([]jen.Code{jen.Case(jen.Nil()).Block(returnTrue())}, r.skipCases...)
+	if len(r.byValue) > 0 {
+		skipCases = append(skipCases, jen.Case(r.byValue...).Block(returnTrue()))
+	}
+	skipCases = append(skipCases, jen.Default().Block(jen.Id("a.unknownNode").Call(jen.Id("node")), returnTrue()))
+	r.file.Comment("skipNode returns whether the recursive rewriter skips node without calling pre or post")
+	r.file.Func().Params(jen.Id("a").Op("*").Id("application")).Id("skipNode").Params(
+		jen.Id("node").Id(r.ifaceName),
+	).Bool().Block(
+		jen.Switch(jen.Id("node := node.(type)")).Block(skipCases...),
+	)
+
+	return "ast_rewrite_iterative.go", r.file
+}
+
+func (r *iterativeRewriteGen) interfaceMethod(t types.Type, iface *types.Interface, spi generatorSPI) error {
+	if !shouldAdd(t, spi.iface()) {
+		return nil
+	}
+	root := types.TypeString(t, noQualifier) == r.ifaceName
+	return spi.findImplementations(iface, func(t types.Type) error {
+		if _, ok := t.Underlying().(*types.Interface); ok {
+			return nil
+		}
+		spi.addType(t)
+		if root {
+			// every node goes through the switch of the root interface when it's
+			// visited, so its implementations are the only types that can be visited
+			r.addSkip(t)
+		}
+		return nil
+	})
+}
+
+func (r *iterativeRewriteGen) structMethod(t types.Type, strct *types.Struct, spi generatorSPI) error {
+	if !shouldAdd(t, spi.iface()) {
+		return nil
+	}
+	// the children of a value type can't be replaced, since the node is a copy
+	r.addChildren(t, r.structChildren(t, strct, spi, true))
+	return nil
+}
+
+func (r *iterativeRewriteGen) ptrToStructMethod(t types.Type, strct *types.Struct, spi generatorSPI) error {
+	if !shouldAdd(t, spi.iface()) {
+		return nil
+	}
+	r.addChildren(t, r.structChildren(t, strct, spi, false))
+	return nil
+}
+
+func (r *iterativeRewriteGen) ptrToBasicMethod(t types.Type, _ *types.Basic, spi generatorSPI) error {
+	return nil
+}
+
+func (r *iterativeRewriteGen) sliceMethod(t types.Type, slice *types.Slice, spi generatorSPI) error {
+	if !shouldAdd(t, spi.iface()) {
+		return nil
+	}
+	if !shouldAdd(slice.Elem(), spi.iface()) {
+		return nil
+	}
+	spi.addType(slice.Elem())
+
+	/*
+		for x, el := range node {
+			emit(el, "", x, func(idx int) replacerFunc {
+				return func(newNode, parent AST) {
+					parent.(InterfaceSlice)[idx] = newNode.(AST)
+				}
+			}(x))
+		}
+	*/
+	r.addChildren(t, []jen.Code{
+		jen.For(jen.Id("x, el").Op(":=").Id("range node")).Block(
+			r.emit(jen.Id("el"), "", jen.Id("x"), r.sliceReplacer(t, slice.Elem(), jen.Index(jen.Id("idx")))),
+		),
+	})
+	return nil
+}
+
+func (r *iterativeRewriteGen) basicMethod(t types.Type, _ *types.Basic, spi generatorSPI) error {
+	return nil
+}
+
+func (r *iterativeRewriteGen) addChildren(t types.Type, stmts []jen.Code) {
+	if len(stmts) == 0 {
+		return
+	}
+	r.childCases = append(r.childCases, jen.Case(jen.Id(types.TypeString(t, noQualifier))).Block(stmts...))
+}
+
+func (r *iterativeRewriteGen) addSkip(t types.Type) {
+	stmt := returnFalse()
+	switch underlying := t.Underlying().(type) {
+	case *types.Pointer:
+		stmt = jen.Return(jen.Id("node == nil"))
+		if _, ok := underlying.Elem().Underlying().(*types.Basic); ok {
+			// the recursive rewriter doesn't visit pointers to basic types at all
+			stmt = returnTrue()
+		}
+	case *types.Slice:
+		stmt = jen.Return(jen.Id("node == nil"))
+	}
+	r.skipCases = append(r.skipCases, jen.Case(jen.Id(types.TypeString(t, noQualifier))).Block(stmt))
+	if _, ok := t.(*types.Pointer); !ok {
+		r.byValue = append(r.byValue, jen.Op("*").Id(types.TypeString(t, noQualifier)))
+	}
+}
+
+func (r *iterativeRewriteGen) structChildren(t types.Type, strct *types.Struct, spi generatorSPI, fail bool) []jen.Code {
+	var stmts []jen.Code
+	for i := 0; i < strct.NumFields(); i++ {
+		field := strct.Field(i)
+		if types.Implements(field.Type(), spi.iface()) {
+			spi.addType(field.Type())
+			replacer := failReplacer(t, field.Name())
+			if !fail {
+				replacer = r.assignReplacer(t, field.Type(), jen.Dot(field.Name()))
+			}
+			stmts = append(stmts, r.emit(jen.Id("node").Dot(field.Name()), field.Name(), jen.Lit(-1),
+				jen.Func().Params(jen.Id("newNode, parent").Id(r.ifaceName)).Block(replacer)))
+			continue
+		}
+		slice, isSlice := field.Type().(*types.Slice)
+		if isSlice && types.Implements(slice.Elem(), spi.iface()) {
+			spi.addType(slice.Elem())
+			var replacer jen.Code
+			if fail {
+				replacer = jen.Func().Params(jen.Id("newNode, parent").Id(r.ifaceName)).Block(failReplacer(t, field.Name()))
+			} else {
+				replacer = r.sliceReplacer(t, slice.Elem(), jen.Dot(field.Name()).Index(jen.Id("idx")))
+			}
+			stmts = append(stmts, jen.For(jen.Id("x, el").Op(":=").Id("range node."+field.Name())).Block(
+				r.emit(jen.Id("el"), field.Name(), jen.Id("x"), replacer),
+			))
+		}
+	}
+	return stmts
+}
+
+func (r *iterativeRewriteGen) emit(child jen.Code, name string, index jen.Code, replacer jen.Code) jen.Code {
+	return jen.Id("emit").Call(child, jen.Lit(name), index, replacer)
+}
+
+// assignReplacer generates the statement that stores the new node in the parent
+func (r *iterativeRewriteGen) assignReplacer(t, field types.Type, replace jen.Code) *jen.Statement {
+	return jen.Id("parent").
+		Assert(jen.Id(types.TypeString(t, noQualifier))).
+		Add(replace).
+		Op("=").
+		Id("newNode").Assert(jen.Id(types.TypeString(field, noQualifier)))
+}
+
+// sliceReplacer generates a replacer for an element of a slice, which needs to capture
+// the index of the element since the loop variable is shared between iterations
+func (r *iterativeRewriteGen) sliceReplacer(t, elem types.Type, replace jen.Code) jen.Code {
+	return jen.Func().Params(jen.Id("idx int")).Id("replacerFunc").Block(
+		jen.Return(jen.Func().Params(jen.Id("newNode, parent").Id(r.ifaceName)).Block(
+			r.assignReplacer(t, elem, replace),
+		)),
+	).Call(jen.Id("x"))
+}
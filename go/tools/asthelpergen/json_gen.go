@@ -0,0 +1,311 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package asthelpergen
+
+import (
+	"fmt"
+	"go/types"
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+)
+
+const jsonName = "json"
+
+// jsonGen generates the functions that serialize the AST into JSON. Every node is an object
+// with its type in the "_type" field followed by its struct fields, with their Go names.
+// Fields that are not AST nodes are serialized with encoding/json.
+type jsonGen struct {
+	file      *jen.File
+	ifaceName string
+}
+
+var _ generator = (*jsonGen)(nil)
+
+func newJSONGen(pkgname string, ifaceName string) *jsonGen {
+	file := jen.NewFile(pkgname)
+
+	return &jsonGen{
+		file:      file,
+		ifaceName: ifaceName,
+	}
+}
+
+func (j *jsonGen) genFile() (string, *jen.File) {
+	return "ast_json.go", j.file
+}
+
+func (j *jsonGen) interfaceMethod(t types.Type, iface *types.Interface, spi generatorSPI) error {
+	if !shouldAdd(t, spi.iface()) {
+		return nil
+	}
+
+	if types.TypeString(t, noQualifier) == j.ifaceName {
+		j.marshalFunc()
+	}
+
+	/*
+		func jsonAST(buf *bytes.Buffer, in AST) error {
+			if in == nil {
+				buf.WriteString("null")
+				return nil
+			}
+			switch in := in.(type) {
+			case *RefContainer:
+				return jsonRefOfRefContainer(buf, in)
+			case ValueContainer:
+				return jsonValueContainer(buf, in)
+			case *ValueContainer:
+				if in == nil {
+					buf.WriteString("null")
+					return nil
+				}
+				return jsonValueContainer(buf, *in)
+			default:
+				return fmt.Errorf("unknown AST node type %T", in)
+			}
+		}
+	*/
+	var cases, byValue []jen.Code
+	_ = spi.findImplementations(iface, func(t types.Type) error {
+		if _, ok := t.Underlying().(*types.Interface); ok {
+			return nil
+		}
+		typeString := types.TypeString(t, noQualifier)
+		funcName := jsonName + printableTypeName(t)
+		spi.addType(t)
+		cases = append(cases, jen.Case(jen.Id(typeString)).Block(
+			jen.Return(jen.Id(funcName).Call(jen.Id("buf"), jen.Id("in"))),
+		))
+		if _, ok := t.(*types.Pointer); !ok {
+			// a pointer to a value implementation implements the interface too
+			byValue = append(byValue, jen.Case(jen.Op("*").Id(typeString)).Block(
+				writeJSONNull(),
+				jen.Return(jen.Id(funcName).Call(jen.Id("buf"), jen.Op("*").Id("in"))),
+			))
+		}
+		return nil
+	})
+	cases = append(cases, byValue...)
+	cases = append(cases, jen.Default().Block(
+		jen.Return(jen.Qual("fmt", "Errorf").Call(jen.Lit(fmt.Sprintf("unknown %s node type %%T", j.ifaceName)), jen.Id("in"))),
+	))
+
+	j.jsonFunc(t,
+		writeJSONNull(),
+		jen.Switch(jen.Id("in := in.(type)").Block(cases...)),
+	)
+	return nil
+}
+
+func (j *jsonGen) structMethod(t types.Type, strct *types.Struct, spi generatorSPI) error {
+	if !shouldAdd(t, spi.iface()) {
+		return nil
+	}
+
+	/*
+		func jsonLeaf(buf *bytes.Buffer, in Leaf) error {
+			buf.WriteString(`{"_type":"Leaf"`)
+			buf.WriteString(`,"v":`)
+			if err := jsonValue(buf, in.v); err != nil {
+				return err
+			}
+			buf.WriteByte('}')
+			return nil
+		}
+	*/
+	j.jsonFunc(t, j.writeStructFields(t, strct, spi)...)
+	return nil
+}
+
+func (j *jsonGen) ptrToStructMethod(t types.Type, strct *types.Struct, spi generatorSPI) error {
+	if !shouldAdd(t, spi.iface()) {
+		return nil
+	}
+
+	stmts := []jen.Code{writeJSONNull()}
+	stmts = append(stmts, j.writeStructFields(t, strct, spi)...)
+	j.jsonFunc(t, stmts...)
+	return nil
+}
+
+func (j *jsonGen) ptrToBasicMethod(t types.Type, _ *types.Basic, spi generatorSPI) error {
+	if !shouldAdd(t, spi.iface()) {
+		return nil
+	}
+
+	elem := t.(*types.Pointer).Elem()
+	spi.addType(elem)
+	j.jsonFunc(t,
+		writeJSONNull(),
+		jen.Return(jen.Id(jsonName+printableTypeName(elem)).Call(jen.Id("buf"), jen.Op("*").Id("in"))),
+	)
+	return nil
+}
+
+func (j *jsonGen) sliceMethod(t types.Type, slice *types.Slice, spi generatorSPI) error {
+	if !shouldAdd(t, spi.iface()) {
+		return nil
+	}
+
+	/*
+		func jsonLeafSlice(buf *bytes.Buffer, in LeafSlice) error {
+			buf.WriteByte('[')
+			for i, el := range in {
+				if i > 0 {
+					buf.WriteByte(',')
+				}
+				if err := jsonRefOfLeaf(buf, el); err != nil {
+					return err
+				}
+			}
+			buf.WriteByte(']')
+			return nil
+		}
+	*/
+	if !types.Implements(slice.Elem(), spi.iface()) {
+		j.writeValueNode(t)
+		return nil
+	}
+	stmts := writeJSONArray(slice, jen.Id("in"), spi)
+	j.jsonFunc(t, append(stmts, returnNil())...)
+	return nil
+}
+
+func (j *jsonGen) basicMethod(t types.Type, _ *types.Basic, spi generatorSPI) error {
+	if !shouldAdd(t, spi.iface()) {
+		return nil
+	}
+
+	/*
+		func jsonBasicType(buf *bytes.Buffer, in BasicType) error {
+			buf.WriteString(`{"_type":"BasicType","Value":`)
+			if err := jsonValue(buf, in); err != nil {
+				return err
+			}
+			buf.WriteByte('}')
+			return nil
+		}
+	*/
+	j.writeValueNode(t)
+	return nil
+}
+
+// writeValueNode writes a node without children, e.g. a basic type or a slice of bytes,
+// as an object with its value serialized by encoding/json
+func (j *jsonGen) writeValueNode(t types.Type) {
+	j.jsonFunc(t,
+		writeJSONString(fmt.Sprintf(`{"_type":%q,"Value":`, nodeTypeName(t))),
+		writeJSONChild(jsonName+"Value", jen.Id("in")),
+		jen.Id("buf").Dot("WriteByte").Call(jen.LitRune('}')),
+		returnNil(),
+	)
+}
+
+// writeStructFields writes the struct as an object with the "_type" discriminator followed by
+// all its fields; the generated code lives in the AST package, so unexported fields are included
+func (j *jsonGen) writeStructFields(t types.Type, strct *types.Struct, spi generatorSPI) []jen.Code {
+	stmts := []jen.Code{
+		writeJSONString(fmt.Sprintf(`{"_type":%q`, nodeTypeName(t))),
+	}
+	for i := 0; i < strct.NumFields(); i++ {
+		field := strct.Field(i)
+		if field.Name() == "_" {
+			continue
+		}
+		stmts = append(stmts, writeJSONString(fmt.Sprintf(`,%q:`, field.Name())))
+
+		value := jen.Id("in").Dot(field.Name())
+		if types.Implements(field.Type(), spi.iface()) {
+			spi.addType(field.Type())
+			stmts = append(stmts, writeJSONChild(jsonName+printableTypeName(field.Type()), value))
+			continue
+		}
+		if slice, isSlice := field.Type().(*types.Slice); isSlice && types.Implements(slice.Elem(), spi.iface()) {
+			stmts = append(stmts, writeJSONArray(slice, value, spi)...)
+			continue
+		}
+		stmts = append(stmts, writeJSONChild(jsonName+"Value", value))
+	}
+	return append(stmts,
+		jen.Id("buf").Dot("WriteByte").Call(jen.LitRune('}')),
+		returnNil(),
+	)
+}
+
+// writeJSONArray writes a slice of AST nodes as an array
+func writeJSONArray(slice *types.Slice, in jen.Code, spi generatorSPI) []jen.Code {
+	spi.addType(slice.Elem())
+	return []jen.Code{
+		jen.Id("buf").Dot("WriteByte").Call(jen.LitRune('[')),
+		jen.For(jen.Id("i, el := range").Add(in)).Block(
+			jen.If(jen.Id("i > 0")).Block(jen.Id("buf").Dot("WriteByte").Call(jen.LitRune(','))),
+			writeJSONChild(jsonName+printableTypeName(slice.Elem()), jen.Id("el")),
+		),
+		jen.Id("buf").Dot("WriteByte").Call(jen.LitRune(']')),
+	}
+}
+
+func writeJSONChild(funcName string, value jen.Code) jen.Code {
+	return jen.If(jen.Err().Op(":=").Id(funcName).Call(jen.Id("buf"), value), jen.Err().Op("!=").Nil()).Block(
+		jen.Return(jen.Err()),
+	)
+}
+
+func writeJSONString(s string) jen.Code {
+	return jen.Id("buf").Dot("WriteString").Call(jen.Lit(s))
+}
+
+func writeJSONNull() jen.Code {
+	return jen.If(jen.Id("in == nil")).Block(
+		writeJSONString("null"),
+		returnNil(),
+	)
+}
+
+// nodeTypeName is the name of the type of a node in its "_type" field
+func nodeTypeName(t types.Type) string {
+	return strings.TrimPrefix(types.TypeString(t, noQualifier), "*")
+}
+
+// marshalFunc generates the exported entry point of the serialization, and the helper
+// for the fields that are not AST nodes
+func (j *jsonGen) marshalFunc() {
+	funcName := "Marshal" + j.ifaceName + "JSON"
+	j.file.Comment(fmt.Sprintf("%s returns the JSON representation of an AST. Every node is an object with", funcName))
+	j.file.Comment("its type in the \"_type\" field followed by its struct fields, with their Go names.")
+	j.file.Add(jen.Func().Id(funcName).Call(jen.Id("node").Id(j.ifaceName)).Params(jen.Index().Byte(), jen.Error()).Block(
+		jen.Var().Id("buf").Qual("bytes", "Buffer"),
+		jen.If(jen.Err().Op(":=").Id(jsonName+j.ifaceName).Call(jen.Op("&").Id("buf"), jen.Id("node")), jen.Err().Op("!=").Nil()).Block(
+			jen.Return(jen.Nil(), jen.Err()),
+		),
+		jen.Return(jen.Id("buf").Dot("Bytes").Call(), jen.Nil()),
+	))
+
+	j.file.Add(jen.Func().Id(jsonName+"Value").Call(jen.Id("buf").Op("*").Qual("bytes", "Buffer"), jen.Id("v").Interface()).Error().Block(
+		jen.List(jen.Id("data"), jen.Err()).Op(":=").Qual("encoding/json", "Marshal").Call(jen.Id("v")),
+		jen.If(jen.Err().Op("!=").Nil()).Block(jen.Return(jen.Err())),
+		jen.Id("buf").Dot("Write").Call(jen.Id("data")),
+		returnNil(),
+	))
+}
+
+func (j *jsonGen) jsonFunc(t types.Type, stmts ...jen.Code) {
+	typeString := types.TypeString(t, noQualifier)
+	funcName := jsonName + printableTypeName(t)
+	j.file.Add(jen.Func().Id(funcName).Call(jen.Id("buf").Op("*").Qual("bytes", "Buffer"), jen.Id("in").Id(typeString)).Error().Block(stmts...))
+}
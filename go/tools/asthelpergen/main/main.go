@@ -20,6 +20,7 @@ import (
 	"flag"
 	"log"
 	"os"
+	"strings"
 
 	"vitess.io/vitess/go/tools/goimports"
 
@@ -27,24 +28,42 @@ import (
 )
 
 func main() {
-	var patterns TypePaths
-	var generate, except string
+	var options Options
+	var patterns, typed, parents TypePaths
 	var verify bool
+	var header, footer string
 
-	flag.Var(&patterns, "in", "Go packages to load the generator")
-	flag.StringVar(&generate, "iface", "", "Root interface generate rewriter for")
+	flag.Var(&patterns, "in", "Go packages to load the generator, with all their files (can be repeated)")
+	flag.StringVar(&options.RootInterface, "iface", "", "Root interface generate rewriter for")
 	flag.BoolVar(&verify, "verify", false, "ensure that the generated files are correct")
-	flag.StringVar(&except, "except", "", "don't deep clone these types")
+	flag.StringVar(&options.ExceptCloneType, "except", "", "don't deep clone these types")
+	flag.Var(&typed, "typed", "generate a typed Walk function for this type (can be repeated)")
+	flag.Var(&parents, "parent", "generate a typed accessor for parents of this type in the Cursor (can be repeated)")
+	flag.StringVar(&options.EnclosingInterface, "enclosing", "", "track the nearest ancestor that implements this interface in the Cursor")
+	flag.BoolVar(&options.IterativeRewriter, "iterative", false, "generate the helpers for the non-recursive RewriteIterative")
+	flag.BoolVar(&options.JSON, "json", false, "generate the functions that serialize the AST into JSON")
+	flag.StringVar(&header, "header", "", "file with the comment at the top of the generated files, instead of the Vitess license header")
+	flag.StringVar(&footer, "footer", "", "file with a comment to add at the end of the generated files")
 	flag.Parse()
 
-	result, err := GenerateASTHelpers(patterns, generate, except)
+	options.Packages = patterns
+	options.TypedWalkers = typed
+	options.ParentAccessors = parents
+	options.FileHeader = readComment(header)
+	options.FileFooter = readComment(footer)
+
+	result, err := GenerateASTHelpers(&options)
 	if err != nil {
 		log.Fatal(err)
 	}
 
 	if verify {
-		for _, err := range VerifyFilesOnDisk(result) {
-			log.Fatal(err)
+		errs := VerifyFilesOnDisk(result)
+		for _, err := range errs {
+			log.Print(err)
+		}
+		if len(errs) > 0 {
+			log.Fatal("the generated AST helpers are out of date: run `make asthelpergen` to regenerate them")
 		}
 		log.Printf("%d files OK", len(result))
 	} else {
@@ -61,3 +80,16 @@ func main() {
 		}
 	}
 }
+
+// readComment returns the contents of the given file, without its trailing newlines, to be
+// used as a comment in the generated files, or an empty string if no file was given
+func readComment(path string) string {
+	if path == "" {
+		return ""
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		log.Fatalf("failed to read '%s': %v", path, err)
+	}
+	return strings.TrimRight(string(content), "\n")
+}
@@ -19,6 +19,7 @@ package asthelpergen
 import (
 	"fmt"
 	"go/types"
+	"log"
 
 	"github.com/dave/jennifer/jen"
 )
@@ -30,25 +31,253 @@ const (
 type rewriteGen struct {
 	ifaceName string
 	file      *jen.File
+
+	// parentAccessors are the types that get a typed accessor for the parent in the Cursor
+	parentAccessors []string
+	// enclosing is the name of the interface whose nearest implementation among the ancestors
+	// of the current node is tracked by the Cursor, or an empty string to not track any
+	enclosing      string
+	enclosingIface *types.Interface
+	// seen are all the types that have a rewrite method, by name
+	seen map[string]types.Type
+	// knownTypes are the implementations of the root interface, which are all the types
+	// of nodes that the rewriter can visit
+	knownTypes []jen.Code
 }
 
 var _ generator = (*rewriteGen)(nil)
 
-func newRewriterGen(pkgname string, ifaceName string) *rewriteGen {
+func newRewriterGen(pkgname string, ifaceName string, parentAccessors []string, enclosing string) *rewriteGen {
 	file := jen.NewFile(pkgname)
-	file.HeaderComment(licenseFileHeader)
-	file.HeaderComment("Code generated by ASTHelperGen. DO NOT EDIT.")
 
 	return &rewriteGen{
-		ifaceName: ifaceName,
-		file:      file,
+		ifaceName:       ifaceName,
+		file:            file,
+		parentAccessors: parentAccessors,
+		enclosing:       enclosing,
+		seen:            map[string]types.Type{},
 	}
 }
 
 func (r *rewriteGen) genFile() (string, *jen.File) {
+	r.isKnownType()
+	r.typedRewriter()
+	for _, parent := range r.parentAccessors {
+		r.parentAccessor(parent)
+	}
+	if r.enclosing != "" {
+		r.enclosingAccessor()
+	}
 	return "ast_rewrite.go", r.file
 }
 
+// isKnownType generates a function that tells whether the rewriter knows the type of a node
+func (r *rewriteGen) isKnownType() {
+	/*
+		// IsKnownType returns whether node has one of the types of node that Rewrite knows how to visit
+		func IsKnownType(node AST) bool {
+			switch node.(type) {
+			case *RefContainer, ValueContainer:
+				return true
+			default:
+				return false
+			}
+		}
+	*/
+	r.file.Comment("IsKnownType returns whether node has one of the types that the rewriter knows how to visit.")
+	r.file.Comment("The rewriter panics when it finds a node of any other type, unless it has been configured")
+	r.file.Comment("to skip it.")
+	r.file.Func().Id("IsKnownType").Params(jen.Id("node").Id(r.ifaceName)).Bool().Block(
+		jen.Switch(jen.Id("node").Assert(jen.Id("type"))).Block(
+			jen.Case(r.knownTypes...).Block(returnTrue()),
+			jen.Default().Block(jen.Return(jen.False())),
+		),
+	)
+}
+
+// typedRewriter generates the TypedRewriter, which holds the handlers that the generated
+// rewrite methods dispatch to by the concrete type of the node they are visiting
+func (r *rewriteGen) typedRewriter() {
+	/*
+		// TypedRewriter ...
+		type TypedRewriter struct {
+			handlers map[reflect.Type]typedHandlers
+		}
+
+		type typedHandlers struct {
+			pre, post ApplyFunc
+		}
+	*/
+	handlerMap := jen.Map(jen.Qual("reflect", "Type")).Id("typedHandlers")
+	r.file.Comment("TypedRewriter rewrites the AST like Rewrite, but instead of calling the same pre and post")
+	r.file.Comment("functions for every node, it calls the handlers that were registered for the concrete type")
+	r.file.Comment("of the node. The generated code already knows the type of every node it visits, so the")
+	r.file.Comment("handlers don't need to switch on it again. The nodes without handlers are still traversed.")
+	r.file.Type().Id("TypedRewriter").Struct(
+		jen.Id("handlers").Add(handlerMap),
+	)
+	r.file.Comment("typedHandlers are the handlers registered in a TypedRewriter for one type")
+	r.file.Type().Id("typedHandlers").Struct(
+		jen.Id("pre, post").Id("ApplyFunc"),
+	)
+
+	r.file.Comment("NewTypedRewriter returns a TypedRewriter without any handlers")
+	r.file.Func().Id("NewTypedRewriter").Params().Op("*").Id("TypedRewriter").Block(
+		jen.Return(jen.Op("&").Id("TypedRewriter").Values(jen.Dict{
+			jen.Id("handlers"): jen.Add(handlerMap).Values(),
+		})),
+	)
+
+	/*
+		// Pre registers ...
+		func (r *TypedRewriter) Pre(node AST, pre ApplyFunc) *TypedRewriter {
+			t := reflect.TypeOf(node)
+			h := r.handlers[t]
+			h.pre = pre
+			r.handlers[t] = h
+			return r
+		}
+	*/
+	for _, handler := range []struct{ name, field, doc string }{
+		{"Pre", "pre", "before the children of"},
+		{"Post", "post", "after the children of"},
+	} {
+		r.file.Comment(fmt.Sprintf("%s registers f to be called %s every node with the same concrete type as node,", handler.name, handler.doc))
+		r.file.Comment("replacing any handler that was registered for that type before. Only the type of node is used,")
+		r.file.Comment(fmt.Sprintf("so it can be a nil pointer. f follows the same rules as the %s function of Rewrite.", handler.field))
+		r.file.Comment("It returns the TypedRewriter, so that the calls can be chained.")
+		r.file.Func().Params(jen.Id("r").Op("*").Id("TypedRewriter")).Id(handler.name).Params(
+			jen.Id("node").Id(r.ifaceName),
+			jen.Id("f").Id("ApplyFunc"),
+		).Op("*").Id("TypedRewriter").Block(
+			jen.Id("t").Op(":=").Qual("reflect", "TypeOf").Call(jen.Id("node")),
+			jen.Id("h").Op(":=").Id("r.handlers[t]"),
+			jen.Id("h."+handler.field).Op("=").Id("f"),
+			jen.Id("r.handlers[t]").Op("=").Id("h"),
+			jen.Return(jen.Id("r")),
+		)
+	}
+}
+
+// typedHandlers picks the pre and post functions for the node being rewritten: the ones of the
+// application, or the handlers registered for the type of the node when using a TypedRewriter
+func typedHandlers(t types.Type) []jen.Code {
+	/*
+		pre, post := a.pre, a.post
+		if a.typed != nil {
+			h := a.typed.handlers[reflect.TypeOf((*Leaf)(nil))]
+			pre, post = h.pre, h.post
+		}
+	*/
+	// the type is taken from a nil pointer, so that values don't have to be boxed to get it
+	var typeOf *jen.Statement
+	if ptr, ok := t.(*types.Pointer); ok {
+		typeOf = jen.Qual("reflect", "TypeOf").Call(jen.Parens(jen.Id(types.TypeString(ptr, noQualifier))).Call(jen.Nil()))
+	} else {
+		typeOf = jen.Qual("reflect", "TypeOf").Call(jen.Parens(jen.Op("*").Id(types.TypeString(t, noQualifier))).Call(jen.Nil())).Dot("Elem").Call()
+	}
+	return []jen.Code{
+		jen.Id("pre, post").Op(":=").Id("a.pre, a.post"),
+		jen.If(jen.Id("a.typed != nil")).Block(
+			jen.Id("h").Op(":=").Id("a.typed.handlers").Index(typeOf),
+			jen.Id("pre, post").Op("=").Id("h.pre, h.post"),
+		),
+	}
+}
+
+// parentAccessor generates a method in the Cursor that returns its parent if it has the given type
+func (r *rewriteGen) parentAccessor(typeString string) {
+	/*
+		// ParentIfSelect returns the parent of the current node if it is a *Select
+		func (c *Cursor) ParentIfSelect() (*Select, bool) {
+			parent, ok := c.parent.(*Select)
+			return parent, ok
+		}
+	*/
+	t, ok := r.seen[typeString]
+	if !ok {
+		log.Fatalf("no type called '%s' found for the parent accessors", typeString)
+	}
+	if _, ok := t.Underlying().(*types.Interface); ok {
+		log.Fatalf("cannot generate a parent accessor for interface type '%s'", typeString)
+	}
+
+	funcName := "ParentIf" + shortTypeName(t)
+	r.file.Comment(fmt.Sprintf("%s returns the parent of the current node if it is a %s", funcName, typeString))
+	r.file.Func().Params(jen.Id("c").Op("*").Id("Cursor")).Id(funcName).Params().Params(jen.Id(typeString), jen.Bool()).Block(
+		jen.List(jen.Id("parent"), jen.Id("ok")).Op(":=").Id("c.parent").Assert(jen.Id(typeString)),
+		jen.Return(jen.Id("parent"), jen.Id("ok")),
+	)
+}
+
+// enclosingAccessor generates the method in the Cursor that returns the enclosing node, and the
+// function that tells the non-recursive rewriter which nodes it has to track as enclosing nodes
+func (r *rewriteGen) enclosingAccessor() {
+	/*
+		// EnclosingStatement returns ...
+		func (c *Cursor) EnclosingStatement() Statement {
+			enclosing, _ := c.enclosing.(Statement)
+			return enclosing
+		}
+
+		// isEnclosing returns ...
+		func isEnclosing(node AST) bool {
+			_, ok := node.(Statement)
+			return ok
+		}
+	*/
+	funcName := "Enclosing" + r.enclosing
+	r.file.Comment(fmt.Sprintf("%s returns the nearest ancestor of the current node that is a %s,", funcName, r.enclosing))
+	r.file.Comment(fmt.Sprintf("or nil if there is none. A %s does not enclose itself, so when the current node", r.enclosing))
+	r.file.Comment(fmt.Sprintf("is a %s, this returns the %s that contains it, if any.", r.enclosing, r.enclosing))
+	r.file.Func().Params(jen.Id("c").Op("*").Id("Cursor")).Id(funcName).Params().Id(r.enclosing).Block(
+		jen.List(jen.Id("enclosing"), jen.Id("_")).Op(":=").Id("c.enclosing").Assert(jen.Id(r.enclosing)),
+		jen.Return(jen.Id("enclosing")),
+	)
+
+	r.file.Comment(fmt.Sprintf("isEnclosing returns whether node is a %s, which the Cursor tracks as the enclosing", r.enclosing))
+	r.file.Comment("node of its children")
+	r.file.Func().Id("isEnclosing").Params(jen.Id("node").Id(r.ifaceName)).Bool().Block(
+		jen.List(jen.Id("_"), jen.Id("ok")).Op(":=").Id("node").Assert(jen.Id(r.enclosing)),
+		jen.Return(jen.Id("ok")),
+	)
+}
+
+// trackEnclosing surrounds the rewrite of the fields of a node with the statements that make it
+// the enclosing node in the Cursor, if the node implements the enclosing interface
+func (r *rewriteGen) trackEnclosing(t types.Type, fields []jen.Code, spi generatorSPI) []jen.Code {
+	/*
+		outer := a.cur.enclosing
+		a.cur.enclosing = node
+		...
+		a.cur.enclosing = outer
+	*/
+	if r.enclosing == "" || len(fields) == 0 {
+		return fields
+	}
+	if r.enclosingIface == nil {
+		obj := spi.scope().Lookup(r.enclosing)
+		if obj == nil {
+			log.Fatalf("no type called '%s' found for the enclosing nodes", r.enclosing)
+		}
+		iface, ok := obj.Type().Underlying().(*types.Interface)
+		if !ok {
+			log.Fatalf("the enclosing type '%s' is not an interface", r.enclosing)
+		}
+		r.enclosingIface = iface
+	}
+	if !types.Implements(t, r.enclosingIface) {
+		return fields
+	}
+
+	stmts := []jen.Code{
+		jen.Id("outer").Op(":=").Id("a.cur.enclosing"),
+		jen.Id("a.cur.enclosing").Op("=").Id("node"),
+	}
+	stmts = append(stmts, fields...)
+	return append(stmts, jen.Id("a.cur.enclosing").Op("=").Id("outer"))
+}
+
 func (r *rewriteGen) interfaceMethod(t types.Type, iface *types.Interface, spi generatorSPI) error {
 	if !shouldAdd(t, spi.iface()) {
 		return nil
@@ -70,7 +299,8 @@ func (r *rewriteGen) interfaceMethod(t types.Type, iface *types.Interface, spi g
 		jen.If(jen.Id("node == nil").Block(returnTrue())),
 	}
 
-	var cases []jen.Code
+	root := types.TypeString(t, noQualifier) == r.ifaceName
+	var cases, byValue []jen.Code
 	_ = spi.findImplementations(iface, func(t types.Type) error {
 		if _, ok := t.Underlying().(*types.Interface); ok {
 			return nil
@@ -78,6 +308,13 @@ func (r *rewriteGen) interfaceMethod(t types.Type, iface *types.Interface, spi g
 		typeString := types.TypeString(t, noQualifier)
 		funcName := rewriteName + printableTypeName(t)
 		spi.addType(t)
+		if _, ok := t.(*types.Pointer); !ok {
+			// a pointer to a value implementation implements the interface too
+			byValue = append(byValue, jen.Op("*").Id(typeString))
+		}
+		if root {
+			r.knownTypes = append(r.knownTypes, jen.Id(typeString))
+		}
 		caseBlock := jen.Case(jen.Id(typeString)).Block(
 			jen.Return(jen.Id("a").Dot(funcName).Call(jen.Id("parent, node, replacer"))),
 		)
@@ -85,9 +322,19 @@ func (r *rewriteGen) interfaceMethod(t types.Type, iface *types.Interface, spi g
 		return nil
 	})
 
+	if len(byValue) > 0 {
+		cases = append(cases, jen.Case(byValue...).Block(
+			jen.Comment("these nodes are only visited by value"),
+			returnTrue(),
+		))
+		if root {
+			r.knownTypes = append(r.knownTypes, byValue...)
+		}
+	}
 	cases = append(cases,
 		jen.Default().Block(
-			jen.Comment("this should never happen"),
+			jen.Comment("this should never happen, unless the AST helpers are out of date"),
+			jen.Id("a.unknownNode").Call(jen.Id("node")),
 			returnTrue(),
 		))
 
@@ -105,8 +352,13 @@ func (r *rewriteGen) structMethod(t types.Type, strct *types.Struct, spi generat
 	}
 	fields := r.rewriteAllStructFields(t, strct, spi, true)
 
-	stmts := []jen.Code{executePre()}
-	stmts = append(stmts, fields...)
+	var stmts []jen.Code
+	if len(fields) > 0 {
+		stmts = append(stmts, checkDepth())
+	}
+	stmts = append(stmts, typedHandlers(t)...)
+	stmts = append(stmts, r.executePre())
+	stmts = append(stmts, r.trackEnclosing(t, fields, spi)...)
 	stmts = append(stmts, executePost(len(fields) > 0))
 	stmts = append(stmts, returnTrue())
 
@@ -130,9 +382,13 @@ func (r *rewriteGen) ptrToStructMethod(t types.Type, strct *types.Struct, spi ge
 			return nil
 		}
 	*/
-	stmts = append(stmts, executePre())
 	fields := r.rewriteAllStructFields(t, strct, spi, false)
-	stmts = append(stmts, fields...)
+	if len(fields) > 0 {
+		stmts = append(stmts, checkDepth())
+	}
+	stmts = append(stmts, typedHandlers(t)...)
+	stmts = append(stmts, r.executePre())
+	stmts = append(stmts, r.trackEnclosing(t, fields, spi)...)
 	stmts = append(stmts, executePost(len(fields) > 0))
 	stmts = append(stmts, returnTrue())
 
@@ -179,23 +435,14 @@ func (r *rewriteGen) sliceMethod(t types.Type, slice *types.Slice, spi generator
 		jen.If(jen.Id("node == nil").Block(returnTrue())),
 	}
 
-	typeString := types.TypeString(t, noQualifier)
-
-	preStmts := setupCursor()
-	preStmts = append(preStmts,
-		jen.Id("kontinue").Op(":=").Id("!a.pre(&a.cur)"),
-		jen.If(jen.Id("a.cur.revisit").Block(
-			jen.Id("node").Op("=").Id("a.cur.node.("+typeString+")"),
-			jen.Id("a.cur.revisit").Op("=").False(),
-			jen.Return(jen.Id("a.rewrite"+typeString+"(parent, node, replacer)")),
-		)),
-		jen.If(jen.Id("kontinue").Block(jen.Return(jen.True()))),
-	)
-
-	stmts = append(stmts, jen.If(jen.Id("a.pre!= nil").Block(preStmts...)))
+	haveChildren := shouldAdd(slice.Elem(), spi.iface())
+	if haveChildren {
+		stmts = append(stmts, checkDepth())
+	}
+	stmts = append(stmts, typedHandlers(t)...)
+	stmts = append(stmts, r.executePre())
 
-	haveChildren := false
-	if shouldAdd(slice.Elem(), spi.iface()) {
+	if haveChildren {
 		/*
 			for i, el := range node {
 						if err := rewriteRefOfLeaf(node, el, func(newNode, parent AST) {
@@ -205,10 +452,14 @@ func (r *rewriteGen) sliceMethod(t types.Type, slice *types.Slice, spi generator
 						}
 					}
 		*/
-		haveChildren = true
 		stmts = append(stmts,
 			jen.For(jen.Id("x, el").Op(":=").Id("range node")).
-				Block(r.rewriteChildSlice(t, slice.Elem(), "notUsed", jen.Id("el"), jen.Index(jen.Id("idx")), false)))
+				Block(enterField("", jen.Id("x"), r.rewriteChildSlice(t, slice.Elem(), "notUsed", jen.Id("el"), jen.Index(jen.Id("idx")), false))...),
+			r.applySliceEdits(t, slice.Elem(), jen.Id("node"),
+				jen.Id("replacer(result, parent)"),
+				jen.Id("node = result"),
+			),
+		)
 	}
 
 	stmts = append(stmts, executePost(haveChildren))
@@ -225,10 +476,46 @@ func setupCursor() []jen.Code {
 		jen.Id("a.cur.node = node"),
 	}
 }
-func executePre() jen.Code {
+
+// checkDepth aborts the rewrite before visiting the children of a node that is
+// deeper in the AST than the maximum depth of the application
+func checkDepth() jen.Code {
+	/*
+		if a.tooDeep() {
+			return false
+		}
+	*/
+	return jen.If(jen.Id("a.tooDeep()")).Block(returnFalse())
+}
+
+func (r *rewriteGen) executePre() jen.Code {
+	/*
+		if pre != nil {
+			a.cur.replacer = replacer
+			a.cur.parent = parent
+			a.cur.node = node
+			kontinue := !pre(&a.cur)
+			if a.cur.revisit {
+				a.cur.revisit = false
+				return a.rewriteAST(parent, a.cur.node, replacer)
+			}
+			if kontinue {
+				return true
+			}
+		}
+	*/
+	// the node that replaced the current one can be of any type that fits in the
+	// parent's field, so it's visited again through the root interface
 	curStmts := setupCursor()
-	curStmts = append(curStmts, jen.If(jen.Id("!a.pre(&a.cur)")).Block(returnTrue()))
-	return jen.If(jen.Id("a.pre!= nil").Block(curStmts...))
+	curStmts = append(curStmts,
+		jen.Id("kontinue").Op(":=").Id("!pre(&a.cur)"),
+		jen.If(jen.Id("a.cur.revisit").Block(
+			jen.Id("a.cur.revisit").Op("=").False(),
+			jen.Return(jen.Id("a."+rewriteName+r.ifaceName+"(parent, a.cur.node, replacer)")),
+		)),
+		jen.If(jen.Id("kontinue").Block(returnTrue())),
+	)
+	return jen.If(jen.Id("pre != nil").Block(curStmts...))
 }
 
 func executePost(seenChildren bool) jen.Code {
@@ -238,12 +525,12 @@ func executePost(seenChildren bool) jen.Code {
 		curStmts = setupCursor()
 	} else {
 		curStmts = append(curStmts,
-			jen.If(jen.Id("a.pre == nil")).Block(setupCursor()...))
+			jen.If(jen.Id("pre == nil")).Block(setupCursor()...))
 	}
 
-	curStmts = append(curStmts, jen.If(jen.Id("!a.post(&a.cur)")).Block(returnFalse()))
+	curStmts = append(curStmts, jen.If(jen.Id("!post(&a.cur)")).Block(returnFalse()))
 
-	return jen.If(jen.Id("a.post != nil")).Block(curStmts...)
+	return jen.If(jen.Id("post != nil")).Block(curStmts...)
 }
 
 func (r *rewriteGen) basicMethod(t types.Type, _ *types.Basic, spi generatorSPI) error {
@@ -251,7 +538,8 @@ func (r *rewriteGen) basicMethod(t types.Type, _ *types.Basic, spi generatorSPI)
 		return nil
 	}
 
-	stmts := []jen.Code{executePre(), executePost(false), returnTrue()}
+	stmts := typedHandlers(t)
+	stmts = append(stmts, r.executePre(), executePost(false), returnTrue())
 	r.rewriteFunc(t, stmts)
 	return nil
 }
@@ -263,6 +551,7 @@ func (r *rewriteGen) rewriteFunc(t types.Type, stmts []jen.Code) {
 	*/
 
 	typeString := types.TypeString(t, noQualifier)
+	r.seen[typeString] = t
 	funcName := fmt.Sprintf("%s%s", rewriteName, printableTypeName(t))
 	code := jen.Func().Params(
 		jen.Id("a").Op("*").Id("application"),
@@ -287,24 +576,75 @@ func (r *rewriteGen) rewriteAllStructFields(t types.Type, strct *types.Struct, s
 		field := strct.Field(i)
 		if types.Implements(field.Type(), spi.iface()) {
 			spi.addType(field.Type())
-			output = append(output, r.rewriteChild(t, field.Type(), field.Name(), jen.Id("node").Dot(field.Name()), jen.Dot(field.Name()), fail))
+			output = append(output, enterField(field.Name(), jen.Lit(-1), r.rewriteChild(t, field.Type(), field.Name(), jen.Id("node").Dot(field.Name()), jen.Dot(field.Name()), fail))...)
 			continue
 		}
 		slice, isSlice := field.Type().(*types.Slice)
 		if isSlice && types.Implements(slice.Elem(), spi.iface()) {
 			spi.addType(slice.Elem())
-			id := jen.Id("x")
+			output = append(output,
+				jen.For(jen.Id("x, el").Op(":=").Id("range node."+field.Name())).
+					Block(enterField(field.Name(), jen.Id("x"), r.rewriteChildSlice(t, slice.Elem(), field.Name(), jen.Id("el"), jen.Dot(field.Name()).Index(jen.Id("idx")), fail))...))
 			if fail {
-				id = jen.Id("_")
+				output = append(output, jen.If(jen.Id("a.cur.takeEdits() != nil")).Block(
+					jen.Panic(jen.Lit(fmt.Sprintf("[BUG] tried to insert or remove in '%s' on '%s'", field.Name(), types.TypeString(t, noQualifier)))),
+				))
+			} else {
+				output = append(output, r.applySliceEdits(field.Type(), slice.Elem(), jen.Id("node."+field.Name()),
+					jen.Id("node."+field.Name()+" = result"),
+				))
 			}
-			output = append(output,
-				jen.For(jen.List(id, jen.Id("el")).Op(":=").Id("range node."+field.Name())).
-					Block(r.rewriteChildSlice(t, slice.Elem(), field.Name(), jen.Id("el"), jen.Dot(field.Name()).Index(jen.Id("idx")), fail)))
 		}
 	}
 	return output
 }
 
+// applySliceEdits generates the code that applies the insertions and removals requested
+// through the Cursor while visiting the elements of a slice. The edits are applied once
+// all the elements have been visited, so the indexes in the Cursor stay consistent.
+func (r *rewriteGen) applySliceEdits(sliceType, elemType types.Type, slice jen.Code, assign ...jen.Code) jen.Code {
+	/*
+		if edits := a.cur.takeEdits(); edits != nil {
+			var result SelectExprs
+			rebuildSlice(edits, len(node), func(idx int, inserted AST) {
+				if idx < 0 {
+					result = append(result, inserted.(SelectExpr))
+				} else {
+					result = append(result, node[idx])
+				}
+			})
+			node.SelectExprs = result
+		}
+	*/
+	rebuild := jen.Id("rebuildSlice").Call(
+		jen.Id("edits"),
+		jen.Len(slice),
+		jen.Func().Params(jen.Id("idx int, inserted").Id(r.ifaceName)).Block(
+			jen.If(jen.Id("idx < 0")).Block(
+				jen.Id("result").Op("=").Append(jen.Id("result"), jen.Id("inserted").Assert(jen.Id(types.TypeString(elemType, noQualifier)))),
+			).Else().Block(
+				jen.Id("result").Op("=").Append(jen.Id("result"), jen.Add(slice).Index(jen.Id("idx"))),
+			),
+		),
+	)
+	stmts := []jen.Code{
+		jen.Var().Id("result").Id(types.TypeString(sliceType, noQualifier)),
+		rebuild,
+	}
+	stmts = append(stmts, assign...)
+	return jen.If(jen.Id("edits := a.cur.takeEdits()"), jen.Id("edits != nil")).Block(stmts...)
+}
+
+// enterField surrounds the rewrite of a child node with the calls that keep track
+// of the field (and the index, for slices) of the parent that contains the child
+func enterField(fieldName string, index jen.Code, rewrite jen.Code) []jen.Code {
+	return []jen.Code{
+		jen.Id("a.cur.enterField").Call(jen.Lit(fieldName), index),
+		rewrite,
+		jen.Id("a.cur.leaveField()"),
+	}
+}
+
 func failReplacer(t types.Type, f string) *jen.Statement {
 	typeString := types.TypeString(t, noQualifier)
 	return jen.Panic(jen.Lit(fmt.Sprintf("[BUG] tried to replace '%s' on '%s'", f, typeString)))
@@ -0,0 +1,342 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package asthelpergen
+
+import (
+	"fmt"
+	"go/types"
+	"log"
+
+	"github.com/dave/jennifer/jen"
+)
+
+const (
+	typedWalkName = "Walk"
+	walkName      = "walk"
+)
+
+// typedWalkGen generates a narrow Walk function for each of the target types,
+// e.g. WalkColName(node SQLNode, pre, post func(*ColName) bool). The generated
+// walkers only descend into the types that can contain the target type, so the rest
+// of the AST is skipped without going through the full type switch.
+// Since the walkers need to know the whole type graph to decide which types can
+// reach the targets, the per-type methods only record the graph, and the code is
+// generated at the end in genFile.
+type typedWalkGen struct {
+	ifaceName string
+	targets   []string
+	file      *jen.File
+
+	// nodes are all the types that the generic rewriter visits, in the order they were found
+	nodes []*typedWalkNode
+}
+
+type typedWalkNode struct {
+	t       types.Type
+	iface   bool
+	nilable bool
+	// impls are the concrete implementations of an interface type
+	impls []types.Type
+	// fields are the fields of a struct type that can contain other nodes
+	fields []typedWalkField
+	// elem is the type of the elements of a slice type, if they can contain other nodes
+	elem types.Type
+}
+
+type typedWalkField struct {
+	name  string
+	t     types.Type
+	slice bool
+}
+
+var _ generator = (*typedWalkGen)(nil)
+
+func newTypedWalkGen(pkgname string, ifaceName string, targets []string) *typedWalkGen {
+	file := jen.NewFile(pkgname)
+
+	return &typedWalkGen{
+		ifaceName: ifaceName,
+		targets:   targets,
+		file:      file,
+	}
+}
+
+func (r *typedWalkGen) genFile() (string, *jen.File) {
+	for _, target := range r.targets {
+		r.typedWalker(target)
+	}
+	return "ast_walk_typed.go", r.file
+}
+
+func (r *typedWalkGen) interfaceMethod(t types.Type, iface *types.Interface, spi generatorSPI) error {
+	if !shouldAdd(t, spi.iface()) {
+		return nil
+	}
+
+	node := &typedWalkNode{t: t, iface: true}
+	_ = spi.findImplementations(iface, func(t types.Type) error {
+		if _, ok := t.Underlying().(*types.Interface); ok {
+			return nil
+		}
+		spi.addType(t)
+		node.impls = append(node.impls, t)
+		return nil
+	})
+	r.nodes = append(r.nodes, node)
+	return nil
+}
+
+func (r *typedWalkGen) structMethod(t types.Type, strct *types.Struct, spi generatorSPI) error {
+	if !shouldAdd(t, spi.iface()) {
+		return nil
+	}
+	r.nodes = append(r.nodes, &typedWalkNode{t: t, fields: typedWalkFields(strct, spi)})
+	return nil
+}
+
+func (r *typedWalkGen) ptrToStructMethod(t types.Type, strct *types.Struct, spi generatorSPI) error {
+	if !shouldAdd(t, spi.iface()) {
+		return nil
+	}
+	r.nodes = append(r.nodes, &typedWalkNode{t: t, nilable: true, fields: typedWalkFields(strct, spi)})
+	return nil
+}
+
+func (r *typedWalkGen) ptrToBasicMethod(t types.Type, _ *types.Basic, spi generatorSPI) error {
+	if !shouldAdd(t, spi.iface()) {
+		return nil
+	}
+	r.nodes = append(r.nodes, &typedWalkNode{t: t, nilable: true})
+	return nil
+}
+
+func (r *typedWalkGen) sliceMethod(t types.Type, slice *types.Slice, spi generatorSPI) error {
+	if !shouldAdd(t, spi.iface()) {
+		return nil
+	}
+	node := &typedWalkNode{t: t, nilable: true}
+	if shouldAdd(slice.Elem(), spi.iface()) {
+		spi.addType(slice.Elem())
+		node.elem = slice.Elem()
+	}
+	r.nodes = append(r.nodes, node)
+	return nil
+}
+
+func (r *typedWalkGen) basicMethod(t types.Type, _ *types.Basic, spi generatorSPI) error {
+	if !shouldAdd(t, spi.iface()) {
+		return nil
+	}
+	r.nodes = append(r.nodes, &typedWalkNode{t: t})
+	return nil
+}
+
+func typedWalkFields(strct *types.Struct, spi generatorSPI) (fields []typedWalkField) {
+	for i := 0; i < strct.NumFields(); i++ {
+		field := strct.Field(i)
+		if types.Implements(field.Type(), spi.iface()) {
+			spi.addType(field.Type())
+			fields = append(fields, typedWalkField{name: field.Name(), t: field.Type()})
+			continue
+		}
+		slice, isSlice := field.Type().(*types.Slice)
+		if isSlice && types.Implements(slice.Elem(), spi.iface()) {
+			spi.addType(slice.Elem())
+			fields = append(fields, typedWalkField{name: field.Name(), t: slice.Elem(), slice: true})
+		}
+	}
+	return
+}
+
+// reachable returns the printable names of all the types that are, or can contain, the target type
+func (r *typedWalkGen) reachable(target string) map[string]bool {
+	reach := map[string]bool{}
+	for _, node := range r.nodes {
+		if types.TypeString(node.t, noQualifier) == target {
+			reach[printableTypeName(node.t)] = true
+		}
+	}
+	if len(reach) == 0 {
+		return nil
+	}
+
+	for changed := true; changed; {
+		changed = false
+		for _, node := range r.nodes {
+			name := printableTypeName(node.t)
+			if reach[name] {
+				continue
+			}
+			var children []types.Type
+			children = append(children, node.impls...)
+			for _, field := range node.fields {
+				children = append(children, field.t)
+			}
+			if node.elem != nil {
+				children = append(children, node.elem)
+			}
+			for _, child := range children {
+				if reach[printableTypeName(child)] {
+					reach[name] = true
+					changed = true
+					break
+				}
+			}
+		}
+	}
+	return reach
+}
+
+// shortTypeName returns the name used in the generated code for the given type,
+// e.g. `ColName` for `*ColName`
+func shortTypeName(t types.Type) string {
+	if ptr, ok := t.(*types.Pointer); ok {
+		if named, ok := ptr.Elem().(*types.Named); ok {
+			return named.Obj().Name()
+		}
+	}
+	return printableTypeName(t)
+}
+
+func (r *typedWalkGen) typedWalker(target string) {
+	reach := r.reachable(target)
+	if reach == nil {
+		log.Fatalf("no type called '%s' found for the typed walker", target)
+	}
+
+	var targetType types.Type
+	for _, node := range r.nodes {
+		if types.TypeString(node.t, noQualifier) == target {
+			targetType = node.t
+			break
+		}
+	}
+
+	name := shortTypeName(targetType)
+	structName := "typedWalker" + name
+	callbackType := jen.Func().Params(jen.Id(target)).Bool()
+
+	/*
+		// WalkLeaf walks the AST ...
+		func WalkLeaf(node AST, pre, post func(*Leaf) bool) {
+			r := &typedWalkerLeaf{pre, post}
+			r.walkAST(node)
+		}
+
+		type typedWalkerLeaf struct {
+			pre, post func(*Leaf) bool
+		}
+	*/
+	funcName := typedWalkName + name
+	r.file.Comment(fmt.Sprintf("%s walks the AST rooted at node and calls pre and post for every %s in it.", funcName, target))
+	r.file.Comment("The subtrees that cannot contain the target type are not visited. If pre returns false, the")
+	r.file.Comment("children of the current node are skipped; if post returns false, the walk is aborted.")
+	r.file.Func().Id(funcName).Params(
+		jen.Id("node").Id(r.ifaceName),
+		jen.Id("pre, post").Add(callbackType),
+	).Block(
+		jen.Id("r").Op(":=").Op("&").Id(structName).Values(jen.Id("pre"), jen.Id("post")),
+		jen.Id("r").Dot(walkName+printableTypeName(r.rootNode().t)).Call(jen.Id("node")),
+	)
+
+	r.file.Type().Id(structName).Struct(
+		jen.Id("pre, post").Add(callbackType),
+	)
+
+	for _, node := range r.nodes {
+		if reach[printableTypeName(node.t)] {
+			r.typedWalkFunc(structName, node, node.t == targetType, reach)
+		}
+	}
+}
+
+// rootNode returns the node for the root interface, which is always the first one to be visited
+func (r *typedWalkGen) rootNode() *typedWalkNode {
+	return r.nodes[0]
+}
+
+func (r *typedWalkGen) typedWalkFunc(structName string, node *typedWalkNode, isTarget bool, reach map[string]bool) {
+	var stmts []jen.Code
+
+	if node.iface {
+		/*
+			switch node := node.(type) {
+			case *RefContainer:
+				return r.walkRefOfRefContainer(node)
+			}
+			return true
+		*/
+		var cases []jen.Code
+		for _, impl := range node.impls {
+			if !reach[printableTypeName(impl)] {
+				continue
+			}
+			cases = append(cases, jen.Case(jen.Id(types.TypeString(impl, noQualifier))).Block(
+				jen.Return(jen.Id("r").Dot(walkName+printableTypeName(impl)).Call(jen.Id("node"))),
+			))
+		}
+		stmts = append(stmts, jen.Switch(jen.Id("node := node.(type)")).Block(cases...), returnTrue())
+		r.typedFunc(structName, node.t, stmts)
+		return
+	}
+
+	if node.nilable {
+		stmts = append(stmts, jen.If(jen.Id("node == nil").Block(returnTrue())))
+	}
+	if isTarget {
+		stmts = append(stmts, jen.If(jen.Id("r.pre != nil && !r.pre(node)")).Block(returnTrue()))
+	}
+	for _, field := range node.fields {
+		if !reach[printableTypeName(field.t)] {
+			continue
+		}
+		if field.slice {
+			stmts = append(stmts, jen.For(jen.Id("_, el := range node."+field.name)).Block(
+				typedWalkChild(field.t, jen.Id("el")),
+			))
+		} else {
+			stmts = append(stmts, typedWalkChild(field.t, jen.Id("node").Dot(field.name)))
+		}
+	}
+	if node.elem != nil && reach[printableTypeName(node.elem)] {
+		stmts = append(stmts, jen.For(jen.Id("_, el := range node")).Block(
+			typedWalkChild(node.elem, jen.Id("el")),
+		))
+	}
+	if isTarget {
+		stmts = append(stmts, jen.If(jen.Id("r.post != nil")).Block(jen.Return(jen.Id("r.post(node)"))))
+	}
+	stmts = append(stmts, returnTrue())
+	r.typedFunc(structName, node.t, stmts)
+}
+
+func typedWalkChild(t types.Type, param jen.Code) jen.Code {
+	return jen.If(jen.Op("!").Id("r").Dot(walkName + printableTypeName(t)).Call(param)).Block(returnFalse())
+}
+
+func (r *typedWalkGen) typedFunc(structName string, t types.Type, stmts []jen.Code) {
+	/*
+		func (r *typedWalkerLeaf) walkNodeType(node NodeType) bool {
+	*/
+	typeString := types.TypeString(t, noQualifier)
+	funcName := walkName + printableTypeName(t)
+	r.file.Func().Params(
+		jen.Id("r").Op("*").Id(structName),
+	).Id(funcName).Params(
+		jen.Id("node").Id(typeString),
+	).Bool().Block(stmts...)
+}
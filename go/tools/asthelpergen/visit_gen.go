@@ -32,8 +32,6 @@ var _ generator = (*visitGen)(nil)
 
 func newVisitGen(pkgname string) *visitGen {
 	file := jen.NewFile(pkgname)
-	file.HeaderComment(licenseFileHeader)
-	file.HeaderComment("Code generated by ASTHelperGen. DO NOT EDIT.")
 
 	return &visitGen{
 		file: file,
@@ -35,6 +35,8 @@ import (
 // If visit returns true, the underlying nodes
 // are also visited. If it returns an error, walking
 // is interrupted, and the error is returned.
+// Walk cannot replace nodes, so it doesn't pay for the Cursor and replacer
+// closures that Rewrite uses; prefer it for code that only inspects the AST.
 func Walk(visit Visit, nodes ...SQLNode) error {
 	for _, node := range nodes {
 		err := VisitSQLNode(node, visit)
@@ -0,0 +1,4010 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by ASTHelperGen. DO NOT EDIT.
+
+package sqlparser
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// MarshalSQLNodeJSON returns the JSON representation of an AST. Every node is an object with
+// its type in the "_type" field followed by its struct fields, with their Go names.
+func MarshalSQLNodeJSON(node SQLNode) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := jsonSQLNode(&buf, node); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+func jsonValue(buf *bytes.Buffer, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	buf.Write(data)
+	return nil
+}
+func jsonSQLNode(buf *bytes.Buffer, in SQLNode) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	switch in := in.(type) {
+	case AccessMode:
+		return jsonAccessMode(buf, in)
+	case *AddColumns:
+		return jsonRefOfAddColumns(buf, in)
+	case *AddConstraintDefinition:
+		return jsonRefOfAddConstraintDefinition(buf, in)
+	case *AddIndexDefinition:
+		return jsonRefOfAddIndexDefinition(buf, in)
+	case AlgorithmValue:
+		return jsonAlgorithmValue(buf, in)
+	case *AliasedExpr:
+		return jsonRefOfAliasedExpr(buf, in)
+	case *AliasedTableExpr:
+		return jsonRefOfAliasedTableExpr(buf, in)
+	case *AlterCharset:
+		return jsonRefOfAlterCharset(buf, in)
+	case *AlterColumn:
+		return jsonRefOfAlterColumn(buf, in)
+	case *AlterDatabase:
+		return jsonRefOfAlterDatabase(buf, in)
+	case *AlterMigration:
+		return jsonRefOfAlterMigration(buf, in)
+	case *AlterTable:
+		return jsonRefOfAlterTable(buf, in)
+	case *AlterView:
+		return jsonRefOfAlterView(buf, in)
+	case *AlterVschema:
+		return jsonRefOfAlterVschema(buf, in)
+	case *AndExpr:
+		return jsonRefOfAndExpr(buf, in)
+	case Argument:
+		return jsonArgument(buf, in)
+	case *AutoIncSpec:
+		return jsonRefOfAutoIncSpec(buf, in)
+	case *Begin:
+		return jsonRefOfBegin(buf, in)
+	case *BinaryExpr:
+		return jsonRefOfBinaryExpr(buf, in)
+	case BoolVal:
+		return jsonBoolVal(buf, in)
+	case *CallProc:
+		return jsonRefOfCallProc(buf, in)
+	case *CaseExpr:
+		return jsonRefOfCaseExpr(buf, in)
+	case *ChangeColumn:
+		return jsonRefOfChangeColumn(buf, in)
+	case *CheckConstraintDefinition:
+		return jsonRefOfCheckConstraintDefinition(buf, in)
+	case ColIdent:
+		return jsonColIdent(buf, in)
+	case *ColName:
+		return jsonRefOfColName(buf, in)
+	case *CollateExpr:
+		return jsonRefOfCollateExpr(buf, in)
+	case *ColumnDefinition:
+		return jsonRefOfColumnDefinition(buf, in)
+	case *ColumnType:
+		return jsonRefOfColumnType(buf, in)
+	case Columns:
+		return jsonColumns(buf, in)
+	case Comments:
+		return jsonComments(buf, in)
+	case *Commit:
+		return jsonRefOfCommit(buf, in)
+	case *CommonTableExpr:
+		return jsonRefOfCommonTableExpr(buf, in)
+	case *ComparisonExpr:
+		return jsonRefOfComparisonExpr(buf, in)
+	case *ConstraintDefinition:
+		return jsonRefOfConstraintDefinition(buf, in)
+	case *ConvertExpr:
+		return jsonRefOfConvertExpr(buf, in)
+	case *ConvertType:
+		return jsonRefOfConvertType(buf, in)
+	case *ConvertUsingExpr:
+		return jsonRefOfConvertUsingExpr(buf, in)
+	case *CreateDatabase:
+		return jsonRefOfCreateDatabase(buf, in)
+	case *CreateTable:
+		return jsonRefOfCreateTable(buf, in)
+	case *CreateView:
+		return jsonRefOfCreateView(buf, in)
+	case *CurTimeFuncExpr:
+		return jsonRefOfCurTimeFuncExpr(buf, in)
+	case *Default:
+		return jsonRefOfDefault(buf, in)
+	case *Delete:
+		return jsonRefOfDelete(buf, in)
+	case *DerivedTable:
+		return jsonRefOfDerivedTable(buf, in)
+	case *DropColumn:
+		return jsonRefOfDropColumn(buf, in)
+	case *DropDatabase:
+		return jsonRefOfDropDatabase(buf, in)
+	case *DropKey:
+		return jsonRefOfDropKey(buf, in)
+	case *DropTable:
+		return jsonRefOfDropTable(buf, in)
+	case *DropView:
+		return jsonRefOfDropView(buf, in)
+	case *ExistsExpr:
+		return jsonRefOfExistsExpr(buf, in)
+	case *ExplainStmt:
+		return jsonRefOfExplainStmt(buf, in)
+	case *ExplainTab:
+		return jsonRefOfExplainTab(buf, in)
+	case Exprs:
+		return jsonExprs(buf, in)
+	case *ExtractFuncExpr:
+		return jsonRefOfExtractFuncExpr(buf, in)
+	case *ExtractedSubquery:
+		return jsonRefOfExtractedSubquery(buf, in)
+	case *Flush:
+		return jsonRefOfFlush(buf, in)
+	case *Force:
+		return jsonRefOfForce(buf, in)
+	case *ForeignKeyDefinition:
+		return jsonRefOfForeignKeyDefinition(buf, in)
+	case *FuncExpr:
+		return jsonRefOfFuncExpr(buf, in)
+	case GroupBy:
+		return jsonGroupBy(buf, in)
+	case *GroupConcatExpr:
+		return jsonRefOfGroupConcatExpr(buf, in)
+	case *IndexDefinition:
+		return jsonRefOfIndexDefinition(buf, in)
+	case *IndexHints:
+		return jsonRefOfIndexHints(buf, in)
+	case *IndexInfo:
+		return jsonRefOfIndexInfo(buf, in)
+	case *Insert:
+		return jsonRefOfInsert(buf, in)
+	case *IntervalExpr:
+		return jsonRefOfIntervalExpr(buf, in)
+	case *IsExpr:
+		return jsonRefOfIsExpr(buf, in)
+	case IsolationLevel:
+		return jsonIsolationLevel(buf, in)
+	case *JoinCondition:
+		return jsonRefOfJoinCondition(buf, in)
+	case *JoinTableExpr:
+		return jsonRefOfJoinTableExpr(buf, in)
+	case *KeyState:
+		return jsonRefOfKeyState(buf, in)
+	case *Limit:
+		return jsonRefOfLimit(buf, in)
+	case ListArg:
+		return jsonListArg(buf, in)
+	case *Literal:
+		return jsonRefOfLiteral(buf, in)
+	case *Load:
+		return jsonRefOfLoad(buf, in)
+	case *LockOption:
+		return jsonRefOfLockOption(buf, in)
+	case *LockTables:
+		return jsonRefOfLockTables(buf, in)
+	case *MatchExpr:
+		return jsonRefOfMatchExpr(buf, in)
+	case *ModifyColumn:
+		return jsonRefOfModifyColumn(buf, in)
+	case *Nextval:
+		return jsonRefOfNextval(buf, in)
+	case *NotExpr:
+		return jsonRefOfNotExpr(buf, in)
+	case *NullVal:
+		return jsonRefOfNullVal(buf, in)
+	case OnDup:
+		return jsonOnDup(buf, in)
+	case *OptLike:
+		return jsonRefOfOptLike(buf, in)
+	case *OrExpr:
+		return jsonRefOfOrExpr(buf, in)
+	case *Order:
+		return jsonRefOfOrder(buf, in)
+	case OrderBy:
+		return jsonOrderBy(buf, in)
+	case *OrderByOption:
+		return jsonRefOfOrderByOption(buf, in)
+	case *OtherAdmin:
+		return jsonRefOfOtherAdmin(buf, in)
+	case *OtherRead:
+		return jsonRefOfOtherRead(buf, in)
+	case *ParenTableExpr:
+		return jsonRefOfParenTableExpr(buf, in)
+	case *PartitionDefinition:
+		return jsonRefOfPartitionDefinition(buf, in)
+	case *PartitionSpec:
+		return jsonRefOfPartitionSpec(buf, in)
+	case Partitions:
+		return jsonPartitions(buf, in)
+	case *RangeCond:
+		return jsonRefOfRangeCond(buf, in)
+	case ReferenceAction:
+		return jsonReferenceAction(buf, in)
+	case *ReferenceDefinition:
+		return jsonRefOfReferenceDefinition(buf, in)
+	case *Release:
+		return jsonRefOfRelease(buf, in)
+	case *RenameIndex:
+		return jsonRefOfRenameIndex(buf, in)
+	case *RenameTable:
+		return jsonRefOfRenameTable(buf, in)
+	case *RenameTableName:
+		return jsonRefOfRenameTableName(buf, in)
+	case *RevertMigration:
+		return jsonRefOfRevertMigration(buf, in)
+	case *Rollback:
+		return jsonRefOfRollback(buf, in)
+	case RootNode:
+		return jsonRootNode(buf, in)
+	case *SRollback:
+		return jsonRefOfSRollback(buf, in)
+	case *Savepoint:
+		return jsonRefOfSavepoint(buf, in)
+	case *Select:
+		return jsonRefOfSelect(buf, in)
+	case SelectExprs:
+		return jsonSelectExprs(buf, in)
+	case *SelectInto:
+		return jsonRefOfSelectInto(buf, in)
+	case *Set:
+		return jsonRefOfSet(buf, in)
+	case *SetExpr:
+		return jsonRefOfSetExpr(buf, in)
+	case SetExprs:
+		return jsonSetExprs(buf, in)
+	case *SetTransaction:
+		return jsonRefOfSetTransaction(buf, in)
+	case *Show:
+		return jsonRefOfShow(buf, in)
+	case *ShowBasic:
+		return jsonRefOfShowBasic(buf, in)
+	case *ShowCreate:
+		return jsonRefOfShowCreate(buf, in)
+	case *ShowFilter:
+		return jsonRefOfShowFilter(buf, in)
+	case *ShowLegacy:
+		return jsonRefOfShowLegacy(buf, in)
+	case *ShowMigrationLogs:
+		return jsonRefOfShowMigrationLogs(buf, in)
+	case *StarExpr:
+		return jsonRefOfStarExpr(buf, in)
+	case *Stream:
+		return jsonRefOfStream(buf, in)
+	case *Subquery:
+		return jsonRefOfSubquery(buf, in)
+	case *SubstrExpr:
+		return jsonRefOfSubstrExpr(buf, in)
+	case TableExprs:
+		return jsonTableExprs(buf, in)
+	case TableIdent:
+		return jsonTableIdent(buf, in)
+	case TableName:
+		return jsonTableName(buf, in)
+	case TableNames:
+		return jsonTableNames(buf, in)
+	case TableOptions:
+		return jsonTableOptions(buf, in)
+	case *TableSpec:
+		return jsonRefOfTableSpec(buf, in)
+	case *TablespaceOperation:
+		return jsonRefOfTablespaceOperation(buf, in)
+	case *TimestampFuncExpr:
+		return jsonRefOfTimestampFuncExpr(buf, in)
+	case *TruncateTable:
+		return jsonRefOfTruncateTable(buf, in)
+	case *UnaryExpr:
+		return jsonRefOfUnaryExpr(buf, in)
+	case *Union:
+		return jsonRefOfUnion(buf, in)
+	case *UnlockTables:
+		return jsonRefOfUnlockTables(buf, in)
+	case *Update:
+		return jsonRefOfUpdate(buf, in)
+	case *UpdateExpr:
+		return jsonRefOfUpdateExpr(buf, in)
+	case UpdateExprs:
+		return jsonUpdateExprs(buf, in)
+	case *Use:
+		return jsonRefOfUse(buf, in)
+	case *VStream:
+		return jsonRefOfVStream(buf, in)
+	case ValTuple:
+		return jsonValTuple(buf, in)
+	case *Validation:
+		return jsonRefOfValidation(buf, in)
+	case Values:
+		return jsonValues(buf, in)
+	case *ValuesFuncExpr:
+		return jsonRefOfValuesFuncExpr(buf, in)
+	case VindexParam:
+		return jsonVindexParam(buf, in)
+	case *VindexSpec:
+		return jsonRefOfVindexSpec(buf, in)
+	case *When:
+		return jsonRefOfWhen(buf, in)
+	case *Where:
+		return jsonRefOfWhere(buf, in)
+	case *With:
+		return jsonRefOfWith(buf, in)
+	case *XorExpr:
+		return jsonRefOfXorExpr(buf, in)
+	case *AccessMode:
+		if in == nil {
+			buf.WriteString("null")
+			return nil
+		}
+		return jsonAccessMode(buf, *in)
+	case *AlgorithmValue:
+		if in == nil {
+			buf.WriteString("null")
+			return nil
+		}
+		return jsonAlgorithmValue(buf, *in)
+	case *Argument:
+		if in == nil {
+			buf.WriteString("null")
+			return nil
+		}
+		return jsonArgument(buf, *in)
+	case *BoolVal:
+		if in == nil {
+			buf.WriteString("null")
+			return nil
+		}
+		return jsonBoolVal(buf, *in)
+	case *ColIdent:
+		if in == nil {
+			buf.WriteString("null")
+			return nil
+		}
+		return jsonColIdent(buf, *in)
+	case *Columns:
+		if in == nil {
+			buf.WriteString("null")
+			return nil
+		}
+		return jsonColumns(buf, *in)
+	case *Comments:
+		if in == nil {
+			buf.WriteString("null")
+			return nil
+		}
+		return jsonComments(buf, *in)
+	case *Exprs:
+		if in == nil {
+			buf.WriteString("null")
+			return nil
+		}
+		return jsonExprs(buf, *in)
+	case *GroupBy:
+		if in == nil {
+			buf.WriteString("null")
+			return nil
+		}
+		return jsonGroupBy(buf, *in)
+	case *IsolationLevel:
+		if in == nil {
+			buf.WriteString("null")
+			return nil
+		}
+		return jsonIsolationLevel(buf, *in)
+	case *ListArg:
+		if in == nil {
+			buf.WriteString("null")
+			return nil
+		}
+		return jsonListArg(buf, *in)
+	case *OnDup:
+		if in == nil {
+			buf.WriteString("null")
+			return nil
+		}
+		return jsonOnDup(buf, *in)
+	case *OrderBy:
+		if in == nil {
+			buf.WriteString("null")
+			return nil
+		}
+		return jsonOrderBy(buf, *in)
+	case *Partitions:
+		if in == nil {
+			buf.WriteString("null")
+			return nil
+		}
+		return jsonPartitions(buf, *in)
+	case *ReferenceAction:
+		if in == nil {
+			buf.WriteString("null")
+			return nil
+		}
+		return jsonReferenceAction(buf, *in)
+	case *RootNode:
+		if in == nil {
+			buf.WriteString("null")
+			return nil
+		}
+		return jsonRootNode(buf, *in)
+	case *SelectExprs:
+		if in == nil {
+			buf.WriteString("null")
+			return nil
+		}
+		return jsonSelectExprs(buf, *in)
+	case *SetExprs:
+		if in == nil {
+			buf.WriteString("null")
+			return nil
+		}
+		return jsonSetExprs(buf, *in)
+	case *TableExprs:
+		if in == nil {
+			buf.WriteString("null")
+			return nil
+		}
+		return jsonTableExprs(buf, *in)
+	case *TableIdent:
+		if in == nil {
+			buf.WriteString("null")
+			return nil
+		}
+		return jsonTableIdent(buf, *in)
+	case *TableName:
+		if in == nil {
+			buf.WriteString("null")
+			return nil
+		}
+		return jsonTableName(buf, *in)
+	case *TableNames:
+		if in == nil {
+			buf.WriteString("null")
+			return nil
+		}
+		return jsonTableNames(buf, *in)
+	case *TableOptions:
+		if in == nil {
+			buf.WriteString("null")
+			return nil
+		}
+		return jsonTableOptions(buf, *in)
+	case *UpdateExprs:
+		if in == nil {
+			buf.WriteString("null")
+			return nil
+		}
+		return jsonUpdateExprs(buf, *in)
+	case *ValTuple:
+		if in == nil {
+			buf.WriteString("null")
+			return nil
+		}
+		return jsonValTuple(buf, *in)
+	case *Values:
+		if in == nil {
+			buf.WriteString("null")
+			return nil
+		}
+		return jsonValues(buf, *in)
+	case *VindexParam:
+		if in == nil {
+			buf.WriteString("null")
+			return nil
+		}
+		return jsonVindexParam(buf, *in)
+	default:
+		return fmt.Errorf("unknown SQLNode node type %T", in)
+	}
+}
+func jsonRefOfAddColumns(buf *bytes.Buffer, in *AddColumns) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"AddColumns\"")
+	buf.WriteString(",\"Columns\":")
+	buf.WriteByte('[')
+	for i, el := range in.Columns {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := jsonRefOfColumnDefinition(buf, el); err != nil {
+			return err
+		}
+	}
+	buf.WriteByte(']')
+	buf.WriteString(",\"First\":")
+	if err := jsonValue(buf, in.First); err != nil {
+		return err
+	}
+	buf.WriteString(",\"After\":")
+	if err := jsonRefOfColName(buf, in.After); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfAddConstraintDefinition(buf *bytes.Buffer, in *AddConstraintDefinition) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"AddConstraintDefinition\"")
+	buf.WriteString(",\"ConstraintDefinition\":")
+	if err := jsonRefOfConstraintDefinition(buf, in.ConstraintDefinition); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfAddIndexDefinition(buf *bytes.Buffer, in *AddIndexDefinition) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"AddIndexDefinition\"")
+	buf.WriteString(",\"IndexDefinition\":")
+	if err := jsonRefOfIndexDefinition(buf, in.IndexDefinition); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfAliasedExpr(buf *bytes.Buffer, in *AliasedExpr) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"AliasedExpr\"")
+	buf.WriteString(",\"Expr\":")
+	if err := jsonExpr(buf, in.Expr); err != nil {
+		return err
+	}
+	buf.WriteString(",\"As\":")
+	if err := jsonColIdent(buf, in.As); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfAliasedTableExpr(buf *bytes.Buffer, in *AliasedTableExpr) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"AliasedTableExpr\"")
+	buf.WriteString(",\"Expr\":")
+	if err := jsonSimpleTableExpr(buf, in.Expr); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Partitions\":")
+	if err := jsonPartitions(buf, in.Partitions); err != nil {
+		return err
+	}
+	buf.WriteString(",\"As\":")
+	if err := jsonTableIdent(buf, in.As); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Hints\":")
+	if err := jsonRefOfIndexHints(buf, in.Hints); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Columns\":")
+	if err := jsonColumns(buf, in.Columns); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfAlterCharset(buf *bytes.Buffer, in *AlterCharset) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"AlterCharset\"")
+	buf.WriteString(",\"CharacterSet\":")
+	if err := jsonValue(buf, in.CharacterSet); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Collate\":")
+	if err := jsonValue(buf, in.Collate); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfAlterColumn(buf *bytes.Buffer, in *AlterColumn) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"AlterColumn\"")
+	buf.WriteString(",\"Column\":")
+	if err := jsonRefOfColName(buf, in.Column); err != nil {
+		return err
+	}
+	buf.WriteString(",\"DropDefault\":")
+	if err := jsonValue(buf, in.DropDefault); err != nil {
+		return err
+	}
+	buf.WriteString(",\"DefaultVal\":")
+	if err := jsonExpr(buf, in.DefaultVal); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfAlterDatabase(buf *bytes.Buffer, in *AlterDatabase) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"AlterDatabase\"")
+	buf.WriteString(",\"DBName\":")
+	if err := jsonTableIdent(buf, in.DBName); err != nil {
+		return err
+	}
+	buf.WriteString(",\"UpdateDataDirectory\":")
+	if err := jsonValue(buf, in.UpdateDataDirectory); err != nil {
+		return err
+	}
+	buf.WriteString(",\"AlterOptions\":")
+	if err := jsonValue(buf, in.AlterOptions); err != nil {
+		return err
+	}
+	buf.WriteString(",\"FullyParsed\":")
+	if err := jsonValue(buf, in.FullyParsed); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfAlterMigration(buf *bytes.Buffer, in *AlterMigration) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"AlterMigration\"")
+	buf.WriteString(",\"Type\":")
+	if err := jsonValue(buf, in.Type); err != nil {
+		return err
+	}
+	buf.WriteString(",\"UUID\":")
+	if err := jsonValue(buf, in.UUID); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfAlterTable(buf *bytes.Buffer, in *AlterTable) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"AlterTable\"")
+	buf.WriteString(",\"Table\":")
+	if err := jsonTableName(buf, in.Table); err != nil {
+		return err
+	}
+	buf.WriteString(",\"AlterOptions\":")
+	buf.WriteByte('[')
+	for i, el := range in.AlterOptions {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := jsonAlterOption(buf, el); err != nil {
+			return err
+		}
+	}
+	buf.WriteByte(']')
+	buf.WriteString(",\"PartitionSpec\":")
+	if err := jsonRefOfPartitionSpec(buf, in.PartitionSpec); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Comments\":")
+	if err := jsonComments(buf, in.Comments); err != nil {
+		return err
+	}
+	buf.WriteString(",\"FullyParsed\":")
+	if err := jsonValue(buf, in.FullyParsed); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfAlterView(buf *bytes.Buffer, in *AlterView) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"AlterView\"")
+	buf.WriteString(",\"ViewName\":")
+	if err := jsonTableName(buf, in.ViewName); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Algorithm\":")
+	if err := jsonValue(buf, in.Algorithm); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Definer\":")
+	if err := jsonValue(buf, in.Definer); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Security\":")
+	if err := jsonValue(buf, in.Security); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Columns\":")
+	if err := jsonColumns(buf, in.Columns); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Select\":")
+	if err := jsonSelectStatement(buf, in.Select); err != nil {
+		return err
+	}
+	buf.WriteString(",\"CheckOption\":")
+	if err := jsonValue(buf, in.CheckOption); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfAlterVschema(buf *bytes.Buffer, in *AlterVschema) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"AlterVschema\"")
+	buf.WriteString(",\"Action\":")
+	if err := jsonValue(buf, in.Action); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Table\":")
+	if err := jsonTableName(buf, in.Table); err != nil {
+		return err
+	}
+	buf.WriteString(",\"VindexSpec\":")
+	if err := jsonRefOfVindexSpec(buf, in.VindexSpec); err != nil {
+		return err
+	}
+	buf.WriteString(",\"VindexCols\":")
+	buf.WriteByte('[')
+	for i, el := range in.VindexCols {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := jsonColIdent(buf, el); err != nil {
+			return err
+		}
+	}
+	buf.WriteByte(']')
+	buf.WriteString(",\"AutoIncSpec\":")
+	if err := jsonRefOfAutoIncSpec(buf, in.AutoIncSpec); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfAndExpr(buf *bytes.Buffer, in *AndExpr) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"AndExpr\"")
+	buf.WriteString(",\"Left\":")
+	if err := jsonExpr(buf, in.Left); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Right\":")
+	if err := jsonExpr(buf, in.Right); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfAutoIncSpec(buf *bytes.Buffer, in *AutoIncSpec) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"AutoIncSpec\"")
+	buf.WriteString(",\"Column\":")
+	if err := jsonColIdent(buf, in.Column); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Sequence\":")
+	if err := jsonTableName(buf, in.Sequence); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfBegin(buf *bytes.Buffer, in *Begin) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"Begin\"")
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfBinaryExpr(buf *bytes.Buffer, in *BinaryExpr) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"BinaryExpr\"")
+	buf.WriteString(",\"Operator\":")
+	if err := jsonValue(buf, in.Operator); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Left\":")
+	if err := jsonExpr(buf, in.Left); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Right\":")
+	if err := jsonExpr(buf, in.Right); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfCallProc(buf *bytes.Buffer, in *CallProc) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"CallProc\"")
+	buf.WriteString(",\"Name\":")
+	if err := jsonTableName(buf, in.Name); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Params\":")
+	if err := jsonExprs(buf, in.Params); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfCaseExpr(buf *bytes.Buffer, in *CaseExpr) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"CaseExpr\"")
+	buf.WriteString(",\"Expr\":")
+	if err := jsonExpr(buf, in.Expr); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Whens\":")
+	buf.WriteByte('[')
+	for i, el := range in.Whens {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := jsonRefOfWhen(buf, el); err != nil {
+			return err
+		}
+	}
+	buf.WriteByte(']')
+	buf.WriteString(",\"Else\":")
+	if err := jsonExpr(buf, in.Else); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfChangeColumn(buf *bytes.Buffer, in *ChangeColumn) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"ChangeColumn\"")
+	buf.WriteString(",\"OldColumn\":")
+	if err := jsonRefOfColName(buf, in.OldColumn); err != nil {
+		return err
+	}
+	buf.WriteString(",\"NewColDefinition\":")
+	if err := jsonRefOfColumnDefinition(buf, in.NewColDefinition); err != nil {
+		return err
+	}
+	buf.WriteString(",\"First\":")
+	if err := jsonValue(buf, in.First); err != nil {
+		return err
+	}
+	buf.WriteString(",\"After\":")
+	if err := jsonRefOfColName(buf, in.After); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfCheckConstraintDefinition(buf *bytes.Buffer, in *CheckConstraintDefinition) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"CheckConstraintDefinition\"")
+	buf.WriteString(",\"Expr\":")
+	if err := jsonExpr(buf, in.Expr); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Enforced\":")
+	if err := jsonValue(buf, in.Enforced); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonColIdent(buf *bytes.Buffer, in ColIdent) error {
+	buf.WriteString("{\"_type\":\"ColIdent\"")
+	buf.WriteString(",\"val\":")
+	if err := jsonValue(buf, in.val); err != nil {
+		return err
+	}
+	buf.WriteString(",\"lowered\":")
+	if err := jsonValue(buf, in.lowered); err != nil {
+		return err
+	}
+	buf.WriteString(",\"at\":")
+	if err := jsonValue(buf, in.at); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfColName(buf *bytes.Buffer, in *ColName) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"ColName\"")
+	buf.WriteString(",\"Metadata\":")
+	if err := jsonValue(buf, in.Metadata); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Name\":")
+	if err := jsonColIdent(buf, in.Name); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Qualifier\":")
+	if err := jsonTableName(buf, in.Qualifier); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfCollateExpr(buf *bytes.Buffer, in *CollateExpr) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"CollateExpr\"")
+	buf.WriteString(",\"Expr\":")
+	if err := jsonExpr(buf, in.Expr); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Charset\":")
+	if err := jsonValue(buf, in.Charset); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfColumnDefinition(buf *bytes.Buffer, in *ColumnDefinition) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"ColumnDefinition\"")
+	buf.WriteString(",\"Name\":")
+	if err := jsonColIdent(buf, in.Name); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Type\":")
+	if err := jsonValue(buf, in.Type); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfColumnType(buf *bytes.Buffer, in *ColumnType) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"ColumnType\"")
+	buf.WriteString(",\"Type\":")
+	if err := jsonValue(buf, in.Type); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Options\":")
+	if err := jsonValue(buf, in.Options); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Length\":")
+	if err := jsonRefOfLiteral(buf, in.Length); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Unsigned\":")
+	if err := jsonValue(buf, in.Unsigned); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Zerofill\":")
+	if err := jsonValue(buf, in.Zerofill); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Scale\":")
+	if err := jsonRefOfLiteral(buf, in.Scale); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Charset\":")
+	if err := jsonValue(buf, in.Charset); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Collate\":")
+	if err := jsonValue(buf, in.Collate); err != nil {
+		return err
+	}
+	buf.WriteString(",\"EnumValues\":")
+	if err := jsonValue(buf, in.EnumValues); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonColumns(buf *bytes.Buffer, in Columns) error {
+	buf.WriteByte('[')
+	for i, el := range in {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := jsonColIdent(buf, el); err != nil {
+			return err
+		}
+	}
+	buf.WriteByte(']')
+	return nil
+}
+func jsonComments(buf *bytes.Buffer, in Comments) error {
+	buf.WriteString("{\"_type\":\"Comments\",\"Value\":")
+	if err := jsonValue(buf, in); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfCommit(buf *bytes.Buffer, in *Commit) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"Commit\"")
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfCommonTableExpr(buf *bytes.Buffer, in *CommonTableExpr) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"CommonTableExpr\"")
+	buf.WriteString(",\"TableID\":")
+	if err := jsonTableIdent(buf, in.TableID); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Columns\":")
+	if err := jsonColumns(buf, in.Columns); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Subquery\":")
+	if err := jsonRefOfSubquery(buf, in.Subquery); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfComparisonExpr(buf *bytes.Buffer, in *ComparisonExpr) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"ComparisonExpr\"")
+	buf.WriteString(",\"Operator\":")
+	if err := jsonValue(buf, in.Operator); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Left\":")
+	if err := jsonExpr(buf, in.Left); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Right\":")
+	if err := jsonExpr(buf, in.Right); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Escape\":")
+	if err := jsonExpr(buf, in.Escape); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfConstraintDefinition(buf *bytes.Buffer, in *ConstraintDefinition) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"ConstraintDefinition\"")
+	buf.WriteString(",\"Name\":")
+	if err := jsonColIdent(buf, in.Name); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Details\":")
+	if err := jsonConstraintInfo(buf, in.Details); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfConvertExpr(buf *bytes.Buffer, in *ConvertExpr) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"ConvertExpr\"")
+	buf.WriteString(",\"Expr\":")
+	if err := jsonExpr(buf, in.Expr); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Type\":")
+	if err := jsonRefOfConvertType(buf, in.Type); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfConvertType(buf *bytes.Buffer, in *ConvertType) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"ConvertType\"")
+	buf.WriteString(",\"Type\":")
+	if err := jsonValue(buf, in.Type); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Length\":")
+	if err := jsonRefOfLiteral(buf, in.Length); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Scale\":")
+	if err := jsonRefOfLiteral(buf, in.Scale); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Operator\":")
+	if err := jsonValue(buf, in.Operator); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Charset\":")
+	if err := jsonValue(buf, in.Charset); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfConvertUsingExpr(buf *bytes.Buffer, in *ConvertUsingExpr) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"ConvertUsingExpr\"")
+	buf.WriteString(",\"Expr\":")
+	if err := jsonExpr(buf, in.Expr); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Type\":")
+	if err := jsonValue(buf, in.Type); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfCreateDatabase(buf *bytes.Buffer, in *CreateDatabase) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"CreateDatabase\"")
+	buf.WriteString(",\"Comments\":")
+	if err := jsonComments(buf, in.Comments); err != nil {
+		return err
+	}
+	buf.WriteString(",\"DBName\":")
+	if err := jsonTableIdent(buf, in.DBName); err != nil {
+		return err
+	}
+	buf.WriteString(",\"IfNotExists\":")
+	if err := jsonValue(buf, in.IfNotExists); err != nil {
+		return err
+	}
+	buf.WriteString(",\"CreateOptions\":")
+	if err := jsonValue(buf, in.CreateOptions); err != nil {
+		return err
+	}
+	buf.WriteString(",\"FullyParsed\":")
+	if err := jsonValue(buf, in.FullyParsed); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfCreateTable(buf *bytes.Buffer, in *CreateTable) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"CreateTable\"")
+	buf.WriteString(",\"Temp\":")
+	if err := jsonValue(buf, in.Temp); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Table\":")
+	if err := jsonTableName(buf, in.Table); err != nil {
+		return err
+	}
+	buf.WriteString(",\"IfNotExists\":")
+	if err := jsonValue(buf, in.IfNotExists); err != nil {
+		return err
+	}
+	buf.WriteString(",\"TableSpec\":")
+	if err := jsonRefOfTableSpec(buf, in.TableSpec); err != nil {
+		return err
+	}
+	buf.WriteString(",\"OptLike\":")
+	if err := jsonRefOfOptLike(buf, in.OptLike); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Comments\":")
+	if err := jsonComments(buf, in.Comments); err != nil {
+		return err
+	}
+	buf.WriteString(",\"FullyParsed\":")
+	if err := jsonValue(buf, in.FullyParsed); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfCreateView(buf *bytes.Buffer, in *CreateView) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"CreateView\"")
+	buf.WriteString(",\"ViewName\":")
+	if err := jsonTableName(buf, in.ViewName); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Algorithm\":")
+	if err := jsonValue(buf, in.Algorithm); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Definer\":")
+	if err := jsonValue(buf, in.Definer); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Security\":")
+	if err := jsonValue(buf, in.Security); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Columns\":")
+	if err := jsonColumns(buf, in.Columns); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Select\":")
+	if err := jsonSelectStatement(buf, in.Select); err != nil {
+		return err
+	}
+	buf.WriteString(",\"CheckOption\":")
+	if err := jsonValue(buf, in.CheckOption); err != nil {
+		return err
+	}
+	buf.WriteString(",\"IsReplace\":")
+	if err := jsonValue(buf, in.IsReplace); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfCurTimeFuncExpr(buf *bytes.Buffer, in *CurTimeFuncExpr) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"CurTimeFuncExpr\"")
+	buf.WriteString(",\"Name\":")
+	if err := jsonColIdent(buf, in.Name); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Fsp\":")
+	if err := jsonExpr(buf, in.Fsp); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfDefault(buf *bytes.Buffer, in *Default) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"Default\"")
+	buf.WriteString(",\"ColName\":")
+	if err := jsonValue(buf, in.ColName); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfDelete(buf *bytes.Buffer, in *Delete) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"Delete\"")
+	buf.WriteString(",\"With\":")
+	if err := jsonRefOfWith(buf, in.With); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Ignore\":")
+	if err := jsonValue(buf, in.Ignore); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Comments\":")
+	if err := jsonComments(buf, in.Comments); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Targets\":")
+	if err := jsonTableNames(buf, in.Targets); err != nil {
+		return err
+	}
+	buf.WriteString(",\"TableExprs\":")
+	if err := jsonTableExprs(buf, in.TableExprs); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Partitions\":")
+	if err := jsonPartitions(buf, in.Partitions); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Where\":")
+	if err := jsonRefOfWhere(buf, in.Where); err != nil {
+		return err
+	}
+	buf.WriteString(",\"OrderBy\":")
+	if err := jsonOrderBy(buf, in.OrderBy); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Limit\":")
+	if err := jsonRefOfLimit(buf, in.Limit); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfDerivedTable(buf *bytes.Buffer, in *DerivedTable) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"DerivedTable\"")
+	buf.WriteString(",\"Select\":")
+	if err := jsonSelectStatement(buf, in.Select); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfDropColumn(buf *bytes.Buffer, in *DropColumn) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"DropColumn\"")
+	buf.WriteString(",\"Name\":")
+	if err := jsonRefOfColName(buf, in.Name); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfDropDatabase(buf *bytes.Buffer, in *DropDatabase) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"DropDatabase\"")
+	buf.WriteString(",\"Comments\":")
+	if err := jsonComments(buf, in.Comments); err != nil {
+		return err
+	}
+	buf.WriteString(",\"DBName\":")
+	if err := jsonTableIdent(buf, in.DBName); err != nil {
+		return err
+	}
+	buf.WriteString(",\"IfExists\":")
+	if err := jsonValue(buf, in.IfExists); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfDropKey(buf *bytes.Buffer, in *DropKey) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"DropKey\"")
+	buf.WriteString(",\"Type\":")
+	if err := jsonValue(buf, in.Type); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Name\":")
+	if err := jsonColIdent(buf, in.Name); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfDropTable(buf *bytes.Buffer, in *DropTable) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"DropTable\"")
+	buf.WriteString(",\"Temp\":")
+	if err := jsonValue(buf, in.Temp); err != nil {
+		return err
+	}
+	buf.WriteString(",\"FromTables\":")
+	if err := jsonTableNames(buf, in.FromTables); err != nil {
+		return err
+	}
+	buf.WriteString(",\"IfExists\":")
+	if err := jsonValue(buf, in.IfExists); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Comments\":")
+	if err := jsonComments(buf, in.Comments); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfDropView(buf *bytes.Buffer, in *DropView) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"DropView\"")
+	buf.WriteString(",\"FromTables\":")
+	if err := jsonTableNames(buf, in.FromTables); err != nil {
+		return err
+	}
+	buf.WriteString(",\"IfExists\":")
+	if err := jsonValue(buf, in.IfExists); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfExistsExpr(buf *bytes.Buffer, in *ExistsExpr) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"ExistsExpr\"")
+	buf.WriteString(",\"Subquery\":")
+	if err := jsonRefOfSubquery(buf, in.Subquery); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfExplainStmt(buf *bytes.Buffer, in *ExplainStmt) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"ExplainStmt\"")
+	buf.WriteString(",\"Type\":")
+	if err := jsonValue(buf, in.Type); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Statement\":")
+	if err := jsonStatement(buf, in.Statement); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfExplainTab(buf *bytes.Buffer, in *ExplainTab) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"ExplainTab\"")
+	buf.WriteString(",\"Table\":")
+	if err := jsonTableName(buf, in.Table); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Wild\":")
+	if err := jsonValue(buf, in.Wild); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonExprs(buf *bytes.Buffer, in Exprs) error {
+	buf.WriteByte('[')
+	for i, el := range in {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := jsonExpr(buf, el); err != nil {
+			return err
+		}
+	}
+	buf.WriteByte(']')
+	return nil
+}
+func jsonRefOfExtractFuncExpr(buf *bytes.Buffer, in *ExtractFuncExpr) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"ExtractFuncExpr\"")
+	buf.WriteString(",\"IntervalTypes\":")
+	if err := jsonValue(buf, in.IntervalTypes); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Expr\":")
+	if err := jsonExpr(buf, in.Expr); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfExtractedSubquery(buf *bytes.Buffer, in *ExtractedSubquery) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"ExtractedSubquery\"")
+	buf.WriteString(",\"Original\":")
+	if err := jsonExpr(buf, in.Original); err != nil {
+		return err
+	}
+	buf.WriteString(",\"OpCode\":")
+	if err := jsonValue(buf, in.OpCode); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Subquery\":")
+	if err := jsonRefOfSubquery(buf, in.Subquery); err != nil {
+		return err
+	}
+	buf.WriteString(",\"OtherSide\":")
+	if err := jsonExpr(buf, in.OtherSide); err != nil {
+		return err
+	}
+	buf.WriteString(",\"NeedsRewrite\":")
+	if err := jsonValue(buf, in.NeedsRewrite); err != nil {
+		return err
+	}
+	buf.WriteString(",\"hasValuesArg\":")
+	if err := jsonValue(buf, in.hasValuesArg); err != nil {
+		return err
+	}
+	buf.WriteString(",\"argName\":")
+	if err := jsonValue(buf, in.argName); err != nil {
+		return err
+	}
+	buf.WriteString(",\"alternative\":")
+	if err := jsonExpr(buf, in.alternative); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfFlush(buf *bytes.Buffer, in *Flush) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"Flush\"")
+	buf.WriteString(",\"IsLocal\":")
+	if err := jsonValue(buf, in.IsLocal); err != nil {
+		return err
+	}
+	buf.WriteString(",\"FlushOptions\":")
+	if err := jsonValue(buf, in.FlushOptions); err != nil {
+		return err
+	}
+	buf.WriteString(",\"TableNames\":")
+	if err := jsonTableNames(buf, in.TableNames); err != nil {
+		return err
+	}
+	buf.WriteString(",\"WithLock\":")
+	if err := jsonValue(buf, in.WithLock); err != nil {
+		return err
+	}
+	buf.WriteString(",\"ForExport\":")
+	if err := jsonValue(buf, in.ForExport); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfForce(buf *bytes.Buffer, in *Force) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"Force\"")
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfForeignKeyDefinition(buf *bytes.Buffer, in *ForeignKeyDefinition) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"ForeignKeyDefinition\"")
+	buf.WriteString(",\"Source\":")
+	if err := jsonColumns(buf, in.Source); err != nil {
+		return err
+	}
+	buf.WriteString(",\"IndexName\":")
+	if err := jsonColIdent(buf, in.IndexName); err != nil {
+		return err
+	}
+	buf.WriteString(",\"ReferenceDefinition\":")
+	if err := jsonRefOfReferenceDefinition(buf, in.ReferenceDefinition); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfFuncExpr(buf *bytes.Buffer, in *FuncExpr) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"FuncExpr\"")
+	buf.WriteString(",\"Qualifier\":")
+	if err := jsonTableIdent(buf, in.Qualifier); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Name\":")
+	if err := jsonColIdent(buf, in.Name); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Distinct\":")
+	if err := jsonValue(buf, in.Distinct); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Exprs\":")
+	if err := jsonSelectExprs(buf, in.Exprs); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonGroupBy(buf *bytes.Buffer, in GroupBy) error {
+	buf.WriteByte('[')
+	for i, el := range in {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := jsonExpr(buf, el); err != nil {
+			return err
+		}
+	}
+	buf.WriteByte(']')
+	return nil
+}
+func jsonRefOfGroupConcatExpr(buf *bytes.Buffer, in *GroupConcatExpr) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"GroupConcatExpr\"")
+	buf.WriteString(",\"Distinct\":")
+	if err := jsonValue(buf, in.Distinct); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Exprs\":")
+	if err := jsonSelectExprs(buf, in.Exprs); err != nil {
+		return err
+	}
+	buf.WriteString(",\"OrderBy\":")
+	if err := jsonOrderBy(buf, in.OrderBy); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Separator\":")
+	if err := jsonValue(buf, in.Separator); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Limit\":")
+	if err := jsonRefOfLimit(buf, in.Limit); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfIndexDefinition(buf *bytes.Buffer, in *IndexDefinition) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"IndexDefinition\"")
+	buf.WriteString(",\"Info\":")
+	if err := jsonRefOfIndexInfo(buf, in.Info); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Columns\":")
+	if err := jsonValue(buf, in.Columns); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Options\":")
+	if err := jsonValue(buf, in.Options); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfIndexHints(buf *bytes.Buffer, in *IndexHints) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"IndexHints\"")
+	buf.WriteString(",\"Type\":")
+	if err := jsonValue(buf, in.Type); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Indexes\":")
+	buf.WriteByte('[')
+	for i, el := range in.Indexes {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := jsonColIdent(buf, el); err != nil {
+			return err
+		}
+	}
+	buf.WriteByte(']')
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfIndexInfo(buf *bytes.Buffer, in *IndexInfo) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"IndexInfo\"")
+	buf.WriteString(",\"Type\":")
+	if err := jsonValue(buf, in.Type); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Name\":")
+	if err := jsonColIdent(buf, in.Name); err != nil {
+		return err
+	}
+	buf.WriteString(",\"ConstraintName\":")
+	if err := jsonColIdent(buf, in.ConstraintName); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Primary\":")
+	if err := jsonValue(buf, in.Primary); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Spatial\":")
+	if err := jsonValue(buf, in.Spatial); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Fulltext\":")
+	if err := jsonValue(buf, in.Fulltext); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Unique\":")
+	if err := jsonValue(buf, in.Unique); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfInsert(buf *bytes.Buffer, in *Insert) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"Insert\"")
+	buf.WriteString(",\"Action\":")
+	if err := jsonValue(buf, in.Action); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Comments\":")
+	if err := jsonComments(buf, in.Comments); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Ignore\":")
+	if err := jsonValue(buf, in.Ignore); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Table\":")
+	if err := jsonTableName(buf, in.Table); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Partitions\":")
+	if err := jsonPartitions(buf, in.Partitions); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Columns\":")
+	if err := jsonColumns(buf, in.Columns); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Rows\":")
+	if err := jsonInsertRows(buf, in.Rows); err != nil {
+		return err
+	}
+	buf.WriteString(",\"OnDup\":")
+	if err := jsonOnDup(buf, in.OnDup); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfIntervalExpr(buf *bytes.Buffer, in *IntervalExpr) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"IntervalExpr\"")
+	buf.WriteString(",\"Expr\":")
+	if err := jsonExpr(buf, in.Expr); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Unit\":")
+	if err := jsonValue(buf, in.Unit); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfIsExpr(buf *bytes.Buffer, in *IsExpr) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"IsExpr\"")
+	buf.WriteString(",\"Left\":")
+	if err := jsonExpr(buf, in.Left); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Right\":")
+	if err := jsonValue(buf, in.Right); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfJoinCondition(buf *bytes.Buffer, in *JoinCondition) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"JoinCondition\"")
+	buf.WriteString(",\"On\":")
+	if err := jsonExpr(buf, in.On); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Using\":")
+	if err := jsonColumns(buf, in.Using); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfJoinTableExpr(buf *bytes.Buffer, in *JoinTableExpr) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"JoinTableExpr\"")
+	buf.WriteString(",\"LeftExpr\":")
+	if err := jsonTableExpr(buf, in.LeftExpr); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Join\":")
+	if err := jsonValue(buf, in.Join); err != nil {
+		return err
+	}
+	buf.WriteString(",\"RightExpr\":")
+	if err := jsonTableExpr(buf, in.RightExpr); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Condition\":")
+	if err := jsonRefOfJoinCondition(buf, in.Condition); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfKeyState(buf *bytes.Buffer, in *KeyState) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"KeyState\"")
+	buf.WriteString(",\"Enable\":")
+	if err := jsonValue(buf, in.Enable); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfLimit(buf *bytes.Buffer, in *Limit) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"Limit\"")
+	buf.WriteString(",\"Offset\":")
+	if err := jsonExpr(buf, in.Offset); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Rowcount\":")
+	if err := jsonExpr(buf, in.Rowcount); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfLiteral(buf *bytes.Buffer, in *Literal) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"Literal\"")
+	buf.WriteString(",\"Type\":")
+	if err := jsonValue(buf, in.Type); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Val\":")
+	if err := jsonValue(buf, in.Val); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfLoad(buf *bytes.Buffer, in *Load) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"Load\"")
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfLockOption(buf *bytes.Buffer, in *LockOption) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"LockOption\"")
+	buf.WriteString(",\"Type\":")
+	if err := jsonValue(buf, in.Type); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfLockTables(buf *bytes.Buffer, in *LockTables) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"LockTables\"")
+	buf.WriteString(",\"Tables\":")
+	if err := jsonValue(buf, in.Tables); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfMatchExpr(buf *bytes.Buffer, in *MatchExpr) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"MatchExpr\"")
+	buf.WriteString(",\"Columns\":")
+	if err := jsonSelectExprs(buf, in.Columns); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Expr\":")
+	if err := jsonExpr(buf, in.Expr); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Option\":")
+	if err := jsonValue(buf, in.Option); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfModifyColumn(buf *bytes.Buffer, in *ModifyColumn) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"ModifyColumn\"")
+	buf.WriteString(",\"NewColDefinition\":")
+	if err := jsonRefOfColumnDefinition(buf, in.NewColDefinition); err != nil {
+		return err
+	}
+	buf.WriteString(",\"First\":")
+	if err := jsonValue(buf, in.First); err != nil {
+		return err
+	}
+	buf.WriteString(",\"After\":")
+	if err := jsonRefOfColName(buf, in.After); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfNextval(buf *bytes.Buffer, in *Nextval) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"Nextval\"")
+	buf.WriteString(",\"Expr\":")
+	if err := jsonExpr(buf, in.Expr); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfNotExpr(buf *bytes.Buffer, in *NotExpr) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"NotExpr\"")
+	buf.WriteString(",\"Expr\":")
+	if err := jsonExpr(buf, in.Expr); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfNullVal(buf *bytes.Buffer, in *NullVal) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"NullVal\"")
+	buf.WriteByte('}')
+	return nil
+}
+func jsonOnDup(buf *bytes.Buffer, in OnDup) error {
+	buf.WriteByte('[')
+	for i, el := range in {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := jsonRefOfUpdateExpr(buf, el); err != nil {
+			return err
+		}
+	}
+	buf.WriteByte(']')
+	return nil
+}
+func jsonRefOfOptLike(buf *bytes.Buffer, in *OptLike) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"OptLike\"")
+	buf.WriteString(",\"LikeTable\":")
+	if err := jsonTableName(buf, in.LikeTable); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfOrExpr(buf *bytes.Buffer, in *OrExpr) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"OrExpr\"")
+	buf.WriteString(",\"Left\":")
+	if err := jsonExpr(buf, in.Left); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Right\":")
+	if err := jsonExpr(buf, in.Right); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfOrder(buf *bytes.Buffer, in *Order) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"Order\"")
+	buf.WriteString(",\"Expr\":")
+	if err := jsonExpr(buf, in.Expr); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Direction\":")
+	if err := jsonValue(buf, in.Direction); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonOrderBy(buf *bytes.Buffer, in OrderBy) error {
+	buf.WriteByte('[')
+	for i, el := range in {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := jsonRefOfOrder(buf, el); err != nil {
+			return err
+		}
+	}
+	buf.WriteByte(']')
+	return nil
+}
+func jsonRefOfOrderByOption(buf *bytes.Buffer, in *OrderByOption) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"OrderByOption\"")
+	buf.WriteString(",\"Cols\":")
+	if err := jsonColumns(buf, in.Cols); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfOtherAdmin(buf *bytes.Buffer, in *OtherAdmin) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"OtherAdmin\"")
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfOtherRead(buf *bytes.Buffer, in *OtherRead) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"OtherRead\"")
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfParenTableExpr(buf *bytes.Buffer, in *ParenTableExpr) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"ParenTableExpr\"")
+	buf.WriteString(",\"Exprs\":")
+	if err := jsonTableExprs(buf, in.Exprs); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfPartitionDefinition(buf *bytes.Buffer, in *PartitionDefinition) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"PartitionDefinition\"")
+	buf.WriteString(",\"Name\":")
+	if err := jsonColIdent(buf, in.Name); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Limit\":")
+	if err := jsonExpr(buf, in.Limit); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Maxvalue\":")
+	if err := jsonValue(buf, in.Maxvalue); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfPartitionSpec(buf *bytes.Buffer, in *PartitionSpec) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"PartitionSpec\"")
+	buf.WriteString(",\"Action\":")
+	if err := jsonValue(buf, in.Action); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Names\":")
+	if err := jsonPartitions(buf, in.Names); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Number\":")
+	if err := jsonRefOfLiteral(buf, in.Number); err != nil {
+		return err
+	}
+	buf.WriteString(",\"IsAll\":")
+	if err := jsonValue(buf, in.IsAll); err != nil {
+		return err
+	}
+	buf.WriteString(",\"TableName\":")
+	if err := jsonTableName(buf, in.TableName); err != nil {
+		return err
+	}
+	buf.WriteString(",\"WithoutValidation\":")
+	if err := jsonValue(buf, in.WithoutValidation); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Definitions\":")
+	buf.WriteByte('[')
+	for i, el := range in.Definitions {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := jsonRefOfPartitionDefinition(buf, el); err != nil {
+			return err
+		}
+	}
+	buf.WriteByte(']')
+	buf.WriteByte('}')
+	return nil
+}
+func jsonPartitions(buf *bytes.Buffer, in Partitions) error {
+	buf.WriteByte('[')
+	for i, el := range in {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := jsonColIdent(buf, el); err != nil {
+			return err
+		}
+	}
+	buf.WriteByte(']')
+	return nil
+}
+func jsonRefOfRangeCond(buf *bytes.Buffer, in *RangeCond) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"RangeCond\"")
+	buf.WriteString(",\"Operator\":")
+	if err := jsonValue(buf, in.Operator); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Left\":")
+	if err := jsonExpr(buf, in.Left); err != nil {
+		return err
+	}
+	buf.WriteString(",\"From\":")
+	if err := jsonExpr(buf, in.From); err != nil {
+		return err
+	}
+	buf.WriteString(",\"To\":")
+	if err := jsonExpr(buf, in.To); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfReferenceDefinition(buf *bytes.Buffer, in *ReferenceDefinition) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"ReferenceDefinition\"")
+	buf.WriteString(",\"ReferencedTable\":")
+	if err := jsonTableName(buf, in.ReferencedTable); err != nil {
+		return err
+	}
+	buf.WriteString(",\"ReferencedColumns\":")
+	if err := jsonColumns(buf, in.ReferencedColumns); err != nil {
+		return err
+	}
+	buf.WriteString(",\"OnDelete\":")
+	if err := jsonReferenceAction(buf, in.OnDelete); err != nil {
+		return err
+	}
+	buf.WriteString(",\"OnUpdate\":")
+	if err := jsonReferenceAction(buf, in.OnUpdate); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfRelease(buf *bytes.Buffer, in *Release) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"Release\"")
+	buf.WriteString(",\"Name\":")
+	if err := jsonColIdent(buf, in.Name); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfRenameIndex(buf *bytes.Buffer, in *RenameIndex) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"RenameIndex\"")
+	buf.WriteString(",\"OldName\":")
+	if err := jsonColIdent(buf, in.OldName); err != nil {
+		return err
+	}
+	buf.WriteString(",\"NewName\":")
+	if err := jsonColIdent(buf, in.NewName); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfRenameTable(buf *bytes.Buffer, in *RenameTable) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"RenameTable\"")
+	buf.WriteString(",\"TablePairs\":")
+	if err := jsonValue(buf, in.TablePairs); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfRenameTableName(buf *bytes.Buffer, in *RenameTableName) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"RenameTableName\"")
+	buf.WriteString(",\"Table\":")
+	if err := jsonTableName(buf, in.Table); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfRevertMigration(buf *bytes.Buffer, in *RevertMigration) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"RevertMigration\"")
+	buf.WriteString(",\"UUID\":")
+	if err := jsonValue(buf, in.UUID); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Comments\":")
+	if err := jsonComments(buf, in.Comments); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfRollback(buf *bytes.Buffer, in *Rollback) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"Rollback\"")
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRootNode(buf *bytes.Buffer, in RootNode) error {
+	buf.WriteString("{\"_type\":\"RootNode\"")
+	buf.WriteString(",\"SQLNode\":")
+	if err := jsonSQLNode(buf, in.SQLNode); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfSRollback(buf *bytes.Buffer, in *SRollback) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"SRollback\"")
+	buf.WriteString(",\"Name\":")
+	if err := jsonColIdent(buf, in.Name); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfSavepoint(buf *bytes.Buffer, in *Savepoint) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"Savepoint\"")
+	buf.WriteString(",\"Name\":")
+	if err := jsonColIdent(buf, in.Name); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfSelect(buf *bytes.Buffer, in *Select) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"Select\"")
+	buf.WriteString(",\"Cache\":")
+	if err := jsonValue(buf, in.Cache); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Distinct\":")
+	if err := jsonValue(buf, in.Distinct); err != nil {
+		return err
+	}
+	buf.WriteString(",\"StraightJoinHint\":")
+	if err := jsonValue(buf, in.StraightJoinHint); err != nil {
+		return err
+	}
+	buf.WriteString(",\"SQLCalcFoundRows\":")
+	if err := jsonValue(buf, in.SQLCalcFoundRows); err != nil {
+		return err
+	}
+	buf.WriteString(",\"From\":")
+	buf.WriteByte('[')
+	for i, el := range in.From {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := jsonTableExpr(buf, el); err != nil {
+			return err
+		}
+	}
+	buf.WriteByte(']')
+	buf.WriteString(",\"Comments\":")
+	if err := jsonComments(buf, in.Comments); err != nil {
+		return err
+	}
+	buf.WriteString(",\"SelectExprs\":")
+	if err := jsonSelectExprs(buf, in.SelectExprs); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Where\":")
+	if err := jsonRefOfWhere(buf, in.Where); err != nil {
+		return err
+	}
+	buf.WriteString(",\"With\":")
+	if err := jsonRefOfWith(buf, in.With); err != nil {
+		return err
+	}
+	buf.WriteString(",\"GroupBy\":")
+	if err := jsonGroupBy(buf, in.GroupBy); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Having\":")
+	if err := jsonRefOfWhere(buf, in.Having); err != nil {
+		return err
+	}
+	buf.WriteString(",\"OrderBy\":")
+	if err := jsonOrderBy(buf, in.OrderBy); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Limit\":")
+	if err := jsonRefOfLimit(buf, in.Limit); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Lock\":")
+	if err := jsonValue(buf, in.Lock); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Into\":")
+	if err := jsonRefOfSelectInto(buf, in.Into); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonSelectExprs(buf *bytes.Buffer, in SelectExprs) error {
+	buf.WriteByte('[')
+	for i, el := range in {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := jsonSelectExpr(buf, el); err != nil {
+			return err
+		}
+	}
+	buf.WriteByte(']')
+	return nil
+}
+func jsonRefOfSelectInto(buf *bytes.Buffer, in *SelectInto) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"SelectInto\"")
+	buf.WriteString(",\"Type\":")
+	if err := jsonValue(buf, in.Type); err != nil {
+		return err
+	}
+	buf.WriteString(",\"FileName\":")
+	if err := jsonValue(buf, in.FileName); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Charset\":")
+	if err := jsonValue(buf, in.Charset); err != nil {
+		return err
+	}
+	buf.WriteString(",\"FormatOption\":")
+	if err := jsonValue(buf, in.FormatOption); err != nil {
+		return err
+	}
+	buf.WriteString(",\"ExportOption\":")
+	if err := jsonValue(buf, in.ExportOption); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Manifest\":")
+	if err := jsonValue(buf, in.Manifest); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Overwrite\":")
+	if err := jsonValue(buf, in.Overwrite); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfSet(buf *bytes.Buffer, in *Set) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"Set\"")
+	buf.WriteString(",\"Comments\":")
+	if err := jsonComments(buf, in.Comments); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Exprs\":")
+	if err := jsonSetExprs(buf, in.Exprs); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfSetExpr(buf *bytes.Buffer, in *SetExpr) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"SetExpr\"")
+	buf.WriteString(",\"Scope\":")
+	if err := jsonValue(buf, in.Scope); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Name\":")
+	if err := jsonColIdent(buf, in.Name); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Expr\":")
+	if err := jsonExpr(buf, in.Expr); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonSetExprs(buf *bytes.Buffer, in SetExprs) error {
+	buf.WriteByte('[')
+	for i, el := range in {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := jsonRefOfSetExpr(buf, el); err != nil {
+			return err
+		}
+	}
+	buf.WriteByte(']')
+	return nil
+}
+func jsonRefOfSetTransaction(buf *bytes.Buffer, in *SetTransaction) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"SetTransaction\"")
+	buf.WriteString(",\"SQLNode\":")
+	if err := jsonSQLNode(buf, in.SQLNode); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Comments\":")
+	if err := jsonComments(buf, in.Comments); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Scope\":")
+	if err := jsonValue(buf, in.Scope); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Characteristics\":")
+	buf.WriteByte('[')
+	for i, el := range in.Characteristics {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := jsonCharacteristic(buf, el); err != nil {
+			return err
+		}
+	}
+	buf.WriteByte(']')
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfShow(buf *bytes.Buffer, in *Show) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"Show\"")
+	buf.WriteString(",\"Internal\":")
+	if err := jsonShowInternal(buf, in.Internal); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfShowBasic(buf *bytes.Buffer, in *ShowBasic) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"ShowBasic\"")
+	buf.WriteString(",\"Command\":")
+	if err := jsonValue(buf, in.Command); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Full\":")
+	if err := jsonValue(buf, in.Full); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Tbl\":")
+	if err := jsonTableName(buf, in.Tbl); err != nil {
+		return err
+	}
+	buf.WriteString(",\"DbName\":")
+	if err := jsonTableIdent(buf, in.DbName); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Filter\":")
+	if err := jsonRefOfShowFilter(buf, in.Filter); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfShowCreate(buf *bytes.Buffer, in *ShowCreate) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"ShowCreate\"")
+	buf.WriteString(",\"Command\":")
+	if err := jsonValue(buf, in.Command); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Op\":")
+	if err := jsonTableName(buf, in.Op); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfShowFilter(buf *bytes.Buffer, in *ShowFilter) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"ShowFilter\"")
+	buf.WriteString(",\"Like\":")
+	if err := jsonValue(buf, in.Like); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Filter\":")
+	if err := jsonExpr(buf, in.Filter); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfShowLegacy(buf *bytes.Buffer, in *ShowLegacy) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"ShowLegacy\"")
+	buf.WriteString(",\"Extended\":")
+	if err := jsonValue(buf, in.Extended); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Type\":")
+	if err := jsonValue(buf, in.Type); err != nil {
+		return err
+	}
+	buf.WriteString(",\"OnTable\":")
+	if err := jsonTableName(buf, in.OnTable); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Table\":")
+	if err := jsonTableName(buf, in.Table); err != nil {
+		return err
+	}
+	buf.WriteString(",\"ShowTablesOpt\":")
+	if err := jsonValue(buf, in.ShowTablesOpt); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Scope\":")
+	if err := jsonValue(buf, in.Scope); err != nil {
+		return err
+	}
+	buf.WriteString(",\"ShowCollationFilterOpt\":")
+	if err := jsonExpr(buf, in.ShowCollationFilterOpt); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfShowMigrationLogs(buf *bytes.Buffer, in *ShowMigrationLogs) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"ShowMigrationLogs\"")
+	buf.WriteString(",\"UUID\":")
+	if err := jsonValue(buf, in.UUID); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Comments\":")
+	if err := jsonComments(buf, in.Comments); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfStarExpr(buf *bytes.Buffer, in *StarExpr) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"StarExpr\"")
+	buf.WriteString(",\"TableName\":")
+	if err := jsonTableName(buf, in.TableName); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfStream(buf *bytes.Buffer, in *Stream) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"Stream\"")
+	buf.WriteString(",\"Comments\":")
+	if err := jsonComments(buf, in.Comments); err != nil {
+		return err
+	}
+	buf.WriteString(",\"SelectExpr\":")
+	if err := jsonSelectExpr(buf, in.SelectExpr); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Table\":")
+	if err := jsonTableName(buf, in.Table); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfSubquery(buf *bytes.Buffer, in *Subquery) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"Subquery\"")
+	buf.WriteString(",\"Select\":")
+	if err := jsonSelectStatement(buf, in.Select); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfSubstrExpr(buf *bytes.Buffer, in *SubstrExpr) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"SubstrExpr\"")
+	buf.WriteString(",\"Name\":")
+	if err := jsonRefOfColName(buf, in.Name); err != nil {
+		return err
+	}
+	buf.WriteString(",\"StrVal\":")
+	if err := jsonRefOfLiteral(buf, in.StrVal); err != nil {
+		return err
+	}
+	buf.WriteString(",\"From\":")
+	if err := jsonExpr(buf, in.From); err != nil {
+		return err
+	}
+	buf.WriteString(",\"To\":")
+	if err := jsonExpr(buf, in.To); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonTableExprs(buf *bytes.Buffer, in TableExprs) error {
+	buf.WriteByte('[')
+	for i, el := range in {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := jsonTableExpr(buf, el); err != nil {
+			return err
+		}
+	}
+	buf.WriteByte(']')
+	return nil
+}
+func jsonTableIdent(buf *bytes.Buffer, in TableIdent) error {
+	buf.WriteString("{\"_type\":\"TableIdent\"")
+	buf.WriteString(",\"v\":")
+	if err := jsonValue(buf, in.v); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonTableName(buf *bytes.Buffer, in TableName) error {
+	buf.WriteString("{\"_type\":\"TableName\"")
+	buf.WriteString(",\"Name\":")
+	if err := jsonTableIdent(buf, in.Name); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Qualifier\":")
+	if err := jsonTableIdent(buf, in.Qualifier); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonTableNames(buf *bytes.Buffer, in TableNames) error {
+	buf.WriteByte('[')
+	for i, el := range in {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := jsonTableName(buf, el); err != nil {
+			return err
+		}
+	}
+	buf.WriteByte(']')
+	return nil
+}
+func jsonTableOptions(buf *bytes.Buffer, in TableOptions) error {
+	buf.WriteString("{\"_type\":\"TableOptions\",\"Value\":")
+	if err := jsonValue(buf, in); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfTableSpec(buf *bytes.Buffer, in *TableSpec) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"TableSpec\"")
+	buf.WriteString(",\"Columns\":")
+	buf.WriteByte('[')
+	for i, el := range in.Columns {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := jsonRefOfColumnDefinition(buf, el); err != nil {
+			return err
+		}
+	}
+	buf.WriteByte(']')
+	buf.WriteString(",\"Indexes\":")
+	buf.WriteByte('[')
+	for i, el := range in.Indexes {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := jsonRefOfIndexDefinition(buf, el); err != nil {
+			return err
+		}
+	}
+	buf.WriteByte(']')
+	buf.WriteString(",\"Constraints\":")
+	buf.WriteByte('[')
+	for i, el := range in.Constraints {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := jsonRefOfConstraintDefinition(buf, el); err != nil {
+			return err
+		}
+	}
+	buf.WriteByte(']')
+	buf.WriteString(",\"Options\":")
+	if err := jsonTableOptions(buf, in.Options); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfTablespaceOperation(buf *bytes.Buffer, in *TablespaceOperation) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"TablespaceOperation\"")
+	buf.WriteString(",\"Import\":")
+	if err := jsonValue(buf, in.Import); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfTimestampFuncExpr(buf *bytes.Buffer, in *TimestampFuncExpr) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"TimestampFuncExpr\"")
+	buf.WriteString(",\"Name\":")
+	if err := jsonValue(buf, in.Name); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Expr1\":")
+	if err := jsonExpr(buf, in.Expr1); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Expr2\":")
+	if err := jsonExpr(buf, in.Expr2); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Unit\":")
+	if err := jsonValue(buf, in.Unit); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfTruncateTable(buf *bytes.Buffer, in *TruncateTable) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"TruncateTable\"")
+	buf.WriteString(",\"Table\":")
+	if err := jsonTableName(buf, in.Table); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfUnaryExpr(buf *bytes.Buffer, in *UnaryExpr) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"UnaryExpr\"")
+	buf.WriteString(",\"Operator\":")
+	if err := jsonValue(buf, in.Operator); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Expr\":")
+	if err := jsonExpr(buf, in.Expr); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfUnion(buf *bytes.Buffer, in *Union) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"Union\"")
+	buf.WriteString(",\"Left\":")
+	if err := jsonSelectStatement(buf, in.Left); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Right\":")
+	if err := jsonSelectStatement(buf, in.Right); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Distinct\":")
+	if err := jsonValue(buf, in.Distinct); err != nil {
+		return err
+	}
+	buf.WriteString(",\"OrderBy\":")
+	if err := jsonOrderBy(buf, in.OrderBy); err != nil {
+		return err
+	}
+	buf.WriteString(",\"With\":")
+	if err := jsonRefOfWith(buf, in.With); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Limit\":")
+	if err := jsonRefOfLimit(buf, in.Limit); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Lock\":")
+	if err := jsonValue(buf, in.Lock); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Into\":")
+	if err := jsonRefOfSelectInto(buf, in.Into); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfUnlockTables(buf *bytes.Buffer, in *UnlockTables) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"UnlockTables\"")
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfUpdate(buf *bytes.Buffer, in *Update) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"Update\"")
+	buf.WriteString(",\"With\":")
+	if err := jsonRefOfWith(buf, in.With); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Comments\":")
+	if err := jsonComments(buf, in.Comments); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Ignore\":")
+	if err := jsonValue(buf, in.Ignore); err != nil {
+		return err
+	}
+	buf.WriteString(",\"TableExprs\":")
+	if err := jsonTableExprs(buf, in.TableExprs); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Exprs\":")
+	if err := jsonUpdateExprs(buf, in.Exprs); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Where\":")
+	if err := jsonRefOfWhere(buf, in.Where); err != nil {
+		return err
+	}
+	buf.WriteString(",\"OrderBy\":")
+	if err := jsonOrderBy(buf, in.OrderBy); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Limit\":")
+	if err := jsonRefOfLimit(buf, in.Limit); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfUpdateExpr(buf *bytes.Buffer, in *UpdateExpr) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"UpdateExpr\"")
+	buf.WriteString(",\"Name\":")
+	if err := jsonRefOfColName(buf, in.Name); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Expr\":")
+	if err := jsonExpr(buf, in.Expr); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonUpdateExprs(buf *bytes.Buffer, in UpdateExprs) error {
+	buf.WriteByte('[')
+	for i, el := range in {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := jsonRefOfUpdateExpr(buf, el); err != nil {
+			return err
+		}
+	}
+	buf.WriteByte(']')
+	return nil
+}
+func jsonRefOfUse(buf *bytes.Buffer, in *Use) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"Use\"")
+	buf.WriteString(",\"DBName\":")
+	if err := jsonTableIdent(buf, in.DBName); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfVStream(buf *bytes.Buffer, in *VStream) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"VStream\"")
+	buf.WriteString(",\"Comments\":")
+	if err := jsonComments(buf, in.Comments); err != nil {
+		return err
+	}
+	buf.WriteString(",\"SelectExpr\":")
+	if err := jsonSelectExpr(buf, in.SelectExpr); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Table\":")
+	if err := jsonTableName(buf, in.Table); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Where\":")
+	if err := jsonRefOfWhere(buf, in.Where); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Limit\":")
+	if err := jsonRefOfLimit(buf, in.Limit); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonValTuple(buf *bytes.Buffer, in ValTuple) error {
+	buf.WriteByte('[')
+	for i, el := range in {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := jsonExpr(buf, el); err != nil {
+			return err
+		}
+	}
+	buf.WriteByte(']')
+	return nil
+}
+func jsonRefOfValidation(buf *bytes.Buffer, in *Validation) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"Validation\"")
+	buf.WriteString(",\"With\":")
+	if err := jsonValue(buf, in.With); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonValues(buf *bytes.Buffer, in Values) error {
+	buf.WriteByte('[')
+	for i, el := range in {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := jsonValTuple(buf, el); err != nil {
+			return err
+		}
+	}
+	buf.WriteByte(']')
+	return nil
+}
+func jsonRefOfValuesFuncExpr(buf *bytes.Buffer, in *ValuesFuncExpr) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"ValuesFuncExpr\"")
+	buf.WriteString(",\"Name\":")
+	if err := jsonRefOfColName(buf, in.Name); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonVindexParam(buf *bytes.Buffer, in VindexParam) error {
+	buf.WriteString("{\"_type\":\"VindexParam\"")
+	buf.WriteString(",\"Key\":")
+	if err := jsonColIdent(buf, in.Key); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Val\":")
+	if err := jsonValue(buf, in.Val); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfVindexSpec(buf *bytes.Buffer, in *VindexSpec) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"VindexSpec\"")
+	buf.WriteString(",\"Name\":")
+	if err := jsonColIdent(buf, in.Name); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Type\":")
+	if err := jsonColIdent(buf, in.Type); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Params\":")
+	buf.WriteByte('[')
+	for i, el := range in.Params {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := jsonVindexParam(buf, el); err != nil {
+			return err
+		}
+	}
+	buf.WriteByte(']')
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfWhen(buf *bytes.Buffer, in *When) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"When\"")
+	buf.WriteString(",\"Cond\":")
+	if err := jsonExpr(buf, in.Cond); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Val\":")
+	if err := jsonExpr(buf, in.Val); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfWhere(buf *bytes.Buffer, in *Where) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"Where\"")
+	buf.WriteString(",\"Type\":")
+	if err := jsonValue(buf, in.Type); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Expr\":")
+	if err := jsonExpr(buf, in.Expr); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfWith(buf *bytes.Buffer, in *With) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"With\"")
+	buf.WriteString(",\"ctes\":")
+	buf.WriteByte('[')
+	for i, el := range in.ctes {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := jsonRefOfCommonTableExpr(buf, el); err != nil {
+			return err
+		}
+	}
+	buf.WriteByte(']')
+	buf.WriteString(",\"Recursive\":")
+	if err := jsonValue(buf, in.Recursive); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfXorExpr(buf *bytes.Buffer, in *XorExpr) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"XorExpr\"")
+	buf.WriteString(",\"Left\":")
+	if err := jsonExpr(buf, in.Left); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Right\":")
+	if err := jsonExpr(buf, in.Right); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonAlterOption(buf *bytes.Buffer, in AlterOption) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	switch in := in.(type) {
+	case *AddColumns:
+		return jsonRefOfAddColumns(buf, in)
+	case *AddConstraintDefinition:
+		return jsonRefOfAddConstraintDefinition(buf, in)
+	case *AddIndexDefinition:
+		return jsonRefOfAddIndexDefinition(buf, in)
+	case AlgorithmValue:
+		return jsonAlgorithmValue(buf, in)
+	case *AlterCharset:
+		return jsonRefOfAlterCharset(buf, in)
+	case *AlterColumn:
+		return jsonRefOfAlterColumn(buf, in)
+	case *ChangeColumn:
+		return jsonRefOfChangeColumn(buf, in)
+	case *DropColumn:
+		return jsonRefOfDropColumn(buf, in)
+	case *DropKey:
+		return jsonRefOfDropKey(buf, in)
+	case *Force:
+		return jsonRefOfForce(buf, in)
+	case *KeyState:
+		return jsonRefOfKeyState(buf, in)
+	case *LockOption:
+		return jsonRefOfLockOption(buf, in)
+	case *ModifyColumn:
+		return jsonRefOfModifyColumn(buf, in)
+	case *OrderByOption:
+		return jsonRefOfOrderByOption(buf, in)
+	case *RenameIndex:
+		return jsonRefOfRenameIndex(buf, in)
+	case *RenameTableName:
+		return jsonRefOfRenameTableName(buf, in)
+	case TableOptions:
+		return jsonTableOptions(buf, in)
+	case *TablespaceOperation:
+		return jsonRefOfTablespaceOperation(buf, in)
+	case *Validation:
+		return jsonRefOfValidation(buf, in)
+	case *AlgorithmValue:
+		if in == nil {
+			buf.WriteString("null")
+			return nil
+		}
+		return jsonAlgorithmValue(buf, *in)
+	case *TableOptions:
+		if in == nil {
+			buf.WriteString("null")
+			return nil
+		}
+		return jsonTableOptions(buf, *in)
+	default:
+		return fmt.Errorf("unknown SQLNode node type %T", in)
+	}
+}
+func jsonCharacteristic(buf *bytes.Buffer, in Characteristic) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	switch in := in.(type) {
+	case AccessMode:
+		return jsonAccessMode(buf, in)
+	case IsolationLevel:
+		return jsonIsolationLevel(buf, in)
+	case *AccessMode:
+		if in == nil {
+			buf.WriteString("null")
+			return nil
+		}
+		return jsonAccessMode(buf, *in)
+	case *IsolationLevel:
+		if in == nil {
+			buf.WriteString("null")
+			return nil
+		}
+		return jsonIsolationLevel(buf, *in)
+	default:
+		return fmt.Errorf("unknown SQLNode node type %T", in)
+	}
+}
+func jsonColTuple(buf *bytes.Buffer, in ColTuple) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	switch in := in.(type) {
+	case ListArg:
+		return jsonListArg(buf, in)
+	case *Subquery:
+		return jsonRefOfSubquery(buf, in)
+	case ValTuple:
+		return jsonValTuple(buf, in)
+	case *ListArg:
+		if in == nil {
+			buf.WriteString("null")
+			return nil
+		}
+		return jsonListArg(buf, *in)
+	case *ValTuple:
+		if in == nil {
+			buf.WriteString("null")
+			return nil
+		}
+		return jsonValTuple(buf, *in)
+	default:
+		return fmt.Errorf("unknown SQLNode node type %T", in)
+	}
+}
+func jsonConstraintInfo(buf *bytes.Buffer, in ConstraintInfo) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	switch in := in.(type) {
+	case *CheckConstraintDefinition:
+		return jsonRefOfCheckConstraintDefinition(buf, in)
+	case *ForeignKeyDefinition:
+		return jsonRefOfForeignKeyDefinition(buf, in)
+	default:
+		return fmt.Errorf("unknown SQLNode node type %T", in)
+	}
+}
+func jsonDBDDLStatement(buf *bytes.Buffer, in DBDDLStatement) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	switch in := in.(type) {
+	case *AlterDatabase:
+		return jsonRefOfAlterDatabase(buf, in)
+	case *CreateDatabase:
+		return jsonRefOfCreateDatabase(buf, in)
+	case *DropDatabase:
+		return jsonRefOfDropDatabase(buf, in)
+	default:
+		return fmt.Errorf("unknown SQLNode node type %T", in)
+	}
+}
+func jsonDDLStatement(buf *bytes.Buffer, in DDLStatement) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	switch in := in.(type) {
+	case *AlterTable:
+		return jsonRefOfAlterTable(buf, in)
+	case *AlterView:
+		return jsonRefOfAlterView(buf, in)
+	case *CreateTable:
+		return jsonRefOfCreateTable(buf, in)
+	case *CreateView:
+		return jsonRefOfCreateView(buf, in)
+	case *DropTable:
+		return jsonRefOfDropTable(buf, in)
+	case *DropView:
+		return jsonRefOfDropView(buf, in)
+	case *RenameTable:
+		return jsonRefOfRenameTable(buf, in)
+	case *TruncateTable:
+		return jsonRefOfTruncateTable(buf, in)
+	default:
+		return fmt.Errorf("unknown SQLNode node type %T", in)
+	}
+}
+func jsonExplain(buf *bytes.Buffer, in Explain) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	switch in := in.(type) {
+	case *ExplainStmt:
+		return jsonRefOfExplainStmt(buf, in)
+	case *ExplainTab:
+		return jsonRefOfExplainTab(buf, in)
+	default:
+		return fmt.Errorf("unknown SQLNode node type %T", in)
+	}
+}
+func jsonExpr(buf *bytes.Buffer, in Expr) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	switch in := in.(type) {
+	case *AndExpr:
+		return jsonRefOfAndExpr(buf, in)
+	case Argument:
+		return jsonArgument(buf, in)
+	case *BinaryExpr:
+		return jsonRefOfBinaryExpr(buf, in)
+	case BoolVal:
+		return jsonBoolVal(buf, in)
+	case *CaseExpr:
+		return jsonRefOfCaseExpr(buf, in)
+	case *ColName:
+		return jsonRefOfColName(buf, in)
+	case *CollateExpr:
+		return jsonRefOfCollateExpr(buf, in)
+	case *ComparisonExpr:
+		return jsonRefOfComparisonExpr(buf, in)
+	case *ConvertExpr:
+		return jsonRefOfConvertExpr(buf, in)
+	case *ConvertUsingExpr:
+		return jsonRefOfConvertUsingExpr(buf, in)
+	case *CurTimeFuncExpr:
+		return jsonRefOfCurTimeFuncExpr(buf, in)
+	case *Default:
+		return jsonRefOfDefault(buf, in)
+	case *ExistsExpr:
+		return jsonRefOfExistsExpr(buf, in)
+	case *ExtractFuncExpr:
+		return jsonRefOfExtractFuncExpr(buf, in)
+	case *ExtractedSubquery:
+		return jsonRefOfExtractedSubquery(buf, in)
+	case *FuncExpr:
+		return jsonRefOfFuncExpr(buf, in)
+	case *GroupConcatExpr:
+		return jsonRefOfGroupConcatExpr(buf, in)
+	case *IntervalExpr:
+		return jsonRefOfIntervalExpr(buf, in)
+	case *IsExpr:
+		return jsonRefOfIsExpr(buf, in)
+	case ListArg:
+		return jsonListArg(buf, in)
+	case *Literal:
+		return jsonRefOfLiteral(buf, in)
+	case *MatchExpr:
+		return jsonRefOfMatchExpr(buf, in)
+	case *NotExpr:
+		return jsonRefOfNotExpr(buf, in)
+	case *NullVal:
+		return jsonRefOfNullVal(buf, in)
+	case *OrExpr:
+		return jsonRefOfOrExpr(buf, in)
+	case *RangeCond:
+		return jsonRefOfRangeCond(buf, in)
+	case *Subquery:
+		return jsonRefOfSubquery(buf, in)
+	case *SubstrExpr:
+		return jsonRefOfSubstrExpr(buf, in)
+	case *TimestampFuncExpr:
+		return jsonRefOfTimestampFuncExpr(buf, in)
+	case *UnaryExpr:
+		return jsonRefOfUnaryExpr(buf, in)
+	case ValTuple:
+		return jsonValTuple(buf, in)
+	case *ValuesFuncExpr:
+		return jsonRefOfValuesFuncExpr(buf, in)
+	case *XorExpr:
+		return jsonRefOfXorExpr(buf, in)
+	case *Argument:
+		if in == nil {
+			buf.WriteString("null")
+			return nil
+		}
+		return jsonArgument(buf, *in)
+	case *BoolVal:
+		if in == nil {
+			buf.WriteString("null")
+			return nil
+		}
+		return jsonBoolVal(buf, *in)
+	case *ListArg:
+		if in == nil {
+			buf.WriteString("null")
+			return nil
+		}
+		return jsonListArg(buf, *in)
+	case *ValTuple:
+		if in == nil {
+			buf.WriteString("null")
+			return nil
+		}
+		return jsonValTuple(buf, *in)
+	default:
+		return fmt.Errorf("unknown SQLNode node type %T", in)
+	}
+}
+func jsonInsertRows(buf *bytes.Buffer, in InsertRows) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	switch in := in.(type) {
+	case *Select:
+		return jsonRefOfSelect(buf, in)
+	case *Union:
+		return jsonRefOfUnion(buf, in)
+	case Values:
+		return jsonValues(buf, in)
+	case *Values:
+		if in == nil {
+			buf.WriteString("null")
+			return nil
+		}
+		return jsonValues(buf, *in)
+	default:
+		return fmt.Errorf("unknown SQLNode node type %T", in)
+	}
+}
+func jsonSelectExpr(buf *bytes.Buffer, in SelectExpr) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	switch in := in.(type) {
+	case *AliasedExpr:
+		return jsonRefOfAliasedExpr(buf, in)
+	case *Nextval:
+		return jsonRefOfNextval(buf, in)
+	case *StarExpr:
+		return jsonRefOfStarExpr(buf, in)
+	default:
+		return fmt.Errorf("unknown SQLNode node type %T", in)
+	}
+}
+func jsonSelectStatement(buf *bytes.Buffer, in SelectStatement) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	switch in := in.(type) {
+	case *Select:
+		return jsonRefOfSelect(buf, in)
+	case *Union:
+		return jsonRefOfUnion(buf, in)
+	default:
+		return fmt.Errorf("unknown SQLNode node type %T", in)
+	}
+}
+func jsonShowInternal(buf *bytes.Buffer, in ShowInternal) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	switch in := in.(type) {
+	case *ShowBasic:
+		return jsonRefOfShowBasic(buf, in)
+	case *ShowCreate:
+		return jsonRefOfShowCreate(buf, in)
+	case *ShowLegacy:
+		return jsonRefOfShowLegacy(buf, in)
+	default:
+		return fmt.Errorf("unknown SQLNode node type %T", in)
+	}
+}
+func jsonSimpleTableExpr(buf *bytes.Buffer, in SimpleTableExpr) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	switch in := in.(type) {
+	case *DerivedTable:
+		return jsonRefOfDerivedTable(buf, in)
+	case TableName:
+		return jsonTableName(buf, in)
+	case *TableName:
+		if in == nil {
+			buf.WriteString("null")
+			return nil
+		}
+		return jsonTableName(buf, *in)
+	default:
+		return fmt.Errorf("unknown SQLNode node type %T", in)
+	}
+}
+func jsonStatement(buf *bytes.Buffer, in Statement) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	switch in := in.(type) {
+	case *AlterDatabase:
+		return jsonRefOfAlterDatabase(buf, in)
+	case *AlterMigration:
+		return jsonRefOfAlterMigration(buf, in)
+	case *AlterTable:
+		return jsonRefOfAlterTable(buf, in)
+	case *AlterView:
+		return jsonRefOfAlterView(buf, in)
+	case *AlterVschema:
+		return jsonRefOfAlterVschema(buf, in)
+	case *Begin:
+		return jsonRefOfBegin(buf, in)
+	case *CallProc:
+		return jsonRefOfCallProc(buf, in)
+	case *Commit:
+		return jsonRefOfCommit(buf, in)
+	case *CreateDatabase:
+		return jsonRefOfCreateDatabase(buf, in)
+	case *CreateTable:
+		return jsonRefOfCreateTable(buf, in)
+	case *CreateView:
+		return jsonRefOfCreateView(buf, in)
+	case *Delete:
+		return jsonRefOfDelete(buf, in)
+	case *DropDatabase:
+		return jsonRefOfDropDatabase(buf, in)
+	case *DropTable:
+		return jsonRefOfDropTable(buf, in)
+	case *DropView:
+		return jsonRefOfDropView(buf, in)
+	case *ExplainStmt:
+		return jsonRefOfExplainStmt(buf, in)
+	case *ExplainTab:
+		return jsonRefOfExplainTab(buf, in)
+	case *Flush:
+		return jsonRefOfFlush(buf, in)
+	case *Insert:
+		return jsonRefOfInsert(buf, in)
+	case *Load:
+		return jsonRefOfLoad(buf, in)
+	case *LockTables:
+		return jsonRefOfLockTables(buf, in)
+	case *OtherAdmin:
+		return jsonRefOfOtherAdmin(buf, in)
+	case *OtherRead:
+		return jsonRefOfOtherRead(buf, in)
+	case *Release:
+		return jsonRefOfRelease(buf, in)
+	case *RenameTable:
+		return jsonRefOfRenameTable(buf, in)
+	case *RevertMigration:
+		return jsonRefOfRevertMigration(buf, in)
+	case *Rollback:
+		return jsonRefOfRollback(buf, in)
+	case *SRollback:
+		return jsonRefOfSRollback(buf, in)
+	case *Savepoint:
+		return jsonRefOfSavepoint(buf, in)
+	case *Select:
+		return jsonRefOfSelect(buf, in)
+	case *Set:
+		return jsonRefOfSet(buf, in)
+	case *SetTransaction:
+		return jsonRefOfSetTransaction(buf, in)
+	case *Show:
+		return jsonRefOfShow(buf, in)
+	case *ShowMigrationLogs:
+		return jsonRefOfShowMigrationLogs(buf, in)
+	case *Stream:
+		return jsonRefOfStream(buf, in)
+	case *TruncateTable:
+		return jsonRefOfTruncateTable(buf, in)
+	case *Union:
+		return jsonRefOfUnion(buf, in)
+	case *UnlockTables:
+		return jsonRefOfUnlockTables(buf, in)
+	case *Update:
+		return jsonRefOfUpdate(buf, in)
+	case *Use:
+		return jsonRefOfUse(buf, in)
+	case *VStream:
+		return jsonRefOfVStream(buf, in)
+	default:
+		return fmt.Errorf("unknown SQLNode node type %T", in)
+	}
+}
+func jsonTableExpr(buf *bytes.Buffer, in TableExpr) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	switch in := in.(type) {
+	case *AliasedTableExpr:
+		return jsonRefOfAliasedTableExpr(buf, in)
+	case *JoinTableExpr:
+		return jsonRefOfJoinTableExpr(buf, in)
+	case *ParenTableExpr:
+		return jsonRefOfParenTableExpr(buf, in)
+	default:
+		return fmt.Errorf("unknown SQLNode node type %T", in)
+	}
+}
+func jsonAccessMode(buf *bytes.Buffer, in AccessMode) error {
+	buf.WriteString("{\"_type\":\"AccessMode\",\"Value\":")
+	if err := jsonValue(buf, in); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonAlgorithmValue(buf *bytes.Buffer, in AlgorithmValue) error {
+	buf.WriteString("{\"_type\":\"AlgorithmValue\",\"Value\":")
+	if err := jsonValue(buf, in); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonArgument(buf *bytes.Buffer, in Argument) error {
+	buf.WriteString("{\"_type\":\"Argument\",\"Value\":")
+	if err := jsonValue(buf, in); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonBoolVal(buf *bytes.Buffer, in BoolVal) error {
+	buf.WriteString("{\"_type\":\"BoolVal\",\"Value\":")
+	if err := jsonValue(buf, in); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonIsolationLevel(buf *bytes.Buffer, in IsolationLevel) error {
+	buf.WriteString("{\"_type\":\"IsolationLevel\",\"Value\":")
+	if err := jsonValue(buf, in); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonListArg(buf *bytes.Buffer, in ListArg) error {
+	buf.WriteString("{\"_type\":\"ListArg\",\"Value\":")
+	if err := jsonValue(buf, in); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonReferenceAction(buf *bytes.Buffer, in ReferenceAction) error {
+	buf.WriteString("{\"_type\":\"ReferenceAction\",\"Value\":")
+	if err := jsonValue(buf, in); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfColIdent(buf *bytes.Buffer, in *ColIdent) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"ColIdent\"")
+	buf.WriteString(",\"val\":")
+	if err := jsonValue(buf, in.val); err != nil {
+		return err
+	}
+	buf.WriteString(",\"lowered\":")
+	if err := jsonValue(buf, in.lowered); err != nil {
+		return err
+	}
+	buf.WriteString(",\"at\":")
+	if err := jsonValue(buf, in.at); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfRootNode(buf *bytes.Buffer, in *RootNode) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"RootNode\"")
+	buf.WriteString(",\"SQLNode\":")
+	if err := jsonSQLNode(buf, in.SQLNode); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfTableIdent(buf *bytes.Buffer, in *TableIdent) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"TableIdent\"")
+	buf.WriteString(",\"v\":")
+	if err := jsonValue(buf, in.v); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfTableName(buf *bytes.Buffer, in *TableName) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"TableName\"")
+	buf.WriteString(",\"Name\":")
+	if err := jsonTableIdent(buf, in.Name); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Qualifier\":")
+	if err := jsonTableIdent(buf, in.Qualifier); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+func jsonRefOfVindexParam(buf *bytes.Buffer, in *VindexParam) error {
+	if in == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	buf.WriteString("{\"_type\":\"VindexParam\"")
+	buf.WriteString(",\"Key\":")
+	if err := jsonColIdent(buf, in.Key); err != nil {
+		return err
+	}
+	buf.WriteString(",\"Val\":")
+	if err := jsonValue(buf, in.Val); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
@@ -17,6 +17,8 @@ limitations under the License.
 
 package sqlparser
 
+import "reflect"
+
 func (a *application) rewriteSQLNode(parent SQLNode, node SQLNode, replacer replacerFunc) bool {
 	if node == nil {
 		return true
@@ -324,8 +326,12 @@ func (a *application) rewriteSQLNode(parent SQLNode, node SQLNode, replacer repl
 		return a.rewriteRefOfWith(parent, node, replacer)
 	case *XorExpr:
 		return a.rewriteRefOfXorExpr(parent, node, replacer)
+	case *AccessMode, *AlgorithmValue, *Argument, *BoolVal, *ColIdent, *Columns, *Comments, *Exprs, *GroupBy, *IsolationLevel, *ListArg, *OnDup, *OrderBy, *Partitions, *ReferenceAction, *RootNode, *SelectExprs, *SetExprs, *TableExprs, *TableIdent, *TableName, *TableNames, *TableOptions, *UpdateExprs, *ValTuple, *Values, *VindexParam:
+		// these nodes are only visited by value
+		return true
 	default:
-		// this should never happen
+		// this should never happen, unless the AST helpers are out of date
+		a.unknownNode(node)
 		return true
 	}
 }
@@ -333,15 +339,29 @@ func (a *application) rewriteRefOfAddColumns(parent SQLNode, node *AddColumns, r
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*AddColumns)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
 	for x, el := range node.Columns {
+		a.cur.enterField("Columns", x)
 		if !a.rewriteRefOfColumnDefinition(node, el, func(idx int) replacerFunc {
 			return func(newNode, parent SQLNode) {
 				parent.(*AddColumns).Columns[idx] = newNode.(*ColumnDefinition)
@@ -349,17 +369,31 @@ func (a *application) rewriteRefOfAddColumns(parent SQLNode, node *AddColumns, r
 		}(x)) {
 			return false
 		}
+		a.cur.leaveField()
+	}
+	if edits := a.cur.takeEdits(); edits != nil {
+		var result []*ColumnDefinition
+		rebuildSlice(edits, len(node.Columns), func(idx int, inserted SQLNode) {
+			if idx < 0 {
+				result = append(result, inserted.(*ColumnDefinition))
+			} else {
+				result = append(result, node.Columns[idx])
+			}
+		})
+		node.Columns = result
 	}
+	a.cur.enterField("After", -1)
 	if !a.rewriteRefOfColName(node, node.After, func(newNode, parent SQLNode) {
 		parent.(*AddColumns).After = newNode.(*ColName)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -369,24 +403,39 @@ func (a *application) rewriteRefOfAddConstraintDefinition(parent SQLNode, node *
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*AddConstraintDefinition)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	a.cur.enterField("ConstraintDefinition", -1)
 	if !a.rewriteRefOfConstraintDefinition(node, node.ConstraintDefinition, func(newNode, parent SQLNode) {
 		parent.(*AddConstraintDefinition).ConstraintDefinition = newNode.(*ConstraintDefinition)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -396,24 +445,39 @@ func (a *application) rewriteRefOfAddIndexDefinition(parent SQLNode, node *AddIn
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*AddIndexDefinition)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	a.cur.enterField("IndexDefinition", -1)
 	if !a.rewriteRefOfIndexDefinition(node, node.IndexDefinition, func(newNode, parent SQLNode) {
 		parent.(*AddIndexDefinition).IndexDefinition = newNode.(*IndexDefinition)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -423,29 +487,46 @@ func (a *application) rewriteRefOfAliasedExpr(parent SQLNode, node *AliasedExpr,
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*AliasedExpr)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	a.cur.enterField("Expr", -1)
 	if !a.rewriteExpr(node, node.Expr, func(newNode, parent SQLNode) {
 		parent.(*AliasedExpr).Expr = newNode.(Expr)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("As", -1)
 	if !a.rewriteColIdent(node, node.As, func(newNode, parent SQLNode) {
 		parent.(*AliasedExpr).As = newNode.(ColIdent)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -455,44 +536,67 @@ func (a *application) rewriteRefOfAliasedTableExpr(parent SQLNode, node *Aliased
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*AliasedTableExpr)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	a.cur.enterField("Expr", -1)
 	if !a.rewriteSimpleTableExpr(node, node.Expr, func(newNode, parent SQLNode) {
 		parent.(*AliasedTableExpr).Expr = newNode.(SimpleTableExpr)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("Partitions", -1)
 	if !a.rewritePartitions(node, node.Partitions, func(newNode, parent SQLNode) {
 		parent.(*AliasedTableExpr).Partitions = newNode.(Partitions)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("As", -1)
 	if !a.rewriteTableIdent(node, node.As, func(newNode, parent SQLNode) {
 		parent.(*AliasedTableExpr).As = newNode.(TableIdent)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("Hints", -1)
 	if !a.rewriteRefOfIndexHints(node, node.Hints, func(newNode, parent SQLNode) {
 		parent.(*AliasedTableExpr).Hints = newNode.(*IndexHints)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("Columns", -1)
 	if !a.rewriteColumns(node, node.Columns, func(newNode, parent SQLNode) {
 		parent.(*AliasedTableExpr).Columns = newNode.(Columns)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -502,21 +606,31 @@ func (a *application) rewriteRefOfAlterCharset(parent SQLNode, node *AlterCharse
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*AlterCharset)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
-	if a.post != nil {
-		if a.pre == nil {
+	if post != nil {
+		if pre == nil {
 			a.cur.replacer = replacer
 			a.cur.parent = parent
 			a.cur.node = node
 		}
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -526,29 +640,46 @@ func (a *application) rewriteRefOfAlterColumn(parent SQLNode, node *AlterColumn,
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*AlterColumn)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	a.cur.enterField("Column", -1)
 	if !a.rewriteRefOfColName(node, node.Column, func(newNode, parent SQLNode) {
 		parent.(*AlterColumn).Column = newNode.(*ColName)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("DefaultVal", -1)
 	if !a.rewriteExpr(node, node.DefaultVal, func(newNode, parent SQLNode) {
 		parent.(*AlterColumn).DefaultVal = newNode.(Expr)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -558,24 +689,42 @@ func (a *application) rewriteRefOfAlterDatabase(parent SQLNode, node *AlterDatab
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*AlterDatabase)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	outer := a.cur.enclosing
+	a.cur.enclosing = node
+	a.cur.enterField("DBName", -1)
 	if !a.rewriteTableIdent(node, node.DBName, func(newNode, parent SQLNode) {
 		parent.(*AlterDatabase).DBName = newNode.(TableIdent)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	a.cur.enclosing = outer
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -585,21 +734,31 @@ func (a *application) rewriteRefOfAlterMigration(parent SQLNode, node *AlterMigr
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*AlterMigration)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
-	if a.post != nil {
-		if a.pre == nil {
+	if post != nil {
+		if pre == nil {
 			a.cur.replacer = replacer
 			a.cur.parent = parent
 			a.cur.node = node
 		}
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -609,20 +768,38 @@ func (a *application) rewriteRefOfAlterTable(parent SQLNode, node *AlterTable, r
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*AlterTable)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	outer := a.cur.enclosing
+	a.cur.enclosing = node
+	a.cur.enterField("Table", -1)
 	if !a.rewriteTableName(node, node.Table, func(newNode, parent SQLNode) {
 		parent.(*AlterTable).Table = newNode.(TableName)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
 	for x, el := range node.AlterOptions {
+		a.cur.enterField("AlterOptions", x)
 		if !a.rewriteAlterOption(node, el, func(idx int) replacerFunc {
 			return func(newNode, parent SQLNode) {
 				parent.(*AlterTable).AlterOptions[idx] = newNode.(AlterOption)
@@ -630,22 +807,39 @@ func (a *application) rewriteRefOfAlterTable(parent SQLNode, node *AlterTable, r
 		}(x)) {
 			return false
 		}
+		a.cur.leaveField()
+	}
+	if edits := a.cur.takeEdits(); edits != nil {
+		var result []AlterOption
+		rebuildSlice(edits, len(node.AlterOptions), func(idx int, inserted SQLNode) {
+			if idx < 0 {
+				result = append(result, inserted.(AlterOption))
+			} else {
+				result = append(result, node.AlterOptions[idx])
+			}
+		})
+		node.AlterOptions = result
 	}
+	a.cur.enterField("PartitionSpec", -1)
 	if !a.rewriteRefOfPartitionSpec(node, node.PartitionSpec, func(newNode, parent SQLNode) {
 		parent.(*AlterTable).PartitionSpec = newNode.(*PartitionSpec)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("Comments", -1)
 	if !a.rewriteComments(node, node.Comments, func(newNode, parent SQLNode) {
 		parent.(*AlterTable).Comments = newNode.(Comments)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	a.cur.enclosing = outer
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -655,34 +849,56 @@ func (a *application) rewriteRefOfAlterView(parent SQLNode, node *AlterView, rep
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*AlterView)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	outer := a.cur.enclosing
+	a.cur.enclosing = node
+	a.cur.enterField("ViewName", -1)
 	if !a.rewriteTableName(node, node.ViewName, func(newNode, parent SQLNode) {
 		parent.(*AlterView).ViewName = newNode.(TableName)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("Columns", -1)
 	if !a.rewriteColumns(node, node.Columns, func(newNode, parent SQLNode) {
 		parent.(*AlterView).Columns = newNode.(Columns)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("Select", -1)
 	if !a.rewriteSelectStatement(node, node.Select, func(newNode, parent SQLNode) {
 		parent.(*AlterView).Select = newNode.(SelectStatement)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	a.cur.enclosing = outer
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -692,25 +908,45 @@ func (a *application) rewriteRefOfAlterVschema(parent SQLNode, node *AlterVschem
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*AlterVschema)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	outer := a.cur.enclosing
+	a.cur.enclosing = node
+	a.cur.enterField("Table", -1)
 	if !a.rewriteTableName(node, node.Table, func(newNode, parent SQLNode) {
 		parent.(*AlterVschema).Table = newNode.(TableName)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("VindexSpec", -1)
 	if !a.rewriteRefOfVindexSpec(node, node.VindexSpec, func(newNode, parent SQLNode) {
 		parent.(*AlterVschema).VindexSpec = newNode.(*VindexSpec)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
 	for x, el := range node.VindexCols {
+		a.cur.enterField("VindexCols", x)
 		if !a.rewriteColIdent(node, el, func(idx int) replacerFunc {
 			return func(newNode, parent SQLNode) {
 				parent.(*AlterVschema).VindexCols[idx] = newNode.(ColIdent)
@@ -718,17 +954,32 @@ func (a *application) rewriteRefOfAlterVschema(parent SQLNode, node *AlterVschem
 		}(x)) {
 			return false
 		}
+		a.cur.leaveField()
+	}
+	if edits := a.cur.takeEdits(); edits != nil {
+		var result []ColIdent
+		rebuildSlice(edits, len(node.VindexCols), func(idx int, inserted SQLNode) {
+			if idx < 0 {
+				result = append(result, inserted.(ColIdent))
+			} else {
+				result = append(result, node.VindexCols[idx])
+			}
+		})
+		node.VindexCols = result
 	}
+	a.cur.enterField("AutoIncSpec", -1)
 	if !a.rewriteRefOfAutoIncSpec(node, node.AutoIncSpec, func(newNode, parent SQLNode) {
 		parent.(*AlterVschema).AutoIncSpec = newNode.(*AutoIncSpec)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	a.cur.enclosing = outer
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -738,29 +989,46 @@ func (a *application) rewriteRefOfAndExpr(parent SQLNode, node *AndExpr, replace
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*AndExpr)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	a.cur.enterField("Left", -1)
 	if !a.rewriteExpr(node, node.Left, func(newNode, parent SQLNode) {
 		parent.(*AndExpr).Left = newNode.(Expr)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("Right", -1)
 	if !a.rewriteExpr(node, node.Right, func(newNode, parent SQLNode) {
 		parent.(*AndExpr).Right = newNode.(Expr)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -770,29 +1038,46 @@ func (a *application) rewriteRefOfAutoIncSpec(parent SQLNode, node *AutoIncSpec,
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*AutoIncSpec)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	a.cur.enterField("Column", -1)
 	if !a.rewriteColIdent(node, node.Column, func(newNode, parent SQLNode) {
 		parent.(*AutoIncSpec).Column = newNode.(ColIdent)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("Sequence", -1)
 	if !a.rewriteTableName(node, node.Sequence, func(newNode, parent SQLNode) {
 		parent.(*AutoIncSpec).Sequence = newNode.(TableName)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -802,21 +1087,31 @@ func (a *application) rewriteRefOfBegin(parent SQLNode, node *Begin, replacer re
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*Begin)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
-	if a.post != nil {
-		if a.pre == nil {
+	if post != nil {
+		if pre == nil {
 			a.cur.replacer = replacer
 			a.cur.parent = parent
 			a.cur.node = node
 		}
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -826,29 +1121,46 @@ func (a *application) rewriteRefOfBinaryExpr(parent SQLNode, node *BinaryExpr, r
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*BinaryExpr)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	a.cur.enterField("Left", -1)
 	if !a.rewriteExpr(node, node.Left, func(newNode, parent SQLNode) {
 		parent.(*BinaryExpr).Left = newNode.(Expr)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("Right", -1)
 	if !a.rewriteExpr(node, node.Right, func(newNode, parent SQLNode) {
 		parent.(*BinaryExpr).Right = newNode.(Expr)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -858,29 +1170,49 @@ func (a *application) rewriteRefOfCallProc(parent SQLNode, node *CallProc, repla
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*CallProc)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	outer := a.cur.enclosing
+	a.cur.enclosing = node
+	a.cur.enterField("Name", -1)
 	if !a.rewriteTableName(node, node.Name, func(newNode, parent SQLNode) {
 		parent.(*CallProc).Name = newNode.(TableName)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("Params", -1)
 	if !a.rewriteExprs(node, node.Params, func(newNode, parent SQLNode) {
 		parent.(*CallProc).Params = newNode.(Exprs)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	a.cur.enclosing = outer
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -890,20 +1222,36 @@ func (a *application) rewriteRefOfCaseExpr(parent SQLNode, node *CaseExpr, repla
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*CaseExpr)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	a.cur.enterField("Expr", -1)
 	if !a.rewriteExpr(node, node.Expr, func(newNode, parent SQLNode) {
 		parent.(*CaseExpr).Expr = newNode.(Expr)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
 	for x, el := range node.Whens {
+		a.cur.enterField("Whens", x)
 		if !a.rewriteRefOfWhen(node, el, func(idx int) replacerFunc {
 			return func(newNode, parent SQLNode) {
 				parent.(*CaseExpr).Whens[idx] = newNode.(*When)
@@ -911,17 +1259,31 @@ func (a *application) rewriteRefOfCaseExpr(parent SQLNode, node *CaseExpr, repla
 		}(x)) {
 			return false
 		}
+		a.cur.leaveField()
 	}
+	if edits := a.cur.takeEdits(); edits != nil {
+		var result []*When
+		rebuildSlice(edits, len(node.Whens), func(idx int, inserted SQLNode) {
+			if idx < 0 {
+				result = append(result, inserted.(*When))
+			} else {
+				result = append(result, node.Whens[idx])
+			}
+		})
+		node.Whens = result
+	}
+	a.cur.enterField("Else", -1)
 	if !a.rewriteExpr(node, node.Else, func(newNode, parent SQLNode) {
 		parent.(*CaseExpr).Else = newNode.(Expr)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -931,34 +1293,53 @@ func (a *application) rewriteRefOfChangeColumn(parent SQLNode, node *ChangeColum
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*ChangeColumn)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	a.cur.enterField("OldColumn", -1)
 	if !a.rewriteRefOfColName(node, node.OldColumn, func(newNode, parent SQLNode) {
 		parent.(*ChangeColumn).OldColumn = newNode.(*ColName)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("NewColDefinition", -1)
 	if !a.rewriteRefOfColumnDefinition(node, node.NewColDefinition, func(newNode, parent SQLNode) {
 		parent.(*ChangeColumn).NewColDefinition = newNode.(*ColumnDefinition)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("After", -1)
 	if !a.rewriteRefOfColName(node, node.After, func(newNode, parent SQLNode) {
 		parent.(*ChangeColumn).After = newNode.(*ColName)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -968,45 +1349,70 @@ func (a *application) rewriteRefOfCheckConstraintDefinition(parent SQLNode, node
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*CheckConstraintDefinition)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	a.cur.enterField("Expr", -1)
 	if !a.rewriteExpr(node, node.Expr, func(newNode, parent SQLNode) {
 		parent.(*CheckConstraintDefinition).Expr = newNode.(Expr)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
 	return true
 }
 func (a *application) rewriteColIdent(parent SQLNode, node ColIdent, replacer replacerFunc) bool {
-	if a.pre != nil {
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*ColIdent)(nil)).Elem()]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
-	if a.post != nil {
-		if a.pre == nil {
+	if post != nil {
+		if pre == nil {
 			a.cur.replacer = replacer
 			a.cur.parent = parent
 			a.cur.node = node
 		}
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -1016,29 +1422,46 @@ func (a *application) rewriteRefOfColName(parent SQLNode, node *ColName, replace
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*ColName)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	a.cur.enterField("Name", -1)
 	if !a.rewriteColIdent(node, node.Name, func(newNode, parent SQLNode) {
 		parent.(*ColName).Name = newNode.(ColIdent)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("Qualifier", -1)
 	if !a.rewriteTableName(node, node.Qualifier, func(newNode, parent SQLNode) {
 		parent.(*ColName).Qualifier = newNode.(TableName)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -1048,24 +1471,39 @@ func (a *application) rewriteRefOfCollateExpr(parent SQLNode, node *CollateExpr,
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*CollateExpr)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	a.cur.enterField("Expr", -1)
 	if !a.rewriteExpr(node, node.Expr, func(newNode, parent SQLNode) {
 		parent.(*CollateExpr).Expr = newNode.(Expr)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -1075,24 +1513,39 @@ func (a *application) rewriteRefOfColumnDefinition(parent SQLNode, node *ColumnD
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*ColumnDefinition)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	a.cur.enterField("Name", -1)
 	if !a.rewriteColIdent(node, node.Name, func(newNode, parent SQLNode) {
 		parent.(*ColumnDefinition).Name = newNode.(ColIdent)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -1102,29 +1555,46 @@ func (a *application) rewriteRefOfColumnType(parent SQLNode, node *ColumnType, r
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*ColumnType)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	a.cur.enterField("Length", -1)
 	if !a.rewriteRefOfLiteral(node, node.Length, func(newNode, parent SQLNode) {
 		parent.(*ColumnType).Length = newNode.(*Literal)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("Scale", -1)
 	if !a.rewriteRefOfLiteral(node, node.Scale, func(newNode, parent SQLNode) {
 		parent.(*ColumnType).Scale = newNode.(*Literal)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -1134,21 +1604,29 @@ func (a *application) rewriteColumns(parent SQLNode, node Columns, replacer repl
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*Columns)(nil)).Elem()]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		kontinue := !a.pre(&a.cur)
+		kontinue := !pre(&a.cur)
 		if a.cur.revisit {
-			node = a.cur.node.(Columns)
 			a.cur.revisit = false
-			return a.rewriteColumns(parent, node, replacer)
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
 		}
 		if kontinue {
 			return true
 		}
 	}
 	for x, el := range node {
+		a.cur.enterField("", x)
 		if !a.rewriteColIdent(node, el, func(idx int) replacerFunc {
 			return func(newNode, parent SQLNode) {
 				parent.(Columns)[idx] = newNode.(ColIdent)
@@ -1156,12 +1634,25 @@ func (a *application) rewriteColumns(parent SQLNode, node Columns, replacer repl
 		}(x)) {
 			return false
 		}
+		a.cur.leaveField()
 	}
-	if a.post != nil {
+	if edits := a.cur.takeEdits(); edits != nil {
+		var result Columns
+		rebuildSlice(edits, len(node), func(idx int, inserted SQLNode) {
+			if idx < 0 {
+				result = append(result, inserted.(ColIdent))
+			} else {
+				result = append(result, node[idx])
+			}
+		})
+		replacer(result, parent)
+		node = result
+	}
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -1171,27 +1662,31 @@ func (a *application) rewriteComments(parent SQLNode, node Comments, replacer re
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*Comments)(nil)).Elem()]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		kontinue := !a.pre(&a.cur)
+		kontinue := !pre(&a.cur)
 		if a.cur.revisit {
-			node = a.cur.node.(Comments)
 			a.cur.revisit = false
-			return a.rewriteComments(parent, node, replacer)
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
 		}
 		if kontinue {
 			return true
 		}
 	}
-	if a.post != nil {
-		if a.pre == nil {
+	if post != nil {
+		if pre == nil {
 			a.cur.replacer = replacer
 			a.cur.parent = parent
 			a.cur.node = node
 		}
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -1201,21 +1696,31 @@ func (a *application) rewriteRefOfCommit(parent SQLNode, node *Commit, replacer
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*Commit)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
-	if a.post != nil {
-		if a.pre == nil {
+	if post != nil {
+		if pre == nil {
 			a.cur.replacer = replacer
 			a.cur.parent = parent
 			a.cur.node = node
 		}
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -1225,34 +1730,53 @@ func (a *application) rewriteRefOfCommonTableExpr(parent SQLNode, node *CommonTa
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*CommonTableExpr)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	a.cur.enterField("TableID", -1)
 	if !a.rewriteTableIdent(node, node.TableID, func(newNode, parent SQLNode) {
 		parent.(*CommonTableExpr).TableID = newNode.(TableIdent)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("Columns", -1)
 	if !a.rewriteColumns(node, node.Columns, func(newNode, parent SQLNode) {
 		parent.(*CommonTableExpr).Columns = newNode.(Columns)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("Subquery", -1)
 	if !a.rewriteRefOfSubquery(node, node.Subquery, func(newNode, parent SQLNode) {
 		parent.(*CommonTableExpr).Subquery = newNode.(*Subquery)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -1262,34 +1786,53 @@ func (a *application) rewriteRefOfComparisonExpr(parent SQLNode, node *Compariso
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*ComparisonExpr)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	a.cur.enterField("Left", -1)
 	if !a.rewriteExpr(node, node.Left, func(newNode, parent SQLNode) {
 		parent.(*ComparisonExpr).Left = newNode.(Expr)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("Right", -1)
 	if !a.rewriteExpr(node, node.Right, func(newNode, parent SQLNode) {
 		parent.(*ComparisonExpr).Right = newNode.(Expr)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("Escape", -1)
 	if !a.rewriteExpr(node, node.Escape, func(newNode, parent SQLNode) {
 		parent.(*ComparisonExpr).Escape = newNode.(Expr)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -1299,29 +1842,46 @@ func (a *application) rewriteRefOfConstraintDefinition(parent SQLNode, node *Con
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*ConstraintDefinition)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	a.cur.enterField("Name", -1)
 	if !a.rewriteColIdent(node, node.Name, func(newNode, parent SQLNode) {
 		parent.(*ConstraintDefinition).Name = newNode.(ColIdent)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("Details", -1)
 	if !a.rewriteConstraintInfo(node, node.Details, func(newNode, parent SQLNode) {
 		parent.(*ConstraintDefinition).Details = newNode.(ConstraintInfo)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -1331,29 +1891,46 @@ func (a *application) rewriteRefOfConvertExpr(parent SQLNode, node *ConvertExpr,
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*ConvertExpr)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	a.cur.enterField("Expr", -1)
 	if !a.rewriteExpr(node, node.Expr, func(newNode, parent SQLNode) {
 		parent.(*ConvertExpr).Expr = newNode.(Expr)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("Type", -1)
 	if !a.rewriteRefOfConvertType(node, node.Type, func(newNode, parent SQLNode) {
 		parent.(*ConvertExpr).Type = newNode.(*ConvertType)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -1363,29 +1940,46 @@ func (a *application) rewriteRefOfConvertType(parent SQLNode, node *ConvertType,
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*ConvertType)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	a.cur.enterField("Length", -1)
 	if !a.rewriteRefOfLiteral(node, node.Length, func(newNode, parent SQLNode) {
 		parent.(*ConvertType).Length = newNode.(*Literal)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("Scale", -1)
 	if !a.rewriteRefOfLiteral(node, node.Scale, func(newNode, parent SQLNode) {
 		parent.(*ConvertType).Scale = newNode.(*Literal)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -1395,24 +1989,39 @@ func (a *application) rewriteRefOfConvertUsingExpr(parent SQLNode, node *Convert
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*ConvertUsingExpr)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	a.cur.enterField("Expr", -1)
 	if !a.rewriteExpr(node, node.Expr, func(newNode, parent SQLNode) {
 		parent.(*ConvertUsingExpr).Expr = newNode.(Expr)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -1422,29 +2031,49 @@ func (a *application) rewriteRefOfCreateDatabase(parent SQLNode, node *CreateDat
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*CreateDatabase)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	outer := a.cur.enclosing
+	a.cur.enclosing = node
+	a.cur.enterField("Comments", -1)
 	if !a.rewriteComments(node, node.Comments, func(newNode, parent SQLNode) {
 		parent.(*CreateDatabase).Comments = newNode.(Comments)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("DBName", -1)
 	if !a.rewriteTableIdent(node, node.DBName, func(newNode, parent SQLNode) {
 		parent.(*CreateDatabase).DBName = newNode.(TableIdent)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	a.cur.enclosing = outer
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -1454,39 +2083,63 @@ func (a *application) rewriteRefOfCreateTable(parent SQLNode, node *CreateTable,
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*CreateTable)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	outer := a.cur.enclosing
+	a.cur.enclosing = node
+	a.cur.enterField("Table", -1)
 	if !a.rewriteTableName(node, node.Table, func(newNode, parent SQLNode) {
 		parent.(*CreateTable).Table = newNode.(TableName)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("TableSpec", -1)
 	if !a.rewriteRefOfTableSpec(node, node.TableSpec, func(newNode, parent SQLNode) {
 		parent.(*CreateTable).TableSpec = newNode.(*TableSpec)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("OptLike", -1)
 	if !a.rewriteRefOfOptLike(node, node.OptLike, func(newNode, parent SQLNode) {
 		parent.(*CreateTable).OptLike = newNode.(*OptLike)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("Comments", -1)
 	if !a.rewriteComments(node, node.Comments, func(newNode, parent SQLNode) {
 		parent.(*CreateTable).Comments = newNode.(Comments)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	a.cur.enclosing = outer
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -1496,34 +2149,56 @@ func (a *application) rewriteRefOfCreateView(parent SQLNode, node *CreateView, r
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*CreateView)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	outer := a.cur.enclosing
+	a.cur.enclosing = node
+	a.cur.enterField("ViewName", -1)
 	if !a.rewriteTableName(node, node.ViewName, func(newNode, parent SQLNode) {
 		parent.(*CreateView).ViewName = newNode.(TableName)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("Columns", -1)
 	if !a.rewriteColumns(node, node.Columns, func(newNode, parent SQLNode) {
 		parent.(*CreateView).Columns = newNode.(Columns)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("Select", -1)
 	if !a.rewriteSelectStatement(node, node.Select, func(newNode, parent SQLNode) {
 		parent.(*CreateView).Select = newNode.(SelectStatement)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	a.cur.enclosing = outer
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -1533,29 +2208,46 @@ func (a *application) rewriteRefOfCurTimeFuncExpr(parent SQLNode, node *CurTimeF
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*CurTimeFuncExpr)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
-			return true
-		}
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
+			return true
+		}
 	}
+	a.cur.enterField("Name", -1)
 	if !a.rewriteColIdent(node, node.Name, func(newNode, parent SQLNode) {
 		parent.(*CurTimeFuncExpr).Name = newNode.(ColIdent)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("Fsp", -1)
 	if !a.rewriteExpr(node, node.Fsp, func(newNode, parent SQLNode) {
 		parent.(*CurTimeFuncExpr).Fsp = newNode.(Expr)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -1565,21 +2257,31 @@ func (a *application) rewriteRefOfDefault(parent SQLNode, node *Default, replace
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*Default)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
-	if a.post != nil {
-		if a.pre == nil {
+	if post != nil {
+		if pre == nil {
 			a.cur.replacer = replacer
 			a.cur.parent = parent
 			a.cur.node = node
 		}
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -1589,59 +2291,91 @@ func (a *application) rewriteRefOfDelete(parent SQLNode, node *Delete, replacer
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*Delete)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	outer := a.cur.enclosing
+	a.cur.enclosing = node
+	a.cur.enterField("With", -1)
 	if !a.rewriteRefOfWith(node, node.With, func(newNode, parent SQLNode) {
 		parent.(*Delete).With = newNode.(*With)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("Comments", -1)
 	if !a.rewriteComments(node, node.Comments, func(newNode, parent SQLNode) {
 		parent.(*Delete).Comments = newNode.(Comments)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("Targets", -1)
 	if !a.rewriteTableNames(node, node.Targets, func(newNode, parent SQLNode) {
 		parent.(*Delete).Targets = newNode.(TableNames)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("TableExprs", -1)
 	if !a.rewriteTableExprs(node, node.TableExprs, func(newNode, parent SQLNode) {
 		parent.(*Delete).TableExprs = newNode.(TableExprs)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("Partitions", -1)
 	if !a.rewritePartitions(node, node.Partitions, func(newNode, parent SQLNode) {
 		parent.(*Delete).Partitions = newNode.(Partitions)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("Where", -1)
 	if !a.rewriteRefOfWhere(node, node.Where, func(newNode, parent SQLNode) {
 		parent.(*Delete).Where = newNode.(*Where)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("OrderBy", -1)
 	if !a.rewriteOrderBy(node, node.OrderBy, func(newNode, parent SQLNode) {
 		parent.(*Delete).OrderBy = newNode.(OrderBy)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("Limit", -1)
 	if !a.rewriteRefOfLimit(node, node.Limit, func(newNode, parent SQLNode) {
 		parent.(*Delete).Limit = newNode.(*Limit)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	a.cur.enclosing = outer
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -1651,24 +2385,39 @@ func (a *application) rewriteRefOfDerivedTable(parent SQLNode, node *DerivedTabl
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*DerivedTable)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	a.cur.enterField("Select", -1)
 	if !a.rewriteSelectStatement(node, node.Select, func(newNode, parent SQLNode) {
 		parent.(*DerivedTable).Select = newNode.(SelectStatement)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -1678,24 +2427,39 @@ func (a *application) rewriteRefOfDropColumn(parent SQLNode, node *DropColumn, r
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*DropColumn)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	a.cur.enterField("Name", -1)
 	if !a.rewriteRefOfColName(node, node.Name, func(newNode, parent SQLNode) {
 		parent.(*DropColumn).Name = newNode.(*ColName)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -1705,29 +2469,49 @@ func (a *application) rewriteRefOfDropDatabase(parent SQLNode, node *DropDatabas
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*DropDatabase)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	outer := a.cur.enclosing
+	a.cur.enclosing = node
+	a.cur.enterField("Comments", -1)
 	if !a.rewriteComments(node, node.Comments, func(newNode, parent SQLNode) {
 		parent.(*DropDatabase).Comments = newNode.(Comments)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("DBName", -1)
 	if !a.rewriteTableIdent(node, node.DBName, func(newNode, parent SQLNode) {
 		parent.(*DropDatabase).DBName = newNode.(TableIdent)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	a.cur.enclosing = outer
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -1737,24 +2521,39 @@ func (a *application) rewriteRefOfDropKey(parent SQLNode, node *DropKey, replace
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*DropKey)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	a.cur.enterField("Name", -1)
 	if !a.rewriteColIdent(node, node.Name, func(newNode, parent SQLNode) {
 		parent.(*DropKey).Name = newNode.(ColIdent)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -1764,29 +2563,49 @@ func (a *application) rewriteRefOfDropTable(parent SQLNode, node *DropTable, rep
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*DropTable)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	outer := a.cur.enclosing
+	a.cur.enclosing = node
+	a.cur.enterField("FromTables", -1)
 	if !a.rewriteTableNames(node, node.FromTables, func(newNode, parent SQLNode) {
 		parent.(*DropTable).FromTables = newNode.(TableNames)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("Comments", -1)
 	if !a.rewriteComments(node, node.Comments, func(newNode, parent SQLNode) {
 		parent.(*DropTable).Comments = newNode.(Comments)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	a.cur.enclosing = outer
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -1796,24 +2615,42 @@ func (a *application) rewriteRefOfDropView(parent SQLNode, node *DropView, repla
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*DropView)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	outer := a.cur.enclosing
+	a.cur.enclosing = node
+	a.cur.enterField("FromTables", -1)
 	if !a.rewriteTableNames(node, node.FromTables, func(newNode, parent SQLNode) {
 		parent.(*DropView).FromTables = newNode.(TableNames)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	a.cur.enclosing = outer
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -1823,24 +2660,39 @@ func (a *application) rewriteRefOfExistsExpr(parent SQLNode, node *ExistsExpr, r
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*ExistsExpr)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	a.cur.enterField("Subquery", -1)
 	if !a.rewriteRefOfSubquery(node, node.Subquery, func(newNode, parent SQLNode) {
 		parent.(*ExistsExpr).Subquery = newNode.(*Subquery)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -1850,24 +2702,42 @@ func (a *application) rewriteRefOfExplainStmt(parent SQLNode, node *ExplainStmt,
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*ExplainStmt)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	outer := a.cur.enclosing
+	a.cur.enclosing = node
+	a.cur.enterField("Statement", -1)
 	if !a.rewriteStatement(node, node.Statement, func(newNode, parent SQLNode) {
 		parent.(*ExplainStmt).Statement = newNode.(Statement)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	a.cur.enclosing = outer
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -1877,24 +2747,42 @@ func (a *application) rewriteRefOfExplainTab(parent SQLNode, node *ExplainTab, r
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*ExplainTab)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	outer := a.cur.enclosing
+	a.cur.enclosing = node
+	a.cur.enterField("Table", -1)
 	if !a.rewriteTableName(node, node.Table, func(newNode, parent SQLNode) {
 		parent.(*ExplainTab).Table = newNode.(TableName)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	a.cur.enclosing = outer
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -1904,21 +2792,29 @@ func (a *application) rewriteExprs(parent SQLNode, node Exprs, replacer replacer
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*Exprs)(nil)).Elem()]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		kontinue := !a.pre(&a.cur)
+		kontinue := !pre(&a.cur)
 		if a.cur.revisit {
-			node = a.cur.node.(Exprs)
 			a.cur.revisit = false
-			return a.rewriteExprs(parent, node, replacer)
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
 		}
 		if kontinue {
 			return true
 		}
 	}
 	for x, el := range node {
+		a.cur.enterField("", x)
 		if !a.rewriteExpr(node, el, func(idx int) replacerFunc {
 			return func(newNode, parent SQLNode) {
 				parent.(Exprs)[idx] = newNode.(Expr)
@@ -1926,12 +2822,25 @@ func (a *application) rewriteExprs(parent SQLNode, node Exprs, replacer replacer
 		}(x)) {
 			return false
 		}
+		a.cur.leaveField()
 	}
-	if a.post != nil {
+	if edits := a.cur.takeEdits(); edits != nil {
+		var result Exprs
+		rebuildSlice(edits, len(node), func(idx int, inserted SQLNode) {
+			if idx < 0 {
+				result = append(result, inserted.(Expr))
+			} else {
+				result = append(result, node[idx])
+			}
+		})
+		replacer(result, parent)
+		node = result
+	}
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -1941,24 +2850,39 @@ func (a *application) rewriteRefOfExtractFuncExpr(parent SQLNode, node *ExtractF
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*ExtractFuncExpr)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	a.cur.enterField("Expr", -1)
 	if !a.rewriteExpr(node, node.Expr, func(newNode, parent SQLNode) {
 		parent.(*ExtractFuncExpr).Expr = newNode.(Expr)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -1968,39 +2892,60 @@ func (a *application) rewriteRefOfExtractedSubquery(parent SQLNode, node *Extrac
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*ExtractedSubquery)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	a.cur.enterField("Original", -1)
 	if !a.rewriteExpr(node, node.Original, func(newNode, parent SQLNode) {
 		parent.(*ExtractedSubquery).Original = newNode.(Expr)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("Subquery", -1)
 	if !a.rewriteRefOfSubquery(node, node.Subquery, func(newNode, parent SQLNode) {
 		parent.(*ExtractedSubquery).Subquery = newNode.(*Subquery)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("OtherSide", -1)
 	if !a.rewriteExpr(node, node.OtherSide, func(newNode, parent SQLNode) {
 		parent.(*ExtractedSubquery).OtherSide = newNode.(Expr)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("alternative", -1)
 	if !a.rewriteExpr(node, node.alternative, func(newNode, parent SQLNode) {
 		parent.(*ExtractedSubquery).alternative = newNode.(Expr)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -2010,24 +2955,42 @@ func (a *application) rewriteRefOfFlush(parent SQLNode, node *Flush, replacer re
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*Flush)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	outer := a.cur.enclosing
+	a.cur.enclosing = node
+	a.cur.enterField("TableNames", -1)
 	if !a.rewriteTableNames(node, node.TableNames, func(newNode, parent SQLNode) {
 		parent.(*Flush).TableNames = newNode.(TableNames)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	a.cur.enclosing = outer
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -2037,21 +3000,31 @@ func (a *application) rewriteRefOfForce(parent SQLNode, node *Force, replacer re
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*Force)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
-	if a.post != nil {
-		if a.pre == nil {
+	if post != nil {
+		if pre == nil {
 			a.cur.replacer = replacer
 			a.cur.parent = parent
 			a.cur.node = node
 		}
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -2061,34 +3034,53 @@ func (a *application) rewriteRefOfForeignKeyDefinition(parent SQLNode, node *For
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*ForeignKeyDefinition)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	a.cur.enterField("Source", -1)
 	if !a.rewriteColumns(node, node.Source, func(newNode, parent SQLNode) {
 		parent.(*ForeignKeyDefinition).Source = newNode.(Columns)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("IndexName", -1)
 	if !a.rewriteColIdent(node, node.IndexName, func(newNode, parent SQLNode) {
 		parent.(*ForeignKeyDefinition).IndexName = newNode.(ColIdent)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("ReferenceDefinition", -1)
 	if !a.rewriteRefOfReferenceDefinition(node, node.ReferenceDefinition, func(newNode, parent SQLNode) {
 		parent.(*ForeignKeyDefinition).ReferenceDefinition = newNode.(*ReferenceDefinition)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -2098,34 +3090,53 @@ func (a *application) rewriteRefOfFuncExpr(parent SQLNode, node *FuncExpr, repla
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*FuncExpr)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	a.cur.enterField("Qualifier", -1)
 	if !a.rewriteTableIdent(node, node.Qualifier, func(newNode, parent SQLNode) {
 		parent.(*FuncExpr).Qualifier = newNode.(TableIdent)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("Name", -1)
 	if !a.rewriteColIdent(node, node.Name, func(newNode, parent SQLNode) {
 		parent.(*FuncExpr).Name = newNode.(ColIdent)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("Exprs", -1)
 	if !a.rewriteSelectExprs(node, node.Exprs, func(newNode, parent SQLNode) {
 		parent.(*FuncExpr).Exprs = newNode.(SelectExprs)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -2135,21 +3146,29 @@ func (a *application) rewriteGroupBy(parent SQLNode, node GroupBy, replacer repl
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*GroupBy)(nil)).Elem()]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		kontinue := !a.pre(&a.cur)
+		kontinue := !pre(&a.cur)
 		if a.cur.revisit {
-			node = a.cur.node.(GroupBy)
 			a.cur.revisit = false
-			return a.rewriteGroupBy(parent, node, replacer)
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
 		}
 		if kontinue {
 			return true
 		}
 	}
 	for x, el := range node {
+		a.cur.enterField("", x)
 		if !a.rewriteExpr(node, el, func(idx int) replacerFunc {
 			return func(newNode, parent SQLNode) {
 				parent.(GroupBy)[idx] = newNode.(Expr)
@@ -2157,12 +3176,25 @@ func (a *application) rewriteGroupBy(parent SQLNode, node GroupBy, replacer repl
 		}(x)) {
 			return false
 		}
+		a.cur.leaveField()
+	}
+	if edits := a.cur.takeEdits(); edits != nil {
+		var result GroupBy
+		rebuildSlice(edits, len(node), func(idx int, inserted SQLNode) {
+			if idx < 0 {
+				result = append(result, inserted.(Expr))
+			} else {
+				result = append(result, node[idx])
+			}
+		})
+		replacer(result, parent)
+		node = result
 	}
-	if a.post != nil {
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -2172,34 +3204,53 @@ func (a *application) rewriteRefOfGroupConcatExpr(parent SQLNode, node *GroupCon
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*GroupConcatExpr)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	a.cur.enterField("Exprs", -1)
 	if !a.rewriteSelectExprs(node, node.Exprs, func(newNode, parent SQLNode) {
 		parent.(*GroupConcatExpr).Exprs = newNode.(SelectExprs)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("OrderBy", -1)
 	if !a.rewriteOrderBy(node, node.OrderBy, func(newNode, parent SQLNode) {
 		parent.(*GroupConcatExpr).OrderBy = newNode.(OrderBy)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("Limit", -1)
 	if !a.rewriteRefOfLimit(node, node.Limit, func(newNode, parent SQLNode) {
 		parent.(*GroupConcatExpr).Limit = newNode.(*Limit)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -2209,24 +3260,39 @@ func (a *application) rewriteRefOfIndexDefinition(parent SQLNode, node *IndexDef
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*IndexDefinition)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	a.cur.enterField("Info", -1)
 	if !a.rewriteRefOfIndexInfo(node, node.Info, func(newNode, parent SQLNode) {
 		parent.(*IndexDefinition).Info = newNode.(*IndexInfo)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -2236,15 +3302,29 @@ func (a *application) rewriteRefOfIndexHints(parent SQLNode, node *IndexHints, r
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*IndexHints)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
 	for x, el := range node.Indexes {
+		a.cur.enterField("Indexes", x)
 		if !a.rewriteColIdent(node, el, func(idx int) replacerFunc {
 			return func(newNode, parent SQLNode) {
 				parent.(*IndexHints).Indexes[idx] = newNode.(ColIdent)
@@ -2252,12 +3332,24 @@ func (a *application) rewriteRefOfIndexHints(parent SQLNode, node *IndexHints, r
 		}(x)) {
 			return false
 		}
+		a.cur.leaveField()
 	}
-	if a.post != nil {
+	if edits := a.cur.takeEdits(); edits != nil {
+		var result []ColIdent
+		rebuildSlice(edits, len(node.Indexes), func(idx int, inserted SQLNode) {
+			if idx < 0 {
+				result = append(result, inserted.(ColIdent))
+			} else {
+				result = append(result, node.Indexes[idx])
+			}
+		})
+		node.Indexes = result
+	}
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -2267,29 +3359,46 @@ func (a *application) rewriteRefOfIndexInfo(parent SQLNode, node *IndexInfo, rep
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*IndexInfo)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	a.cur.enterField("Name", -1)
 	if !a.rewriteColIdent(node, node.Name, func(newNode, parent SQLNode) {
 		parent.(*IndexInfo).Name = newNode.(ColIdent)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("ConstraintName", -1)
 	if !a.rewriteColIdent(node, node.ConstraintName, func(newNode, parent SQLNode) {
 		parent.(*IndexInfo).ConstraintName = newNode.(ColIdent)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -2299,49 +3408,77 @@ func (a *application) rewriteRefOfInsert(parent SQLNode, node *Insert, replacer
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*Insert)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	outer := a.cur.enclosing
+	a.cur.enclosing = node
+	a.cur.enterField("Comments", -1)
 	if !a.rewriteComments(node, node.Comments, func(newNode, parent SQLNode) {
 		parent.(*Insert).Comments = newNode.(Comments)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("Table", -1)
 	if !a.rewriteTableName(node, node.Table, func(newNode, parent SQLNode) {
 		parent.(*Insert).Table = newNode.(TableName)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("Partitions", -1)
 	if !a.rewritePartitions(node, node.Partitions, func(newNode, parent SQLNode) {
 		parent.(*Insert).Partitions = newNode.(Partitions)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("Columns", -1)
 	if !a.rewriteColumns(node, node.Columns, func(newNode, parent SQLNode) {
 		parent.(*Insert).Columns = newNode.(Columns)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("Rows", -1)
 	if !a.rewriteInsertRows(node, node.Rows, func(newNode, parent SQLNode) {
 		parent.(*Insert).Rows = newNode.(InsertRows)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("OnDup", -1)
 	if !a.rewriteOnDup(node, node.OnDup, func(newNode, parent SQLNode) {
 		parent.(*Insert).OnDup = newNode.(OnDup)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	a.cur.enclosing = outer
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -2351,24 +3488,39 @@ func (a *application) rewriteRefOfIntervalExpr(parent SQLNode, node *IntervalExp
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*IntervalExpr)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	a.cur.enterField("Expr", -1)
 	if !a.rewriteExpr(node, node.Expr, func(newNode, parent SQLNode) {
 		parent.(*IntervalExpr).Expr = newNode.(Expr)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -2378,24 +3530,39 @@ func (a *application) rewriteRefOfIsExpr(parent SQLNode, node *IsExpr, replacer
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*IsExpr)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	a.cur.enterField("Left", -1)
 	if !a.rewriteExpr(node, node.Left, func(newNode, parent SQLNode) {
 		parent.(*IsExpr).Left = newNode.(Expr)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -2405,29 +3572,46 @@ func (a *application) rewriteRefOfJoinCondition(parent SQLNode, node *JoinCondit
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*JoinCondition)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	a.cur.enterField("On", -1)
 	if !a.rewriteExpr(node, node.On, func(newNode, parent SQLNode) {
 		parent.(*JoinCondition).On = newNode.(Expr)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("Using", -1)
 	if !a.rewriteColumns(node, node.Using, func(newNode, parent SQLNode) {
 		parent.(*JoinCondition).Using = newNode.(Columns)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -2437,34 +3621,53 @@ func (a *application) rewriteRefOfJoinTableExpr(parent SQLNode, node *JoinTableE
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*JoinTableExpr)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	a.cur.enterField("LeftExpr", -1)
 	if !a.rewriteTableExpr(node, node.LeftExpr, func(newNode, parent SQLNode) {
 		parent.(*JoinTableExpr).LeftExpr = newNode.(TableExpr)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("RightExpr", -1)
 	if !a.rewriteTableExpr(node, node.RightExpr, func(newNode, parent SQLNode) {
 		parent.(*JoinTableExpr).RightExpr = newNode.(TableExpr)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("Condition", -1)
 	if !a.rewriteRefOfJoinCondition(node, node.Condition, func(newNode, parent SQLNode) {
 		parent.(*JoinTableExpr).Condition = newNode.(*JoinCondition)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -2474,21 +3677,31 @@ func (a *application) rewriteRefOfKeyState(parent SQLNode, node *KeyState, repla
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*KeyState)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
-	if a.post != nil {
-		if a.pre == nil {
+	if post != nil {
+		if pre == nil {
 			a.cur.replacer = replacer
 			a.cur.parent = parent
 			a.cur.node = node
 		}
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -2498,29 +3711,46 @@ func (a *application) rewriteRefOfLimit(parent SQLNode, node *Limit, replacer re
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*Limit)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	a.cur.enterField("Offset", -1)
 	if !a.rewriteExpr(node, node.Offset, func(newNode, parent SQLNode) {
 		parent.(*Limit).Offset = newNode.(Expr)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("Rowcount", -1)
 	if !a.rewriteExpr(node, node.Rowcount, func(newNode, parent SQLNode) {
 		parent.(*Limit).Rowcount = newNode.(Expr)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -2530,21 +3760,31 @@ func (a *application) rewriteRefOfLiteral(parent SQLNode, node *Literal, replace
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*Literal)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
-	if a.post != nil {
-		if a.pre == nil {
+	if post != nil {
+		if pre == nil {
 			a.cur.replacer = replacer
 			a.cur.parent = parent
 			a.cur.node = node
 		}
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -2554,21 +3794,31 @@ func (a *application) rewriteRefOfLoad(parent SQLNode, node *Load, replacer repl
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*Load)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
-	if a.post != nil {
-		if a.pre == nil {
+	if post != nil {
+		if pre == nil {
 			a.cur.replacer = replacer
 			a.cur.parent = parent
 			a.cur.node = node
 		}
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -2578,21 +3828,31 @@ func (a *application) rewriteRefOfLockOption(parent SQLNode, node *LockOption, r
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*LockOption)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
-	if a.post != nil {
-		if a.pre == nil {
+	if post != nil {
+		if pre == nil {
 			a.cur.replacer = replacer
 			a.cur.parent = parent
 			a.cur.node = node
 		}
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -2602,21 +3862,31 @@ func (a *application) rewriteRefOfLockTables(parent SQLNode, node *LockTables, r
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*LockTables)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
-	if a.post != nil {
-		if a.pre == nil {
+	if post != nil {
+		if pre == nil {
 			a.cur.replacer = replacer
 			a.cur.parent = parent
 			a.cur.node = node
 		}
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -2626,29 +3896,46 @@ func (a *application) rewriteRefOfMatchExpr(parent SQLNode, node *MatchExpr, rep
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*MatchExpr)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	a.cur.enterField("Columns", -1)
 	if !a.rewriteSelectExprs(node, node.Columns, func(newNode, parent SQLNode) {
 		parent.(*MatchExpr).Columns = newNode.(SelectExprs)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("Expr", -1)
 	if !a.rewriteExpr(node, node.Expr, func(newNode, parent SQLNode) {
 		parent.(*MatchExpr).Expr = newNode.(Expr)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -2658,29 +3945,46 @@ func (a *application) rewriteRefOfModifyColumn(parent SQLNode, node *ModifyColum
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*ModifyColumn)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	a.cur.enterField("NewColDefinition", -1)
 	if !a.rewriteRefOfColumnDefinition(node, node.NewColDefinition, func(newNode, parent SQLNode) {
 		parent.(*ModifyColumn).NewColDefinition = newNode.(*ColumnDefinition)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("After", -1)
 	if !a.rewriteRefOfColName(node, node.After, func(newNode, parent SQLNode) {
 		parent.(*ModifyColumn).After = newNode.(*ColName)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -2690,24 +3994,39 @@ func (a *application) rewriteRefOfNextval(parent SQLNode, node *Nextval, replace
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*Nextval)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	a.cur.enterField("Expr", -1)
 	if !a.rewriteExpr(node, node.Expr, func(newNode, parent SQLNode) {
 		parent.(*Nextval).Expr = newNode.(Expr)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -2717,24 +4036,39 @@ func (a *application) rewriteRefOfNotExpr(parent SQLNode, node *NotExpr, replace
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*NotExpr)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	a.cur.enterField("Expr", -1)
 	if !a.rewriteExpr(node, node.Expr, func(newNode, parent SQLNode) {
 		parent.(*NotExpr).Expr = newNode.(Expr)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -2744,21 +4078,31 @@ func (a *application) rewriteRefOfNullVal(parent SQLNode, node *NullVal, replace
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*NullVal)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
-	if a.post != nil {
-		if a.pre == nil {
+	if post != nil {
+		if pre == nil {
 			a.cur.replacer = replacer
 			a.cur.parent = parent
 			a.cur.node = node
 		}
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -2768,21 +4112,29 @@ func (a *application) rewriteOnDup(parent SQLNode, node OnDup, replacer replacer
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*OnDup)(nil)).Elem()]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		kontinue := !a.pre(&a.cur)
+		kontinue := !pre(&a.cur)
 		if a.cur.revisit {
-			node = a.cur.node.(OnDup)
 			a.cur.revisit = false
-			return a.rewriteOnDup(parent, node, replacer)
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
 		}
 		if kontinue {
 			return true
 		}
 	}
 	for x, el := range node {
+		a.cur.enterField("", x)
 		if !a.rewriteRefOfUpdateExpr(node, el, func(idx int) replacerFunc {
 			return func(newNode, parent SQLNode) {
 				parent.(OnDup)[idx] = newNode.(*UpdateExpr)
@@ -2790,12 +4142,25 @@ func (a *application) rewriteOnDup(parent SQLNode, node OnDup, replacer replacer
 		}(x)) {
 			return false
 		}
+		a.cur.leaveField()
+	}
+	if edits := a.cur.takeEdits(); edits != nil {
+		var result OnDup
+		rebuildSlice(edits, len(node), func(idx int, inserted SQLNode) {
+			if idx < 0 {
+				result = append(result, inserted.(*UpdateExpr))
+			} else {
+				result = append(result, node[idx])
+			}
+		})
+		replacer(result, parent)
+		node = result
 	}
-	if a.post != nil {
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -2805,24 +4170,39 @@ func (a *application) rewriteRefOfOptLike(parent SQLNode, node *OptLike, replace
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*OptLike)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	a.cur.enterField("LikeTable", -1)
 	if !a.rewriteTableName(node, node.LikeTable, func(newNode, parent SQLNode) {
 		parent.(*OptLike).LikeTable = newNode.(TableName)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -2832,29 +4212,46 @@ func (a *application) rewriteRefOfOrExpr(parent SQLNode, node *OrExpr, replacer
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*OrExpr)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	a.cur.enterField("Left", -1)
 	if !a.rewriteExpr(node, node.Left, func(newNode, parent SQLNode) {
 		parent.(*OrExpr).Left = newNode.(Expr)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("Right", -1)
 	if !a.rewriteExpr(node, node.Right, func(newNode, parent SQLNode) {
 		parent.(*OrExpr).Right = newNode.(Expr)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -2864,24 +4261,39 @@ func (a *application) rewriteRefOfOrder(parent SQLNode, node *Order, replacer re
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*Order)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	a.cur.enterField("Expr", -1)
 	if !a.rewriteExpr(node, node.Expr, func(newNode, parent SQLNode) {
 		parent.(*Order).Expr = newNode.(Expr)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -2891,21 +4303,29 @@ func (a *application) rewriteOrderBy(parent SQLNode, node OrderBy, replacer repl
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*OrderBy)(nil)).Elem()]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		kontinue := !a.pre(&a.cur)
+		kontinue := !pre(&a.cur)
 		if a.cur.revisit {
-			node = a.cur.node.(OrderBy)
 			a.cur.revisit = false
-			return a.rewriteOrderBy(parent, node, replacer)
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
 		}
 		if kontinue {
 			return true
 		}
 	}
 	for x, el := range node {
+		a.cur.enterField("", x)
 		if !a.rewriteRefOfOrder(node, el, func(idx int) replacerFunc {
 			return func(newNode, parent SQLNode) {
 				parent.(OrderBy)[idx] = newNode.(*Order)
@@ -2913,12 +4333,25 @@ func (a *application) rewriteOrderBy(parent SQLNode, node OrderBy, replacer repl
 		}(x)) {
 			return false
 		}
+		a.cur.leaveField()
+	}
+	if edits := a.cur.takeEdits(); edits != nil {
+		var result OrderBy
+		rebuildSlice(edits, len(node), func(idx int, inserted SQLNode) {
+			if idx < 0 {
+				result = append(result, inserted.(*Order))
+			} else {
+				result = append(result, node[idx])
+			}
+		})
+		replacer(result, parent)
+		node = result
 	}
-	if a.post != nil {
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -2928,24 +4361,39 @@ func (a *application) rewriteRefOfOrderByOption(parent SQLNode, node *OrderByOpt
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*OrderByOption)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	a.cur.enterField("Cols", -1)
 	if !a.rewriteColumns(node, node.Cols, func(newNode, parent SQLNode) {
 		parent.(*OrderByOption).Cols = newNode.(Columns)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -2955,21 +4403,31 @@ func (a *application) rewriteRefOfOtherAdmin(parent SQLNode, node *OtherAdmin, r
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*OtherAdmin)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
-	if a.post != nil {
-		if a.pre == nil {
+	if post != nil {
+		if pre == nil {
 			a.cur.replacer = replacer
 			a.cur.parent = parent
 			a.cur.node = node
 		}
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -2979,21 +4437,31 @@ func (a *application) rewriteRefOfOtherRead(parent SQLNode, node *OtherRead, rep
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*OtherRead)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
-	if a.post != nil {
-		if a.pre == nil {
+	if post != nil {
+		if pre == nil {
 			a.cur.replacer = replacer
 			a.cur.parent = parent
 			a.cur.node = node
 		}
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -3003,24 +4471,39 @@ func (a *application) rewriteRefOfParenTableExpr(parent SQLNode, node *ParenTabl
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*ParenTableExpr)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	a.cur.enterField("Exprs", -1)
 	if !a.rewriteTableExprs(node, node.Exprs, func(newNode, parent SQLNode) {
 		parent.(*ParenTableExpr).Exprs = newNode.(TableExprs)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -3030,29 +4513,46 @@ func (a *application) rewriteRefOfPartitionDefinition(parent SQLNode, node *Part
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*PartitionDefinition)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	a.cur.enterField("Name", -1)
 	if !a.rewriteColIdent(node, node.Name, func(newNode, parent SQLNode) {
 		parent.(*PartitionDefinition).Name = newNode.(ColIdent)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("Limit", -1)
 	if !a.rewriteExpr(node, node.Limit, func(newNode, parent SQLNode) {
 		parent.(*PartitionDefinition).Limit = newNode.(Expr)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -3062,30 +4562,50 @@ func (a *application) rewriteRefOfPartitionSpec(parent SQLNode, node *PartitionS
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*PartitionSpec)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	a.cur.enterField("Names", -1)
 	if !a.rewritePartitions(node, node.Names, func(newNode, parent SQLNode) {
 		parent.(*PartitionSpec).Names = newNode.(Partitions)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("Number", -1)
 	if !a.rewriteRefOfLiteral(node, node.Number, func(newNode, parent SQLNode) {
 		parent.(*PartitionSpec).Number = newNode.(*Literal)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("TableName", -1)
 	if !a.rewriteTableName(node, node.TableName, func(newNode, parent SQLNode) {
 		parent.(*PartitionSpec).TableName = newNode.(TableName)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
 	for x, el := range node.Definitions {
+		a.cur.enterField("Definitions", x)
 		if !a.rewriteRefOfPartitionDefinition(node, el, func(idx int) replacerFunc {
 			return func(newNode, parent SQLNode) {
 				parent.(*PartitionSpec).Definitions[idx] = newNode.(*PartitionDefinition)
@@ -3093,12 +4613,24 @@ func (a *application) rewriteRefOfPartitionSpec(parent SQLNode, node *PartitionS
 		}(x)) {
 			return false
 		}
+		a.cur.leaveField()
+	}
+	if edits := a.cur.takeEdits(); edits != nil {
+		var result []*PartitionDefinition
+		rebuildSlice(edits, len(node.Definitions), func(idx int, inserted SQLNode) {
+			if idx < 0 {
+				result = append(result, inserted.(*PartitionDefinition))
+			} else {
+				result = append(result, node.Definitions[idx])
+			}
+		})
+		node.Definitions = result
 	}
-	if a.post != nil {
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -3108,21 +4640,29 @@ func (a *application) rewritePartitions(parent SQLNode, node Partitions, replace
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*Partitions)(nil)).Elem()]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		kontinue := !a.pre(&a.cur)
+		kontinue := !pre(&a.cur)
 		if a.cur.revisit {
-			node = a.cur.node.(Partitions)
 			a.cur.revisit = false
-			return a.rewritePartitions(parent, node, replacer)
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
 		}
 		if kontinue {
 			return true
 		}
 	}
 	for x, el := range node {
+		a.cur.enterField("", x)
 		if !a.rewriteColIdent(node, el, func(idx int) replacerFunc {
 			return func(newNode, parent SQLNode) {
 				parent.(Partitions)[idx] = newNode.(ColIdent)
@@ -3130,12 +4670,25 @@ func (a *application) rewritePartitions(parent SQLNode, node Partitions, replace
 		}(x)) {
 			return false
 		}
+		a.cur.leaveField()
+	}
+	if edits := a.cur.takeEdits(); edits != nil {
+		var result Partitions
+		rebuildSlice(edits, len(node), func(idx int, inserted SQLNode) {
+			if idx < 0 {
+				result = append(result, inserted.(ColIdent))
+			} else {
+				result = append(result, node[idx])
+			}
+		})
+		replacer(result, parent)
+		node = result
 	}
-	if a.post != nil {
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -3145,34 +4698,53 @@ func (a *application) rewriteRefOfRangeCond(parent SQLNode, node *RangeCond, rep
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*RangeCond)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	a.cur.enterField("Left", -1)
 	if !a.rewriteExpr(node, node.Left, func(newNode, parent SQLNode) {
 		parent.(*RangeCond).Left = newNode.(Expr)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("From", -1)
 	if !a.rewriteExpr(node, node.From, func(newNode, parent SQLNode) {
 		parent.(*RangeCond).From = newNode.(Expr)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("To", -1)
 	if !a.rewriteExpr(node, node.To, func(newNode, parent SQLNode) {
 		parent.(*RangeCond).To = newNode.(Expr)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -3182,39 +4754,60 @@ func (a *application) rewriteRefOfReferenceDefinition(parent SQLNode, node *Refe
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*ReferenceDefinition)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	a.cur.enterField("ReferencedTable", -1)
 	if !a.rewriteTableName(node, node.ReferencedTable, func(newNode, parent SQLNode) {
 		parent.(*ReferenceDefinition).ReferencedTable = newNode.(TableName)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("ReferencedColumns", -1)
 	if !a.rewriteColumns(node, node.ReferencedColumns, func(newNode, parent SQLNode) {
 		parent.(*ReferenceDefinition).ReferencedColumns = newNode.(Columns)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("OnDelete", -1)
 	if !a.rewriteReferenceAction(node, node.OnDelete, func(newNode, parent SQLNode) {
 		parent.(*ReferenceDefinition).OnDelete = newNode.(ReferenceAction)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("OnUpdate", -1)
 	if !a.rewriteReferenceAction(node, node.OnUpdate, func(newNode, parent SQLNode) {
 		parent.(*ReferenceDefinition).OnUpdate = newNode.(ReferenceAction)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -3224,24 +4817,42 @@ func (a *application) rewriteRefOfRelease(parent SQLNode, node *Release, replace
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*Release)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	outer := a.cur.enclosing
+	a.cur.enclosing = node
+	a.cur.enterField("Name", -1)
 	if !a.rewriteColIdent(node, node.Name, func(newNode, parent SQLNode) {
 		parent.(*Release).Name = newNode.(ColIdent)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	a.cur.enclosing = outer
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -3251,29 +4862,46 @@ func (a *application) rewriteRefOfRenameIndex(parent SQLNode, node *RenameIndex,
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*RenameIndex)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	a.cur.enterField("OldName", -1)
 	if !a.rewriteColIdent(node, node.OldName, func(newNode, parent SQLNode) {
 		parent.(*RenameIndex).OldName = newNode.(ColIdent)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("NewName", -1)
 	if !a.rewriteColIdent(node, node.NewName, func(newNode, parent SQLNode) {
 		parent.(*RenameIndex).NewName = newNode.(ColIdent)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -3283,21 +4911,31 @@ func (a *application) rewriteRefOfRenameTable(parent SQLNode, node *RenameTable,
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*RenameTable)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
-	if a.post != nil {
-		if a.pre == nil {
+	if post != nil {
+		if pre == nil {
 			a.cur.replacer = replacer
 			a.cur.parent = parent
 			a.cur.node = node
 		}
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -3307,24 +4945,39 @@ func (a *application) rewriteRefOfRenameTableName(parent SQLNode, node *RenameTa
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*RenameTableName)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	a.cur.enterField("Table", -1)
 	if !a.rewriteTableName(node, node.Table, func(newNode, parent SQLNode) {
 		parent.(*RenameTableName).Table = newNode.(TableName)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -3334,24 +4987,42 @@ func (a *application) rewriteRefOfRevertMigration(parent SQLNode, node *RevertMi
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*RevertMigration)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	outer := a.cur.enclosing
+	a.cur.enclosing = node
+	a.cur.enterField("Comments", -1)
 	if !a.rewriteComments(node, node.Comments, func(newNode, parent SQLNode) {
 		parent.(*RevertMigration).Comments = newNode.(Comments)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	a.cur.enclosing = outer
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -3361,45 +5032,70 @@ func (a *application) rewriteRefOfRollback(parent SQLNode, node *Rollback, repla
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*Rollback)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
-	if a.post != nil {
-		if a.pre == nil {
+	if post != nil {
+		if pre == nil {
 			a.cur.replacer = replacer
 			a.cur.parent = parent
 			a.cur.node = node
 		}
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
 	return true
 }
 func (a *application) rewriteRootNode(parent SQLNode, node RootNode, replacer replacerFunc) bool {
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*RootNode)(nil)).Elem()]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	a.cur.enterField("SQLNode", -1)
 	if !a.rewriteSQLNode(node, node.SQLNode, func(newNode, parent SQLNode) {
 		panic("[BUG] tried to replace 'SQLNode' on 'RootNode'")
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -3409,24 +5105,42 @@ func (a *application) rewriteRefOfSRollback(parent SQLNode, node *SRollback, rep
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*SRollback)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	outer := a.cur.enclosing
+	a.cur.enclosing = node
+	a.cur.enterField("Name", -1)
 	if !a.rewriteColIdent(node, node.Name, func(newNode, parent SQLNode) {
 		parent.(*SRollback).Name = newNode.(ColIdent)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	a.cur.enclosing = outer
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -3436,24 +5150,42 @@ func (a *application) rewriteRefOfSavepoint(parent SQLNode, node *Savepoint, rep
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*Savepoint)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	outer := a.cur.enclosing
+	a.cur.enclosing = node
+	a.cur.enterField("Name", -1)
 	if !a.rewriteColIdent(node, node.Name, func(newNode, parent SQLNode) {
 		parent.(*Savepoint).Name = newNode.(ColIdent)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	a.cur.enclosing = outer
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -3463,15 +5195,31 @@ func (a *application) rewriteRefOfSelect(parent SQLNode, node *Select, replacer
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*Select)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	outer := a.cur.enclosing
+	a.cur.enclosing = node
 	for x, el := range node.From {
+		a.cur.enterField("From", x)
 		if !a.rewriteTableExpr(node, el, func(idx int) replacerFunc {
 			return func(newNode, parent SQLNode) {
 				parent.(*Select).From[idx] = newNode.(TableExpr)
@@ -3479,57 +5227,88 @@ func (a *application) rewriteRefOfSelect(parent SQLNode, node *Select, replacer
 		}(x)) {
 			return false
 		}
+		a.cur.leaveField()
+	}
+	if edits := a.cur.takeEdits(); edits != nil {
+		var result []TableExpr
+		rebuildSlice(edits, len(node.From), func(idx int, inserted SQLNode) {
+			if idx < 0 {
+				result = append(result, inserted.(TableExpr))
+			} else {
+				result = append(result, node.From[idx])
+			}
+		})
+		node.From = result
 	}
+	a.cur.enterField("Comments", -1)
 	if !a.rewriteComments(node, node.Comments, func(newNode, parent SQLNode) {
 		parent.(*Select).Comments = newNode.(Comments)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("SelectExprs", -1)
 	if !a.rewriteSelectExprs(node, node.SelectExprs, func(newNode, parent SQLNode) {
 		parent.(*Select).SelectExprs = newNode.(SelectExprs)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("Where", -1)
 	if !a.rewriteRefOfWhere(node, node.Where, func(newNode, parent SQLNode) {
 		parent.(*Select).Where = newNode.(*Where)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("With", -1)
 	if !a.rewriteRefOfWith(node, node.With, func(newNode, parent SQLNode) {
 		parent.(*Select).With = newNode.(*With)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("GroupBy", -1)
 	if !a.rewriteGroupBy(node, node.GroupBy, func(newNode, parent SQLNode) {
 		parent.(*Select).GroupBy = newNode.(GroupBy)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("Having", -1)
 	if !a.rewriteRefOfWhere(node, node.Having, func(newNode, parent SQLNode) {
 		parent.(*Select).Having = newNode.(*Where)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("OrderBy", -1)
 	if !a.rewriteOrderBy(node, node.OrderBy, func(newNode, parent SQLNode) {
 		parent.(*Select).OrderBy = newNode.(OrderBy)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("Limit", -1)
 	if !a.rewriteRefOfLimit(node, node.Limit, func(newNode, parent SQLNode) {
 		parent.(*Select).Limit = newNode.(*Limit)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("Into", -1)
 	if !a.rewriteRefOfSelectInto(node, node.Into, func(newNode, parent SQLNode) {
 		parent.(*Select).Into = newNode.(*SelectInto)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	a.cur.enclosing = outer
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -3539,21 +5318,29 @@ func (a *application) rewriteSelectExprs(parent SQLNode, node SelectExprs, repla
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*SelectExprs)(nil)).Elem()]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		kontinue := !a.pre(&a.cur)
+		kontinue := !pre(&a.cur)
 		if a.cur.revisit {
-			node = a.cur.node.(SelectExprs)
 			a.cur.revisit = false
-			return a.rewriteSelectExprs(parent, node, replacer)
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
 		}
 		if kontinue {
 			return true
 		}
 	}
 	for x, el := range node {
+		a.cur.enterField("", x)
 		if !a.rewriteSelectExpr(node, el, func(idx int) replacerFunc {
 			return func(newNode, parent SQLNode) {
 				parent.(SelectExprs)[idx] = newNode.(SelectExpr)
@@ -3561,12 +5348,25 @@ func (a *application) rewriteSelectExprs(parent SQLNode, node SelectExprs, repla
 		}(x)) {
 			return false
 		}
+		a.cur.leaveField()
+	}
+	if edits := a.cur.takeEdits(); edits != nil {
+		var result SelectExprs
+		rebuildSlice(edits, len(node), func(idx int, inserted SQLNode) {
+			if idx < 0 {
+				result = append(result, inserted.(SelectExpr))
+			} else {
+				result = append(result, node[idx])
+			}
+		})
+		replacer(result, parent)
+		node = result
 	}
-	if a.post != nil {
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -3576,21 +5376,31 @@ func (a *application) rewriteRefOfSelectInto(parent SQLNode, node *SelectInto, r
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*SelectInto)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
-	if a.post != nil {
-		if a.pre == nil {
+	if post != nil {
+		if pre == nil {
 			a.cur.replacer = replacer
 			a.cur.parent = parent
 			a.cur.node = node
 		}
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -3600,29 +5410,49 @@ func (a *application) rewriteRefOfSet(parent SQLNode, node *Set, replacer replac
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*Set)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	outer := a.cur.enclosing
+	a.cur.enclosing = node
+	a.cur.enterField("Comments", -1)
 	if !a.rewriteComments(node, node.Comments, func(newNode, parent SQLNode) {
 		parent.(*Set).Comments = newNode.(Comments)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("Exprs", -1)
 	if !a.rewriteSetExprs(node, node.Exprs, func(newNode, parent SQLNode) {
 		parent.(*Set).Exprs = newNode.(SetExprs)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	a.cur.enclosing = outer
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -3632,29 +5462,46 @@ func (a *application) rewriteRefOfSetExpr(parent SQLNode, node *SetExpr, replace
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*SetExpr)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	a.cur.enterField("Name", -1)
 	if !a.rewriteColIdent(node, node.Name, func(newNode, parent SQLNode) {
 		parent.(*SetExpr).Name = newNode.(ColIdent)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("Expr", -1)
 	if !a.rewriteExpr(node, node.Expr, func(newNode, parent SQLNode) {
 		parent.(*SetExpr).Expr = newNode.(Expr)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -3664,21 +5511,29 @@ func (a *application) rewriteSetExprs(parent SQLNode, node SetExprs, replacer re
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*SetExprs)(nil)).Elem()]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		kontinue := !a.pre(&a.cur)
+		kontinue := !pre(&a.cur)
 		if a.cur.revisit {
-			node = a.cur.node.(SetExprs)
 			a.cur.revisit = false
-			return a.rewriteSetExprs(parent, node, replacer)
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
 		}
 		if kontinue {
 			return true
 		}
 	}
 	for x, el := range node {
+		a.cur.enterField("", x)
 		if !a.rewriteRefOfSetExpr(node, el, func(idx int) replacerFunc {
 			return func(newNode, parent SQLNode) {
 				parent.(SetExprs)[idx] = newNode.(*SetExpr)
@@ -3686,12 +5541,25 @@ func (a *application) rewriteSetExprs(parent SQLNode, node SetExprs, replacer re
 		}(x)) {
 			return false
 		}
+		a.cur.leaveField()
+	}
+	if edits := a.cur.takeEdits(); edits != nil {
+		var result SetExprs
+		rebuildSlice(edits, len(node), func(idx int, inserted SQLNode) {
+			if idx < 0 {
+				result = append(result, inserted.(*SetExpr))
+			} else {
+				result = append(result, node[idx])
+			}
+		})
+		replacer(result, parent)
+		node = result
 	}
-	if a.post != nil {
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -3701,25 +5569,45 @@ func (a *application) rewriteRefOfSetTransaction(parent SQLNode, node *SetTransa
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*SetTransaction)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	outer := a.cur.enclosing
+	a.cur.enclosing = node
+	a.cur.enterField("SQLNode", -1)
 	if !a.rewriteSQLNode(node, node.SQLNode, func(newNode, parent SQLNode) {
 		parent.(*SetTransaction).SQLNode = newNode.(SQLNode)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("Comments", -1)
 	if !a.rewriteComments(node, node.Comments, func(newNode, parent SQLNode) {
 		parent.(*SetTransaction).Comments = newNode.(Comments)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
 	for x, el := range node.Characteristics {
+		a.cur.enterField("Characteristics", x)
 		if !a.rewriteCharacteristic(node, el, func(idx int) replacerFunc {
 			return func(newNode, parent SQLNode) {
 				parent.(*SetTransaction).Characteristics[idx] = newNode.(Characteristic)
@@ -3727,12 +5615,25 @@ func (a *application) rewriteRefOfSetTransaction(parent SQLNode, node *SetTransa
 		}(x)) {
 			return false
 		}
+		a.cur.leaveField()
+	}
+	if edits := a.cur.takeEdits(); edits != nil {
+		var result []Characteristic
+		rebuildSlice(edits, len(node.Characteristics), func(idx int, inserted SQLNode) {
+			if idx < 0 {
+				result = append(result, inserted.(Characteristic))
+			} else {
+				result = append(result, node.Characteristics[idx])
+			}
+		})
+		node.Characteristics = result
 	}
-	if a.post != nil {
+	a.cur.enclosing = outer
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -3742,24 +5643,42 @@ func (a *application) rewriteRefOfShow(parent SQLNode, node *Show, replacer repl
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*Show)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	outer := a.cur.enclosing
+	a.cur.enclosing = node
+	a.cur.enterField("Internal", -1)
 	if !a.rewriteShowInternal(node, node.Internal, func(newNode, parent SQLNode) {
 		parent.(*Show).Internal = newNode.(ShowInternal)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	a.cur.enclosing = outer
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -3769,34 +5688,53 @@ func (a *application) rewriteRefOfShowBasic(parent SQLNode, node *ShowBasic, rep
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*ShowBasic)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	a.cur.enterField("Tbl", -1)
 	if !a.rewriteTableName(node, node.Tbl, func(newNode, parent SQLNode) {
 		parent.(*ShowBasic).Tbl = newNode.(TableName)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("DbName", -1)
 	if !a.rewriteTableIdent(node, node.DbName, func(newNode, parent SQLNode) {
 		parent.(*ShowBasic).DbName = newNode.(TableIdent)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("Filter", -1)
 	if !a.rewriteRefOfShowFilter(node, node.Filter, func(newNode, parent SQLNode) {
 		parent.(*ShowBasic).Filter = newNode.(*ShowFilter)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -3806,24 +5744,39 @@ func (a *application) rewriteRefOfShowCreate(parent SQLNode, node *ShowCreate, r
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*ShowCreate)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	a.cur.enterField("Op", -1)
 	if !a.rewriteTableName(node, node.Op, func(newNode, parent SQLNode) {
 		parent.(*ShowCreate).Op = newNode.(TableName)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -3833,24 +5786,39 @@ func (a *application) rewriteRefOfShowFilter(parent SQLNode, node *ShowFilter, r
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*ShowFilter)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	a.cur.enterField("Filter", -1)
 	if !a.rewriteExpr(node, node.Filter, func(newNode, parent SQLNode) {
 		parent.(*ShowFilter).Filter = newNode.(Expr)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -3860,34 +5828,53 @@ func (a *application) rewriteRefOfShowLegacy(parent SQLNode, node *ShowLegacy, r
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*ShowLegacy)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	a.cur.enterField("OnTable", -1)
 	if !a.rewriteTableName(node, node.OnTable, func(newNode, parent SQLNode) {
 		parent.(*ShowLegacy).OnTable = newNode.(TableName)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("Table", -1)
 	if !a.rewriteTableName(node, node.Table, func(newNode, parent SQLNode) {
 		parent.(*ShowLegacy).Table = newNode.(TableName)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("ShowCollationFilterOpt", -1)
 	if !a.rewriteExpr(node, node.ShowCollationFilterOpt, func(newNode, parent SQLNode) {
 		parent.(*ShowLegacy).ShowCollationFilterOpt = newNode.(Expr)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -3897,24 +5884,42 @@ func (a *application) rewriteRefOfShowMigrationLogs(parent SQLNode, node *ShowMi
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*ShowMigrationLogs)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	outer := a.cur.enclosing
+	a.cur.enclosing = node
+	a.cur.enterField("Comments", -1)
 	if !a.rewriteComments(node, node.Comments, func(newNode, parent SQLNode) {
 		parent.(*ShowMigrationLogs).Comments = newNode.(Comments)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	a.cur.enclosing = outer
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -3924,24 +5929,39 @@ func (a *application) rewriteRefOfStarExpr(parent SQLNode, node *StarExpr, repla
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*StarExpr)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	a.cur.enterField("TableName", -1)
 	if !a.rewriteTableName(node, node.TableName, func(newNode, parent SQLNode) {
 		parent.(*StarExpr).TableName = newNode.(TableName)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -3951,34 +5971,56 @@ func (a *application) rewriteRefOfStream(parent SQLNode, node *Stream, replacer
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*Stream)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	outer := a.cur.enclosing
+	a.cur.enclosing = node
+	a.cur.enterField("Comments", -1)
 	if !a.rewriteComments(node, node.Comments, func(newNode, parent SQLNode) {
 		parent.(*Stream).Comments = newNode.(Comments)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("SelectExpr", -1)
 	if !a.rewriteSelectExpr(node, node.SelectExpr, func(newNode, parent SQLNode) {
 		parent.(*Stream).SelectExpr = newNode.(SelectExpr)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("Table", -1)
 	if !a.rewriteTableName(node, node.Table, func(newNode, parent SQLNode) {
 		parent.(*Stream).Table = newNode.(TableName)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	a.cur.enclosing = outer
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -3988,24 +6030,39 @@ func (a *application) rewriteRefOfSubquery(parent SQLNode, node *Subquery, repla
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*Subquery)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	a.cur.enterField("Select", -1)
 	if !a.rewriteSelectStatement(node, node.Select, func(newNode, parent SQLNode) {
 		parent.(*Subquery).Select = newNode.(SelectStatement)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -4015,39 +6072,60 @@ func (a *application) rewriteRefOfSubstrExpr(parent SQLNode, node *SubstrExpr, r
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*SubstrExpr)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	a.cur.enterField("Name", -1)
 	if !a.rewriteRefOfColName(node, node.Name, func(newNode, parent SQLNode) {
 		parent.(*SubstrExpr).Name = newNode.(*ColName)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("StrVal", -1)
 	if !a.rewriteRefOfLiteral(node, node.StrVal, func(newNode, parent SQLNode) {
 		parent.(*SubstrExpr).StrVal = newNode.(*Literal)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("From", -1)
 	if !a.rewriteExpr(node, node.From, func(newNode, parent SQLNode) {
 		parent.(*SubstrExpr).From = newNode.(Expr)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("To", -1)
 	if !a.rewriteExpr(node, node.To, func(newNode, parent SQLNode) {
 		parent.(*SubstrExpr).To = newNode.(Expr)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -4057,21 +6135,29 @@ func (a *application) rewriteTableExprs(parent SQLNode, node TableExprs, replace
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*TableExprs)(nil)).Elem()]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		kontinue := !a.pre(&a.cur)
+		kontinue := !pre(&a.cur)
 		if a.cur.revisit {
-			node = a.cur.node.(TableExprs)
 			a.cur.revisit = false
-			return a.rewriteTableExprs(parent, node, replacer)
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
 		}
 		if kontinue {
 			return true
 		}
 	}
 	for x, el := range node {
+		a.cur.enterField("", x)
 		if !a.rewriteTableExpr(node, el, func(idx int) replacerFunc {
 			return func(newNode, parent SQLNode) {
 				parent.(TableExprs)[idx] = newNode.(TableExpr)
@@ -4079,62 +6165,102 @@ func (a *application) rewriteTableExprs(parent SQLNode, node TableExprs, replace
 		}(x)) {
 			return false
 		}
+		a.cur.leaveField()
+	}
+	if edits := a.cur.takeEdits(); edits != nil {
+		var result TableExprs
+		rebuildSlice(edits, len(node), func(idx int, inserted SQLNode) {
+			if idx < 0 {
+				result = append(result, inserted.(TableExpr))
+			} else {
+				result = append(result, node[idx])
+			}
+		})
+		replacer(result, parent)
+		node = result
 	}
-	if a.post != nil {
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
 	return true
 }
 func (a *application) rewriteTableIdent(parent SQLNode, node TableIdent, replacer replacerFunc) bool {
-	if a.pre != nil {
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*TableIdent)(nil)).Elem()]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
-	if a.post != nil {
-		if a.pre == nil {
+	if post != nil {
+		if pre == nil {
 			a.cur.replacer = replacer
 			a.cur.parent = parent
 			a.cur.node = node
 		}
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
 	return true
 }
 func (a *application) rewriteTableName(parent SQLNode, node TableName, replacer replacerFunc) bool {
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*TableName)(nil)).Elem()]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	a.cur.enterField("Name", -1)
 	if !a.rewriteTableIdent(node, node.Name, func(newNode, parent SQLNode) {
 		panic("[BUG] tried to replace 'Name' on 'TableName'")
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("Qualifier", -1)
 	if !a.rewriteTableIdent(node, node.Qualifier, func(newNode, parent SQLNode) {
 		panic("[BUG] tried to replace 'Qualifier' on 'TableName'")
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -4144,21 +6270,29 @@ func (a *application) rewriteTableNames(parent SQLNode, node TableNames, replace
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*TableNames)(nil)).Elem()]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		kontinue := !a.pre(&a.cur)
+		kontinue := !pre(&a.cur)
 		if a.cur.revisit {
-			node = a.cur.node.(TableNames)
 			a.cur.revisit = false
-			return a.rewriteTableNames(parent, node, replacer)
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
 		}
 		if kontinue {
 			return true
 		}
 	}
 	for x, el := range node {
+		a.cur.enterField("", x)
 		if !a.rewriteTableName(node, el, func(idx int) replacerFunc {
 			return func(newNode, parent SQLNode) {
 				parent.(TableNames)[idx] = newNode.(TableName)
@@ -4166,12 +6300,25 @@ func (a *application) rewriteTableNames(parent SQLNode, node TableNames, replace
 		}(x)) {
 			return false
 		}
+		a.cur.leaveField()
+	}
+	if edits := a.cur.takeEdits(); edits != nil {
+		var result TableNames
+		rebuildSlice(edits, len(node), func(idx int, inserted SQLNode) {
+			if idx < 0 {
+				result = append(result, inserted.(TableName))
+			} else {
+				result = append(result, node[idx])
+			}
+		})
+		replacer(result, parent)
+		node = result
 	}
-	if a.post != nil {
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -4181,27 +6328,31 @@ func (a *application) rewriteTableOptions(parent SQLNode, node TableOptions, rep
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*TableOptions)(nil)).Elem()]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		kontinue := !a.pre(&a.cur)
+		kontinue := !pre(&a.cur)
 		if a.cur.revisit {
-			node = a.cur.node.(TableOptions)
 			a.cur.revisit = false
-			return a.rewriteTableOptions(parent, node, replacer)
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
 		}
 		if kontinue {
 			return true
 		}
 	}
-	if a.post != nil {
-		if a.pre == nil {
+	if post != nil {
+		if pre == nil {
 			a.cur.replacer = replacer
 			a.cur.parent = parent
 			a.cur.node = node
 		}
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -4211,15 +6362,29 @@ func (a *application) rewriteRefOfTableSpec(parent SQLNode, node *TableSpec, rep
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*TableSpec)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
 	for x, el := range node.Columns {
+		a.cur.enterField("Columns", x)
 		if !a.rewriteRefOfColumnDefinition(node, el, func(idx int) replacerFunc {
 			return func(newNode, parent SQLNode) {
 				parent.(*TableSpec).Columns[idx] = newNode.(*ColumnDefinition)
@@ -4227,8 +6392,21 @@ func (a *application) rewriteRefOfTableSpec(parent SQLNode, node *TableSpec, rep
 		}(x)) {
 			return false
 		}
+		a.cur.leaveField()
+	}
+	if edits := a.cur.takeEdits(); edits != nil {
+		var result []*ColumnDefinition
+		rebuildSlice(edits, len(node.Columns), func(idx int, inserted SQLNode) {
+			if idx < 0 {
+				result = append(result, inserted.(*ColumnDefinition))
+			} else {
+				result = append(result, node.Columns[idx])
+			}
+		})
+		node.Columns = result
 	}
 	for x, el := range node.Indexes {
+		a.cur.enterField("Indexes", x)
 		if !a.rewriteRefOfIndexDefinition(node, el, func(idx int) replacerFunc {
 			return func(newNode, parent SQLNode) {
 				parent.(*TableSpec).Indexes[idx] = newNode.(*IndexDefinition)
@@ -4236,8 +6414,21 @@ func (a *application) rewriteRefOfTableSpec(parent SQLNode, node *TableSpec, rep
 		}(x)) {
 			return false
 		}
+		a.cur.leaveField()
+	}
+	if edits := a.cur.takeEdits(); edits != nil {
+		var result []*IndexDefinition
+		rebuildSlice(edits, len(node.Indexes), func(idx int, inserted SQLNode) {
+			if idx < 0 {
+				result = append(result, inserted.(*IndexDefinition))
+			} else {
+				result = append(result, node.Indexes[idx])
+			}
+		})
+		node.Indexes = result
 	}
 	for x, el := range node.Constraints {
+		a.cur.enterField("Constraints", x)
 		if !a.rewriteRefOfConstraintDefinition(node, el, func(idx int) replacerFunc {
 			return func(newNode, parent SQLNode) {
 				parent.(*TableSpec).Constraints[idx] = newNode.(*ConstraintDefinition)
@@ -4245,17 +6436,31 @@ func (a *application) rewriteRefOfTableSpec(parent SQLNode, node *TableSpec, rep
 		}(x)) {
 			return false
 		}
+		a.cur.leaveField()
+	}
+	if edits := a.cur.takeEdits(); edits != nil {
+		var result []*ConstraintDefinition
+		rebuildSlice(edits, len(node.Constraints), func(idx int, inserted SQLNode) {
+			if idx < 0 {
+				result = append(result, inserted.(*ConstraintDefinition))
+			} else {
+				result = append(result, node.Constraints[idx])
+			}
+		})
+		node.Constraints = result
 	}
+	a.cur.enterField("Options", -1)
 	if !a.rewriteTableOptions(node, node.Options, func(newNode, parent SQLNode) {
 		parent.(*TableSpec).Options = newNode.(TableOptions)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -4265,21 +6470,31 @@ func (a *application) rewriteRefOfTablespaceOperation(parent SQLNode, node *Tabl
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*TablespaceOperation)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
-	if a.post != nil {
-		if a.pre == nil {
+	if post != nil {
+		if pre == nil {
 			a.cur.replacer = replacer
 			a.cur.parent = parent
 			a.cur.node = node
 		}
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -4289,29 +6504,46 @@ func (a *application) rewriteRefOfTimestampFuncExpr(parent SQLNode, node *Timest
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*TimestampFuncExpr)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	a.cur.enterField("Expr1", -1)
 	if !a.rewriteExpr(node, node.Expr1, func(newNode, parent SQLNode) {
 		parent.(*TimestampFuncExpr).Expr1 = newNode.(Expr)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("Expr2", -1)
 	if !a.rewriteExpr(node, node.Expr2, func(newNode, parent SQLNode) {
 		parent.(*TimestampFuncExpr).Expr2 = newNode.(Expr)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -4321,24 +6553,42 @@ func (a *application) rewriteRefOfTruncateTable(parent SQLNode, node *TruncateTa
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*TruncateTable)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	outer := a.cur.enclosing
+	a.cur.enclosing = node
+	a.cur.enterField("Table", -1)
 	if !a.rewriteTableName(node, node.Table, func(newNode, parent SQLNode) {
 		parent.(*TruncateTable).Table = newNode.(TableName)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	a.cur.enclosing = outer
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -4348,24 +6598,39 @@ func (a *application) rewriteRefOfUnaryExpr(parent SQLNode, node *UnaryExpr, rep
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*UnaryExpr)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	a.cur.enterField("Expr", -1)
 	if !a.rewriteExpr(node, node.Expr, func(newNode, parent SQLNode) {
 		parent.(*UnaryExpr).Expr = newNode.(Expr)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -4375,49 +6640,77 @@ func (a *application) rewriteRefOfUnion(parent SQLNode, node *Union, replacer re
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*Union)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	outer := a.cur.enclosing
+	a.cur.enclosing = node
+	a.cur.enterField("Left", -1)
 	if !a.rewriteSelectStatement(node, node.Left, func(newNode, parent SQLNode) {
 		parent.(*Union).Left = newNode.(SelectStatement)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("Right", -1)
 	if !a.rewriteSelectStatement(node, node.Right, func(newNode, parent SQLNode) {
 		parent.(*Union).Right = newNode.(SelectStatement)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("OrderBy", -1)
 	if !a.rewriteOrderBy(node, node.OrderBy, func(newNode, parent SQLNode) {
 		parent.(*Union).OrderBy = newNode.(OrderBy)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("With", -1)
 	if !a.rewriteRefOfWith(node, node.With, func(newNode, parent SQLNode) {
 		parent.(*Union).With = newNode.(*With)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("Limit", -1)
 	if !a.rewriteRefOfLimit(node, node.Limit, func(newNode, parent SQLNode) {
 		parent.(*Union).Limit = newNode.(*Limit)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("Into", -1)
 	if !a.rewriteRefOfSelectInto(node, node.Into, func(newNode, parent SQLNode) {
 		parent.(*Union).Into = newNode.(*SelectInto)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	a.cur.enclosing = outer
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -4427,21 +6720,31 @@ func (a *application) rewriteRefOfUnlockTables(parent SQLNode, node *UnlockTable
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*UnlockTables)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
-	if a.post != nil {
-		if a.pre == nil {
+	if post != nil {
+		if pre == nil {
 			a.cur.replacer = replacer
 			a.cur.parent = parent
 			a.cur.node = node
 		}
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -4451,54 +6754,84 @@ func (a *application) rewriteRefOfUpdate(parent SQLNode, node *Update, replacer
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*Update)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	outer := a.cur.enclosing
+	a.cur.enclosing = node
+	a.cur.enterField("With", -1)
 	if !a.rewriteRefOfWith(node, node.With, func(newNode, parent SQLNode) {
 		parent.(*Update).With = newNode.(*With)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("Comments", -1)
 	if !a.rewriteComments(node, node.Comments, func(newNode, parent SQLNode) {
 		parent.(*Update).Comments = newNode.(Comments)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("TableExprs", -1)
 	if !a.rewriteTableExprs(node, node.TableExprs, func(newNode, parent SQLNode) {
 		parent.(*Update).TableExprs = newNode.(TableExprs)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("Exprs", -1)
 	if !a.rewriteUpdateExprs(node, node.Exprs, func(newNode, parent SQLNode) {
 		parent.(*Update).Exprs = newNode.(UpdateExprs)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("Where", -1)
 	if !a.rewriteRefOfWhere(node, node.Where, func(newNode, parent SQLNode) {
 		parent.(*Update).Where = newNode.(*Where)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("OrderBy", -1)
 	if !a.rewriteOrderBy(node, node.OrderBy, func(newNode, parent SQLNode) {
 		parent.(*Update).OrderBy = newNode.(OrderBy)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("Limit", -1)
 	if !a.rewriteRefOfLimit(node, node.Limit, func(newNode, parent SQLNode) {
 		parent.(*Update).Limit = newNode.(*Limit)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	a.cur.enclosing = outer
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -4508,29 +6841,46 @@ func (a *application) rewriteRefOfUpdateExpr(parent SQLNode, node *UpdateExpr, r
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*UpdateExpr)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	a.cur.enterField("Name", -1)
 	if !a.rewriteRefOfColName(node, node.Name, func(newNode, parent SQLNode) {
 		parent.(*UpdateExpr).Name = newNode.(*ColName)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("Expr", -1)
 	if !a.rewriteExpr(node, node.Expr, func(newNode, parent SQLNode) {
 		parent.(*UpdateExpr).Expr = newNode.(Expr)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -4540,21 +6890,29 @@ func (a *application) rewriteUpdateExprs(parent SQLNode, node UpdateExprs, repla
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*UpdateExprs)(nil)).Elem()]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		kontinue := !a.pre(&a.cur)
+		kontinue := !pre(&a.cur)
 		if a.cur.revisit {
-			node = a.cur.node.(UpdateExprs)
 			a.cur.revisit = false
-			return a.rewriteUpdateExprs(parent, node, replacer)
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
 		}
 		if kontinue {
 			return true
 		}
 	}
 	for x, el := range node {
+		a.cur.enterField("", x)
 		if !a.rewriteRefOfUpdateExpr(node, el, func(idx int) replacerFunc {
 			return func(newNode, parent SQLNode) {
 				parent.(UpdateExprs)[idx] = newNode.(*UpdateExpr)
@@ -4562,12 +6920,25 @@ func (a *application) rewriteUpdateExprs(parent SQLNode, node UpdateExprs, repla
 		}(x)) {
 			return false
 		}
+		a.cur.leaveField()
+	}
+	if edits := a.cur.takeEdits(); edits != nil {
+		var result UpdateExprs
+		rebuildSlice(edits, len(node), func(idx int, inserted SQLNode) {
+			if idx < 0 {
+				result = append(result, inserted.(*UpdateExpr))
+			} else {
+				result = append(result, node[idx])
+			}
+		})
+		replacer(result, parent)
+		node = result
 	}
-	if a.post != nil {
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -4577,24 +6948,42 @@ func (a *application) rewriteRefOfUse(parent SQLNode, node *Use, replacer replac
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*Use)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	outer := a.cur.enclosing
+	a.cur.enclosing = node
+	a.cur.enterField("DBName", -1)
 	if !a.rewriteTableIdent(node, node.DBName, func(newNode, parent SQLNode) {
 		parent.(*Use).DBName = newNode.(TableIdent)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	a.cur.enclosing = outer
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -4604,44 +6993,70 @@ func (a *application) rewriteRefOfVStream(parent SQLNode, node *VStream, replace
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*VStream)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	outer := a.cur.enclosing
+	a.cur.enclosing = node
+	a.cur.enterField("Comments", -1)
 	if !a.rewriteComments(node, node.Comments, func(newNode, parent SQLNode) {
 		parent.(*VStream).Comments = newNode.(Comments)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("SelectExpr", -1)
 	if !a.rewriteSelectExpr(node, node.SelectExpr, func(newNode, parent SQLNode) {
 		parent.(*VStream).SelectExpr = newNode.(SelectExpr)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("Table", -1)
 	if !a.rewriteTableName(node, node.Table, func(newNode, parent SQLNode) {
 		parent.(*VStream).Table = newNode.(TableName)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("Where", -1)
 	if !a.rewriteRefOfWhere(node, node.Where, func(newNode, parent SQLNode) {
 		parent.(*VStream).Where = newNode.(*Where)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("Limit", -1)
 	if !a.rewriteRefOfLimit(node, node.Limit, func(newNode, parent SQLNode) {
 		parent.(*VStream).Limit = newNode.(*Limit)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	a.cur.enclosing = outer
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -4651,21 +7066,29 @@ func (a *application) rewriteValTuple(parent SQLNode, node ValTuple, replacer re
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*ValTuple)(nil)).Elem()]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		kontinue := !a.pre(&a.cur)
+		kontinue := !pre(&a.cur)
 		if a.cur.revisit {
-			node = a.cur.node.(ValTuple)
 			a.cur.revisit = false
-			return a.rewriteValTuple(parent, node, replacer)
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
 		}
 		if kontinue {
 			return true
 		}
 	}
 	for x, el := range node {
+		a.cur.enterField("", x)
 		if !a.rewriteExpr(node, el, func(idx int) replacerFunc {
 			return func(newNode, parent SQLNode) {
 				parent.(ValTuple)[idx] = newNode.(Expr)
@@ -4673,12 +7096,25 @@ func (a *application) rewriteValTuple(parent SQLNode, node ValTuple, replacer re
 		}(x)) {
 			return false
 		}
+		a.cur.leaveField()
+	}
+	if edits := a.cur.takeEdits(); edits != nil {
+		var result ValTuple
+		rebuildSlice(edits, len(node), func(idx int, inserted SQLNode) {
+			if idx < 0 {
+				result = append(result, inserted.(Expr))
+			} else {
+				result = append(result, node[idx])
+			}
+		})
+		replacer(result, parent)
+		node = result
 	}
-	if a.post != nil {
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -4688,21 +7124,31 @@ func (a *application) rewriteRefOfValidation(parent SQLNode, node *Validation, r
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*Validation)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
-	if a.post != nil {
-		if a.pre == nil {
+	if post != nil {
+		if pre == nil {
 			a.cur.replacer = replacer
 			a.cur.parent = parent
 			a.cur.node = node
 		}
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -4712,21 +7158,29 @@ func (a *application) rewriteValues(parent SQLNode, node Values, replacer replac
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*Values)(nil)).Elem()]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		kontinue := !a.pre(&a.cur)
+		kontinue := !pre(&a.cur)
 		if a.cur.revisit {
-			node = a.cur.node.(Values)
 			a.cur.revisit = false
-			return a.rewriteValues(parent, node, replacer)
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
 		}
 		if kontinue {
 			return true
 		}
 	}
 	for x, el := range node {
+		a.cur.enterField("", x)
 		if !a.rewriteValTuple(node, el, func(idx int) replacerFunc {
 			return func(newNode, parent SQLNode) {
 				parent.(Values)[idx] = newNode.(ValTuple)
@@ -4734,12 +7188,25 @@ func (a *application) rewriteValues(parent SQLNode, node Values, replacer replac
 		}(x)) {
 			return false
 		}
+		a.cur.leaveField()
+	}
+	if edits := a.cur.takeEdits(); edits != nil {
+		var result Values
+		rebuildSlice(edits, len(node), func(idx int, inserted SQLNode) {
+			if idx < 0 {
+				result = append(result, inserted.(ValTuple))
+			} else {
+				result = append(result, node[idx])
+			}
+		})
+		replacer(result, parent)
+		node = result
 	}
-	if a.post != nil {
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -4749,48 +7216,78 @@ func (a *application) rewriteRefOfValuesFuncExpr(parent SQLNode, node *ValuesFun
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*ValuesFuncExpr)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	a.cur.enterField("Name", -1)
 	if !a.rewriteRefOfColName(node, node.Name, func(newNode, parent SQLNode) {
 		parent.(*ValuesFuncExpr).Name = newNode.(*ColName)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
 	return true
 }
 func (a *application) rewriteVindexParam(parent SQLNode, node VindexParam, replacer replacerFunc) bool {
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*VindexParam)(nil)).Elem()]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	a.cur.enterField("Key", -1)
 	if !a.rewriteColIdent(node, node.Key, func(newNode, parent SQLNode) {
 		panic("[BUG] tried to replace 'Key' on 'VindexParam'")
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -4800,25 +7297,43 @@ func (a *application) rewriteRefOfVindexSpec(parent SQLNode, node *VindexSpec, r
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*VindexSpec)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	a.cur.enterField("Name", -1)
 	if !a.rewriteColIdent(node, node.Name, func(newNode, parent SQLNode) {
 		parent.(*VindexSpec).Name = newNode.(ColIdent)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("Type", -1)
 	if !a.rewriteColIdent(node, node.Type, func(newNode, parent SQLNode) {
 		parent.(*VindexSpec).Type = newNode.(ColIdent)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
 	for x, el := range node.Params {
+		a.cur.enterField("Params", x)
 		if !a.rewriteVindexParam(node, el, func(idx int) replacerFunc {
 			return func(newNode, parent SQLNode) {
 				parent.(*VindexSpec).Params[idx] = newNode.(VindexParam)
@@ -4826,12 +7341,24 @@ func (a *application) rewriteRefOfVindexSpec(parent SQLNode, node *VindexSpec, r
 		}(x)) {
 			return false
 		}
+		a.cur.leaveField()
+	}
+	if edits := a.cur.takeEdits(); edits != nil {
+		var result []VindexParam
+		rebuildSlice(edits, len(node.Params), func(idx int, inserted SQLNode) {
+			if idx < 0 {
+				result = append(result, inserted.(VindexParam))
+			} else {
+				result = append(result, node.Params[idx])
+			}
+		})
+		node.Params = result
 	}
-	if a.post != nil {
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -4841,29 +7368,46 @@ func (a *application) rewriteRefOfWhen(parent SQLNode, node *When, replacer repl
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*When)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	a.cur.enterField("Cond", -1)
 	if !a.rewriteExpr(node, node.Cond, func(newNode, parent SQLNode) {
 		parent.(*When).Cond = newNode.(Expr)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("Val", -1)
 	if !a.rewriteExpr(node, node.Val, func(newNode, parent SQLNode) {
 		parent.(*When).Val = newNode.(Expr)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -4873,24 +7417,39 @@ func (a *application) rewriteRefOfWhere(parent SQLNode, node *Where, replacer re
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*Where)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	a.cur.enterField("Expr", -1)
 	if !a.rewriteExpr(node, node.Expr, func(newNode, parent SQLNode) {
 		parent.(*Where).Expr = newNode.(Expr)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -4900,15 +7459,29 @@ func (a *application) rewriteRefOfWith(parent SQLNode, node *With, replacer repl
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*With)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
 	for x, el := range node.ctes {
+		a.cur.enterField("ctes", x)
 		if !a.rewriteRefOfCommonTableExpr(node, el, func(idx int) replacerFunc {
 			return func(newNode, parent SQLNode) {
 				parent.(*With).ctes[idx] = newNode.(*CommonTableExpr)
@@ -4916,12 +7489,24 @@ func (a *application) rewriteRefOfWith(parent SQLNode, node *With, replacer repl
 		}(x)) {
 			return false
 		}
+		a.cur.leaveField()
+	}
+	if edits := a.cur.takeEdits(); edits != nil {
+		var result []*CommonTableExpr
+		rebuildSlice(edits, len(node.ctes), func(idx int, inserted SQLNode) {
+			if idx < 0 {
+				result = append(result, inserted.(*CommonTableExpr))
+			} else {
+				result = append(result, node.ctes[idx])
+			}
+		})
+		node.ctes = result
 	}
-	if a.post != nil {
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -4931,29 +7516,46 @@ func (a *application) rewriteRefOfXorExpr(parent SQLNode, node *XorExpr, replace
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*XorExpr)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	a.cur.enterField("Left", -1)
 	if !a.rewriteExpr(node, node.Left, func(newNode, parent SQLNode) {
 		parent.(*XorExpr).Left = newNode.(Expr)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("Right", -1)
 	if !a.rewriteExpr(node, node.Right, func(newNode, parent SQLNode) {
 		parent.(*XorExpr).Right = newNode.(Expr)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -5002,8 +7604,12 @@ func (a *application) rewriteAlterOption(parent SQLNode, node AlterOption, repla
 		return a.rewriteRefOfTablespaceOperation(parent, node, replacer)
 	case *Validation:
 		return a.rewriteRefOfValidation(parent, node, replacer)
+	case *AlgorithmValue, *TableOptions:
+		// these nodes are only visited by value
+		return true
 	default:
-		// this should never happen
+		// this should never happen, unless the AST helpers are out of date
+		a.unknownNode(node)
 		return true
 	}
 }
@@ -5016,8 +7622,12 @@ func (a *application) rewriteCharacteristic(parent SQLNode, node Characteristic,
 		return a.rewriteAccessMode(parent, node, replacer)
 	case IsolationLevel:
 		return a.rewriteIsolationLevel(parent, node, replacer)
+	case *AccessMode, *IsolationLevel:
+		// these nodes are only visited by value
+		return true
 	default:
-		// this should never happen
+		// this should never happen, unless the AST helpers are out of date
+		a.unknownNode(node)
 		return true
 	}
 }
@@ -5032,8 +7642,12 @@ func (a *application) rewriteColTuple(parent SQLNode, node ColTuple, replacer re
 		return a.rewriteRefOfSubquery(parent, node, replacer)
 	case ValTuple:
 		return a.rewriteValTuple(parent, node, replacer)
+	case *ListArg, *ValTuple:
+		// these nodes are only visited by value
+		return true
 	default:
-		// this should never happen
+		// this should never happen, unless the AST helpers are out of date
+		a.unknownNode(node)
 		return true
 	}
 }
@@ -5047,7 +7661,8 @@ func (a *application) rewriteConstraintInfo(parent SQLNode, node ConstraintInfo,
 	case *ForeignKeyDefinition:
 		return a.rewriteRefOfForeignKeyDefinition(parent, node, replacer)
 	default:
-		// this should never happen
+		// this should never happen, unless the AST helpers are out of date
+		a.unknownNode(node)
 		return true
 	}
 }
@@ -5063,7 +7678,8 @@ func (a *application) rewriteDBDDLStatement(parent SQLNode, node DBDDLStatement,
 	case *DropDatabase:
 		return a.rewriteRefOfDropDatabase(parent, node, replacer)
 	default:
-		// this should never happen
+		// this should never happen, unless the AST helpers are out of date
+		a.unknownNode(node)
 		return true
 	}
 }
@@ -5089,7 +7705,8 @@ func (a *application) rewriteDDLStatement(parent SQLNode, node DDLStatement, rep
 	case *TruncateTable:
 		return a.rewriteRefOfTruncateTable(parent, node, replacer)
 	default:
-		// this should never happen
+		// this should never happen, unless the AST helpers are out of date
+		a.unknownNode(node)
 		return true
 	}
 }
@@ -5103,7 +7720,8 @@ func (a *application) rewriteExplain(parent SQLNode, node Explain, replacer repl
 	case *ExplainTab:
 		return a.rewriteRefOfExplainTab(parent, node, replacer)
 	default:
-		// this should never happen
+		// this should never happen, unless the AST helpers are out of date
+		a.unknownNode(node)
 		return true
 	}
 }
@@ -5178,8 +7796,12 @@ func (a *application) rewriteExpr(parent SQLNode, node Expr, replacer replacerFu
 		return a.rewriteRefOfValuesFuncExpr(parent, node, replacer)
 	case *XorExpr:
 		return a.rewriteRefOfXorExpr(parent, node, replacer)
+	case *Argument, *BoolVal, *ListArg, *ValTuple:
+		// these nodes are only visited by value
+		return true
 	default:
-		// this should never happen
+		// this should never happen, unless the AST helpers are out of date
+		a.unknownNode(node)
 		return true
 	}
 }
@@ -5194,8 +7816,12 @@ func (a *application) rewriteInsertRows(parent SQLNode, node InsertRows, replace
 		return a.rewriteRefOfUnion(parent, node, replacer)
 	case Values:
 		return a.rewriteValues(parent, node, replacer)
+	case *Values:
+		// these nodes are only visited by value
+		return true
 	default:
-		// this should never happen
+		// this should never happen, unless the AST helpers are out of date
+		a.unknownNode(node)
 		return true
 	}
 }
@@ -5211,7 +7837,8 @@ func (a *application) rewriteSelectExpr(parent SQLNode, node SelectExpr, replace
 	case *StarExpr:
 		return a.rewriteRefOfStarExpr(parent, node, replacer)
 	default:
-		// this should never happen
+		// this should never happen, unless the AST helpers are out of date
+		a.unknownNode(node)
 		return true
 	}
 }
@@ -5225,7 +7852,8 @@ func (a *application) rewriteSelectStatement(parent SQLNode, node SelectStatemen
 	case *Union:
 		return a.rewriteRefOfUnion(parent, node, replacer)
 	default:
-		// this should never happen
+		// this should never happen, unless the AST helpers are out of date
+		a.unknownNode(node)
 		return true
 	}
 }
@@ -5241,7 +7869,8 @@ func (a *application) rewriteShowInternal(parent SQLNode, node ShowInternal, rep
 	case *ShowLegacy:
 		return a.rewriteRefOfShowLegacy(parent, node, replacer)
 	default:
-		// this should never happen
+		// this should never happen, unless the AST helpers are out of date
+		a.unknownNode(node)
 		return true
 	}
 }
@@ -5254,8 +7883,12 @@ func (a *application) rewriteSimpleTableExpr(parent SQLNode, node SimpleTableExp
 		return a.rewriteRefOfDerivedTable(parent, node, replacer)
 	case TableName:
 		return a.rewriteTableName(parent, node, replacer)
+	case *TableName:
+		// these nodes are only visited by value
+		return true
 	default:
-		// this should never happen
+		// this should never happen, unless the AST helpers are out of date
+		a.unknownNode(node)
 		return true
 	}
 }
@@ -5347,7 +7980,8 @@ func (a *application) rewriteStatement(parent SQLNode, node Statement, replacer
 	case *VStream:
 		return a.rewriteRefOfVStream(parent, node, replacer)
 	default:
-		// this should never happen
+		// this should never happen, unless the AST helpers are out of date
+		a.unknownNode(node)
 		return true
 	}
 }
@@ -5363,152 +7997,223 @@ func (a *application) rewriteTableExpr(parent SQLNode, node TableExpr, replacer
 	case *ParenTableExpr:
 		return a.rewriteRefOfParenTableExpr(parent, node, replacer)
 	default:
-		// this should never happen
+		// this should never happen, unless the AST helpers are out of date
+		a.unknownNode(node)
 		return true
 	}
 }
 func (a *application) rewriteAccessMode(parent SQLNode, node AccessMode, replacer replacerFunc) bool {
-	if a.pre != nil {
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*AccessMode)(nil)).Elem()]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
-	if a.post != nil {
-		if a.pre == nil {
+	if post != nil {
+		if pre == nil {
 			a.cur.replacer = replacer
 			a.cur.parent = parent
 			a.cur.node = node
 		}
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
 	return true
 }
 func (a *application) rewriteAlgorithmValue(parent SQLNode, node AlgorithmValue, replacer replacerFunc) bool {
-	if a.pre != nil {
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*AlgorithmValue)(nil)).Elem()]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
-	if a.post != nil {
-		if a.pre == nil {
+	if post != nil {
+		if pre == nil {
 			a.cur.replacer = replacer
 			a.cur.parent = parent
 			a.cur.node = node
 		}
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
 	return true
 }
 func (a *application) rewriteArgument(parent SQLNode, node Argument, replacer replacerFunc) bool {
-	if a.pre != nil {
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*Argument)(nil)).Elem()]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
-	if a.post != nil {
-		if a.pre == nil {
+	if post != nil {
+		if pre == nil {
 			a.cur.replacer = replacer
 			a.cur.parent = parent
 			a.cur.node = node
 		}
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
 	return true
 }
 func (a *application) rewriteBoolVal(parent SQLNode, node BoolVal, replacer replacerFunc) bool {
-	if a.pre != nil {
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*BoolVal)(nil)).Elem()]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
-	if a.post != nil {
-		if a.pre == nil {
+	if post != nil {
+		if pre == nil {
 			a.cur.replacer = replacer
 			a.cur.parent = parent
 			a.cur.node = node
 		}
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
 	return true
 }
 func (a *application) rewriteIsolationLevel(parent SQLNode, node IsolationLevel, replacer replacerFunc) bool {
-	if a.pre != nil {
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*IsolationLevel)(nil)).Elem()]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
-	if a.post != nil {
-		if a.pre == nil {
+	if post != nil {
+		if pre == nil {
 			a.cur.replacer = replacer
 			a.cur.parent = parent
 			a.cur.node = node
 		}
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
 	return true
 }
 func (a *application) rewriteListArg(parent SQLNode, node ListArg, replacer replacerFunc) bool {
-	if a.pre != nil {
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*ListArg)(nil)).Elem()]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
-	if a.post != nil {
-		if a.pre == nil {
+	if post != nil {
+		if pre == nil {
 			a.cur.replacer = replacer
 			a.cur.parent = parent
 			a.cur.node = node
 		}
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
 	return true
 }
 func (a *application) rewriteReferenceAction(parent SQLNode, node ReferenceAction, replacer replacerFunc) bool {
-	if a.pre != nil {
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*ReferenceAction)(nil)).Elem()]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
-	if a.post != nil {
-		if a.pre == nil {
+	if post != nil {
+		if pre == nil {
 			a.cur.replacer = replacer
 			a.cur.parent = parent
 			a.cur.node = node
 		}
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -5518,21 +8223,31 @@ func (a *application) rewriteRefOfColIdent(parent SQLNode, node *ColIdent, repla
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*ColIdent)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
-	if a.post != nil {
-		if a.pre == nil {
+	if post != nil {
+		if pre == nil {
 			a.cur.replacer = replacer
 			a.cur.parent = parent
 			a.cur.node = node
 		}
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -5542,24 +8257,39 @@ func (a *application) rewriteRefOfRootNode(parent SQLNode, node *RootNode, repla
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*RootNode)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	a.cur.enterField("SQLNode", -1)
 	if !a.rewriteSQLNode(node, node.SQLNode, func(newNode, parent SQLNode) {
 		parent.(*RootNode).SQLNode = newNode.(SQLNode)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -5569,21 +8299,31 @@ func (a *application) rewriteRefOfTableIdent(parent SQLNode, node *TableIdent, r
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*TableIdent)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
-	if a.post != nil {
-		if a.pre == nil {
+	if post != nil {
+		if pre == nil {
 			a.cur.replacer = replacer
 			a.cur.parent = parent
 			a.cur.node = node
 		}
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -5593,29 +8333,46 @@ func (a *application) rewriteRefOfTableName(parent SQLNode, node *TableName, rep
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*TableName)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	a.cur.enterField("Name", -1)
 	if !a.rewriteTableIdent(node, node.Name, func(newNode, parent SQLNode) {
 		parent.(*TableName).Name = newNode.(TableIdent)
 	}) {
 		return false
 	}
+	a.cur.leaveField()
+	a.cur.enterField("Qualifier", -1)
 	if !a.rewriteTableIdent(node, node.Qualifier, func(newNode, parent SQLNode) {
 		parent.(*TableName).Qualifier = newNode.(TableIdent)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
@@ -5625,26 +8382,164 @@ func (a *application) rewriteRefOfVindexParam(parent SQLNode, node *VindexParam,
 	if node == nil {
 		return true
 	}
-	if a.pre != nil {
+	if a.tooDeep() {
+		return false
+	}
+	pre, post := a.pre, a.post
+	if a.typed != nil {
+		h := a.typed.handlers[reflect.TypeOf((*VindexParam)(nil))]
+		pre, post = h.pre, h.post
+	}
+	if pre != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.pre(&a.cur) {
+		kontinue := !pre(&a.cur)
+		if a.cur.revisit {
+			a.cur.revisit = false
+			return a.rewriteSQLNode(parent, a.cur.node, replacer)
+		}
+		if kontinue {
 			return true
 		}
 	}
+	a.cur.enterField("Key", -1)
 	if !a.rewriteColIdent(node, node.Key, func(newNode, parent SQLNode) {
 		parent.(*VindexParam).Key = newNode.(ColIdent)
 	}) {
 		return false
 	}
-	if a.post != nil {
+	a.cur.leaveField()
+	if post != nil {
 		a.cur.replacer = replacer
 		a.cur.parent = parent
 		a.cur.node = node
-		if !a.post(&a.cur) {
+		if !post(&a.cur) {
 			return false
 		}
 	}
 	return true
 }
+
+// IsKnownType returns whether node has one of the types that the rewriter knows how to visit.
+// The rewriter panics when it finds a node of any other type, unless it has been configured
+// to skip it.
+func IsKnownType(node SQLNode) bool {
+	switch node.(type) {
+	case AccessMode, *AddColumns, *AddConstraintDefinition, *AddIndexDefinition, AlgorithmValue, *AliasedExpr, *AliasedTableExpr, *AlterCharset, *AlterColumn, *AlterDatabase, *AlterMigration, *AlterTable, *AlterView, *AlterVschema, *AndExpr, Argument, *AutoIncSpec, *Begin, *BinaryExpr, BoolVal, *CallProc, *CaseExpr, *ChangeColumn, *CheckConstraintDefinition, ColIdent, *ColName, *CollateExpr, *ColumnDefinition, *ColumnType, Columns, Comments, *Commit, *CommonTableExpr, *ComparisonExpr, *ConstraintDefinition, *ConvertExpr, *ConvertType, *ConvertUsingExpr, *CreateDatabase, *CreateTable, *CreateView, *CurTimeFuncExpr, *Default, *Delete, *DerivedTable, *DropColumn, *DropDatabase, *DropKey, *DropTable, *DropView, *ExistsExpr, *ExplainStmt, *ExplainTab, Exprs, *ExtractFuncExpr, *ExtractedSubquery, *Flush, *Force, *ForeignKeyDefinition, *FuncExpr, GroupBy, *GroupConcatExpr, *IndexDefinition, *IndexHints, *IndexInfo, *Insert, *IntervalExpr, *IsExpr, IsolationLevel, *JoinCondition, *JoinTableExpr, *KeyState, *Limit, ListArg, *Literal, *Load, *LockOption, *LockTables, *MatchExpr, *ModifyColumn, *Nextval, *NotExpr, *NullVal, OnDup, *OptLike, *OrExpr, *Order, OrderBy, *OrderByOption, *OtherAdmin, *OtherRead, *ParenTableExpr, *PartitionDefinition, *PartitionSpec, Partitions, *RangeCond, ReferenceAction, *ReferenceDefinition, *Release, *RenameIndex, *RenameTable, *RenameTableName, *RevertMigration, *Rollback, RootNode, *SRollback, *Savepoint, *Select, SelectExprs, *SelectInto, *Set, *SetExpr, SetExprs, *SetTransaction, *Show, *ShowBasic, *ShowCreate, *ShowFilter, *ShowLegacy, *ShowMigrationLogs, *StarExpr, *Stream, *Subquery, *SubstrExpr, TableExprs, TableIdent, TableName, TableNames, TableOptions, *TableSpec, *TablespaceOperation, *TimestampFuncExpr, *TruncateTable, *UnaryExpr, *Union, *UnlockTables, *Update, *UpdateExpr, UpdateExprs, *Use, *VStream, ValTuple, *Validation, Values, *ValuesFuncExpr, VindexParam, *VindexSpec, *When, *Where, *With, *XorExpr, *AccessMode, *AlgorithmValue, *Argument, *BoolVal, *ColIdent, *Columns, *Comments, *Exprs, *GroupBy, *IsolationLevel, *ListArg, *OnDup, *OrderBy, *Partitions, *ReferenceAction, *RootNode, *SelectExprs, *SetExprs, *TableExprs, *TableIdent, *TableName, *TableNames, *TableOptions, *UpdateExprs, *ValTuple, *Values, *VindexParam:
+		return true
+	default:
+		return false
+	}
+}
+
+// TypedRewriter rewrites the AST like Rewrite, but instead of calling the same pre and post
+// functions for every node, it calls the handlers that were registered for the concrete type
+// of the node. The generated code already knows the type of every node it visits, so the
+// handlers don't need to switch on it again. The nodes without handlers are still traversed.
+type TypedRewriter struct {
+	handlers map[reflect.Type]typedHandlers
+}
+
+// typedHandlers are the handlers registered in a TypedRewriter for one type
+type typedHandlers struct {
+	pre, post ApplyFunc
+}
+
+// NewTypedRewriter returns a TypedRewriter without any handlers
+func NewTypedRewriter() *TypedRewriter {
+	return &TypedRewriter{handlers: map[reflect.Type]typedHandlers{}}
+}
+
+// Pre registers f to be called before the children of every node with the same concrete type as node,
+// replacing any handler that was registered for that type before. Only the type of node is used,
+// so it can be a nil pointer. f follows the same rules as the pre function of Rewrite.
+// It returns the TypedRewriter, so that the calls can be chained.
+func (r *TypedRewriter) Pre(node SQLNode, f ApplyFunc) *TypedRewriter {
+	t := reflect.TypeOf(node)
+	h := r.handlers[t]
+	h.pre = f
+	r.handlers[t] = h
+	return r
+}
+
+// Post registers f to be called after the children of every node with the same concrete type as node,
+// replacing any handler that was registered for that type before. Only the type of node is used,
+// so it can be a nil pointer. f follows the same rules as the post function of Rewrite.
+// It returns the TypedRewriter, so that the calls can be chained.
+func (r *TypedRewriter) Post(node SQLNode, f ApplyFunc) *TypedRewriter {
+	t := reflect.TypeOf(node)
+	h := r.handlers[t]
+	h.post = f
+	r.handlers[t] = h
+	return r
+}
+
+// ParentIfSelect returns the parent of the current node if it is a *Select
+func (c *Cursor) ParentIfSelect() (*Select, bool) {
+	parent, ok := c.parent.(*Select)
+	return parent, ok
+}
+
+// ParentIfUnion returns the parent of the current node if it is a *Union
+func (c *Cursor) ParentIfUnion() (*Union, bool) {
+	parent, ok := c.parent.(*Union)
+	return parent, ok
+}
+
+// ParentIfInsert returns the parent of the current node if it is a *Insert
+func (c *Cursor) ParentIfInsert() (*Insert, bool) {
+	parent, ok := c.parent.(*Insert)
+	return parent, ok
+}
+
+// ParentIfUpdate returns the parent of the current node if it is a *Update
+func (c *Cursor) ParentIfUpdate() (*Update, bool) {
+	parent, ok := c.parent.(*Update)
+	return parent, ok
+}
+
+// ParentIfDelete returns the parent of the current node if it is a *Delete
+func (c *Cursor) ParentIfDelete() (*Delete, bool) {
+	parent, ok := c.parent.(*Delete)
+	return parent, ok
+}
+
+// ParentIfWhere returns the parent of the current node if it is a *Where
+func (c *Cursor) ParentIfWhere() (*Where, bool) {
+	parent, ok := c.parent.(*Where)
+	return parent, ok
+}
+
+// ParentIfAliasedExpr returns the parent of the current node if it is a *AliasedExpr
+func (c *Cursor) ParentIfAliasedExpr() (*AliasedExpr, bool) {
+	parent, ok := c.parent.(*AliasedExpr)
+	return parent, ok
+}
+
+// ParentIfComparisonExpr returns the parent of the current node if it is a *ComparisonExpr
+func (c *Cursor) ParentIfComparisonExpr() (*ComparisonExpr, bool) {
+	parent, ok := c.parent.(*ComparisonExpr)
+	return parent, ok
+}
+
+// ParentIfFuncExpr returns the parent of the current node if it is a *FuncExpr
+func (c *Cursor) ParentIfFuncExpr() (*FuncExpr, bool) {
+	parent, ok := c.parent.(*FuncExpr)
+	return parent, ok
+}
+
+// EnclosingStatement returns the nearest ancestor of the current node that is a Statement,
+// or nil if there is none. A Statement does not enclose itself, so when the current node
+// is a Statement, this returns the Statement that contains it, if any.
+func (c *Cursor) EnclosingStatement() Statement {
+	enclosing, _ := c.enclosing.(Statement)
+	return enclosing
+}
+
+// isEnclosing returns whether node is a Statement, which the Cursor tracks as the enclosing
+// node of its children
+func isEnclosing(node SQLNode) bool {
+	_, ok := node.(Statement)
+	return ok
+}
@@ -0,0 +1,1320 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by ASTHelperGen. DO NOT EDIT.
+
+package sqlparser
+
+// iterChildren calls emit for every child of node that the recursive rewriter
+// visits, in the same order, together with the replacer for the child
+func (a *application) iterChildren(node SQLNode, emit func(child SQLNode, name string, index int, replacer replacerFunc)) {
+	switch node := node.(type) {
+	case *AddColumns:
+		for x, el := range node.Columns {
+			emit(el, "Columns", x, func(idx int) replacerFunc {
+				return func(newNode, parent SQLNode) {
+					parent.(*AddColumns).Columns[idx] = newNode.(*ColumnDefinition)
+				}
+			}(x))
+		}
+		emit(node.After, "After", -1, func(newNode, parent SQLNode) {
+			parent.(*AddColumns).After = newNode.(*ColName)
+		})
+	case *AddConstraintDefinition:
+		emit(node.ConstraintDefinition, "ConstraintDefinition", -1, func(newNode, parent SQLNode) {
+			parent.(*AddConstraintDefinition).ConstraintDefinition = newNode.(*ConstraintDefinition)
+		})
+	case *AddIndexDefinition:
+		emit(node.IndexDefinition, "IndexDefinition", -1, func(newNode, parent SQLNode) {
+			parent.(*AddIndexDefinition).IndexDefinition = newNode.(*IndexDefinition)
+		})
+	case *AliasedExpr:
+		emit(node.Expr, "Expr", -1, func(newNode, parent SQLNode) {
+			parent.(*AliasedExpr).Expr = newNode.(Expr)
+		})
+		emit(node.As, "As", -1, func(newNode, parent SQLNode) {
+			parent.(*AliasedExpr).As = newNode.(ColIdent)
+		})
+	case *AliasedTableExpr:
+		emit(node.Expr, "Expr", -1, func(newNode, parent SQLNode) {
+			parent.(*AliasedTableExpr).Expr = newNode.(SimpleTableExpr)
+		})
+		emit(node.Partitions, "Partitions", -1, func(newNode, parent SQLNode) {
+			parent.(*AliasedTableExpr).Partitions = newNode.(Partitions)
+		})
+		emit(node.As, "As", -1, func(newNode, parent SQLNode) {
+			parent.(*AliasedTableExpr).As = newNode.(TableIdent)
+		})
+		emit(node.Hints, "Hints", -1, func(newNode, parent SQLNode) {
+			parent.(*AliasedTableExpr).Hints = newNode.(*IndexHints)
+		})
+		emit(node.Columns, "Columns", -1, func(newNode, parent SQLNode) {
+			parent.(*AliasedTableExpr).Columns = newNode.(Columns)
+		})
+	case *AlterColumn:
+		emit(node.Column, "Column", -1, func(newNode, parent SQLNode) {
+			parent.(*AlterColumn).Column = newNode.(*ColName)
+		})
+		emit(node.DefaultVal, "DefaultVal", -1, func(newNode, parent SQLNode) {
+			parent.(*AlterColumn).DefaultVal = newNode.(Expr)
+		})
+	case *AlterDatabase:
+		emit(node.DBName, "DBName", -1, func(newNode, parent SQLNode) {
+			parent.(*AlterDatabase).DBName = newNode.(TableIdent)
+		})
+	case *AlterTable:
+		emit(node.Table, "Table", -1, func(newNode, parent SQLNode) {
+			parent.(*AlterTable).Table = newNode.(TableName)
+		})
+		for x, el := range node.AlterOptions {
+			emit(el, "AlterOptions", x, func(idx int) replacerFunc {
+				return func(newNode, parent SQLNode) {
+					parent.(*AlterTable).AlterOptions[idx] = newNode.(AlterOption)
+				}
+			}(x))
+		}
+		emit(node.PartitionSpec, "PartitionSpec", -1, func(newNode, parent SQLNode) {
+			parent.(*AlterTable).PartitionSpec = newNode.(*PartitionSpec)
+		})
+		emit(node.Comments, "Comments", -1, func(newNode, parent SQLNode) {
+			parent.(*AlterTable).Comments = newNode.(Comments)
+		})
+	case *AlterView:
+		emit(node.ViewName, "ViewName", -1, func(newNode, parent SQLNode) {
+			parent.(*AlterView).ViewName = newNode.(TableName)
+		})
+		emit(node.Columns, "Columns", -1, func(newNode, parent SQLNode) {
+			parent.(*AlterView).Columns = newNode.(Columns)
+		})
+		emit(node.Select, "Select", -1, func(newNode, parent SQLNode) {
+			parent.(*AlterView).Select = newNode.(SelectStatement)
+		})
+	case *AlterVschema:
+		emit(node.Table, "Table", -1, func(newNode, parent SQLNode) {
+			parent.(*AlterVschema).Table = newNode.(TableName)
+		})
+		emit(node.VindexSpec, "VindexSpec", -1, func(newNode, parent SQLNode) {
+			parent.(*AlterVschema).VindexSpec = newNode.(*VindexSpec)
+		})
+		for x, el := range node.VindexCols {
+			emit(el, "VindexCols", x, func(idx int) replacerFunc {
+				return func(newNode, parent SQLNode) {
+					parent.(*AlterVschema).VindexCols[idx] = newNode.(ColIdent)
+				}
+			}(x))
+		}
+		emit(node.AutoIncSpec, "AutoIncSpec", -1, func(newNode, parent SQLNode) {
+			parent.(*AlterVschema).AutoIncSpec = newNode.(*AutoIncSpec)
+		})
+	case *AndExpr:
+		emit(node.Left, "Left", -1, func(newNode, parent SQLNode) {
+			parent.(*AndExpr).Left = newNode.(Expr)
+		})
+		emit(node.Right, "Right", -1, func(newNode, parent SQLNode) {
+			parent.(*AndExpr).Right = newNode.(Expr)
+		})
+	case *AutoIncSpec:
+		emit(node.Column, "Column", -1, func(newNode, parent SQLNode) {
+			parent.(*AutoIncSpec).Column = newNode.(ColIdent)
+		})
+		emit(node.Sequence, "Sequence", -1, func(newNode, parent SQLNode) {
+			parent.(*AutoIncSpec).Sequence = newNode.(TableName)
+		})
+	case *BinaryExpr:
+		emit(node.Left, "Left", -1, func(newNode, parent SQLNode) {
+			parent.(*BinaryExpr).Left = newNode.(Expr)
+		})
+		emit(node.Right, "Right", -1, func(newNode, parent SQLNode) {
+			parent.(*BinaryExpr).Right = newNode.(Expr)
+		})
+	case *CallProc:
+		emit(node.Name, "Name", -1, func(newNode, parent SQLNode) {
+			parent.(*CallProc).Name = newNode.(TableName)
+		})
+		emit(node.Params, "Params", -1, func(newNode, parent SQLNode) {
+			parent.(*CallProc).Params = newNode.(Exprs)
+		})
+	case *CaseExpr:
+		emit(node.Expr, "Expr", -1, func(newNode, parent SQLNode) {
+			parent.(*CaseExpr).Expr = newNode.(Expr)
+		})
+		for x, el := range node.Whens {
+			emit(el, "Whens", x, func(idx int) replacerFunc {
+				return func(newNode, parent SQLNode) {
+					parent.(*CaseExpr).Whens[idx] = newNode.(*When)
+				}
+			}(x))
+		}
+		emit(node.Else, "Else", -1, func(newNode, parent SQLNode) {
+			parent.(*CaseExpr).Else = newNode.(Expr)
+		})
+	case *ChangeColumn:
+		emit(node.OldColumn, "OldColumn", -1, func(newNode, parent SQLNode) {
+			parent.(*ChangeColumn).OldColumn = newNode.(*ColName)
+		})
+		emit(node.NewColDefinition, "NewColDefinition", -1, func(newNode, parent SQLNode) {
+			parent.(*ChangeColumn).NewColDefinition = newNode.(*ColumnDefinition)
+		})
+		emit(node.After, "After", -1, func(newNode, parent SQLNode) {
+			parent.(*ChangeColumn).After = newNode.(*ColName)
+		})
+	case *CheckConstraintDefinition:
+		emit(node.Expr, "Expr", -1, func(newNode, parent SQLNode) {
+			parent.(*CheckConstraintDefinition).Expr = newNode.(Expr)
+		})
+	case *ColName:
+		emit(node.Name, "Name", -1, func(newNode, parent SQLNode) {
+			parent.(*ColName).Name = newNode.(ColIdent)
+		})
+		emit(node.Qualifier, "Qualifier", -1, func(newNode, parent SQLNode) {
+			parent.(*ColName).Qualifier = newNode.(TableName)
+		})
+	case *CollateExpr:
+		emit(node.Expr, "Expr", -1, func(newNode, parent SQLNode) {
+			parent.(*CollateExpr).Expr = newNode.(Expr)
+		})
+	case *ColumnDefinition:
+		emit(node.Name, "Name", -1, func(newNode, parent SQLNode) {
+			parent.(*ColumnDefinition).Name = newNode.(ColIdent)
+		})
+	case *ColumnType:
+		emit(node.Length, "Length", -1, func(newNode, parent SQLNode) {
+			parent.(*ColumnType).Length = newNode.(*Literal)
+		})
+		emit(node.Scale, "Scale", -1, func(newNode, parent SQLNode) {
+			parent.(*ColumnType).Scale = newNode.(*Literal)
+		})
+	case Columns:
+		for x, el := range node {
+			emit(el, "", x, func(idx int) replacerFunc {
+				return func(newNode, parent SQLNode) {
+					parent.(Columns)[idx] = newNode.(ColIdent)
+				}
+			}(x))
+		}
+	case *CommonTableExpr:
+		emit(node.TableID, "TableID", -1, func(newNode, parent SQLNode) {
+			parent.(*CommonTableExpr).TableID = newNode.(TableIdent)
+		})
+		emit(node.Columns, "Columns", -1, func(newNode, parent SQLNode) {
+			parent.(*CommonTableExpr).Columns = newNode.(Columns)
+		})
+		emit(node.Subquery, "Subquery", -1, func(newNode, parent SQLNode) {
+			parent.(*CommonTableExpr).Subquery = newNode.(*Subquery)
+		})
+	case *ComparisonExpr:
+		emit(node.Left, "Left", -1, func(newNode, parent SQLNode) {
+			parent.(*ComparisonExpr).Left = newNode.(Expr)
+		})
+		emit(node.Right, "Right", -1, func(newNode, parent SQLNode) {
+			parent.(*ComparisonExpr).Right = newNode.(Expr)
+		})
+		emit(node.Escape, "Escape", -1, func(newNode, parent SQLNode) {
+			parent.(*ComparisonExpr).Escape = newNode.(Expr)
+		})
+	case *ConstraintDefinition:
+		emit(node.Name, "Name", -1, func(newNode, parent SQLNode) {
+			parent.(*ConstraintDefinition).Name = newNode.(ColIdent)
+		})
+		emit(node.Details, "Details", -1, func(newNode, parent SQLNode) {
+			parent.(*ConstraintDefinition).Details = newNode.(ConstraintInfo)
+		})
+	case *ConvertExpr:
+		emit(node.Expr, "Expr", -1, func(newNode, parent SQLNode) {
+			parent.(*ConvertExpr).Expr = newNode.(Expr)
+		})
+		emit(node.Type, "Type", -1, func(newNode, parent SQLNode) {
+			parent.(*ConvertExpr).Type = newNode.(*ConvertType)
+		})
+	case *ConvertType:
+		emit(node.Length, "Length", -1, func(newNode, parent SQLNode) {
+			parent.(*ConvertType).Length = newNode.(*Literal)
+		})
+		emit(node.Scale, "Scale", -1, func(newNode, parent SQLNode) {
+			parent.(*ConvertType).Scale = newNode.(*Literal)
+		})
+	case *ConvertUsingExpr:
+		emit(node.Expr, "Expr", -1, func(newNode, parent SQLNode) {
+			parent.(*ConvertUsingExpr).Expr = newNode.(Expr)
+		})
+	case *CreateDatabase:
+		emit(node.Comments, "Comments", -1, func(newNode, parent SQLNode) {
+			parent.(*CreateDatabase).Comments = newNode.(Comments)
+		})
+		emit(node.DBName, "DBName", -1, func(newNode, parent SQLNode) {
+			parent.(*CreateDatabase).DBName = newNode.(TableIdent)
+		})
+	case *CreateTable:
+		emit(node.Table, "Table", -1, func(newNode, parent SQLNode) {
+			parent.(*CreateTable).Table = newNode.(TableName)
+		})
+		emit(node.TableSpec, "TableSpec", -1, func(newNode, parent SQLNode) {
+			parent.(*CreateTable).TableSpec = newNode.(*TableSpec)
+		})
+		emit(node.OptLike, "OptLike", -1, func(newNode, parent SQLNode) {
+			parent.(*CreateTable).OptLike = newNode.(*OptLike)
+		})
+		emit(node.Comments, "Comments", -1, func(newNode, parent SQLNode) {
+			parent.(*CreateTable).Comments = newNode.(Comments)
+		})
+	case *CreateView:
+		emit(node.ViewName, "ViewName", -1, func(newNode, parent SQLNode) {
+			parent.(*CreateView).ViewName = newNode.(TableName)
+		})
+		emit(node.Columns, "Columns", -1, func(newNode, parent SQLNode) {
+			parent.(*CreateView).Columns = newNode.(Columns)
+		})
+		emit(node.Select, "Select", -1, func(newNode, parent SQLNode) {
+			parent.(*CreateView).Select = newNode.(SelectStatement)
+		})
+	case *CurTimeFuncExpr:
+		emit(node.Name, "Name", -1, func(newNode, parent SQLNode) {
+			parent.(*CurTimeFuncExpr).Name = newNode.(ColIdent)
+		})
+		emit(node.Fsp, "Fsp", -1, func(newNode, parent SQLNode) {
+			parent.(*CurTimeFuncExpr).Fsp = newNode.(Expr)
+		})
+	case *Delete:
+		emit(node.With, "With", -1, func(newNode, parent SQLNode) {
+			parent.(*Delete).With = newNode.(*With)
+		})
+		emit(node.Comments, "Comments", -1, func(newNode, parent SQLNode) {
+			parent.(*Delete).Comments = newNode.(Comments)
+		})
+		emit(node.Targets, "Targets", -1, func(newNode, parent SQLNode) {
+			parent.(*Delete).Targets = newNode.(TableNames)
+		})
+		emit(node.TableExprs, "TableExprs", -1, func(newNode, parent SQLNode) {
+			parent.(*Delete).TableExprs = newNode.(TableExprs)
+		})
+		emit(node.Partitions, "Partitions", -1, func(newNode, parent SQLNode) {
+			parent.(*Delete).Partitions = newNode.(Partitions)
+		})
+		emit(node.Where, "Where", -1, func(newNode, parent SQLNode) {
+			parent.(*Delete).Where = newNode.(*Where)
+		})
+		emit(node.OrderBy, "OrderBy", -1, func(newNode, parent SQLNode) {
+			parent.(*Delete).OrderBy = newNode.(OrderBy)
+		})
+		emit(node.Limit, "Limit", -1, func(newNode, parent SQLNode) {
+			parent.(*Delete).Limit = newNode.(*Limit)
+		})
+	case *DerivedTable:
+		emit(node.Select, "Select", -1, func(newNode, parent SQLNode) {
+			parent.(*DerivedTable).Select = newNode.(SelectStatement)
+		})
+	case *DropColumn:
+		emit(node.Name, "Name", -1, func(newNode, parent SQLNode) {
+			parent.(*DropColumn).Name = newNode.(*ColName)
+		})
+	case *DropDatabase:
+		emit(node.Comments, "Comments", -1, func(newNode, parent SQLNode) {
+			parent.(*DropDatabase).Comments = newNode.(Comments)
+		})
+		emit(node.DBName, "DBName", -1, func(newNode, parent SQLNode) {
+			parent.(*DropDatabase).DBName = newNode.(TableIdent)
+		})
+	case *DropKey:
+		emit(node.Name, "Name", -1, func(newNode, parent SQLNode) {
+			parent.(*DropKey).Name = newNode.(ColIdent)
+		})
+	case *DropTable:
+		emit(node.FromTables, "FromTables", -1, func(newNode, parent SQLNode) {
+			parent.(*DropTable).FromTables = newNode.(TableNames)
+		})
+		emit(node.Comments, "Comments", -1, func(newNode, parent SQLNode) {
+			parent.(*DropTable).Comments = newNode.(Comments)
+		})
+	case *DropView:
+		emit(node.FromTables, "FromTables", -1, func(newNode, parent SQLNode) {
+			parent.(*DropView).FromTables = newNode.(TableNames)
+		})
+	case *ExistsExpr:
+		emit(node.Subquery, "Subquery", -1, func(newNode, parent SQLNode) {
+			parent.(*ExistsExpr).Subquery = newNode.(*Subquery)
+		})
+	case *ExplainStmt:
+		emit(node.Statement, "Statement", -1, func(newNode, parent SQLNode) {
+			parent.(*ExplainStmt).Statement = newNode.(Statement)
+		})
+	case *ExplainTab:
+		emit(node.Table, "Table", -1, func(newNode, parent SQLNode) {
+			parent.(*ExplainTab).Table = newNode.(TableName)
+		})
+	case Exprs:
+		for x, el := range node {
+			emit(el, "", x, func(idx int) replacerFunc {
+				return func(newNode, parent SQLNode) {
+					parent.(Exprs)[idx] = newNode.(Expr)
+				}
+			}(x))
+		}
+	case *ExtractFuncExpr:
+		emit(node.Expr, "Expr", -1, func(newNode, parent SQLNode) {
+			parent.(*ExtractFuncExpr).Expr = newNode.(Expr)
+		})
+	case *ExtractedSubquery:
+		emit(node.Original, "Original", -1, func(newNode, parent SQLNode) {
+			parent.(*ExtractedSubquery).Original = newNode.(Expr)
+		})
+		emit(node.Subquery, "Subquery", -1, func(newNode, parent SQLNode) {
+			parent.(*ExtractedSubquery).Subquery = newNode.(*Subquery)
+		})
+		emit(node.OtherSide, "OtherSide", -1, func(newNode, parent SQLNode) {
+			parent.(*ExtractedSubquery).OtherSide = newNode.(Expr)
+		})
+		emit(node.alternative, "alternative", -1, func(newNode, parent SQLNode) {
+			parent.(*ExtractedSubquery).alternative = newNode.(Expr)
+		})
+	case *Flush:
+		emit(node.TableNames, "TableNames", -1, func(newNode, parent SQLNode) {
+			parent.(*Flush).TableNames = newNode.(TableNames)
+		})
+	case *ForeignKeyDefinition:
+		emit(node.Source, "Source", -1, func(newNode, parent SQLNode) {
+			parent.(*ForeignKeyDefinition).Source = newNode.(Columns)
+		})
+		emit(node.IndexName, "IndexName", -1, func(newNode, parent SQLNode) {
+			parent.(*ForeignKeyDefinition).IndexName = newNode.(ColIdent)
+		})
+		emit(node.ReferenceDefinition, "ReferenceDefinition", -1, func(newNode, parent SQLNode) {
+			parent.(*ForeignKeyDefinition).ReferenceDefinition = newNode.(*ReferenceDefinition)
+		})
+	case *FuncExpr:
+		emit(node.Qualifier, "Qualifier", -1, func(newNode, parent SQLNode) {
+			parent.(*FuncExpr).Qualifier = newNode.(TableIdent)
+		})
+		emit(node.Name, "Name", -1, func(newNode, parent SQLNode) {
+			parent.(*FuncExpr).Name = newNode.(ColIdent)
+		})
+		emit(node.Exprs, "Exprs", -1, func(newNode, parent SQLNode) {
+			parent.(*FuncExpr).Exprs = newNode.(SelectExprs)
+		})
+	case GroupBy:
+		for x, el := range node {
+			emit(el, "", x, func(idx int) replacerFunc {
+				return func(newNode, parent SQLNode) {
+					parent.(GroupBy)[idx] = newNode.(Expr)
+				}
+			}(x))
+		}
+	case *GroupConcatExpr:
+		emit(node.Exprs, "Exprs", -1, func(newNode, parent SQLNode) {
+			parent.(*GroupConcatExpr).Exprs = newNode.(SelectExprs)
+		})
+		emit(node.OrderBy, "OrderBy", -1, func(newNode, parent SQLNode) {
+			parent.(*GroupConcatExpr).OrderBy = newNode.(OrderBy)
+		})
+		emit(node.Limit, "Limit", -1, func(newNode, parent SQLNode) {
+			parent.(*GroupConcatExpr).Limit = newNode.(*Limit)
+		})
+	case *IndexDefinition:
+		emit(node.Info, "Info", -1, func(newNode, parent SQLNode) {
+			parent.(*IndexDefinition).Info = newNode.(*IndexInfo)
+		})
+	case *IndexHints:
+		for x, el := range node.Indexes {
+			emit(el, "Indexes", x, func(idx int) replacerFunc {
+				return func(newNode, parent SQLNode) {
+					parent.(*IndexHints).Indexes[idx] = newNode.(ColIdent)
+				}
+			}(x))
+		}
+	case *IndexInfo:
+		emit(node.Name, "Name", -1, func(newNode, parent SQLNode) {
+			parent.(*IndexInfo).Name = newNode.(ColIdent)
+		})
+		emit(node.ConstraintName, "ConstraintName", -1, func(newNode, parent SQLNode) {
+			parent.(*IndexInfo).ConstraintName = newNode.(ColIdent)
+		})
+	case *Insert:
+		emit(node.Comments, "Comments", -1, func(newNode, parent SQLNode) {
+			parent.(*Insert).Comments = newNode.(Comments)
+		})
+		emit(node.Table, "Table", -1, func(newNode, parent SQLNode) {
+			parent.(*Insert).Table = newNode.(TableName)
+		})
+		emit(node.Partitions, "Partitions", -1, func(newNode, parent SQLNode) {
+			parent.(*Insert).Partitions = newNode.(Partitions)
+		})
+		emit(node.Columns, "Columns", -1, func(newNode, parent SQLNode) {
+			parent.(*Insert).Columns = newNode.(Columns)
+		})
+		emit(node.Rows, "Rows", -1, func(newNode, parent SQLNode) {
+			parent.(*Insert).Rows = newNode.(InsertRows)
+		})
+		emit(node.OnDup, "OnDup", -1, func(newNode, parent SQLNode) {
+			parent.(*Insert).OnDup = newNode.(OnDup)
+		})
+	case *IntervalExpr:
+		emit(node.Expr, "Expr", -1, func(newNode, parent SQLNode) {
+			parent.(*IntervalExpr).Expr = newNode.(Expr)
+		})
+	case *IsExpr:
+		emit(node.Left, "Left", -1, func(newNode, parent SQLNode) {
+			parent.(*IsExpr).Left = newNode.(Expr)
+		})
+	case *JoinCondition:
+		emit(node.On, "On", -1, func(newNode, parent SQLNode) {
+			parent.(*JoinCondition).On = newNode.(Expr)
+		})
+		emit(node.Using, "Using", -1, func(newNode, parent SQLNode) {
+			parent.(*JoinCondition).Using = newNode.(Columns)
+		})
+	case *JoinTableExpr:
+		emit(node.LeftExpr, "LeftExpr", -1, func(newNode, parent SQLNode) {
+			parent.(*JoinTableExpr).LeftExpr = newNode.(TableExpr)
+		})
+		emit(node.RightExpr, "RightExpr", -1, func(newNode, parent SQLNode) {
+			parent.(*JoinTableExpr).RightExpr = newNode.(TableExpr)
+		})
+		emit(node.Condition, "Condition", -1, func(newNode, parent SQLNode) {
+			parent.(*JoinTableExpr).Condition = newNode.(*JoinCondition)
+		})
+	case *Limit:
+		emit(node.Offset, "Offset", -1, func(newNode, parent SQLNode) {
+			parent.(*Limit).Offset = newNode.(Expr)
+		})
+		emit(node.Rowcount, "Rowcount", -1, func(newNode, parent SQLNode) {
+			parent.(*Limit).Rowcount = newNode.(Expr)
+		})
+	case *MatchExpr:
+		emit(node.Columns, "Columns", -1, func(newNode, parent SQLNode) {
+			parent.(*MatchExpr).Columns = newNode.(SelectExprs)
+		})
+		emit(node.Expr, "Expr", -1, func(newNode, parent SQLNode) {
+			parent.(*MatchExpr).Expr = newNode.(Expr)
+		})
+	case *ModifyColumn:
+		emit(node.NewColDefinition, "NewColDefinition", -1, func(newNode, parent SQLNode) {
+			parent.(*ModifyColumn).NewColDefinition = newNode.(*ColumnDefinition)
+		})
+		emit(node.After, "After", -1, func(newNode, parent SQLNode) {
+			parent.(*ModifyColumn).After = newNode.(*ColName)
+		})
+	case *Nextval:
+		emit(node.Expr, "Expr", -1, func(newNode, parent SQLNode) {
+			parent.(*Nextval).Expr = newNode.(Expr)
+		})
+	case *NotExpr:
+		emit(node.Expr, "Expr", -1, func(newNode, parent SQLNode) {
+			parent.(*NotExpr).Expr = newNode.(Expr)
+		})
+	case OnDup:
+		for x, el := range node {
+			emit(el, "", x, func(idx int) replacerFunc {
+				return func(newNode, parent SQLNode) {
+					parent.(OnDup)[idx] = newNode.(*UpdateExpr)
+				}
+			}(x))
+		}
+	case *OptLike:
+		emit(node.LikeTable, "LikeTable", -1, func(newNode, parent SQLNode) {
+			parent.(*OptLike).LikeTable = newNode.(TableName)
+		})
+	case *OrExpr:
+		emit(node.Left, "Left", -1, func(newNode, parent SQLNode) {
+			parent.(*OrExpr).Left = newNode.(Expr)
+		})
+		emit(node.Right, "Right", -1, func(newNode, parent SQLNode) {
+			parent.(*OrExpr).Right = newNode.(Expr)
+		})
+	case *Order:
+		emit(node.Expr, "Expr", -1, func(newNode, parent SQLNode) {
+			parent.(*Order).Expr = newNode.(Expr)
+		})
+	case OrderBy:
+		for x, el := range node {
+			emit(el, "", x, func(idx int) replacerFunc {
+				return func(newNode, parent SQLNode) {
+					parent.(OrderBy)[idx] = newNode.(*Order)
+				}
+			}(x))
+		}
+	case *OrderByOption:
+		emit(node.Cols, "Cols", -1, func(newNode, parent SQLNode) {
+			parent.(*OrderByOption).Cols = newNode.(Columns)
+		})
+	case *ParenTableExpr:
+		emit(node.Exprs, "Exprs", -1, func(newNode, parent SQLNode) {
+			parent.(*ParenTableExpr).Exprs = newNode.(TableExprs)
+		})
+	case *PartitionDefinition:
+		emit(node.Name, "Name", -1, func(newNode, parent SQLNode) {
+			parent.(*PartitionDefinition).Name = newNode.(ColIdent)
+		})
+		emit(node.Limit, "Limit", -1, func(newNode, parent SQLNode) {
+			parent.(*PartitionDefinition).Limit = newNode.(Expr)
+		})
+	case *PartitionSpec:
+		emit(node.Names, "Names", -1, func(newNode, parent SQLNode) {
+			parent.(*PartitionSpec).Names = newNode.(Partitions)
+		})
+		emit(node.Number, "Number", -1, func(newNode, parent SQLNode) {
+			parent.(*PartitionSpec).Number = newNode.(*Literal)
+		})
+		emit(node.TableName, "TableName", -1, func(newNode, parent SQLNode) {
+			parent.(*PartitionSpec).TableName = newNode.(TableName)
+		})
+		for x, el := range node.Definitions {
+			emit(el, "Definitions", x, func(idx int) replacerFunc {
+				return func(newNode, parent SQLNode) {
+					parent.(*PartitionSpec).Definitions[idx] = newNode.(*PartitionDefinition)
+				}
+			}(x))
+		}
+	case Partitions:
+		for x, el := range node {
+			emit(el, "", x, func(idx int) replacerFunc {
+				return func(newNode, parent SQLNode) {
+					parent.(Partitions)[idx] = newNode.(ColIdent)
+				}
+			}(x))
+		}
+	case *RangeCond:
+		emit(node.Left, "Left", -1, func(newNode, parent SQLNode) {
+			parent.(*RangeCond).Left = newNode.(Expr)
+		})
+		emit(node.From, "From", -1, func(newNode, parent SQLNode) {
+			parent.(*RangeCond).From = newNode.(Expr)
+		})
+		emit(node.To, "To", -1, func(newNode, parent SQLNode) {
+			parent.(*RangeCond).To = newNode.(Expr)
+		})
+	case *ReferenceDefinition:
+		emit(node.ReferencedTable, "ReferencedTable", -1, func(newNode, parent SQLNode) {
+			parent.(*ReferenceDefinition).ReferencedTable = newNode.(TableName)
+		})
+		emit(node.ReferencedColumns, "ReferencedColumns", -1, func(newNode, parent SQLNode) {
+			parent.(*ReferenceDefinition).ReferencedColumns = newNode.(Columns)
+		})
+		emit(node.OnDelete, "OnDelete", -1, func(newNode, parent SQLNode) {
+			parent.(*ReferenceDefinition).OnDelete = newNode.(ReferenceAction)
+		})
+		emit(node.OnUpdate, "OnUpdate", -1, func(newNode, parent SQLNode) {
+			parent.(*ReferenceDefinition).OnUpdate = newNode.(ReferenceAction)
+		})
+	case *Release:
+		emit(node.Name, "Name", -1, func(newNode, parent SQLNode) {
+			parent.(*Release).Name = newNode.(ColIdent)
+		})
+	case *RenameIndex:
+		emit(node.OldName, "OldName", -1, func(newNode, parent SQLNode) {
+			parent.(*RenameIndex).OldName = newNode.(ColIdent)
+		})
+		emit(node.NewName, "NewName", -1, func(newNode, parent SQLNode) {
+			parent.(*RenameIndex).NewName = newNode.(ColIdent)
+		})
+	case *RenameTableName:
+		emit(node.Table, "Table", -1, func(newNode, parent SQLNode) {
+			parent.(*RenameTableName).Table = newNode.(TableName)
+		})
+	case *RevertMigration:
+		emit(node.Comments, "Comments", -1, func(newNode, parent SQLNode) {
+			parent.(*RevertMigration).Comments = newNode.(Comments)
+		})
+	case RootNode:
+		emit(node.SQLNode, "SQLNode", -1, func(newNode, parent SQLNode) {
+			panic("[BUG] tried to replace 'SQLNode' on 'RootNode'")
+		})
+	case *SRollback:
+		emit(node.Name, "Name", -1, func(newNode, parent SQLNode) {
+			parent.(*SRollback).Name = newNode.(ColIdent)
+		})
+	case *Savepoint:
+		emit(node.Name, "Name", -1, func(newNode, parent SQLNode) {
+			parent.(*Savepoint).Name = newNode.(ColIdent)
+		})
+	case *Select:
+		for x, el := range node.From {
+			emit(el, "From", x, func(idx int) replacerFunc {
+				return func(newNode, parent SQLNode) {
+					parent.(*Select).From[idx] = newNode.(TableExpr)
+				}
+			}(x))
+		}
+		emit(node.Comments, "Comments", -1, func(newNode, parent SQLNode) {
+			parent.(*Select).Comments = newNode.(Comments)
+		})
+		emit(node.SelectExprs, "SelectExprs", -1, func(newNode, parent SQLNode) {
+			parent.(*Select).SelectExprs = newNode.(SelectExprs)
+		})
+		emit(node.Where, "Where", -1, func(newNode, parent SQLNode) {
+			parent.(*Select).Where = newNode.(*Where)
+		})
+		emit(node.With, "With", -1, func(newNode, parent SQLNode) {
+			parent.(*Select).With = newNode.(*With)
+		})
+		emit(node.GroupBy, "GroupBy", -1, func(newNode, parent SQLNode) {
+			parent.(*Select).GroupBy = newNode.(GroupBy)
+		})
+		emit(node.Having, "Having", -1, func(newNode, parent SQLNode) {
+			parent.(*Select).Having = newNode.(*Where)
+		})
+		emit(node.OrderBy, "OrderBy", -1, func(newNode, parent SQLNode) {
+			parent.(*Select).OrderBy = newNode.(OrderBy)
+		})
+		emit(node.Limit, "Limit", -1, func(newNode, parent SQLNode) {
+			parent.(*Select).Limit = newNode.(*Limit)
+		})
+		emit(node.Into, "Into", -1, func(newNode, parent SQLNode) {
+			parent.(*Select).Into = newNode.(*SelectInto)
+		})
+	case SelectExprs:
+		for x, el := range node {
+			emit(el, "", x, func(idx int) replacerFunc {
+				return func(newNode, parent SQLNode) {
+					parent.(SelectExprs)[idx] = newNode.(SelectExpr)
+				}
+			}(x))
+		}
+	case *Set:
+		emit(node.Comments, "Comments", -1, func(newNode, parent SQLNode) {
+			parent.(*Set).Comments = newNode.(Comments)
+		})
+		emit(node.Exprs, "Exprs", -1, func(newNode, parent SQLNode) {
+			parent.(*Set).Exprs = newNode.(SetExprs)
+		})
+	case *SetExpr:
+		emit(node.Name, "Name", -1, func(newNode, parent SQLNode) {
+			parent.(*SetExpr).Name = newNode.(ColIdent)
+		})
+		emit(node.Expr, "Expr", -1, func(newNode, parent SQLNode) {
+			parent.(*SetExpr).Expr = newNode.(Expr)
+		})
+	case SetExprs:
+		for x, el := range node {
+			emit(el, "", x, func(idx int) replacerFunc {
+				return func(newNode, parent SQLNode) {
+					parent.(SetExprs)[idx] = newNode.(*SetExpr)
+				}
+			}(x))
+		}
+	case *SetTransaction:
+		emit(node.SQLNode, "SQLNode", -1, func(newNode, parent SQLNode) {
+			parent.(*SetTransaction).SQLNode = newNode.(SQLNode)
+		})
+		emit(node.Comments, "Comments", -1, func(newNode, parent SQLNode) {
+			parent.(*SetTransaction).Comments = newNode.(Comments)
+		})
+		for x, el := range node.Characteristics {
+			emit(el, "Characteristics", x, func(idx int) replacerFunc {
+				return func(newNode, parent SQLNode) {
+					parent.(*SetTransaction).Characteristics[idx] = newNode.(Characteristic)
+				}
+			}(x))
+		}
+	case *Show:
+		emit(node.Internal, "Internal", -1, func(newNode, parent SQLNode) {
+			parent.(*Show).Internal = newNode.(ShowInternal)
+		})
+	case *ShowBasic:
+		emit(node.Tbl, "Tbl", -1, func(newNode, parent SQLNode) {
+			parent.(*ShowBasic).Tbl = newNode.(TableName)
+		})
+		emit(node.DbName, "DbName", -1, func(newNode, parent SQLNode) {
+			parent.(*ShowBasic).DbName = newNode.(TableIdent)
+		})
+		emit(node.Filter, "Filter", -1, func(newNode, parent SQLNode) {
+			parent.(*ShowBasic).Filter = newNode.(*ShowFilter)
+		})
+	case *ShowCreate:
+		emit(node.Op, "Op", -1, func(newNode, parent SQLNode) {
+			parent.(*ShowCreate).Op = newNode.(TableName)
+		})
+	case *ShowFilter:
+		emit(node.Filter, "Filter", -1, func(newNode, parent SQLNode) {
+			parent.(*ShowFilter).Filter = newNode.(Expr)
+		})
+	case *ShowLegacy:
+		emit(node.OnTable, "OnTable", -1, func(newNode, parent SQLNode) {
+			parent.(*ShowLegacy).OnTable = newNode.(TableName)
+		})
+		emit(node.Table, "Table", -1, func(newNode, parent SQLNode) {
+			parent.(*ShowLegacy).Table = newNode.(TableName)
+		})
+		emit(node.ShowCollationFilterOpt, "ShowCollationFilterOpt", -1, func(newNode, parent SQLNode) {
+			parent.(*ShowLegacy).ShowCollationFilterOpt = newNode.(Expr)
+		})
+	case *ShowMigrationLogs:
+		emit(node.Comments, "Comments", -1, func(newNode, parent SQLNode) {
+			parent.(*ShowMigrationLogs).Comments = newNode.(Comments)
+		})
+	case *StarExpr:
+		emit(node.TableName, "TableName", -1, func(newNode, parent SQLNode) {
+			parent.(*StarExpr).TableName = newNode.(TableName)
+		})
+	case *Stream:
+		emit(node.Comments, "Comments", -1, func(newNode, parent SQLNode) {
+			parent.(*Stream).Comments = newNode.(Comments)
+		})
+		emit(node.SelectExpr, "SelectExpr", -1, func(newNode, parent SQLNode) {
+			parent.(*Stream).SelectExpr = newNode.(SelectExpr)
+		})
+		emit(node.Table, "Table", -1, func(newNode, parent SQLNode) {
+			parent.(*Stream).Table = newNode.(TableName)
+		})
+	case *Subquery:
+		emit(node.Select, "Select", -1, func(newNode, parent SQLNode) {
+			parent.(*Subquery).Select = newNode.(SelectStatement)
+		})
+	case *SubstrExpr:
+		emit(node.Name, "Name", -1, func(newNode, parent SQLNode) {
+			parent.(*SubstrExpr).Name = newNode.(*ColName)
+		})
+		emit(node.StrVal, "StrVal", -1, func(newNode, parent SQLNode) {
+			parent.(*SubstrExpr).StrVal = newNode.(*Literal)
+		})
+		emit(node.From, "From", -1, func(newNode, parent SQLNode) {
+			parent.(*SubstrExpr).From = newNode.(Expr)
+		})
+		emit(node.To, "To", -1, func(newNode, parent SQLNode) {
+			parent.(*SubstrExpr).To = newNode.(Expr)
+		})
+	case TableExprs:
+		for x, el := range node {
+			emit(el, "", x, func(idx int) replacerFunc {
+				return func(newNode, parent SQLNode) {
+					parent.(TableExprs)[idx] = newNode.(TableExpr)
+				}
+			}(x))
+		}
+	case TableName:
+		emit(node.Name, "Name", -1, func(newNode, parent SQLNode) {
+			panic("[BUG] tried to replace 'Name' on 'TableName'")
+		})
+		emit(node.Qualifier, "Qualifier", -1, func(newNode, parent SQLNode) {
+			panic("[BUG] tried to replace 'Qualifier' on 'TableName'")
+		})
+	case TableNames:
+		for x, el := range node {
+			emit(el, "", x, func(idx int) replacerFunc {
+				return func(newNode, parent SQLNode) {
+					parent.(TableNames)[idx] = newNode.(TableName)
+				}
+			}(x))
+		}
+	case *TableSpec:
+		for x, el := range node.Columns {
+			emit(el, "Columns", x, func(idx int) replacerFunc {
+				return func(newNode, parent SQLNode) {
+					parent.(*TableSpec).Columns[idx] = newNode.(*ColumnDefinition)
+				}
+			}(x))
+		}
+		for x, el := range node.Indexes {
+			emit(el, "Indexes", x, func(idx int) replacerFunc {
+				return func(newNode, parent SQLNode) {
+					parent.(*TableSpec).Indexes[idx] = newNode.(*IndexDefinition)
+				}
+			}(x))
+		}
+		for x, el := range node.Constraints {
+			emit(el, "Constraints", x, func(idx int) replacerFunc {
+				return func(newNode, parent SQLNode) {
+					parent.(*TableSpec).Constraints[idx] = newNode.(*ConstraintDefinition)
+				}
+			}(x))
+		}
+		emit(node.Options, "Options", -1, func(newNode, parent SQLNode) {
+			parent.(*TableSpec).Options = newNode.(TableOptions)
+		})
+	case *TimestampFuncExpr:
+		emit(node.Expr1, "Expr1", -1, func(newNode, parent SQLNode) {
+			parent.(*TimestampFuncExpr).Expr1 = newNode.(Expr)
+		})
+		emit(node.Expr2, "Expr2", -1, func(newNode, parent SQLNode) {
+			parent.(*TimestampFuncExpr).Expr2 = newNode.(Expr)
+		})
+	case *TruncateTable:
+		emit(node.Table, "Table", -1, func(newNode, parent SQLNode) {
+			parent.(*TruncateTable).Table = newNode.(TableName)
+		})
+	case *UnaryExpr:
+		emit(node.Expr, "Expr", -1, func(newNode, parent SQLNode) {
+			parent.(*UnaryExpr).Expr = newNode.(Expr)
+		})
+	case *Union:
+		emit(node.Left, "Left", -1, func(newNode, parent SQLNode) {
+			parent.(*Union).Left = newNode.(SelectStatement)
+		})
+		emit(node.Right, "Right", -1, func(newNode, parent SQLNode) {
+			parent.(*Union).Right = newNode.(SelectStatement)
+		})
+		emit(node.OrderBy, "OrderBy", -1, func(newNode, parent SQLNode) {
+			parent.(*Union).OrderBy = newNode.(OrderBy)
+		})
+		emit(node.With, "With", -1, func(newNode, parent SQLNode) {
+			parent.(*Union).With = newNode.(*With)
+		})
+		emit(node.Limit, "Limit", -1, func(newNode, parent SQLNode) {
+			parent.(*Union).Limit = newNode.(*Limit)
+		})
+		emit(node.Into, "Into", -1, func(newNode, parent SQLNode) {
+			parent.(*Union).Into = newNode.(*SelectInto)
+		})
+	case *Update:
+		emit(node.With, "With", -1, func(newNode, parent SQLNode) {
+			parent.(*Update).With = newNode.(*With)
+		})
+		emit(node.Comments, "Comments", -1, func(newNode, parent SQLNode) {
+			parent.(*Update).Comments = newNode.(Comments)
+		})
+		emit(node.TableExprs, "TableExprs", -1, func(newNode, parent SQLNode) {
+			parent.(*Update).TableExprs = newNode.(TableExprs)
+		})
+		emit(node.Exprs, "Exprs", -1, func(newNode, parent SQLNode) {
+			parent.(*Update).Exprs = newNode.(UpdateExprs)
+		})
+		emit(node.Where, "Where", -1, func(newNode, parent SQLNode) {
+			parent.(*Update).Where = newNode.(*Where)
+		})
+		emit(node.OrderBy, "OrderBy", -1, func(newNode, parent SQLNode) {
+			parent.(*Update).OrderBy = newNode.(OrderBy)
+		})
+		emit(node.Limit, "Limit", -1, func(newNode, parent SQLNode) {
+			parent.(*Update).Limit = newNode.(*Limit)
+		})
+	case *UpdateExpr:
+		emit(node.Name, "Name", -1, func(newNode, parent SQLNode) {
+			parent.(*UpdateExpr).Name = newNode.(*ColName)
+		})
+		emit(node.Expr, "Expr", -1, func(newNode, parent SQLNode) {
+			parent.(*UpdateExpr).Expr = newNode.(Expr)
+		})
+	case UpdateExprs:
+		for x, el := range node {
+			emit(el, "", x, func(idx int) replacerFunc {
+				return func(newNode, parent SQLNode) {
+					parent.(UpdateExprs)[idx] = newNode.(*UpdateExpr)
+				}
+			}(x))
+		}
+	case *Use:
+		emit(node.DBName, "DBName", -1, func(newNode, parent SQLNode) {
+			parent.(*Use).DBName = newNode.(TableIdent)
+		})
+	case *VStream:
+		emit(node.Comments, "Comments", -1, func(newNode, parent SQLNode) {
+			parent.(*VStream).Comments = newNode.(Comments)
+		})
+		emit(node.SelectExpr, "SelectExpr", -1, func(newNode, parent SQLNode) {
+			parent.(*VStream).SelectExpr = newNode.(SelectExpr)
+		})
+		emit(node.Table, "Table", -1, func(newNode, parent SQLNode) {
+			parent.(*VStream).Table = newNode.(TableName)
+		})
+		emit(node.Where, "Where", -1, func(newNode, parent SQLNode) {
+			parent.(*VStream).Where = newNode.(*Where)
+		})
+		emit(node.Limit, "Limit", -1, func(newNode, parent SQLNode) {
+			parent.(*VStream).Limit = newNode.(*Limit)
+		})
+	case ValTuple:
+		for x, el := range node {
+			emit(el, "", x, func(idx int) replacerFunc {
+				return func(newNode, parent SQLNode) {
+					parent.(ValTuple)[idx] = newNode.(Expr)
+				}
+			}(x))
+		}
+	case Values:
+		for x, el := range node {
+			emit(el, "", x, func(idx int) replacerFunc {
+				return func(newNode, parent SQLNode) {
+					parent.(Values)[idx] = newNode.(ValTuple)
+				}
+			}(x))
+		}
+	case *ValuesFuncExpr:
+		emit(node.Name, "Name", -1, func(newNode, parent SQLNode) {
+			parent.(*ValuesFuncExpr).Name = newNode.(*ColName)
+		})
+	case VindexParam:
+		emit(node.Key, "Key", -1, func(newNode, parent SQLNode) {
+			panic("[BUG] tried to replace 'Key' on 'VindexParam'")
+		})
+	case *VindexSpec:
+		emit(node.Name, "Name", -1, func(newNode, parent SQLNode) {
+			parent.(*VindexSpec).Name = newNode.(ColIdent)
+		})
+		emit(node.Type, "Type", -1, func(newNode, parent SQLNode) {
+			parent.(*VindexSpec).Type = newNode.(ColIdent)
+		})
+		for x, el := range node.Params {
+			emit(el, "Params", x, func(idx int) replacerFunc {
+				return func(newNode, parent SQLNode) {
+					parent.(*VindexSpec).Params[idx] = newNode.(VindexParam)
+				}
+			}(x))
+		}
+	case *When:
+		emit(node.Cond, "Cond", -1, func(newNode, parent SQLNode) {
+			parent.(*When).Cond = newNode.(Expr)
+		})
+		emit(node.Val, "Val", -1, func(newNode, parent SQLNode) {
+			parent.(*When).Val = newNode.(Expr)
+		})
+	case *Where:
+		emit(node.Expr, "Expr", -1, func(newNode, parent SQLNode) {
+			parent.(*Where).Expr = newNode.(Expr)
+		})
+	case *With:
+		for x, el := range node.ctes {
+			emit(el, "ctes", x, func(idx int) replacerFunc {
+				return func(newNode, parent SQLNode) {
+					parent.(*With).ctes[idx] = newNode.(*CommonTableExpr)
+				}
+			}(x))
+		}
+	case *XorExpr:
+		emit(node.Left, "Left", -1, func(newNode, parent SQLNode) {
+			parent.(*XorExpr).Left = newNode.(Expr)
+		})
+		emit(node.Right, "Right", -1, func(newNode, parent SQLNode) {
+			parent.(*XorExpr).Right = newNode.(Expr)
+		})
+	case *RootNode:
+		emit(node.SQLNode, "SQLNode", -1, func(newNode, parent SQLNode) {
+			parent.(*RootNode).SQLNode = newNode.(SQLNode)
+		})
+	case *TableName:
+		emit(node.Name, "Name", -1, func(newNode, parent SQLNode) {
+			parent.(*TableName).Name = newNode.(TableIdent)
+		})
+		emit(node.Qualifier, "Qualifier", -1, func(newNode, parent SQLNode) {
+			parent.(*TableName).Qualifier = newNode.(TableIdent)
+		})
+	case *VindexParam:
+		emit(node.Key, "Key", -1, func(newNode, parent SQLNode) {
+			parent.(*VindexParam).Key = newNode.(ColIdent)
+		})
+	}
+}
+
+// skipNode returns whether the recursive rewriter skips node without calling pre or post
+func (a *application) skipNode(node SQLNode) bool {
+	switch node := node.(type) {
+	case nil:
+		return true
+	case AccessMode:
+		return false
+	case *AddColumns:
+		return node == nil
+	case *AddConstraintDefinition:
+		return node == nil
+	case *AddIndexDefinition:
+		return node == nil
+	case AlgorithmValue:
+		return false
+	case *AliasedExpr:
+		return node == nil
+	case *AliasedTableExpr:
+		return node == nil
+	case *AlterCharset:
+		return node == nil
+	case *AlterColumn:
+		return node == nil
+	case *AlterDatabase:
+		return node == nil
+	case *AlterMigration:
+		return node == nil
+	case *AlterTable:
+		return node == nil
+	case *AlterView:
+		return node == nil
+	case *AlterVschema:
+		return node == nil
+	case *AndExpr:
+		return node == nil
+	case Argument:
+		return false
+	case *AutoIncSpec:
+		return node == nil
+	case *Begin:
+		return node == nil
+	case *BinaryExpr:
+		return node == nil
+	case BoolVal:
+		return false
+	case *CallProc:
+		return node == nil
+	case *CaseExpr:
+		return node == nil
+	case *ChangeColumn:
+		return node == nil
+	case *CheckConstraintDefinition:
+		return node == nil
+	case ColIdent:
+		return false
+	case *ColName:
+		return node == nil
+	case *CollateExpr:
+		return node == nil
+	case *ColumnDefinition:
+		return node == nil
+	case *ColumnType:
+		return node == nil
+	case Columns:
+		return node == nil
+	case Comments:
+		return node == nil
+	case *Commit:
+		return node == nil
+	case *CommonTableExpr:
+		return node == nil
+	case *ComparisonExpr:
+		return node == nil
+	case *ConstraintDefinition:
+		return node == nil
+	case *ConvertExpr:
+		return node == nil
+	case *ConvertType:
+		return node == nil
+	case *ConvertUsingExpr:
+		return node == nil
+	case *CreateDatabase:
+		return node == nil
+	case *CreateTable:
+		return node == nil
+	case *CreateView:
+		return node == nil
+	case *CurTimeFuncExpr:
+		return node == nil
+	case *Default:
+		return node == nil
+	case *Delete:
+		return node == nil
+	case *DerivedTable:
+		return node == nil
+	case *DropColumn:
+		return node == nil
+	case *DropDatabase:
+		return node == nil
+	case *DropKey:
+		return node == nil
+	case *DropTable:
+		return node == nil
+	case *DropView:
+		return node == nil
+	case *ExistsExpr:
+		return node == nil
+	case *ExplainStmt:
+		return node == nil
+	case *ExplainTab:
+		return node == nil
+	case Exprs:
+		return node == nil
+	case *ExtractFuncExpr:
+		return node == nil
+	case *ExtractedSubquery:
+		return node == nil
+	case *Flush:
+		return node == nil
+	case *Force:
+		return node == nil
+	case *ForeignKeyDefinition:
+		return node == nil
+	case *FuncExpr:
+		return node == nil
+	case GroupBy:
+		return node == nil
+	case *GroupConcatExpr:
+		return node == nil
+	case *IndexDefinition:
+		return node == nil
+	case *IndexHints:
+		return node == nil
+	case *IndexInfo:
+		return node == nil
+	case *Insert:
+		return node == nil
+	case *IntervalExpr:
+		return node == nil
+	case *IsExpr:
+		return node == nil
+	case IsolationLevel:
+		return false
+	case *JoinCondition:
+		return node == nil
+	case *JoinTableExpr:
+		return node == nil
+	case *KeyState:
+		return node == nil
+	case *Limit:
+		return node == nil
+	case ListArg:
+		return false
+	case *Literal:
+		return node == nil
+	case *Load:
+		return node == nil
+	case *LockOption:
+		return node == nil
+	case *LockTables:
+		return node == nil
+	case *MatchExpr:
+		return node == nil
+	case *ModifyColumn:
+		return node == nil
+	case *Nextval:
+		return node == nil
+	case *NotExpr:
+		return node == nil
+	case *NullVal:
+		return node == nil
+	case OnDup:
+		return node == nil
+	case *OptLike:
+		return node == nil
+	case *OrExpr:
+		return node == nil
+	case *Order:
+		return node == nil
+	case OrderBy:
+		return node == nil
+	case *OrderByOption:
+		return node == nil
+	case *OtherAdmin:
+		return node == nil
+	case *OtherRead:
+		return node == nil
+	case *ParenTableExpr:
+		return node == nil
+	case *PartitionDefinition:
+		return node == nil
+	case *PartitionSpec:
+		return node == nil
+	case Partitions:
+		return node == nil
+	case *RangeCond:
+		return node == nil
+	case ReferenceAction:
+		return false
+	case *ReferenceDefinition:
+		return node == nil
+	case *Release:
+		return node == nil
+	case *RenameIndex:
+		return node == nil
+	case *RenameTable:
+		return node == nil
+	case *RenameTableName:
+		return node == nil
+	case *RevertMigration:
+		return node == nil
+	case *Rollback:
+		return node == nil
+	case RootNode:
+		return false
+	case *SRollback:
+		return node == nil
+	case *Savepoint:
+		return node == nil
+	case *Select:
+		return node == nil
+	case SelectExprs:
+		return node == nil
+	case *SelectInto:
+		return node == nil
+	case *Set:
+		return node == nil
+	case *SetExpr:
+		return node == nil
+	case SetExprs:
+		return node == nil
+	case *SetTransaction:
+		return node == nil
+	case *Show:
+		return node == nil
+	case *ShowBasic:
+		return node == nil
+	case *ShowCreate:
+		return node == nil
+	case *ShowFilter:
+		return node == nil
+	case *ShowLegacy:
+		return node == nil
+	case *ShowMigrationLogs:
+		return node == nil
+	case *StarExpr:
+		return node == nil
+	case *Stream:
+		return node == nil
+	case *Subquery:
+		return node == nil
+	case *SubstrExpr:
+		return node == nil
+	case TableExprs:
+		return node == nil
+	case TableIdent:
+		return false
+	case TableName:
+		return false
+	case TableNames:
+		return node == nil
+	case TableOptions:
+		return node == nil
+	case *TableSpec:
+		return node == nil
+	case *TablespaceOperation:
+		return node == nil
+	case *TimestampFuncExpr:
+		return node == nil
+	case *TruncateTable:
+		return node == nil
+	case *UnaryExpr:
+		return node == nil
+	case *Union:
+		return node == nil
+	case *UnlockTables:
+		return node == nil
+	case *Update:
+		return node == nil
+	case *UpdateExpr:
+		return node == nil
+	case UpdateExprs:
+		return node == nil
+	case *Use:
+		return node == nil
+	case *VStream:
+		return node == nil
+	case ValTuple:
+		return node == nil
+	case *Validation:
+		return node == nil
+	case Values:
+		return node == nil
+	case *ValuesFuncExpr:
+		return node == nil
+	case VindexParam:
+		return false
+	case *VindexSpec:
+		return node == nil
+	case *When:
+		return node == nil
+	case *Where:
+		return node == nil
+	case *With:
+		return node == nil
+	case *XorExpr:
+		return node == nil
+	case *AccessMode, *AlgorithmValue, *Argument, *BoolVal, *ColIdent, *Columns, *Comments, *Exprs, *GroupBy, *IsolationLevel, *ListArg, *OnDup, *OrderBy, *Partitions, *ReferenceAction, *RootNode, *SelectExprs, *SetExprs, *TableExprs, *TableIdent, *TableName, *TableNames, *TableOptions, *UpdateExprs, *ValTuple, *Values, *VindexParam:
+		return true
+	default:
+		a.unknownNode(node)
+		return true
+	}
+}
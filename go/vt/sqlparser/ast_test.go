@@ -818,6 +818,100 @@ func TestShowTableStatus(t *testing.T) {
 	require.NotNil(t, tree)
 }
 
+func TestCloneRoundTrip(t *testing.T) {
+	queries := []string{
+		"select a, b as c, count(*) from t join u on t.id = u.id where d in (1, 2, 3) group by a having count(*) > 1 order by b desc limit 10",
+		"select * from (select a from t) as x where exists (select 1 from u where u.a = x.a)",
+		"insert into t(a, b) values (1, 'x'), (2, 'y') on duplicate key update b = values(b)",
+		"update /* comment */ t set a = a + 1 where b = :b",
+		"delete from t where a is null",
+		"create table t (id int primary key, name varchar(10) default 'x')",
+		"with cte as (select a from t) select * from cte union select b from u",
+	}
+
+	for _, query := range queries {
+		stmt, err := Parse(query)
+		require.NoError(t, err)
+		formatted := String(stmt)
+
+		clone := CloneStatement(stmt)
+		assert.Equal(t, formatted, String(clone), query)
+		assert.True(t, EqualsStatement(stmt, clone), query)
+
+		// mutating the literals and the table names of the clone must not affect the
+		// original; columns are shared between the clones, see TestCloneSharesColName
+		Rewrite(clone, func(cursor *Cursor) bool {
+			switch node := cursor.Node().(type) {
+			case *ColName:
+				return false
+			case *Literal:
+				node.Val = "42"
+			case TableName:
+				cursor.Replace(TableName{Name: NewTableIdent("other")})
+			}
+			return true
+		}, nil)
+		assert.NotEqual(t, formatted, String(clone), query)
+		assert.Equal(t, formatted, String(stmt), query)
+	}
+}
+
+func TestCloneSharesColName(t *testing.T) {
+	// *ColName is deliberately not deep cloned, so that the information attached
+	// to a column during planning is shared between the copies of an AST
+	stmt, err := Parse("select a from t where b = 1")
+	require.NoError(t, err)
+
+	var original, cloned []*ColName
+	collect := func(cols *[]*ColName) func(node SQLNode) (bool, error) {
+		return func(node SQLNode) (bool, error) {
+			if col, ok := node.(*ColName); ok {
+				*cols = append(*cols, col)
+			}
+			return true, nil
+		}
+	}
+	require.NoError(t, Walk(collect(&original), stmt))
+	require.NoError(t, Walk(collect(&cloned), CloneStatement(stmt)))
+	assert.Equal(t, original, cloned)
+	for i := range original {
+		assert.Same(t, original[i], cloned[i])
+	}
+}
+
+func TestEqualsSQLNode(t *testing.T) {
+	parse := func(query string) Statement {
+		stmt, err := Parse(query)
+		require.NoError(t, err)
+		return stmt
+	}
+
+	var cases = []struct {
+		a, b  SQLNode
+		equal bool
+	}{
+		{nil, nil, true},
+		{parse("select 1 from dual"), nil, false},
+		{nil, parse("select 1 from dual"), false},
+		{Expr(nil), Expr(nil), true},
+		{parse("select a, b from t where c = 1"), parse("select a, b from t where c = 1"), true},
+		{parse("select a, b from t"), parse("select a, b, c from t"), false},
+		{parse("select a from t"), parse("select a from t, u"), false},
+		{parse("select a from t where c = 1"), parse("select a from t where c = 2"), false},
+		{parse("select a from t where c = 1"), parse("select a from t where c = '1'"), false},
+		{parse("select a from t where c = 1"), parse("select a from t where c > 1"), false},
+		{parse("select a from t where c = 1"), parse("select a from t having c = 1"), false},
+		{parse("select distinct a from t"), parse("select a from t"), false},
+		{SelectExprs{}, SelectExprs(nil), true},
+		{SelectExprs{&StarExpr{}}, SelectExprs{&StarExpr{}, &StarExpr{}}, false},
+	}
+
+	for _, tc := range cases {
+		assert.Equal(t, tc.equal, EqualsSQLNode(tc.a, tc.b), "%s == %s", String(tc.a), String(tc.b))
+		assert.Equal(t, tc.equal, EqualsSQLNode(tc.b, tc.a), "%s == %s", String(tc.b), String(tc.a))
+	}
+}
+
 func BenchmarkStringTraces(b *testing.B) {
 	for _, trace := range []string{"django_queries.txt", "lobsters.sql.gz"} {
 		b.Run(trace, func(b *testing.B) {
@@ -846,3 +940,31 @@ func BenchmarkStringTraces(b *testing.B) {
 		})
 	}
 }
+
+func TestMarshalSQLNodeJSON(t *testing.T) {
+	stmt, err := Parse("select a, b + 1 from t where c = 'x' and d in (1, 2)")
+	require.NoError(t, err)
+
+	out, err := MarshalSQLNodeJSON(stmt)
+	require.NoError(t, err)
+	require.True(t, json.Valid(out), "invalid JSON: %s", out)
+
+	var tree map[string]interface{}
+	require.NoError(t, json.Unmarshal(out, &tree))
+	assert.Equal(t, "Select", tree["_type"])
+	exprs := tree["SelectExprs"].([]interface{})
+	require.Len(t, exprs, 2)
+	assert.Equal(t, "AliasedExpr", exprs[0].(map[string]interface{})["_type"])
+	where := tree["Where"].(map[string]interface{})
+	assert.Equal(t, "AndExpr", where["Expr"].(map[string]interface{})["_type"])
+
+	for _, query := range validSQL {
+		stmt, err := Parse(query.input)
+		if err != nil {
+			continue
+		}
+		out, err := MarshalSQLNodeJSON(stmt)
+		require.NoError(t, err, query.input)
+		require.True(t, json.Valid(out), "invalid JSON for %s: %s", query.input, out)
+	}
+}
@@ -0,0 +1,1334 @@
+/*
+Copyright 2021 The Vitess Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by ASTHelperGen. DO NOT EDIT.
+
+package sqlparser
+
+// WalkColName walks the AST rooted at node and calls pre and post for every *ColName in it.
+// The subtrees that cannot contain the target type are not visited. If pre returns false, the
+// children of the current node are skipped; if post returns false, the walk is aborted.
+func WalkColName(node SQLNode, pre, post func(*ColName) bool) {
+	r := &typedWalkerColName{pre, post}
+	r.walkSQLNode(node)
+}
+
+type typedWalkerColName struct {
+	pre, post func(*ColName) bool
+}
+
+func (r *typedWalkerColName) walkSQLNode(node SQLNode) bool {
+	switch node := node.(type) {
+	case *AddColumns:
+		return r.walkRefOfAddColumns(node)
+	case *AddConstraintDefinition:
+		return r.walkRefOfAddConstraintDefinition(node)
+	case *AliasedExpr:
+		return r.walkRefOfAliasedExpr(node)
+	case *AliasedTableExpr:
+		return r.walkRefOfAliasedTableExpr(node)
+	case *AlterColumn:
+		return r.walkRefOfAlterColumn(node)
+	case *AlterTable:
+		return r.walkRefOfAlterTable(node)
+	case *AlterView:
+		return r.walkRefOfAlterView(node)
+	case *AndExpr:
+		return r.walkRefOfAndExpr(node)
+	case *BinaryExpr:
+		return r.walkRefOfBinaryExpr(node)
+	case *CallProc:
+		return r.walkRefOfCallProc(node)
+	case *CaseExpr:
+		return r.walkRefOfCaseExpr(node)
+	case *ChangeColumn:
+		return r.walkRefOfChangeColumn(node)
+	case *CheckConstraintDefinition:
+		return r.walkRefOfCheckConstraintDefinition(node)
+	case *ColName:
+		return r.walkRefOfColName(node)
+	case *CollateExpr:
+		return r.walkRefOfCollateExpr(node)
+	case *CommonTableExpr:
+		return r.walkRefOfCommonTableExpr(node)
+	case *ComparisonExpr:
+		return r.walkRefOfComparisonExpr(node)
+	case *ConstraintDefinition:
+		return r.walkRefOfConstraintDefinition(node)
+	case *ConvertExpr:
+		return r.walkRefOfConvertExpr(node)
+	case *ConvertUsingExpr:
+		return r.walkRefOfConvertUsingExpr(node)
+	case *CreateTable:
+		return r.walkRefOfCreateTable(node)
+	case *CreateView:
+		return r.walkRefOfCreateView(node)
+	case *CurTimeFuncExpr:
+		return r.walkRefOfCurTimeFuncExpr(node)
+	case *Delete:
+		return r.walkRefOfDelete(node)
+	case *DerivedTable:
+		return r.walkRefOfDerivedTable(node)
+	case *DropColumn:
+		return r.walkRefOfDropColumn(node)
+	case *ExistsExpr:
+		return r.walkRefOfExistsExpr(node)
+	case *ExplainStmt:
+		return r.walkRefOfExplainStmt(node)
+	case Exprs:
+		return r.walkExprs(node)
+	case *ExtractFuncExpr:
+		return r.walkRefOfExtractFuncExpr(node)
+	case *ExtractedSubquery:
+		return r.walkRefOfExtractedSubquery(node)
+	case *FuncExpr:
+		return r.walkRefOfFuncExpr(node)
+	case GroupBy:
+		return r.walkGroupBy(node)
+	case *GroupConcatExpr:
+		return r.walkRefOfGroupConcatExpr(node)
+	case *Insert:
+		return r.walkRefOfInsert(node)
+	case *IntervalExpr:
+		return r.walkRefOfIntervalExpr(node)
+	case *IsExpr:
+		return r.walkRefOfIsExpr(node)
+	case *JoinCondition:
+		return r.walkRefOfJoinCondition(node)
+	case *JoinTableExpr:
+		return r.walkRefOfJoinTableExpr(node)
+	case *Limit:
+		return r.walkRefOfLimit(node)
+	case *MatchExpr:
+		return r.walkRefOfMatchExpr(node)
+	case *ModifyColumn:
+		return r.walkRefOfModifyColumn(node)
+	case *Nextval:
+		return r.walkRefOfNextval(node)
+	case *NotExpr:
+		return r.walkRefOfNotExpr(node)
+	case OnDup:
+		return r.walkOnDup(node)
+	case *OrExpr:
+		return r.walkRefOfOrExpr(node)
+	case *Order:
+		return r.walkRefOfOrder(node)
+	case OrderBy:
+		return r.walkOrderBy(node)
+	case *ParenTableExpr:
+		return r.walkRefOfParenTableExpr(node)
+	case *PartitionDefinition:
+		return r.walkRefOfPartitionDefinition(node)
+	case *PartitionSpec:
+		return r.walkRefOfPartitionSpec(node)
+	case *RangeCond:
+		return r.walkRefOfRangeCond(node)
+	case RootNode:
+		return r.walkRootNode(node)
+	case *Select:
+		return r.walkRefOfSelect(node)
+	case SelectExprs:
+		return r.walkSelectExprs(node)
+	case *Set:
+		return r.walkRefOfSet(node)
+	case *SetExpr:
+		return r.walkRefOfSetExpr(node)
+	case SetExprs:
+		return r.walkSetExprs(node)
+	case *SetTransaction:
+		return r.walkRefOfSetTransaction(node)
+	case *Show:
+		return r.walkRefOfShow(node)
+	case *ShowBasic:
+		return r.walkRefOfShowBasic(node)
+	case *ShowFilter:
+		return r.walkRefOfShowFilter(node)
+	case *ShowLegacy:
+		return r.walkRefOfShowLegacy(node)
+	case *Stream:
+		return r.walkRefOfStream(node)
+	case *Subquery:
+		return r.walkRefOfSubquery(node)
+	case *SubstrExpr:
+		return r.walkRefOfSubstrExpr(node)
+	case TableExprs:
+		return r.walkTableExprs(node)
+	case *TableSpec:
+		return r.walkRefOfTableSpec(node)
+	case *TimestampFuncExpr:
+		return r.walkRefOfTimestampFuncExpr(node)
+	case *UnaryExpr:
+		return r.walkRefOfUnaryExpr(node)
+	case *Union:
+		return r.walkRefOfUnion(node)
+	case *Update:
+		return r.walkRefOfUpdate(node)
+	case *UpdateExpr:
+		return r.walkRefOfUpdateExpr(node)
+	case UpdateExprs:
+		return r.walkUpdateExprs(node)
+	case *VStream:
+		return r.walkRefOfVStream(node)
+	case ValTuple:
+		return r.walkValTuple(node)
+	case Values:
+		return r.walkValues(node)
+	case *ValuesFuncExpr:
+		return r.walkRefOfValuesFuncExpr(node)
+	case *When:
+		return r.walkRefOfWhen(node)
+	case *Where:
+		return r.walkRefOfWhere(node)
+	case *With:
+		return r.walkRefOfWith(node)
+	case *XorExpr:
+		return r.walkRefOfXorExpr(node)
+	}
+	return true
+}
+func (r *typedWalkerColName) walkRefOfAddColumns(node *AddColumns) bool {
+	if node == nil {
+		return true
+	}
+	if !r.walkRefOfColName(node.After) {
+		return false
+	}
+	return true
+}
+func (r *typedWalkerColName) walkRefOfAddConstraintDefinition(node *AddConstraintDefinition) bool {
+	if node == nil {
+		return true
+	}
+	if !r.walkRefOfConstraintDefinition(node.ConstraintDefinition) {
+		return false
+	}
+	return true
+}
+func (r *typedWalkerColName) walkRefOfAliasedExpr(node *AliasedExpr) bool {
+	if node == nil {
+		return true
+	}
+	if !r.walkExpr(node.Expr) {
+		return false
+	}
+	return true
+}
+func (r *typedWalkerColName) walkRefOfAliasedTableExpr(node *AliasedTableExpr) bool {
+	if node == nil {
+		return true
+	}
+	if !r.walkSimpleTableExpr(node.Expr) {
+		return false
+	}
+	return true
+}
+func (r *typedWalkerColName) walkRefOfAlterColumn(node *AlterColumn) bool {
+	if node == nil {
+		return true
+	}
+	if !r.walkRefOfColName(node.Column) {
+		return false
+	}
+	if !r.walkExpr(node.DefaultVal) {
+		return false
+	}
+	return true
+}
+func (r *typedWalkerColName) walkRefOfAlterTable(node *AlterTable) bool {
+	if node == nil {
+		return true
+	}
+	for _, el := range node.AlterOptions {
+		if !r.walkAlterOption(el) {
+			return false
+		}
+	}
+	if !r.walkRefOfPartitionSpec(node.PartitionSpec) {
+		return false
+	}
+	return true
+}
+func (r *typedWalkerColName) walkRefOfAlterView(node *AlterView) bool {
+	if node == nil {
+		return true
+	}
+	if !r.walkSelectStatement(node.Select) {
+		return false
+	}
+	return true
+}
+func (r *typedWalkerColName) walkRefOfAndExpr(node *AndExpr) bool {
+	if node == nil {
+		return true
+	}
+	if !r.walkExpr(node.Left) {
+		return false
+	}
+	if !r.walkExpr(node.Right) {
+		return false
+	}
+	return true
+}
+func (r *typedWalkerColName) walkRefOfBinaryExpr(node *BinaryExpr) bool {
+	if node == nil {
+		return true
+	}
+	if !r.walkExpr(node.Left) {
+		return false
+	}
+	if !r.walkExpr(node.Right) {
+		return false
+	}
+	return true
+}
+func (r *typedWalkerColName) walkRefOfCallProc(node *CallProc) bool {
+	if node == nil {
+		return true
+	}
+	if !r.walkExprs(node.Params) {
+		return false
+	}
+	return true
+}
+func (r *typedWalkerColName) walkRefOfCaseExpr(node *CaseExpr) bool {
+	if node == nil {
+		return true
+	}
+	if !r.walkExpr(node.Expr) {
+		return false
+	}
+	for _, el := range node.Whens {
+		if !r.walkRefOfWhen(el) {
+			return false
+		}
+	}
+	if !r.walkExpr(node.Else) {
+		return false
+	}
+	return true
+}
+func (r *typedWalkerColName) walkRefOfChangeColumn(node *ChangeColumn) bool {
+	if node == nil {
+		return true
+	}
+	if !r.walkRefOfColName(node.OldColumn) {
+		return false
+	}
+	if !r.walkRefOfColName(node.After) {
+		return false
+	}
+	return true
+}
+func (r *typedWalkerColName) walkRefOfCheckConstraintDefinition(node *CheckConstraintDefinition) bool {
+	if node == nil {
+		return true
+	}
+	if !r.walkExpr(node.Expr) {
+		return false
+	}
+	return true
+}
+func (r *typedWalkerColName) walkRefOfColName(node *ColName) bool {
+	if node == nil {
+		return true
+	}
+	if r.pre != nil && !r.pre(node) {
+		return true
+	}
+	if r.post != nil {
+		return r.post(node)
+	}
+	return true
+}
+func (r *typedWalkerColName) walkRefOfCollateExpr(node *CollateExpr) bool {
+	if node == nil {
+		return true
+	}
+	if !r.walkExpr(node.Expr) {
+		return false
+	}
+	return true
+}
+func (r *typedWalkerColName) walkRefOfCommonTableExpr(node *CommonTableExpr) bool {
+	if node == nil {
+		return true
+	}
+	if !r.walkRefOfSubquery(node.Subquery) {
+		return false
+	}
+	return true
+}
+func (r *typedWalkerColName) walkRefOfComparisonExpr(node *ComparisonExpr) bool {
+	if node == nil {
+		return true
+	}
+	if !r.walkExpr(node.Left) {
+		return false
+	}
+	if !r.walkExpr(node.Right) {
+		return false
+	}
+	if !r.walkExpr(node.Escape) {
+		return false
+	}
+	return true
+}
+func (r *typedWalkerColName) walkRefOfConstraintDefinition(node *ConstraintDefinition) bool {
+	if node == nil {
+		return true
+	}
+	if !r.walkConstraintInfo(node.Details) {
+		return false
+	}
+	return true
+}
+func (r *typedWalkerColName) walkRefOfConvertExpr(node *ConvertExpr) bool {
+	if node == nil {
+		return true
+	}
+	if !r.walkExpr(node.Expr) {
+		return false
+	}
+	return true
+}
+func (r *typedWalkerColName) walkRefOfConvertUsingExpr(node *ConvertUsingExpr) bool {
+	if node == nil {
+		return true
+	}
+	if !r.walkExpr(node.Expr) {
+		return false
+	}
+	return true
+}
+func (r *typedWalkerColName) walkRefOfCreateTable(node *CreateTable) bool {
+	if node == nil {
+		return true
+	}
+	if !r.walkRefOfTableSpec(node.TableSpec) {
+		return false
+	}
+	return true
+}
+func (r *typedWalkerColName) walkRefOfCreateView(node *CreateView) bool {
+	if node == nil {
+		return true
+	}
+	if !r.walkSelectStatement(node.Select) {
+		return false
+	}
+	return true
+}
+func (r *typedWalkerColName) walkRefOfCurTimeFuncExpr(node *CurTimeFuncExpr) bool {
+	if node == nil {
+		return true
+	}
+	if !r.walkExpr(node.Fsp) {
+		return false
+	}
+	return true
+}
+func (r *typedWalkerColName) walkRefOfDelete(node *Delete) bool {
+	if node == nil {
+		return true
+	}
+	if !r.walkRefOfWith(node.With) {
+		return false
+	}
+	if !r.walkTableExprs(node.TableExprs) {
+		return false
+	}
+	if !r.walkRefOfWhere(node.Where) {
+		return false
+	}
+	if !r.walkOrderBy(node.OrderBy) {
+		return false
+	}
+	if !r.walkRefOfLimit(node.Limit) {
+		return false
+	}
+	return true
+}
+func (r *typedWalkerColName) walkRefOfDerivedTable(node *DerivedTable) bool {
+	if node == nil {
+		return true
+	}
+	if !r.walkSelectStatement(node.Select) {
+		return false
+	}
+	return true
+}
+func (r *typedWalkerColName) walkRefOfDropColumn(node *DropColumn) bool {
+	if node == nil {
+		return true
+	}
+	if !r.walkRefOfColName(node.Name) {
+		return false
+	}
+	return true
+}
+func (r *typedWalkerColName) walkRefOfExistsExpr(node *ExistsExpr) bool {
+	if node == nil {
+		return true
+	}
+	if !r.walkRefOfSubquery(node.Subquery) {
+		return false
+	}
+	return true
+}
+func (r *typedWalkerColName) walkRefOfExplainStmt(node *ExplainStmt) bool {
+	if node == nil {
+		return true
+	}
+	if !r.walkStatement(node.Statement) {
+		return false
+	}
+	return true
+}
+func (r *typedWalkerColName) walkExprs(node Exprs) bool {
+	if node == nil {
+		return true
+	}
+	for _, el := range node {
+		if !r.walkExpr(el) {
+			return false
+		}
+	}
+	return true
+}
+func (r *typedWalkerColName) walkRefOfExtractFuncExpr(node *ExtractFuncExpr) bool {
+	if node == nil {
+		return true
+	}
+	if !r.walkExpr(node.Expr) {
+		return false
+	}
+	return true
+}
+func (r *typedWalkerColName) walkRefOfExtractedSubquery(node *ExtractedSubquery) bool {
+	if node == nil {
+		return true
+	}
+	if !r.walkExpr(node.Original) {
+		return false
+	}
+	if !r.walkRefOfSubquery(node.Subquery) {
+		return false
+	}
+	if !r.walkExpr(node.OtherSide) {
+		return false
+	}
+	if !r.walkExpr(node.alternative) {
+		return false
+	}
+	return true
+}
+func (r *typedWalkerColName) walkRefOfFuncExpr(node *FuncExpr) bool {
+	if node == nil {
+		return true
+	}
+	if !r.walkSelectExprs(node.Exprs) {
+		return false
+	}
+	return true
+}
+func (r *typedWalkerColName) walkGroupBy(node GroupBy) bool {
+	if node == nil {
+		return true
+	}
+	for _, el := range node {
+		if !r.walkExpr(el) {
+			return false
+		}
+	}
+	return true
+}
+func (r *typedWalkerColName) walkRefOfGroupConcatExpr(node *GroupConcatExpr) bool {
+	if node == nil {
+		return true
+	}
+	if !r.walkSelectExprs(node.Exprs) {
+		return false
+	}
+	if !r.walkOrderBy(node.OrderBy) {
+		return false
+	}
+	if !r.walkRefOfLimit(node.Limit) {
+		return false
+	}
+	return true
+}
+func (r *typedWalkerColName) walkRefOfInsert(node *Insert) bool {
+	if node == nil {
+		return true
+	}
+	if !r.walkInsertRows(node.Rows) {
+		return false
+	}
+	if !r.walkOnDup(node.OnDup) {
+		return false
+	}
+	return true
+}
+func (r *typedWalkerColName) walkRefOfIntervalExpr(node *IntervalExpr) bool {
+	if node == nil {
+		return true
+	}
+	if !r.walkExpr(node.Expr) {
+		return false
+	}
+	return true
+}
+func (r *typedWalkerColName) walkRefOfIsExpr(node *IsExpr) bool {
+	if node == nil {
+		return true
+	}
+	if !r.walkExpr(node.Left) {
+		return false
+	}
+	return true
+}
+func (r *typedWalkerColName) walkRefOfJoinCondition(node *JoinCondition) bool {
+	if node == nil {
+		return true
+	}
+	if !r.walkExpr(node.On) {
+		return false
+	}
+	return true
+}
+func (r *typedWalkerColName) walkRefOfJoinTableExpr(node *JoinTableExpr) bool {
+	if node == nil {
+		return true
+	}
+	if !r.walkTableExpr(node.LeftExpr) {
+		return false
+	}
+	if !r.walkTableExpr(node.RightExpr) {
+		return false
+	}
+	if !r.walkRefOfJoinCondition(node.Condition) {
+		return false
+	}
+	return true
+}
+func (r *typedWalkerColName) walkRefOfLimit(node *Limit) bool {
+	if node == nil {
+		return true
+	}
+	if !r.walkExpr(node.Offset) {
+		return false
+	}
+	if !r.walkExpr(node.Rowcount) {
+		return false
+	}
+	return true
+}
+func (r *typedWalkerColName) walkRefOfMatchExpr(node *MatchExpr) bool {
+	if node == nil {
+		return true
+	}
+	if !r.walkSelectExprs(node.Columns) {
+		return false
+	}
+	if !r.walkExpr(node.Expr) {
+		return false
+	}
+	return true
+}
+func (r *typedWalkerColName) walkRefOfModifyColumn(node *ModifyColumn) bool {
+	if node == nil {
+		return true
+	}
+	if !r.walkRefOfColName(node.After) {
+		return false
+	}
+	return true
+}
+func (r *typedWalkerColName) walkRefOfNextval(node *Nextval) bool {
+	if node == nil {
+		return true
+	}
+	if !r.walkExpr(node.Expr) {
+		return false
+	}
+	return true
+}
+func (r *typedWalkerColName) walkRefOfNotExpr(node *NotExpr) bool {
+	if node == nil {
+		return true
+	}
+	if !r.walkExpr(node.Expr) {
+		return false
+	}
+	return true
+}
+func (r *typedWalkerColName) walkOnDup(node OnDup) bool {
+	if node == nil {
+		return true
+	}
+	for _, el := range node {
+		if !r.walkRefOfUpdateExpr(el) {
+			return false
+		}
+	}
+	return true
+}
+func (r *typedWalkerColName) walkRefOfOrExpr(node *OrExpr) bool {
+	if node == nil {
+		return true
+	}
+	if !r.walkExpr(node.Left) {
+		return false
+	}
+	if !r.walkExpr(node.Right) {
+		return false
+	}
+	return true
+}
+func (r *typedWalkerColName) walkRefOfOrder(node *Order) bool {
+	if node == nil {
+		return true
+	}
+	if !r.walkExpr(node.Expr) {
+		return false
+	}
+	return true
+}
+func (r *typedWalkerColName) walkOrderBy(node OrderBy) bool {
+	if node == nil {
+		return true
+	}
+	for _, el := range node {
+		if !r.walkRefOfOrder(el) {
+			return false
+		}
+	}
+	return true
+}
+func (r *typedWalkerColName) walkRefOfParenTableExpr(node *ParenTableExpr) bool {
+	if node == nil {
+		return true
+	}
+	if !r.walkTableExprs(node.Exprs) {
+		return false
+	}
+	return true
+}
+func (r *typedWalkerColName) walkRefOfPartitionDefinition(node *PartitionDefinition) bool {
+	if node == nil {
+		return true
+	}
+	if !r.walkExpr(node.Limit) {
+		return false
+	}
+	return true
+}
+func (r *typedWalkerColName) walkRefOfPartitionSpec(node *PartitionSpec) bool {
+	if node == nil {
+		return true
+	}
+	for _, el := range node.Definitions {
+		if !r.walkRefOfPartitionDefinition(el) {
+			return false
+		}
+	}
+	return true
+}
+func (r *typedWalkerColName) walkRefOfRangeCond(node *RangeCond) bool {
+	if node == nil {
+		return true
+	}
+	if !r.walkExpr(node.Left) {
+		return false
+	}
+	if !r.walkExpr(node.From) {
+		return false
+	}
+	if !r.walkExpr(node.To) {
+		return false
+	}
+	return true
+}
+func (r *typedWalkerColName) walkRootNode(node RootNode) bool {
+	if !r.walkSQLNode(node.SQLNode) {
+		return false
+	}
+	return true
+}
+func (r *typedWalkerColName) walkRefOfSelect(node *Select) bool {
+	if node == nil {
+		return true
+	}
+	for _, el := range node.From {
+		if !r.walkTableExpr(el) {
+			return false
+		}
+	}
+	if !r.walkSelectExprs(node.SelectExprs) {
+		return false
+	}
+	if !r.walkRefOfWhere(node.Where) {
+		return false
+	}
+	if !r.walkRefOfWith(node.With) {
+		return false
+	}
+	if !r.walkGroupBy(node.GroupBy) {
+		return false
+	}
+	if !r.walkRefOfWhere(node.Having) {
+		return false
+	}
+	if !r.walkOrderBy(node.OrderBy) {
+		return false
+	}
+	if !r.walkRefOfLimit(node.Limit) {
+		return false
+	}
+	return true
+}
+func (r *typedWalkerColName) walkSelectExprs(node SelectExprs) bool {
+	if node == nil {
+		return true
+	}
+	for _, el := range node {
+		if !r.walkSelectExpr(el) {
+			return false
+		}
+	}
+	return true
+}
+func (r *typedWalkerColName) walkRefOfSet(node *Set) bool {
+	if node == nil {
+		return true
+	}
+	if !r.walkSetExprs(node.Exprs) {
+		return false
+	}
+	return true
+}
+func (r *typedWalkerColName) walkRefOfSetExpr(node *SetExpr) bool {
+	if node == nil {
+		return true
+	}
+	if !r.walkExpr(node.Expr) {
+		return false
+	}
+	return true
+}
+func (r *typedWalkerColName) walkSetExprs(node SetExprs) bool {
+	if node == nil {
+		return true
+	}
+	for _, el := range node {
+		if !r.walkRefOfSetExpr(el) {
+			return false
+		}
+	}
+	return true
+}
+func (r *typedWalkerColName) walkRefOfSetTransaction(node *SetTransaction) bool {
+	if node == nil {
+		return true
+	}
+	if !r.walkSQLNode(node.SQLNode) {
+		return false
+	}
+	return true
+}
+func (r *typedWalkerColName) walkRefOfShow(node *Show) bool {
+	if node == nil {
+		return true
+	}
+	if !r.walkShowInternal(node.Internal) {
+		return false
+	}
+	return true
+}
+func (r *typedWalkerColName) walkRefOfShowBasic(node *ShowBasic) bool {
+	if node == nil {
+		return true
+	}
+	if !r.walkRefOfShowFilter(node.Filter) {
+		return false
+	}
+	return true
+}
+func (r *typedWalkerColName) walkRefOfShowFilter(node *ShowFilter) bool {
+	if node == nil {
+		return true
+	}
+	if !r.walkExpr(node.Filter) {
+		return false
+	}
+	return true
+}
+func (r *typedWalkerColName) walkRefOfShowLegacy(node *ShowLegacy) bool {
+	if node == nil {
+		return true
+	}
+	if !r.walkExpr(node.ShowCollationFilterOpt) {
+		return false
+	}
+	return true
+}
+func (r *typedWalkerColName) walkRefOfStream(node *Stream) bool {
+	if node == nil {
+		return true
+	}
+	if !r.walkSelectExpr(node.SelectExpr) {
+		return false
+	}
+	return true
+}
+func (r *typedWalkerColName) walkRefOfSubquery(node *Subquery) bool {
+	if node == nil {
+		return true
+	}
+	if !r.walkSelectStatement(node.Select) {
+		return false
+	}
+	return true
+}
+func (r *typedWalkerColName) walkRefOfSubstrExpr(node *SubstrExpr) bool {
+	if node == nil {
+		return true
+	}
+	if !r.walkRefOfColName(node.Name) {
+		return false
+	}
+	if !r.walkExpr(node.From) {
+		return false
+	}
+	if !r.walkExpr(node.To) {
+		return false
+	}
+	return true
+}
+func (r *typedWalkerColName) walkTableExprs(node TableExprs) bool {
+	if node == nil {
+		return true
+	}
+	for _, el := range node {
+		if !r.walkTableExpr(el) {
+			return false
+		}
+	}
+	return true
+}
+func (r *typedWalkerColName) walkRefOfTableSpec(node *TableSpec) bool {
+	if node == nil {
+		return true
+	}
+	for _, el := range node.Constraints {
+		if !r.walkRefOfConstraintDefinition(el) {
+			return false
+		}
+	}
+	return true
+}
+func (r *typedWalkerColName) walkRefOfTimestampFuncExpr(node *TimestampFuncExpr) bool {
+	if node == nil {
+		return true
+	}
+	if !r.walkExpr(node.Expr1) {
+		return false
+	}
+	if !r.walkExpr(node.Expr2) {
+		return false
+	}
+	return true
+}
+func (r *typedWalkerColName) walkRefOfUnaryExpr(node *UnaryExpr) bool {
+	if node == nil {
+		return true
+	}
+	if !r.walkExpr(node.Expr) {
+		return false
+	}
+	return true
+}
+func (r *typedWalkerColName) walkRefOfUnion(node *Union) bool {
+	if node == nil {
+		return true
+	}
+	if !r.walkSelectStatement(node.Left) {
+		return false
+	}
+	if !r.walkSelectStatement(node.Right) {
+		return false
+	}
+	if !r.walkOrderBy(node.OrderBy) {
+		return false
+	}
+	if !r.walkRefOfWith(node.With) {
+		return false
+	}
+	if !r.walkRefOfLimit(node.Limit) {
+		return false
+	}
+	return true
+}
+func (r *typedWalkerColName) walkRefOfUpdate(node *Update) bool {
+	if node == nil {
+		return true
+	}
+	if !r.walkRefOfWith(node.With) {
+		return false
+	}
+	if !r.walkTableExprs(node.TableExprs) {
+		return false
+	}
+	if !r.walkUpdateExprs(node.Exprs) {
+		return false
+	}
+	if !r.walkRefOfWhere(node.Where) {
+		return false
+	}
+	if !r.walkOrderBy(node.OrderBy) {
+		return false
+	}
+	if !r.walkRefOfLimit(node.Limit) {
+		return false
+	}
+	return true
+}
+func (r *typedWalkerColName) walkRefOfUpdateExpr(node *UpdateExpr) bool {
+	if node == nil {
+		return true
+	}
+	if !r.walkRefOfColName(node.Name) {
+		return false
+	}
+	if !r.walkExpr(node.Expr) {
+		return false
+	}
+	return true
+}
+func (r *typedWalkerColName) walkUpdateExprs(node UpdateExprs) bool {
+	if node == nil {
+		return true
+	}
+	for _, el := range node {
+		if !r.walkRefOfUpdateExpr(el) {
+			return false
+		}
+	}
+	return true
+}
+func (r *typedWalkerColName) walkRefOfVStream(node *VStream) bool {
+	if node == nil {
+		return true
+	}
+	if !r.walkSelectExpr(node.SelectExpr) {
+		return false
+	}
+	if !r.walkRefOfWhere(node.Where) {
+		return false
+	}
+	if !r.walkRefOfLimit(node.Limit) {
+		return false
+	}
+	return true
+}
+func (r *typedWalkerColName) walkValTuple(node ValTuple) bool {
+	if node == nil {
+		return true
+	}
+	for _, el := range node {
+		if !r.walkExpr(el) {
+			return false
+		}
+	}
+	return true
+}
+func (r *typedWalkerColName) walkValues(node Values) bool {
+	if node == nil {
+		return true
+	}
+	for _, el := range node {
+		if !r.walkValTuple(el) {
+			return false
+		}
+	}
+	return true
+}
+func (r *typedWalkerColName) walkRefOfValuesFuncExpr(node *ValuesFuncExpr) bool {
+	if node == nil {
+		return true
+	}
+	if !r.walkRefOfColName(node.Name) {
+		return false
+	}
+	return true
+}
+func (r *typedWalkerColName) walkRefOfWhen(node *When) bool {
+	if node == nil {
+		return true
+	}
+	if !r.walkExpr(node.Cond) {
+		return false
+	}
+	if !r.walkExpr(node.Val) {
+		return false
+	}
+	return true
+}
+func (r *typedWalkerColName) walkRefOfWhere(node *Where) bool {
+	if node == nil {
+		return true
+	}
+	if !r.walkExpr(node.Expr) {
+		return false
+	}
+	return true
+}
+func (r *typedWalkerColName) walkRefOfWith(node *With) bool {
+	if node == nil {
+		return true
+	}
+	for _, el := range node.ctes {
+		if !r.walkRefOfCommonTableExpr(el) {
+			return false
+		}
+	}
+	return true
+}
+func (r *typedWalkerColName) walkRefOfXorExpr(node *XorExpr) bool {
+	if node == nil {
+		return true
+	}
+	if !r.walkExpr(node.Left) {
+		return false
+	}
+	if !r.walkExpr(node.Right) {
+		return false
+	}
+	return true
+}
+func (r *typedWalkerColName) walkAlterOption(node AlterOption) bool {
+	switch node := node.(type) {
+	case *AddColumns:
+		return r.walkRefOfAddColumns(node)
+	case *AddConstraintDefinition:
+		return r.walkRefOfAddConstraintDefinition(node)
+	case *AlterColumn:
+		return r.walkRefOfAlterColumn(node)
+	case *ChangeColumn:
+		return r.walkRefOfChangeColumn(node)
+	case *DropColumn:
+		return r.walkRefOfDropColumn(node)
+	case *ModifyColumn:
+		return r.walkRefOfModifyColumn(node)
+	}
+	return true
+}
+func (r *typedWalkerColName) walkColTuple(node ColTuple) bool {
+	switch node := node.(type) {
+	case *Subquery:
+		return r.walkRefOfSubquery(node)
+	case ValTuple:
+		return r.walkValTuple(node)
+	}
+	return true
+}
+func (r *typedWalkerColName) walkConstraintInfo(node ConstraintInfo) bool {
+	switch node := node.(type) {
+	case *CheckConstraintDefinition:
+		return r.walkRefOfCheckConstraintDefinition(node)
+	}
+	return true
+}
+func (r *typedWalkerColName) walkDDLStatement(node DDLStatement) bool {
+	switch node := node.(type) {
+	case *AlterTable:
+		return r.walkRefOfAlterTable(node)
+	case *AlterView:
+		return r.walkRefOfAlterView(node)
+	case *CreateTable:
+		return r.walkRefOfCreateTable(node)
+	case *CreateView:
+		return r.walkRefOfCreateView(node)
+	}
+	return true
+}
+func (r *typedWalkerColName) walkExplain(node Explain) bool {
+	switch node := node.(type) {
+	case *ExplainStmt:
+		return r.walkRefOfExplainStmt(node)
+	}
+	return true
+}
+func (r *typedWalkerColName) walkExpr(node Expr) bool {
+	switch node := node.(type) {
+	case *AndExpr:
+		return r.walkRefOfAndExpr(node)
+	case *BinaryExpr:
+		return r.walkRefOfBinaryExpr(node)
+	case *CaseExpr:
+		return r.walkRefOfCaseExpr(node)
+	case *ColName:
+		return r.walkRefOfColName(node)
+	case *CollateExpr:
+		return r.walkRefOfCollateExpr(node)
+	case *ComparisonExpr:
+		return r.walkRefOfComparisonExpr(node)
+	case *ConvertExpr:
+		return r.walkRefOfConvertExpr(node)
+	case *ConvertUsingExpr:
+		return r.walkRefOfConvertUsingExpr(node)
+	case *CurTimeFuncExpr:
+		return r.walkRefOfCurTimeFuncExpr(node)
+	case *ExistsExpr:
+		return r.walkRefOfExistsExpr(node)
+	case *ExtractFuncExpr:
+		return r.walkRefOfExtractFuncExpr(node)
+	case *ExtractedSubquery:
+		return r.walkRefOfExtractedSubquery(node)
+	case *FuncExpr:
+		return r.walkRefOfFuncExpr(node)
+	case *GroupConcatExpr:
+		return r.walkRefOfGroupConcatExpr(node)
+	case *IntervalExpr:
+		return r.walkRefOfIntervalExpr(node)
+	case *IsExpr:
+		return r.walkRefOfIsExpr(node)
+	case *MatchExpr:
+		return r.walkRefOfMatchExpr(node)
+	case *NotExpr:
+		return r.walkRefOfNotExpr(node)
+	case *OrExpr:
+		return r.walkRefOfOrExpr(node)
+	case *RangeCond:
+		return r.walkRefOfRangeCond(node)
+	case *Subquery:
+		return r.walkRefOfSubquery(node)
+	case *SubstrExpr:
+		return r.walkRefOfSubstrExpr(node)
+	case *TimestampFuncExpr:
+		return r.walkRefOfTimestampFuncExpr(node)
+	case *UnaryExpr:
+		return r.walkRefOfUnaryExpr(node)
+	case ValTuple:
+		return r.walkValTuple(node)
+	case *ValuesFuncExpr:
+		return r.walkRefOfValuesFuncExpr(node)
+	case *XorExpr:
+		return r.walkRefOfXorExpr(node)
+	}
+	return true
+}
+func (r *typedWalkerColName) walkInsertRows(node InsertRows) bool {
+	switch node := node.(type) {
+	case *Select:
+		return r.walkRefOfSelect(node)
+	case *Union:
+		return r.walkRefOfUnion(node)
+	case Values:
+		return r.walkValues(node)
+	}
+	return true
+}
+func (r *typedWalkerColName) walkSelectExpr(node SelectExpr) bool {
+	switch node := node.(type) {
+	case *AliasedExpr:
+		return r.walkRefOfAliasedExpr(node)
+	case *Nextval:
+		return r.walkRefOfNextval(node)
+	}
+	return true
+}
+func (r *typedWalkerColName) walkSelectStatement(node SelectStatement) bool {
+	switch node := node.(type) {
+	case *Select:
+		return r.walkRefOfSelect(node)
+	case *Union:
+		return r.walkRefOfUnion(node)
+	}
+	return true
+}
+func (r *typedWalkerColName) walkShowInternal(node ShowInternal) bool {
+	switch node := node.(type) {
+	case *ShowBasic:
+		return r.walkRefOfShowBasic(node)
+	case *ShowLegacy:
+		return r.walkRefOfShowLegacy(node)
+	}
+	return true
+}
+func (r *typedWalkerColName) walkSimpleTableExpr(node SimpleTableExpr) bool {
+	switch node := node.(type) {
+	case *DerivedTable:
+		return r.walkRefOfDerivedTable(node)
+	}
+	return true
+}
+func (r *typedWalkerColName) walkStatement(node Statement) bool {
+	switch node := node.(type) {
+	case *AlterTable:
+		return r.walkRefOfAlterTable(node)
+	case *AlterView:
+		return r.walkRefOfAlterView(node)
+	case *CallProc:
+		return r.walkRefOfCallProc(node)
+	case *CreateTable:
+		return r.walkRefOfCreateTable(node)
+	case *CreateView:
+		return r.walkRefOfCreateView(node)
+	case *Delete:
+		return r.walkRefOfDelete(node)
+	case *ExplainStmt:
+		return r.walkRefOfExplainStmt(node)
+	case *Insert:
+		return r.walkRefOfInsert(node)
+	case *Select:
+		return r.walkRefOfSelect(node)
+	case *Set:
+		return r.walkRefOfSet(node)
+	case *SetTransaction:
+		return r.walkRefOfSetTransaction(node)
+	case *Show:
+		return r.walkRefOfShow(node)
+	case *Stream:
+		return r.walkRefOfStream(node)
+	case *Union:
+		return r.walkRefOfUnion(node)
+	case *Update:
+		return r.walkRefOfUpdate(node)
+	case *VStream:
+		return r.walkRefOfVStream(node)
+	}
+	return true
+}
+func (r *typedWalkerColName) walkTableExpr(node TableExpr) bool {
+	switch node := node.(type) {
+	case *AliasedTableExpr:
+		return r.walkRefOfAliasedTableExpr(node)
+	case *JoinTableExpr:
+		return r.walkRefOfJoinTableExpr(node)
+	case *ParenTableExpr:
+		return r.walkRefOfParenTableExpr(node)
+	}
+	return true
+}
+func (r *typedWalkerColName) walkRefOfRootNode(node *RootNode) bool {
+	if node == nil {
+		return true
+	}
+	if !r.walkSQLNode(node.SQLNode) {
+		return false
+	}
+	return true
+}
@@ -16,6 +16,16 @@ limitations under the License.
 
 package sqlparser
 
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	vtrpcpb "vitess.io/vitess/go/vt/proto/vtrpc"
+	"vitess.io/vitess/go/vt/vterrors"
+)
+
 // The rewriter was heavily inspired by https://github.com/golang/tools/blob/master/go/ast/astutil/rewrite.go
 
 // Rewrite traverses a syntax tree recursively, starting with root,
@@ -29,11 +39,11 @@ package sqlparser
 // If post is not nil, and a prior call of pre didn't return false,
 // post is called for each node after its children are traversed
 // (post-order). If post returns false, traversal is terminated and
-// Apply returns immediately.
+// Rewrite returns immediately. The generated code propagates the abort
+// by returning false up the call chain, so no panics are involved.
 //
 // Only fields that refer to AST nodes are considered children;
 // i.e., fields of basic types (strings, []byte, etc.) are ignored.
-//
 func Rewrite(node SQLNode, pre, post ApplyFunc) (result SQLNode) {
 	parent := &RootNode{node}
 
@@ -42,16 +52,248 @@ func Rewrite(node SQLNode, pre, post ApplyFunc) (result SQLNode) {
 		parent.SQLNode = newNode
 	}
 
-	a := &application{
-		pre:  pre,
-		post: post,
+	a := newApplication(pre, post)
+	defer a.release()
+	a.cur.root = node
+
+	a.rewriteSQLNode(parent, node, replacer)
+
+	return parent.SQLNode
+}
+
+// Rewrite traverses the AST rooted at node like the Rewrite function, calling the handlers
+// registered for the type of every node, and returns the AST, possibly modified.
+//
+//	NewTypedRewriter().
+//		Pre((*ColName)(nil), func(cursor *Cursor) bool { ... }).
+//		Post((*Select)(nil), func(cursor *Cursor) bool { ... }).
+//		Rewrite(stmt)
+func (r *TypedRewriter) Rewrite(node SQLNode) SQLNode {
+	parent := &RootNode{node}
+
+	replacer := func(newNode SQLNode, _ SQLNode) {
+		parent.SQLNode = newNode
 	}
 
+	a := newApplication(nil, nil)
+	defer a.release()
+	a.typed = r
+	a.cur.root = node
+
 	a.rewriteSQLNode(parent, node, replacer)
 
 	return parent.SQLNode
 }
 
+// RewriteIterative works like Rewrite, but it walks the AST with an explicit stack instead
+// of recursing once for every level of the AST, so it can rewrite ASTs of any depth without
+// risking a stack overflow. pre and post are called in the same order as in Rewrite, and
+// Replace and ReplaceAndRevisit work the same way. InsertBefore, InsertAfter and Remove
+// are not supported, and panic when the rewrite continues after them.
+// Since the children of a node are collected right after pre is called for it, changes
+// to the fields of a node made while visiting its children are not seen by the walk.
+func RewriteIterative(node SQLNode, pre, post ApplyFunc) SQLNode {
+	parent := &RootNode{node}
+
+	replacer := func(newNode SQLNode, _ SQLNode) {
+		parent.SQLNode = newNode
+	}
+
+	a := newApplication(pre, post)
+	defer a.release()
+	a.cur.root = node
+
+	a.iterate(parent, node, replacer)
+
+	return parent.SQLNode
+}
+
+// iterFrame is an entry in the stack of RewriteIterative: a node that has to be visited,
+// or one whose children have been visited and that is waiting for post to be called
+type iterFrame struct {
+	parent   SQLNode
+	node     SQLNode
+	replacer replacerFunc
+	field    cursorField
+	// depth is the number of fields that lead from the root to the node
+	depth int
+	post  bool
+	// enclosing is the enclosing node of the node, as returned by the Cursor
+	enclosing SQLNode
+}
+
+func (a *application) iterate(parent, node SQLNode, replacer replacerFunc) {
+	stack := []iterFrame{{parent: parent, node: node, replacer: replacer}}
+	var children []iterFrame
+
+	for len(stack) > 0 {
+		f := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		// the frames are visited depth-first, so the fields of the ancestors of the node
+		// are already in the Cursor, and only the innermost one needs to be set
+		if f.depth == 0 {
+			a.cur.fields = a.cur.fields[:0]
+		} else {
+			a.cur.fields = append(a.cur.fields[:f.depth-1], f.field)
+		}
+		a.cur.parent = f.parent
+		a.cur.node = f.node
+		a.cur.replacer = f.replacer
+		a.cur.enclosing = f.enclosing
+
+		if f.post {
+			if !a.post(&a.cur) {
+				return
+			}
+			a.checkNoEdits()
+			continue
+		}
+
+		if a.skipNode(f.node) {
+			continue
+		}
+		if a.pre != nil {
+			kontinue := !a.pre(&a.cur)
+			a.checkNoEdits()
+			if a.cur.revisit {
+				a.cur.revisit = false
+				f.node = a.cur.node
+				stack = append(stack, f)
+				continue
+			}
+			if kontinue {
+				continue
+			}
+		}
+
+		if a.post != nil {
+			f.post = true
+			stack = append(stack, f)
+		}
+
+		enclosing := f.enclosing
+		if isEnclosing(f.node) {
+			enclosing = f.node
+		}
+
+		// the children are pushed in reverse, so they're popped in order
+		children = children[:0]
+		a.iterChildren(f.node, func(child SQLNode, name string, index int, replacer replacerFunc) {
+			children = append(children, iterFrame{
+				parent:    f.node,
+				node:      child,
+				replacer:  replacer,
+				field:     cursorField{name, index},
+				depth:     f.depth + 1,
+				enclosing: enclosing,
+			})
+		})
+		for i := len(children) - 1; i >= 0; i-- {
+			stack = append(stack, children[i])
+		}
+	}
+}
+
+func (a *application) checkNoEdits() {
+	if len(a.cur.edits) > 0 {
+		panic("InsertBefore, InsertAfter and Remove are not supported by RewriteIterative")
+	}
+}
+
+// RewriteOptions are the optional settings of RewriteWithOptions
+type RewriteOptions struct {
+	// MaxDepth is the maximum depth of the AST that the rewrite will descend into, where
+	// the root node has a depth of 0. Rewrite recurses once for every level of the AST,
+	// so setting a limit protects against exhausting the stack on malicious input.
+	// The default of 0 means no limit.
+	MaxDepth int
+
+	// OnUnknownNode, if set, is called for every node whose type is not known to the
+	// generated rewriter (see IsKnownType), and the node and its children are skipped.
+	// By default, the rewrite panics when it finds such a node, unless SkipUnknownNodes
+	// has been called.
+	OnUnknownNode func(node SQLNode)
+}
+
+// defaultOnUnknownNode is used by the rewrites that don't set RewriteOptions.OnUnknownNode
+var defaultOnUnknownNode func(node SQLNode)
+
+// SkipUnknownNodes makes all the rewrites that don't set RewriteOptions.OnUnknownNode skip
+// the nodes of unknown types, calling onUnknown for each of them, instead of panicking.
+// It is meant to be used by servers as a safety valve during rolling upgrades, and must be
+// called during startup, before any query is rewritten. A nil onUnknown restores the panics.
+func SkipUnknownNodes(onUnknown func(node SQLNode)) {
+	defaultOnUnknownNode = onUnknown
+}
+
+// RewriteWithOptions works like Rewrite, but with the given options. If the AST is deeper
+// than opts.MaxDepth, the rewrite is aborted as if post had returned false, and an error
+// is returned together with the partially rewritten AST.
+func RewriteWithOptions(node SQLNode, pre, post ApplyFunc, opts RewriteOptions) (SQLNode, error) {
+	parent := &RootNode{node}
+
+	replacer := func(newNode SQLNode, _ SQLNode) {
+		parent.SQLNode = newNode
+	}
+
+	a := newApplication(pre, post)
+	defer a.release()
+	a.maxDepth = opts.MaxDepth
+	a.onUnknown = opts.OnUnknownNode
+	a.cur.root = node
+
+	if !a.rewriteSQLNode(parent, node, replacer) && a.tooDeep() {
+		return parent.SQLNode, vterrors.Errorf(vtrpcpb.Code_INVALID_ARGUMENT, "the AST is deeper than the maximum of %d levels", opts.MaxDepth)
+	}
+	return parent.SQLNode, nil
+}
+
+// RewriteWithFilter works like Rewrite, but it only descends into the subtrees whose
+// root passes the filter: when filter returns false for a node, neither pre nor post
+// are called for that node or for any of its children. This lets analyzers that only
+// care about some kinds of nodes avoid walking large subtrees such as literal lists.
+func RewriteWithFilter(node SQLNode, pre, post ApplyFunc, filter func(SQLNode) bool) (result SQLNode) {
+	filtered := func(cursor *Cursor) bool {
+		if !filter(cursor.Node()) {
+			return false
+		}
+		return pre == nil || pre(cursor)
+	}
+	return Rewrite(node, filtered, post)
+}
+
+// RewriteChange describes a replacement that was requested during RewriteDryRun
+type RewriteChange struct {
+	// Path is the path from the root of the AST to the replaced node, as returned
+	// by Cursor.Path and joined with dots, e.g. "Select.SelectExprs[1].Expr"
+	Path    string
+	OldNode SQLNode
+	NewNode SQLNode
+}
+
+// RewriteDryRun works like Rewrite, but it leaves the AST untouched: the replacements
+// requested by pre and post through Replace and ReplaceAndRevisit are not applied, but
+// returned in the order in which they were made. Since the new nodes are not part of
+// the AST, ReplaceAndRevisit does not visit them, and works just like Replace.
+// InsertBefore, InsertAfter and Remove are not supported, and panic.
+func RewriteDryRun(node SQLNode, pre, post ApplyFunc) []RewriteChange {
+	parent := &RootNode{node}
+
+	replacer := func(newNode SQLNode, _ SQLNode) {
+		panic("[BUG] the AST was modified during a dry run")
+	}
+
+	a := newApplication(pre, post)
+	defer a.release()
+	a.cur.root = node
+	a.cur.dryRun = true
+
+	a.rewriteSQLNode(parent, node, replacer)
+
+	return a.cur.changes
+}
+
 // RootNode is the root node of the AST when rewriting. It is the first element of the tree.
 type RootNode struct {
 	SQLNode
@@ -75,6 +317,27 @@ type Cursor struct {
 
 	// marks that the node has been replaced, and the new node should be visited
 	revisit bool
+
+	// root is the node the rewrite started from, and fields is the stack of
+	// the fields that lead from it to the current node
+	root   SQLNode
+	fields []cursorField
+
+	// edits are the insertions and removals in slices that haven't been applied yet
+	edits []sliceEdit
+
+	// enclosing is the nearest ancestor of the current node that implements the
+	// interface tracked by the generated rewriter
+	enclosing SQLNode
+
+	// dryRun is set by RewriteDryRun: the replacements are recorded in changes instead of being applied
+	dryRun  bool
+	changes []RewriteChange
+}
+
+type cursorField struct {
+	name  string
+	index int
 }
 
 // Node returns the current Node.
@@ -83,25 +346,182 @@ func (c *Cursor) Node() SQLNode { return c.node }
 // Parent returns the parent of the current Node.
 func (c *Cursor) Parent() SQLNode { return c.parent }
 
+// FieldName returns the name of the field of the parent that contains the current Node,
+// e.g. "Where" or "Having" when the current Node is the *Where of a *Select.
+// It returns an empty string for the root of the AST, and for the elements of
+// slice types such as SelectExprs, which are not stored in a named field.
+func (c *Cursor) FieldName() string {
+	if len(c.fields) == 0 {
+		return ""
+	}
+	return c.fields[len(c.fields)-1].name
+}
+
+// FieldIndex returns the index of the current Node in its parent's slice, or -1 if
+// the current Node is not an element of a slice.
+func (c *Cursor) FieldIndex() int {
+	if len(c.fields) == 0 {
+		return -1
+	}
+	return c.fields[len(c.fields)-1].index
+}
+
+// Path returns the path from the root of the AST to the current Node: the name of the type
+// of the root, followed by the names of the fields that lead to the Node. The elements of
+// slices have their index appended, e.g. the path to the second column of a *Select is
+// [Select SelectExprs[1]], and the path to the left side of its WHERE condition is
+// [Select Where Expr Left]. The path of a node doesn't change when other nodes are replaced.
+func (c *Cursor) Path() []string {
+	path := make([]string, 0, len(c.fields)+1)
+	if t := reflect.TypeOf(c.root); t != nil {
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		path = append(path, t.Name())
+	}
+	for _, field := range c.fields {
+		switch {
+		case field.name != "":
+			path = append(path, field.name)
+		case len(path) == 0:
+			path = append(path, "")
+		}
+		if field.index >= 0 {
+			path[len(path)-1] += fmt.Sprintf("[%d]", field.index)
+		}
+	}
+	return path
+}
+
+// InsertBefore inserts newNode before the current Node in the slice that contains it.
+// If the current Node is not an element of a slice, InsertBefore panics. The new node
+// is not visited by Rewrite, and the slice is only modified after all its elements
+// have been visited, so FieldIndex keeps returning the original indexes.
+func (c *Cursor) InsertBefore(newNode SQLNode) {
+	c.edit(editInsertBefore, newNode)
+}
+
+// InsertAfter inserts newNode after the current Node in the slice that contains it.
+// The same rules as for InsertBefore apply.
+func (c *Cursor) InsertAfter(newNode SQLNode) {
+	c.edit(editInsertAfter, newNode)
+}
+
+// Remove removes the current Node from the slice that contains it. The children of the
+// Node are still visited unless pre returns false. The same rules as for InsertBefore apply.
+func (c *Cursor) Remove() {
+	c.edit(editRemove, nil)
+}
+
+type editOp int
+
+const (
+	editInsertBefore editOp = iota
+	editInsertAfter
+	editRemove
+)
+
+type sliceEdit struct {
+	depth int
+	index int
+	op    editOp
+	node  SQLNode
+}
+
+func (c *Cursor) edit(op editOp, node SQLNode) {
+	if c.dryRun {
+		panic("InsertBefore, InsertAfter and Remove are not supported by RewriteDryRun")
+	}
+	index := c.FieldIndex()
+	if index < 0 {
+		panic("the current node is not an element of a slice")
+	}
+	c.edits = append(c.edits, sliceEdit{depth: len(c.fields), index: index, op: op, node: node})
+}
+
+// takeEdits is called by the generated code after visiting all the elements of a slice,
+// and returns the edits that must be applied to that slice. The edits of any slices
+// deeper in the AST have already been taken, so they're always at the end of the list.
+// The returned slice is only valid until the next edit.
+func (c *Cursor) takeEdits() []sliceEdit {
+	if len(c.edits) == 0 {
+		return nil
+	}
+	i := len(c.edits)
+	for i > 0 && c.edits[i-1].depth > len(c.fields) {
+		i--
+	}
+	if i == len(c.edits) {
+		return nil
+	}
+	edits := c.edits[i:]
+	c.edits = c.edits[:i]
+	return edits
+}
+
+// rebuildSlice is called by the generated code to apply edits to a slice of length n.
+// It calls emit for every element of the resulting slice, in order: with the index of
+// the element in the original slice, or with -1 and the node that was inserted.
+func rebuildSlice(edits []sliceEdit, n int, emit func(idx int, inserted SQLNode)) {
+	for idx := 0; idx < n; idx++ {
+		removed := false
+		for _, e := range edits {
+			if e.index == idx && e.op == editInsertBefore {
+				emit(-1, e.node)
+			}
+			if e.index == idx && e.op == editRemove {
+				removed = true
+			}
+		}
+		if !removed {
+			emit(idx, nil)
+		}
+		for _, e := range edits {
+			if e.index == idx && e.op == editInsertAfter {
+				emit(-1, e.node)
+			}
+		}
+	}
+}
+
+// enterField is called by the generated code before visiting a child of the current node
+func (c *Cursor) enterField(name string, index int) {
+	c.fields = append(c.fields, cursorField{name, index})
+}
+
+// leaveField is called by the generated code after visiting a child of the current node
+func (c *Cursor) leaveField() {
+	c.fields = c.fields[:len(c.fields)-1]
+}
+
 // Replace replaces the current node in the parent field with this new object. The use needs to make sure to not
 // replace the object with something of the wrong type, or the visitor will panic.
+// If both the current node and the new one can carry comments (e.g. a *Select replaced
+// with a *Union), and the new node has no comments of its own, the comments of the
+// current node are moved to the new node.
 func (c *Cursor) Replace(newNode SQLNode) {
-	c.replacer(newNode, c.parent)
+	newNode = c.carryComments(newNode)
+	if c.dryRun {
+		c.recordChange(newNode)
+	} else {
+		c.replacer(newNode, c.parent)
+	}
 	c.node = newNode
 }
 
 // ReplaceAndRevisit replaces the current node in the parent field with this new object.
 // When used, this will abort the visitation of the current node - no post or children visited,
-// and the new node visited.
+// and the new node visited, starting with a new call to pre.
+// It can only be called from pre. The new node is visited as if it had been there all along, so
+// if pre keeps replacing the nodes it returns with new ones, the rewrite never terminates:
+// pre must eventually stop producing nodes that it wants to replace.
 func (c *Cursor) ReplaceAndRevisit(newNode SQLNode) {
-	switch newNode.(type) {
-	case SelectExprs:
-	default:
-		// We need to add support to the generated code for when to look at the revisit flag. At the moment it is only
-		// there for slices of SQLNode implementations
-		panic("no support added for this type yet")
+	newNode = c.carryComments(newNode)
+	if c.dryRun {
+		c.recordChange(newNode)
+		c.node = newNode
+		return
 	}
-
 	c.replacer(newNode, c.parent)
 	c.node = newNode
 	c.revisit = true
@@ -109,8 +529,97 @@ func (c *Cursor) ReplaceAndRevisit(newNode SQLNode) {
 
 type replacerFunc func(newNode, parent SQLNode)
 
+// recordChange records the replacement of the current node during a dry run
+func (c *Cursor) recordChange(newNode SQLNode) {
+	c.changes = append(c.changes, RewriteChange{Path: strings.Join(c.Path(), "."), OldNode: c.node, NewNode: newNode})
+}
+
+// commented is implemented by the statements that carry their own comments
+type commented interface {
+	SetComments(comments Comments)
+	GetComments() Comments
+}
+
+// carryComments copies the comments of the current node to newNode, which is replacing it,
+// unless newNode already has comments of its own, and returns the node that must be used as
+// the replacement. The comments are copied, so the old node, which may still be used by the
+// caller, doesn't share them with the new one. During a dry run, newNode may still be part
+// of the AST, which must be left untouched, so the comments are set on a clone of it instead.
+func (c *Cursor) carryComments(newNode SQLNode) SQLNode {
+	from, ok := c.node.(commented)
+	if !ok {
+		return newNode
+	}
+	to, ok := newNode.(commented)
+	if !ok || len(to.GetComments()) > 0 {
+		return newNode
+	}
+	comments := from.GetComments()
+	if len(comments) == 0 {
+		return newNode
+	}
+	if c.dryRun {
+		newNode = CloneSQLNode(newNode)
+		to = newNode.(commented)
+	}
+	to.SetComments(CloneComments(comments))
+	return newNode
+}
+
 // application carries all the shared data so we can pass it around cheaply.
 type application struct {
 	pre, post ApplyFunc
 	cur       Cursor
+
+	// maxDepth is the maximum depth of the nodes whose children can be visited; 0 means no limit
+	maxDepth int
+
+	// onUnknown is called for the nodes of unknown types instead of panicking, if set
+	onUnknown func(SQLNode)
+
+	// typed has the handlers to call instead of pre and post, if the rewrite is done by a TypedRewriter
+	typed *TypedRewriter
+}
+
+// applicationPool holds the state of finished rewrites, so that every rewrite doesn't have to
+// allocate it again and grow the stack of fields of its Cursor from scratch
+var applicationPool = sync.Pool{
+	New: func() interface{} { return &application{} },
+}
+
+func newApplication(pre, post ApplyFunc) *application {
+	a := applicationPool.Get().(*application)
+	a.pre, a.post = pre, post
+	return a
+}
+
+// release clears the application and puts it back into the pool, keeping only the capacity
+// of the stack of fields; the Cursor must not be used once the rewrite has returned
+func (a *application) release() {
+	fields := a.cur.fields[:0]
+	*a = application{}
+	a.cur.fields = fields
+	applicationPool.Put(a)
+}
+
+// tooDeep is called by the generated code before visiting the children of a node, and
+// returns whether the rewrite must be aborted because the node is deeper than maxDepth
+func (a *application) tooDeep() bool {
+	return a.maxDepth > 0 && len(a.cur.fields) > a.maxDepth
+}
+
+// unknownNode is called by the generated code when it finds a node whose type it doesn't know,
+// which means that the AST helpers have not been regenerated after adding a new type of node
+// (see IsKnownType). This is a bug, so it panics, unless the caller asked for such nodes to be
+// reported with RewriteOptions.OnUnknownNode or SkipUnknownNodes, in which case the node and
+// its children are skipped.
+func (a *application) unknownNode(node SQLNode) {
+	onUnknown := a.onUnknown
+	if onUnknown == nil {
+		onUnknown = defaultOnUnknownNode
+	}
+	if onUnknown == nil {
+		panic(fmt.Sprintf("unknown AST node type %T", node))
+	}
+	onUnknown(node)
 }
@@ -17,6 +17,8 @@ limitations under the License.
 package sqlparser
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -92,6 +94,110 @@ func TestReplaceAndRevisitWorksInLaterCalls(t *testing.T) {
 	assert.Equal(t, 2, count)
 }
 
+func TestReplaceAndRevisitExpression(t *testing.T) {
+	stmt, err := Parse("select a from t where b = 1")
+	require.NoError(t, err)
+
+	// every column is replaced by `col + 1`, which is visited again; the columns
+	// inside the new expressions must not be replaced a second time
+	var seen []string
+	result := Rewrite(stmt, func(cursor *Cursor) bool {
+		switch node := cursor.Node().(type) {
+		case *ColName:
+			seen = append(seen, String(node))
+			if _, ok := cursor.Parent().(*BinaryExpr); !ok {
+				cursor.ReplaceAndRevisit(&BinaryExpr{Operator: PlusOp, Left: node, Right: NewIntLiteral("1")})
+			}
+		case *BinaryExpr:
+			seen = append(seen, String(node))
+		}
+		return true
+	}, nil)
+
+	assert.Equal(t, "select a + 1 from t where b + 1 = 1", String(result))
+	assert.Equal(t, []string{"a", "a + 1", "a", "b", "b + 1", "b"}, seen)
+}
+
+func TestRemoveFromSlice(t *testing.T) {
+	stmt, err := Parse("select a, b as c, *, t.*, d + 1 from t")
+	require.NoError(t, err)
+
+	result := Rewrite(stmt, func(cursor *Cursor) bool {
+		if _, ok := cursor.Node().(*AliasedExpr); ok {
+			cursor.Remove()
+			return false
+		}
+		return true
+	}, nil)
+
+	formatted := String(result)
+	assert.Equal(t, "select *, t.* from t", formatted)
+
+	reparsed, err := Parse(formatted)
+	require.NoError(t, err)
+	assert.Equal(t, formatted, String(reparsed))
+}
+
+func TestInsertIntoSlice(t *testing.T) {
+	stmt, err := Parse("select a, b from t join u on t.id = u.id, v")
+	require.NoError(t, err)
+
+	var indexes []int
+	result := Rewrite(stmt, func(cursor *Cursor) bool {
+		switch node := cursor.Node().(type) {
+		case *AliasedExpr:
+			indexes = append(indexes, cursor.FieldIndex())
+			col := node.Expr.(*ColName)
+			cursor.InsertBefore(&AliasedExpr{Expr: NewStrLiteral("before " + col.Name.String())})
+			cursor.InsertAfter(&AliasedExpr{Expr: NewStrLiteral("after " + col.Name.String())})
+		case *AliasedTableExpr:
+			if cursor.FieldName() == "From" {
+				cursor.InsertAfter(&AliasedTableExpr{Expr: TableName{Name: NewTableIdent("w")}})
+			}
+		}
+		return true
+	}, nil)
+
+	assert.Equal(t, []int{0, 1}, indexes)
+	assert.Equal(t, "select 'before a', a, 'after a', 'before b', b, 'after b' from t join u on t.id = u.id, v, w", String(result))
+}
+
+func TestInsertOutsideOfSlicePanics(t *testing.T) {
+	stmt, err := Parse("select a from t where b = 1")
+	require.NoError(t, err)
+
+	assert.Panics(t, func() {
+		Rewrite(stmt, func(cursor *Cursor) bool {
+			if _, ok := cursor.Node().(*Where); ok {
+				cursor.Remove()
+			}
+			return true
+		}, nil)
+	})
+}
+
+func TestRewriteAbort(t *testing.T) {
+	stmt, err := Parse("select a, b from t where c = 1")
+	require.NoError(t, err)
+
+	var seen []string
+	var result SQLNode
+	assert.NotPanics(t, func() {
+		result = Rewrite(stmt, func(cursor *Cursor) bool {
+			if col, ok := cursor.Node().(*ColName); ok {
+				seen = append(seen, col.Name.String())
+			}
+			return true
+		}, func(cursor *Cursor) bool {
+			col, ok := cursor.Node().(*ColName)
+			return !ok || !col.Name.EqualString("b")
+		})
+	})
+
+	assert.Equal(t, []string{"a", "b"}, seen)
+	assert.Equal(t, stmt, result)
+}
+
 func TestChangeValueTypeGivesError(t *testing.T) {
 	parse, err := Parse("select * from a join b on a.id = b.id")
 	require.NoError(t, err)
@@ -110,3 +216,570 @@ func TestChangeValueTypeGivesError(t *testing.T) {
 	}, nil)
 
 }
+
+func TestWalkColName(t *testing.T) {
+	queries := []string{
+		"select a, b.c, d + e as f from t where g = 1 and h in (select i from u where j = t.k) order by l",
+		"insert into t(a, b) values (1, c) on duplicate key update d = e",
+		"update t set a = b where c > (select max(d) from u) limit 1",
+		"select 1 from dual",
+	}
+	for _, q := range queries {
+		stmt, err := Parse(q)
+		require.NoError(t, err)
+
+		var expected, got []*ColName
+		Rewrite(stmt, func(cursor *Cursor) bool {
+			if col, ok := cursor.Node().(*ColName); ok && col != nil {
+				expected = append(expected, col)
+			}
+			return true
+		}, nil)
+		WalkColName(stmt, func(col *ColName) bool {
+			got = append(got, col)
+			return true
+		}, nil)
+		assert.Equal(t, expected, got, q)
+	}
+}
+
+func BenchmarkWalkColName(b *testing.B) {
+	gen := newGenerator(1, 5)
+	exp := gen.expression()
+
+	b.Run("Rewrite", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_ = Rewrite(exp, func(cursor *Cursor) bool {
+				_, _ = cursor.Node().(*ColName)
+				return true
+			}, nil)
+		}
+	})
+	b.Run("WalkColName", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			WalkColName(exp, func(col *ColName) bool {
+				return true
+			}, nil)
+		}
+	})
+}
+
+func TestCursorFieldName(t *testing.T) {
+	stmt, err := Parse("select a, b from t where c = 1 group by d having e > 2")
+	require.NoError(t, err)
+
+	var wheres []string
+	var cols []string
+	Rewrite(stmt, func(cursor *Cursor) bool {
+		switch node := cursor.Node().(type) {
+		case *Where:
+			wheres = append(wheres, cursor.FieldName())
+			assert.Equal(t, -1, cursor.FieldIndex())
+		case *ColName:
+			cols = append(cols, fmt.Sprintf("%s:%s[%d]", String(node), cursor.FieldName(), cursor.FieldIndex()))
+		}
+		return true
+	}, func(cursor *Cursor) bool {
+		if _, ok := cursor.Node().(*Where); ok {
+			// the fields of the children must have been popped by now
+			assert.Contains(t, []string{"Where", "Having"}, cursor.FieldName())
+		}
+		return true
+	})
+
+	assert.Equal(t, []string{"Where", "Having"}, wheres)
+	assert.Equal(t, []string{"a:Expr[-1]", "b:Expr[-1]", "c:Left[-1]", "d:[0]", "e:Left[-1]"}, cols)
+}
+
+func TestCursorPath(t *testing.T) {
+	stmt, err := Parse("select a, b from t where c = 1 and d in (e, f)")
+	require.NoError(t, err)
+
+	rewrites := map[string]func(SQLNode, ApplyFunc, ApplyFunc) SQLNode{
+		"Rewrite":          Rewrite,
+		"RewriteIterative": RewriteIterative,
+	}
+	for name, rewrite := range rewrites {
+		var pre, post []string
+		rewrite(stmt, func(cursor *Cursor) bool {
+			switch cursor.Node().(type) {
+			case *Select, *ColName:
+				pre = append(pre, strings.Join(cursor.Path(), "."))
+			}
+			return true
+		}, func(cursor *Cursor) bool {
+			switch cursor.Node().(type) {
+			case *Select, *ColName:
+				post = append(post, strings.Join(cursor.Path(), "."))
+			}
+			return true
+		})
+
+		expected := []string{
+			"Select",
+			"Select.SelectExprs[0].Expr",
+			"Select.SelectExprs[1].Expr",
+			"Select.Where.Expr.Left.Left",
+			"Select.Where.Expr.Right.Left",
+			"Select.Where.Expr.Right.Right[0]",
+			"Select.Where.Expr.Right.Right[1]",
+		}
+		assert.Equal(t, expected, pre, name)
+		assert.ElementsMatch(t, expected, post, name)
+	}
+
+	// the path of a subtree starts at its own root
+	exprs := stmt.(*Select).SelectExprs
+	var paths [][]string
+	Rewrite(exprs, func(cursor *Cursor) bool {
+		if _, ok := cursor.Node().(*ColName); ok {
+			paths = append(paths, cursor.Path())
+		}
+		return true
+	}, nil)
+	assert.Equal(t, [][]string{{"SelectExprs[0]", "Expr"}, {"SelectExprs[1]", "Expr"}}, paths)
+}
+
+func TestCursorPathAfterAbort(t *testing.T) {
+	stmt, err := Parse("select a from t where b = 1")
+	require.NoError(t, err)
+
+	// the state of the rewrites is reused, so aborting deep in the AST or panicking
+	// must not leave any fields behind for the next rewrite
+	Rewrite(stmt, nil, func(cursor *Cursor) bool {
+		_, ok := cursor.Node().(*ColName)
+		return !ok
+	})
+	assert.Panics(t, func() {
+		Rewrite(stmt, func(cursor *Cursor) bool {
+			if _, ok := cursor.Node().(*ColName); ok {
+				panic("boom")
+			}
+			return true
+		}, nil)
+	})
+
+	var paths []string
+	Rewrite(stmt, func(cursor *Cursor) bool {
+		switch cursor.Node().(type) {
+		case *Select, *ColName:
+			paths = append(paths, strings.Join(cursor.Path(), "."))
+		}
+		return true
+	}, nil)
+	assert.Equal(t, []string{"Select", "Select.SelectExprs[0].Expr", "Select.Where.Expr.Left"}, paths)
+}
+
+func TestCursorParentAccessors(t *testing.T) {
+	stmt, err := Parse("select a from t where b = 1")
+	require.NoError(t, err)
+
+	var inSelect, inComparison []string
+	Rewrite(stmt, func(cursor *Cursor) bool {
+		if _, ok := cursor.ParentIfSelect(); ok {
+			inSelect = append(inSelect, fmt.Sprintf("%T", cursor.Node()))
+		}
+		if cmp, ok := cursor.ParentIfComparisonExpr(); ok {
+			assert.Same(t, cursor.Parent(), cmp)
+			inComparison = append(inComparison, String(cursor.Node()))
+		}
+		if _, ok := cursor.ParentIfUpdate(); ok {
+			t.Errorf("there is no update in the query")
+		}
+		return true
+	}, nil)
+
+	assert.Equal(t, []string{"*sqlparser.AliasedTableExpr", "sqlparser.SelectExprs", "*sqlparser.Where"}, inSelect)
+	assert.Equal(t, []string{"b", "1"}, inComparison)
+}
+
+func TestCursorEnclosingStatement(t *testing.T) {
+	stmt, err := Parse("insert into t(a) select b from u where c in (select d from v)")
+	require.NoError(t, err)
+	insert := stmt.(*Insert)
+	outer := insert.Rows.(*Select)
+	inner := outer.Where.Expr.(*ComparisonExpr).Right.(*Subquery).Select.(*Select)
+
+	expected := map[string]Statement{"a": insert, "b": outer, "c": outer, "d": inner}
+	for _, rewrite := range []func(SQLNode, ApplyFunc, ApplyFunc) SQLNode{Rewrite, RewriteIterative} {
+		var statements []Statement
+		rewrite(stmt, func(cursor *Cursor) bool {
+			if _, ok := cursor.Node().(Statement); ok {
+				statements = append(statements, cursor.EnclosingStatement())
+			}
+			if col, ok := cursor.Node().(*ColName); ok {
+				assert.Same(t, expected[String(col)], cursor.EnclosingStatement(), String(col))
+			}
+			return true
+		}, nil)
+
+		assert.Equal(t, []Statement{nil, insert, outer}, statements)
+	}
+}
+
+func TestRewriteWithFilter(t *testing.T) {
+	stmt, err := Parse("select a, (select b from u) from t where c = 1 and d in (select e from v)")
+	require.NoError(t, err)
+
+	noSubqueries := func(node SQLNode) bool {
+		_, isSubquery := node.(*Subquery)
+		return !isSubquery
+	}
+
+	var pre, post []string
+	RewriteWithFilter(stmt, func(cursor *Cursor) bool {
+		if col, ok := cursor.Node().(*ColName); ok {
+			pre = append(pre, String(col))
+		}
+		return true
+	}, func(cursor *Cursor) bool {
+		if col, ok := cursor.Node().(*ColName); ok {
+			post = append(post, String(col))
+		}
+		return true
+	}, noSubqueries)
+
+	assert.Equal(t, []string{"a", "c", "d"}, pre)
+	assert.Equal(t, pre, post)
+
+	// the filter applies to the pre-only and post-only rewrites too
+	post = nil
+	RewriteWithFilter(stmt, nil, func(cursor *Cursor) bool {
+		if col, ok := cursor.Node().(*ColName); ok {
+			post = append(post, String(col))
+		}
+		return true
+	}, noSubqueries)
+	assert.Equal(t, []string{"a", "c", "d"}, post)
+}
+
+func TestTypedRewriter(t *testing.T) {
+	stmt, err := Parse("select a, (select b from u) from t where c = 1 and d in (select e from v)")
+	require.NoError(t, err)
+
+	var cols, subqueries []string
+	result := NewTypedRewriter().
+		Pre((*ColName)(nil), func(cursor *Cursor) bool {
+			cols = append(cols, String(cursor.Node()))
+			return true
+		}).
+		Pre((*Subquery)(nil), func(cursor *Cursor) bool {
+			// the columns of the subqueries are not visited
+			return false
+		}).
+		Post((*Subquery)(nil), func(cursor *Cursor) bool {
+			subqueries = append(subqueries, String(cursor.Node()))
+			return true
+		}).
+		Pre((*Literal)(nil), func(cursor *Cursor) bool {
+			cursor.Replace(NewIntLiteral("2"))
+			return true
+		}).
+		Rewrite(stmt)
+
+	assert.Equal(t, []string{"a", "c", "d"}, cols)
+	assert.Empty(t, subqueries, "post is not called when pre returns false")
+	assert.Equal(t, "select a, (select b from u) from t where c = 2 and d in (select e from v)", String(result))
+}
+
+func TestRewriteDryRun(t *testing.T) {
+	stmt, err := Parse("select a, b + 1 from t where c = 1 and d = 2")
+	require.NoError(t, err)
+	before := String(stmt)
+
+	changes := RewriteDryRun(stmt, func(cursor *Cursor) bool {
+		if lit, ok := cursor.Node().(*Literal); ok {
+			cursor.Replace(NewIntLiteral(lit.Val + "0"))
+		}
+		return true
+	}, func(cursor *Cursor) bool {
+		if cmp, ok := cursor.Node().(*ComparisonExpr); ok && String(cmp.Left) == "d" {
+			cursor.Replace(&NotExpr{Expr: cmp})
+		}
+		return true
+	})
+
+	assert.Equal(t, before, String(stmt), "the AST must not be modified")
+
+	var got []string
+	for _, change := range changes {
+		got = append(got, fmt.Sprintf("%s: %s -> %s", change.Path, String(change.OldNode), String(change.NewNode)))
+	}
+	assert.Equal(t, []string{
+		"Select.SelectExprs[1].Expr.Right: 1 -> 10",
+		"Select.Where.Expr.Left.Right: 1 -> 10",
+		"Select.Where.Expr.Right.Right: 2 -> 20",
+		"Select.Where.Expr.Right: d = 2 -> not d = 2",
+	}, got)
+
+	assert.Panics(t, func() {
+		RewriteDryRun(stmt, func(cursor *Cursor) bool {
+			if _, ok := cursor.Node().(*AliasedExpr); ok {
+				cursor.Remove()
+			}
+			return true
+		}, nil)
+	})
+}
+
+func TestRewriteDryRunCarriesComments(t *testing.T) {
+	stmt, err := Parse("select /* outer */ a from (select b from t) as x")
+	require.NoError(t, err)
+	before := String(stmt)
+
+	// the outer statement is replaced with the subquery, which is still part of the AST:
+	// the comments must be carried over to the recorded change, but not to the AST
+	changes := RewriteDryRun(stmt, func(cursor *Cursor) bool {
+		if sel, ok := cursor.Node().(*Select); ok && len(sel.Comments) > 0 {
+			subquery := sel.From[0].(*AliasedTableExpr).Expr.(*DerivedTable)
+			cursor.Replace(subquery.Select)
+			return false
+		}
+		return true
+	}, nil)
+
+	assert.Equal(t, before, String(stmt), "the AST must not be modified")
+	require.Len(t, changes, 1)
+	assert.Equal(t, "select /* outer */ b from t", String(changes[0].NewNode))
+}
+
+func TestRewritePreservesComments(t *testing.T) {
+	var cases = []struct {
+		query    string
+		expected string
+	}{
+		{
+			"select /* leading */ a from t where b = 1",
+			"select /* leading */ a from renamed where b = 1",
+		},
+		{
+			"select /* outer */ a from (select /* inner */ b from t) as x",
+			"select /* outer */ a from (select /* inner */ b from renamed) as x",
+		},
+		{
+			"select /* left */ a from t union select b from u",
+			"select /* left */ a from renamed union select b from u",
+		},
+	}
+
+	for _, tc := range cases {
+		stmt, err := Parse(tc.query)
+		require.NoError(t, err)
+
+		// the statements that read from t are rebuilt from scratch, without their comments,
+		// and visited again, so the new table name is not replaced a second time
+		result := Rewrite(stmt, func(cursor *Cursor) bool {
+			sel, ok := cursor.Node().(*Select)
+			if !ok || String(TableExprs(sel.From)) != "t" {
+				return true
+			}
+			cursor.ReplaceAndRevisit(&Select{
+				SelectExprs: sel.SelectExprs,
+				From:        TableExprs{&AliasedTableExpr{Expr: TableName{Name: NewTableIdent("renamed")}}},
+				Where:       sel.Where,
+			})
+			return true
+		}, nil)
+		assert.Equal(t, tc.expected, String(result))
+	}
+}
+
+func TestReplaceCopiesComments(t *testing.T) {
+	stmt, err := Parse("select /* keep me */ a from t")
+	require.NoError(t, err)
+	original := stmt.(*Select)
+
+	result := Rewrite(stmt, func(cursor *Cursor) bool {
+		if sel, ok := cursor.Node().(*Select); ok {
+			cursor.Replace(&Select{SelectExprs: sel.SelectExprs, From: sel.From})
+			return false
+		}
+		return true
+	}, nil)
+
+	// the old node keeps its own comments, which can be changed without affecting the new one
+	original.Comments[0] = "/* changed */"
+	assert.Equal(t, "select /* keep me */ a from t", String(result))
+}
+
+func TestReplaceCarriesComments(t *testing.T) {
+	stmt, err := Parse("select /* keep me */ a from t")
+	require.NoError(t, err)
+
+	replacement, err := Parse("select b from u union select c from v")
+	require.NoError(t, err)
+
+	result := Rewrite(stmt, func(cursor *Cursor) bool {
+		if _, ok := cursor.Node().(*Select); ok {
+			cursor.Replace(replacement)
+			return false
+		}
+		return true
+	}, nil)
+	assert.Equal(t, "select /* keep me */ b from u union select c from v", String(result))
+
+	// the comments of the replacement take precedence
+	stmt, err = Parse("select /* old */ a from t")
+	require.NoError(t, err)
+	replacement, err = Parse("select /* new */ b from u")
+	require.NoError(t, err)
+
+	result = Rewrite(stmt, func(cursor *Cursor) bool {
+		if _, ok := cursor.Node().(*Select); ok {
+			cursor.Replace(replacement)
+			return false
+		}
+		return true
+	}, nil)
+	assert.Equal(t, "select /* new */ b from u", String(result))
+}
+
+func TestRewriteMaxDepth(t *testing.T) {
+	// 100k levels of nesting, which used to be walked recursively all the way down
+	var expr Expr = NewIntLiteral("1")
+	for i := 0; i < 100000; i++ {
+		expr = &NotExpr{Expr: expr}
+	}
+	stmt := &Select{SelectExprs: SelectExprs{&AliasedExpr{Expr: expr}}}
+
+	visited := 0
+	_, err := RewriteWithOptions(stmt, func(cursor *Cursor) bool {
+		visited++
+		return true
+	}, nil, RewriteOptions{MaxDepth: 1000})
+	require.EqualError(t, err, "the AST is deeper than the maximum of 1000 levels")
+	// the root and the 1000 levels below it are visited
+	assert.Equal(t, 1001, visited)
+
+	// the limit is not reached by a regular query, and a rewrite can still abort on its own
+	stmt2, err := Parse("select a from t where b = 1")
+	require.NoError(t, err)
+	result, err := RewriteWithOptions(stmt2, nil, func(cursor *Cursor) bool {
+		_, isCol := cursor.Node().(*ColName)
+		return !isCol
+	}, RewriteOptions{MaxDepth: 1000})
+	require.NoError(t, err)
+	assert.Equal(t, stmt2, result)
+
+	// without a limit, the whole AST is visited
+	visited = 0
+	_, err = RewriteWithOptions(stmt, func(cursor *Cursor) bool {
+		visited++
+		return true
+	}, nil, RewriteOptions{})
+	require.NoError(t, err)
+	assert.Greater(t, visited, 100000)
+}
+
+func TestRewriteIterative(t *testing.T) {
+	trace := func(steps *[]string) (pre, post ApplyFunc) {
+		pre = func(cursor *Cursor) bool {
+			*steps = append(*steps, fmt.Sprintf("%T:%s[%d]", cursor.Node(), cursor.FieldName(), cursor.FieldIndex()))
+			return true
+		}
+		post = func(cursor *Cursor) bool {
+			*steps = append(*steps, fmt.Sprintf("/%T", cursor.Node()))
+			return true
+		}
+		return
+	}
+
+	for _, tcase := range validSQL {
+		stmt, err := Parse(tcase.input)
+		if err != nil {
+			continue
+		}
+		var expected, got []string
+		pre, post := trace(&expected)
+		Rewrite(stmt, pre, post)
+		pre, post = trace(&got)
+		RewriteIterative(stmt, pre, post)
+		require.Equal(t, expected, got, tcase.input)
+	}
+
+	// replacing nodes works the same way as in Rewrite
+	stmt, err := Parse("select a, b from t where a = 1")
+	require.NoError(t, err)
+	result := RewriteIterative(stmt, func(cursor *Cursor) bool {
+		if col, ok := cursor.Node().(*ColName); ok && col.Name.EqualString("a") {
+			cursor.Replace(NewColName("x"))
+		}
+		return true
+	}, nil)
+	assert.Equal(t, "select x, b from t where x = 1", String(result))
+}
+
+func TestRewriteIterativeDeepAST(t *testing.T) {
+	var expr Expr = NewIntLiteral("1")
+	for i := 0; i < 1000000; i++ {
+		expr = &NotExpr{Expr: expr}
+	}
+
+	visited := 0
+	RewriteIterative(expr, func(cursor *Cursor) bool {
+		visited++
+		return true
+	}, nil)
+	assert.Equal(t, 1000001, visited)
+}
+
+// unknownExpr is an expression that the generated AST helpers don't know about, like
+// the ones that are added to the AST without regenerating the helpers
+type unknownExpr struct{}
+
+func (unknownExpr) iExpr()                        {}
+func (unknownExpr) Format(buf *TrackedBuffer)     { buf.WriteString("unknown") }
+func (unknownExpr) formatFast(buf *TrackedBuffer) { buf.WriteString("unknown") }
+
+func TestIsKnownType(t *testing.T) {
+	assert.True(t, IsKnownType(&Select{}))
+	assert.True(t, IsKnownType(NewIntLiteral("1")))
+	assert.True(t, IsKnownType(ColIdent{}))
+	assert.True(t, IsKnownType(Exprs{}))
+	assert.False(t, IsKnownType(unknownExpr{}))
+	assert.False(t, IsKnownType(nil))
+}
+
+func TestRewriteUnknownNode(t *testing.T) {
+	expr := &AndExpr{Left: unknownExpr{}, Right: NewIntLiteral("1")}
+	rewriters := map[string]func(SQLNode, ApplyFunc, ApplyFunc) SQLNode{
+		"Rewrite":          Rewrite,
+		"RewriteIterative": RewriteIterative,
+	}
+
+	for name, rewrite := range rewriters {
+		t.Run(name, func(t *testing.T) {
+			assert.PanicsWithValue(t, "unknown AST node type sqlparser.unknownExpr", func() {
+				rewrite(expr, nil, nil)
+			})
+
+			var unknown []SQLNode
+			SkipUnknownNodes(func(node SQLNode) {
+				unknown = append(unknown, node)
+			})
+			defer SkipUnknownNodes(nil)
+
+			var visited []string
+			rewrite(expr, func(cursor *Cursor) bool {
+				visited = append(visited, String(cursor.Node()))
+				return true
+			}, nil)
+			assert.Equal(t, []string{"unknown and 1", "1"}, visited)
+			assert.Equal(t, []SQLNode{unknownExpr{}}, unknown)
+		})
+	}
+
+	t.Run("RewriteWithOptions", func(t *testing.T) {
+		var visited []string
+		var unknown []SQLNode
+		_, err := RewriteWithOptions(expr, func(cursor *Cursor) bool {
+			visited = append(visited, String(cursor.Node()))
+			return true
+		}, nil, RewriteOptions{OnUnknownNode: func(node SQLNode) {
+			unknown = append(unknown, node)
+		}})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"unknown and 1", "1"}, visited)
+		assert.Equal(t, []SQLNode{unknownExpr{}}, unknown)
+	})
+}
@@ -17,12 +17,56 @@ limitations under the License.
 package sqlparser
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
+func TestWalkVisitsSameNodesAsRewrite(t *testing.T) {
+	for i := 0; i < 10; i++ {
+		exp := newGenerator(int64(i*100), 5).expression()
+
+		var rewritten, walked []SQLNode
+		_ = Rewrite(exp, func(cursor *Cursor) bool {
+			rewritten = append(rewritten, cursor.Node())
+			return true
+		}, nil)
+		err := Walk(func(node SQLNode) (bool, error) {
+			walked = append(walked, node)
+			return true, nil
+		}, exp)
+		require.NoError(t, err)
+
+		require.Equal(t, len(rewritten), len(walked))
+		for j := range rewritten {
+			assert.Equal(t, fmt.Sprintf("%T", rewritten[j]), fmt.Sprintf("%T", walked[j]))
+		}
+	}
+}
+
+func TestWalkStopsOnError(t *testing.T) {
+	stmt, err := Parse("select a, b, c from t where d = 1")
+	require.NoError(t, err)
+
+	errFound := errors.New("found b")
+	var seen []string
+	err = Walk(func(node SQLNode) (bool, error) {
+		if col, ok := node.(*ColName); ok {
+			seen = append(seen, col.Name.String())
+			if col.Name.EqualString("b") {
+				return false, errFound
+			}
+		}
+		return true, nil
+	}, stmt)
+
+	assert.Equal(t, errFound, err)
+	assert.Equal(t, []string{"a", "b"}, seen)
+}
+
 func BenchmarkWalkLargeExpression(b *testing.B) {
 	for i := 0; i < 10; i++ {
 		b.Run(fmt.Sprintf("%d", i), func(b *testing.B) {
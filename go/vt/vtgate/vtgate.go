@@ -69,6 +69,7 @@ var (
 	defaultDDLStrategy   = flag.String("ddl_strategy", string(schema.DDLStrategyDirect), "Set default strategy for DDL statements. Override with @@ddl_strategy session variable")
 	dbDDLPlugin          = flag.String("dbddl_plugin", "fail", "controls how to handle CREATE/DROP DATABASE. use it if you are using your own database provisioning service")
 	noScatter            = flag.Bool("no_scatter", false, "when set to true, the planner will fail instead of producing a plan that includes scatter queries")
+	skipUnknownASTNodes  = flag.Bool("skip_unknown_ast_nodes", false, "when set to true, the AST nodes of unknown types are skipped with a warning when rewriting queries, instead of panicking. Only meant as a safety valve during rolling upgrades")
 
 	// TODO(deepthi): change these two vars to unexported and move to healthcheck.go when LegacyHealthcheck is removed
 
@@ -195,6 +196,12 @@ func Init(ctx context.Context, serv srvtopo.Server, cell string, tabletTypesToWa
 	if _, err := schema.ParseDDLStrategy(*defaultDDLStrategy); err != nil {
 		log.Fatalf("Invalid value for -ddl_strategy: %v", err.Error())
 	}
+	if *skipUnknownASTNodes {
+		sqlparser.SkipUnknownNodes(func(node sqlparser.SQLNode) {
+			log.Warningf("skipping unknown AST node type %T while rewriting", node)
+		})
+	}
+
 	tc := NewTxConn(gw, getTxMode())
 	// ScatterConn depends on TxConn to perform forced rollbacks.
 	sc := NewScatterConn("VttabletCall", tc, gw)